@@ -8,6 +8,7 @@ import (
 
 	"agent-economique/internal/datasource/binance"
 	"agent-economique/internal/engine"
+	"agent-economique/internal/shared"
 )
 
 // DataFetcher handles Binance API data retrieval for backtest simulation
@@ -130,6 +131,9 @@ func (df *DataFetcher) calculateLimit(timeframe string, days int) int {
 	perDay, exists := klinesPerDay[timeframe]
 	if !exists {
 		perDay = 288 // Default to 5m
+		if intervalMs, err := shared.ParseTimeframe(timeframe); err == nil {
+			perDay = int((24*60*60*1000 + intervalMs - 1) / intervalMs)
+		}
 	}
 	
 	// Add 20% buffer for safety and weekends
@@ -329,24 +333,13 @@ func (df *DataFetcher) ConvertToEngineFormat(dataset *HistoricalDataSet) (*engin
 	}, nil
 }
 
-// GetTimeframeDuration returns duration for a timeframe string
+// GetTimeframeDuration returns duration for a timeframe string (custom timeframes supported)
 func GetTimeframeDuration(timeframe string) time.Duration {
-	switch timeframe {
-	case "1m":
-		return 1 * time.Minute
-	case "5m":
-		return 5 * time.Minute
-	case "15m":
-		return 15 * time.Minute
-	case "1h":
-		return 1 * time.Hour
-	case "4h":
-		return 4 * time.Hour
-	case "1d":
-		return 24 * time.Hour
-	default:
+	intervalMs, err := shared.ParseTimeframe(timeframe)
+	if err != nil {
 		return 5 * time.Minute // Default to 5m
 	}
+	return time.Duration(intervalMs) * time.Millisecond
 }
 
 // max returns the maximum of two integers
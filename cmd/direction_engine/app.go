@@ -242,15 +242,56 @@ func (app *DirectionEngineApp) loadKlines() error {
 	timeframe := app.directionCfg.Timeframe
 	app.klines = make([]Kline, 0, len(app.dates)*288)
 
+	// Timeframes personnalisés (7m, 3h, 15s...) : agrégation depuis un TF natif ou depuis les trades
+	sourceTF, err := shared.SourceTimeframe(timeframe)
+	if err != nil {
+		return err
+	}
+	aggregator, err := binance.NewTimeframeAggregator(aggConfig)
+	if err != nil {
+		return err
+	}
+
 	for _, date := range app.dates {
-		klinesFile := cache.GetFilePath(symbol, "klines", date, timeframe)
-		batch, err := processor.ParseKlinesBatch(klinesFile, symbol, timeframe, date)
-		if err != nil {
-			fmt.Printf("  ⚠️  Skip date %s: %v\n", date, err)
-			continue
+		var dayKlines []shared.KlineData
+
+		if sourceTF == "" {
+			// Sub-minute: construction des bougies depuis les trades Vision
+			builder, err := binance.NewTradeBarBuilder(timeframe)
+			if err != nil {
+				return err
+			}
+			tradesFile := cache.GetFilePath(symbol, "trades", date)
+			err = reader.StreamTrades(tradesFile, func(trade shared.TradeData) error {
+				if closed, ok := builder.AddTrade(trade); ok {
+					dayKlines = append(dayKlines, *closed)
+				}
+				return nil
+			})
+			if err != nil {
+				fmt.Printf("  ⚠️  Skip date %s: %v\n", date, err)
+				continue
+			}
+			if last, ok := builder.Flush(); ok {
+				dayKlines = append(dayKlines, *last)
+			}
+		} else {
+			klinesFile := cache.GetFilePath(symbol, "klines", date, sourceTF)
+			batch, err := processor.ParseKlinesBatch(klinesFile, symbol, sourceTF, date)
+			if err != nil {
+				fmt.Printf("  ⚠️  Skip date %s: %v\n", date, err)
+				continue
+			}
+			dayKlines = batch.KlinesData
+			if sourceTF != timeframe {
+				if dayKlines, err = aggregator.AggregateKlinesToTimeframe(dayKlines, timeframe); err != nil {
+					fmt.Printf("  ⚠️  Skip date %s: %v\n", date, err)
+					continue
+				}
+			}
 		}
 
-		for _, klineData := range batch.KlinesData {
+		for _, klineData := range dayKlines {
 			kline := Kline{
 				Timestamp:        klineData.OpenTime,
 				Open:             klineData.Open,
@@ -309,12 +350,32 @@ func (app *DirectionEngineApp) processTradesStreaming() error {
 		MaxMemoryMB:   app.config.BinanceData.Streaming.MaxMemoryMB,
 		EnableMetrics: app.config.BinanceData.Streaming.EnableMetrics,
 	}
-	reader, err := binance.NewStreamingReader(cache, streamConfig)
+	zipReader, err := binance.NewStreamingReader(cache, streamConfig)
 	if err != nil {
 		return fmt.Errorf("erreur création reader: %w", err)
 	}
 
 	symbol := app.config.BinanceData.Symbols[0]
+
+	// Lecture depuis le store binaire si activé (évite le parsing CSV) : la compilation tourne
+	// en arrière-plan, chaque date est lue depuis le store dès qu'elle est compilée, depuis
+	// le ZIP si sa compilation échoue
+	tradesSource := func(date string) (shared.StreamingReader, string) {
+		return zipReader, cache.GetFilePath(symbol, "trades", date)
+	}
+	if app.config.BinanceData.Store.Enabled {
+		store, compiled, err := app.compileTradesStore(cache, zipReader, symbol)
+		if err != nil {
+			fmt.Printf("⚠️  Store binaire indisponible, lecture des ZIP: %v\n", err)
+		} else {
+			tradesSource = func(date string) (shared.StreamingReader, string) {
+				if compiled(date) {
+					return store, store.GetFilePath(symbol, "trades", date)
+				}
+				return zipReader, cache.GetFilePath(symbol, "trades", date)
+			}
+		}
+	}
 	var nextMarker int64
 	tradesProcessed := 0
 	markersDetected := 0
@@ -326,7 +387,7 @@ func (app *DirectionEngineApp) processTradesStreaming() error {
 			fmt.Printf("\n📅 Date %d/%d: %s\n", dateIdx+1, len(app.dates), date)
 		}
 
-		tradesFile := cache.GetFilePath(symbol, "trades", date)
+		reader, tradesFile := tradesSource(date)
 		dayTrades := 0
 		
 		// Streamer les trades de cette date
@@ -396,6 +457,69 @@ func (app *DirectionEngineApp) processTradesStreaming() error {
 	return nil
 }
 
+// compileTradesStore lance en arrière-plan la compilation des ZIP trades manquants dans le
+// store binaire ; compiled(date) attend la compilation de la date et indique si le store
+// peut être lu
+func (app *DirectionEngineApp) compileTradesStore(cache *binance.CacheManager, zipReader *binance.StreamingReader, symbol string) (*binance.BinaryStore, func(date string) bool, error) {
+	storeCfg := app.config.BinanceData.Store
+	rootPath := storeCfg.RootPath
+	if rootPath == "" {
+		rootPath = filepath.Join(app.config.BinanceData.CacheRoot, "store")
+	}
+
+	store, err := binance.NewBinaryStore(rootPath, storeCfg.UseMmap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur init store: %w", err)
+	}
+
+	compiler, err := binance.NewStoreCompiler(cache, zipReader, store, storeCfg.MaxConcurrent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur init compilateur store: %w", err)
+	}
+
+	// Une entrée par date, créée avant le lancement : ok est écrit avant la fermeture de done
+	type dateCompile struct {
+		done chan struct{}
+		ok   bool
+	}
+	requests := make([]shared.DownloadRequest, 0, len(app.dates))
+	pending := make(map[string]*dateCompile, len(app.dates))
+	for _, date := range app.dates {
+		if _, dup := pending[date]; dup {
+			continue
+		}
+		requests = append(requests, shared.DownloadRequest{Symbol: symbol, DataType: "trades", Date: date})
+		pending[date] = &dateCompile{done: make(chan struct{})}
+	}
+
+	results := compiler.StartBackground(requests)
+	go func() {
+		compiled := 0
+		for result := range results {
+			entry := pending[result.Request.Date]
+			if result.Error != "" {
+				fmt.Printf("⚠️  Compilation store %s: %s (lecture du ZIP)\n", result.Request.Date, result.Error)
+			} else {
+				entry.ok = true
+				if !result.Skipped {
+					compiled++
+				}
+			}
+			close(entry.done)
+		}
+		fmt.Printf("📦 Store binaire: %d fichier(s) compilé(s) dans %s\n", compiled, rootPath)
+	}()
+
+	return store, func(date string) bool {
+		entry, known := pending[date]
+		if !known {
+			return false
+		}
+		<-entry.done
+		return entry.ok
+	}, nil
+}
+
 func (app *DirectionEngineApp) displayResults() {
 	fmt.Println("\n" + repeatStr("═", 100))
 	fmt.Println("  RÉSULTATS BACKTEST DIRECTION")
@@ -525,14 +649,14 @@ func (app *DirectionEngineApp) exportSignalsToJSON() error {
 
 // processMarker traite un marqueur (calcul indicateurs + détection signaux)
 func (app *DirectionEngineApp) processMarker(markerTimestamp int64) {
-	// Récupérer window de klines jusqu'au marqueur (300 dernières)
+	// Récupérer window de klines jusqu'au marqueur (historique requis, 300 par défaut)
 	klineIdx := app.findKlineIndexAtTimestamp(markerTimestamp)
 	if klineIdx < 0 {
 		return
 	}
 
 	// Construire window de signals.Kline pour le générateur
-	windowSize := 300
+	windowSize := signals.RequiredHistorySize(app.generator, 300)
 	startIdx := klineIdx - windowSize + 1
 	if startIdx < 0 {
 		startIdx = 0
@@ -711,22 +835,13 @@ func (app *DirectionEngineApp) updateCurrentKline(trade shared.TradeData) {
 	app.currentKline.TradesCount++
 }
 
-// calculateNextMarker calcule le prochain marqueur 5m
+// calculateNextMarker calcule le prochain marqueur du timeframe configuré (5m par défaut)
 func (app *DirectionEngineApp) calculateNextMarker(currentTimestamp int64) int64 {
-	t := timestampMsToTime(currentTimestamp)
-	
-	// Aligner sur prochaine 5 minutes
-	minute := t.Minute()
-	nextMinute := ((minute / 5) + 1) * 5
-	
-	if nextMinute >= 60 {
-		t = t.Add(time.Hour)
-		nextMinute = 0
+	next, err := shared.NextTimeframeBoundary(currentTimestamp, app.directionCfg.Timeframe)
+	if err != nil {
+		next, _ = shared.NextTimeframeBoundary(currentTimestamp, "5m")
 	}
-	
-	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), nextMinute, 0, 0, t.Location())
-	
-	return t.UnixNano() / 1e6
+	return next
 }
 
 // findKlineIndexAtTimestamp trouve l'index de la kline à un timestamp
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"agent-economique/internal/datasource/binance"
+	"agent-economique/internal/datasource/bybit"
+	"agent-economique/internal/datasource/gateio"
+	"agent-economique/internal/datasource/multisource"
+	"agent-economique/internal/shared"
+)
+
+// Mesure continue des divergences de klines entre Binance, Bybit et Gate.io
+func main() {
+	timeframe := flag.String("timeframe", "5m", "Timeframe des klines")
+	limit := flag.Int("limit", 300, "Nombre de klines par source")
+	priority := flag.String("priority", "binance,bybit,gateio", "Priorité des sources (la première gagne)")
+	maxPriceDev := flag.Float64("max-price-dev", 0.1, "Écart max du close vs médiane (%)")
+	maxVolumeDev := flag.Float64("max-volume-dev", 50, "Écart max du volume vs médiane (%)")
+	every := flag.Duration("every", 0, "Intervalle de rafraîchissement (0 = une seule passe)")
+	flag.Parse()
+
+	fmt.Println("🎯 DIVERGENCES MULTI-SOURCES - SOL")
+	fmt.Println("=" + strings.Repeat("=", 45))
+
+	merger, err := multisource.NewMerger(multisource.MergerConfig{
+		Timeframe: *timeframe,
+		Priority:  strings.Split(*priority, ","),
+		Validation: shared.ValidationConfig{
+			MaxPriceDeviation:  *maxPriceDev,
+			MaxVolumeDeviation: *maxVolumeDev,
+		},
+		SkipFlaggedSources: true,
+	})
+	if err != nil {
+		fmt.Printf("❌ Configuration invalide: %v\n", err)
+		return
+	}
+
+	sources := []multisource.KlineSource{
+		multisource.NewBinanceFuturesSource(binance.NewFuturesClient(), "SOLUSDT"),
+		multisource.NewBybitSource(bybit.NewClient(), "SOLUSDT"),
+		multisource.NewGateIOSource(gateio.NewClient(), "SOL_USDT"),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for {
+		runPass(ctx, merger, sources, *timeframe, *limit)
+		if *every <= 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Println("\n🛑 Arrêt demandé")
+			return
+		case <-time.After(*every):
+		}
+	}
+}
+
+// runPass récupère toutes les sources, fusionne et affiche les statistiques
+func runPass(ctx context.Context, merger *multisource.Merger, sources []multisource.KlineSource, timeframe string, limit int) {
+	fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	fmt.Printf("\n📡 [%s] Récupération %d klines %s...\n", time.Now().Format("15:04:05"), limit, timeframe)
+	data, errs := multisource.FetchAll(fetchCtx, sources, timeframe, limit)
+	for name, err := range errs {
+		fmt.Printf("⚠️  %s: %v\n", name, err)
+	}
+	if len(data) == 0 {
+		fmt.Println("❌ Aucune source disponible")
+		return
+	}
+
+	result, err := merger.Merge(data)
+	if err != nil {
+		fmt.Printf("❌ Erreur fusion: %v\n", err)
+		return
+	}
+
+	fmt.Printf("✅ %d bougies fusionnées, %d signalées, %d trous\n", len(result.Klines), result.FlaggedBars, len(result.Gaps))
+
+	names := make([]string, 0, len(result.Stats))
+	for name := range result.Stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("\n📊 ÉCARTS PAR SOURCE (vs médiane):")
+	fmt.Printf("%-10s %6s %6s %6s %12s %12s %12s %12s\n", "Source", "Bars", "Miss", "Sel", "|Close| moy", "|Close| max", "|Vol| moy", "|Vol| max")
+	for _, name := range names {
+		s := result.Stats[name]
+		fmt.Printf("%-10s %6d %6d %6d %11.4f%% %11.4f%% %11.2f%% %11.2f%%\n",
+			name, s.Bars, s.Missing, s.Selected, s.MeanAbsClosePct, s.MaxAbsClosePct, s.MeanAbsVolPct, s.MaxAbsVolPct)
+	}
+
+	fmt.Println("\n🔎 5 DERNIÈRES BOUGIES SIGNALÉES:")
+	shown := 0
+	for i := len(result.Klines) - 1; i >= 0 && shown < 5; i-- {
+		k := result.Klines[i]
+		if !k.Flagged {
+			continue
+		}
+		shown++
+		fmt.Printf("  %s  source=%s", time.UnixMilli(k.OpenTime).UTC().Format("01-02 15:04"), k.Source)
+		for _, name := range k.Sources {
+			d := k.Deviations[name]
+			fmt.Printf("  %s: close %+.3f%% vol %+.1f%%", name, d.ClosePct, d.VolumePct)
+		}
+		fmt.Println()
+	}
+	if shown == 0 {
+		fmt.Println("  Aucune")
+	}
+
+	cumulative := merger.CumulativeStats()
+	fmt.Println("\n📈 CUMUL DEPUIS LE DÉMARRAGE:")
+	for _, name := range names {
+		s := cumulative[name]
+		fmt.Printf("  %-10s bars=%d close_flag=%d vol_flag=%d |close| moy=%.4f%%\n", name, s.Bars, s.CloseFlagged, s.VolumeFlagged, s.MeanAbsClosePct)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"agent-economique/internal/datasource/orderbook"
+)
+
+// Enregistrement continu de la profondeur L2 Binance Futures (snapshots + diffs)
+func main() {
+	symbols := flag.String("symbols", "SOLUSDT", "Symboles séparés par des virgules")
+	output := flag.String("output", "data/orderbook", "Répertoire des fichiers .depth")
+	depth := flag.Int("depth", 1000, "Profondeur du snapshot REST")
+	snapshotEvery := flag.Duration("snapshot-every", time.Minute, "Intervalle des snapshots périodiques")
+	snapshotLevels := flag.Int("snapshot-levels", 0, "Niveaux par côté dans les snapshots périodiques (0 = tous)")
+	statsEvery := flag.Duration("stats-every", 30*time.Second, "Intervalle d'affichage des statistiques")
+	flag.Parse()
+
+	fmt.Println("📚 ENREGISTREUR ORDER BOOK - BINANCE FUTURES")
+	fmt.Println("=" + strings.Repeat("=", 45))
+
+	recorder, err := orderbook.NewRecorder(orderbook.RecorderConfig{
+		Symbols:          strings.Split(*symbols, ","),
+		OutputDir:        *output,
+		DepthLimit:       *depth,
+		SnapshotInterval: *snapshotEvery,
+		SnapshotLevels:   *snapshotLevels,
+	}, orderbook.NewBinanceDepthSource("", ""))
+	if err != nil {
+		fmt.Printf("❌ Configuration invalide: %v\n", err)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	done := make(chan struct{})
+	go func() {
+		recorder.Run(ctx)
+		close(done)
+	}()
+
+	ticker := time.NewTicker(*statsEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			fmt.Println("\n🛑 Arrêt demandé")
+			printStats(recorder.Stats())
+			return
+		case <-ticker.C:
+			printStats(recorder.Stats())
+		}
+	}
+}
+
+// printStats affiche les statistiques par symbole
+func printStats(stats map[string]orderbook.RecorderStats) {
+	symbols := make([]string, 0, len(stats))
+	for symbol := range stats {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	fmt.Printf("\n📊 [%s]\n", time.Now().Format("15:04:05"))
+	for _, symbol := range symbols {
+		s := stats[symbol]
+		fmt.Printf("   %-10s snapshots=%d diffs=%d resyncs=%d fichier=%s\n", symbol, s.Snapshots, s.Diffs, s.Resyncs, s.CurrentFile)
+		if s.LastError != "" {
+			fmt.Printf("   ⚠️  dernière erreur: %s\n", s.LastError)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/adshao/go-binance/v2"
 
+	binancedata "agent-economique/internal/datasource/binance"
 	"agent-economique/internal/indicators"
 	"agent-economique/internal/notifications"
 	"agent-economique/internal/shared"
@@ -192,30 +193,11 @@ func (app *ScalpingLiveApp) loadInitialKlines() error {
 	symbol := app.config.BinanceData.Symbols[0]
 	timeframe := app.config.Strategy.ScalpingConfig.Timeframe
 
-	// Appel SDK Binance Futures
-	klines, err := app.binanceClient.NewKlinesService().
-		Symbol(symbol).
-		Interval(timeframe).
-		Limit(300).
-		Do(context.Background())
-
+	klines, err := app.fetchKlines(symbol, timeframe, 300)
 	if err != nil {
-		return fmt.Errorf("Binance SDK error: %w", err)
-	}
-
-	// Convertir Binance Klines → notre struct Kline
-	app.klines = make([]Kline, len(klines))
-	for i, k := range klines {
-		app.klines[i] = Kline{
-			Timestamp:        k.OpenTime,
-			Open:             parseFloat(k.Open),
-			High:             parseFloat(k.High),
-			Low:              parseFloat(k.Low),
-			Close:            parseFloat(k.Close),
-			Volume:           parseFloat(k.Volume),
-			QuoteAssetVolume: parseFloat(k.QuoteAssetVolume),
-		}
+		return err
 	}
+	app.klines = klines
 
 	// Mémoriser dernier timestamp connu
 	if len(app.klines) > 0 {
@@ -231,19 +213,25 @@ func (app *ScalpingLiveApp) loadInitialKlines() error {
 // 2. Sur clôture bougie : Calcul indicateurs + détection signaux
 // IMPORTANT: Synchronisé sur :00, :10, :20, :30, :40, :50 pour coïncider avec clôtures
 func (app *ScalpingLiveApp) runTimerLoop(ctx context.Context) error {
-	loopInterval := 10 * time.Second
+	loopSeconds := 10
 	timeframe := app.config.Strategy.ScalpingConfig.Timeframe
 
-	// 1️⃣ Calculer délai jusqu'au prochain multiple de 10 secondes
+	// Bougies sub-minute (15s, 30s) : tick plus fréquent pour détecter chaque clôture
+	if intervalMs, err := shared.ParseTimeframe(timeframe); err == nil && intervalMs < 60*1000 {
+		loopSeconds = 5
+	}
+	loopInterval := time.Duration(loopSeconds) * time.Second
+
+	// 1️⃣ Calculer délai jusqu'au prochain multiple de loopSeconds
 	now := time.Now()
 	currentSecond := now.Second()
-	secondsUntilNext := 10 - (currentSecond % 10)
-	if secondsUntilNext == 10 {
-		secondsUntilNext = 0 // Déjà sur un multiple de 10
+	secondsUntilNext := loopSeconds - (currentSecond % loopSeconds)
+	if secondsUntilNext == loopSeconds {
+		secondsUntilNext = 0 // Déjà sur un multiple
 	}
 	nextSync := now.Add(time.Duration(secondsUntilNext) * time.Second).Truncate(time.Second)
 
-	fmt.Printf("⏱️  Synchronisation sur multiples de 10s...\n")
+	fmt.Printf("⏱️  Synchronisation sur multiples de %ds...\n", loopSeconds)
 	fmt.Printf("   Heure actuelle: %s\n", now.Format("15:04:05"))
 	fmt.Printf("   Prochain tick: %s (dans %ds)\n", nextSync.Format("15:04:05"), secondsUntilNext)
 	fmt.Printf("   Timeframe bougie: %s\n\n", timeframe)
@@ -336,21 +324,90 @@ func (app *ScalpingLiveApp) fetchLatestKlines() ([]Kline, error) {
 	timeframe := app.config.Strategy.ScalpingConfig.Timeframe
 
 	// Récupérer seulement les 10 dernières via SDK
-	binanceKlines, err := app.binanceClient.NewKlinesService().
-		Symbol(symbol).
-		Interval(timeframe).
-		Limit(10).
-		Do(context.Background())
+	return app.fetchKlines(symbol, timeframe, 10)
+}
 
+// fetchKlines récupère les klines du timeframe demandé
+// - TF natif (1m, 5m...) : klines Binance directes
+// - TF personnalisé (2m, 7m, 3h) : agrégation depuis le TF natif source
+// - TF sub-minute (15s, 30s) : construction depuis les aggTrades
+func (app *ScalpingLiveApp) fetchKlines(symbol, timeframe string, limit int) ([]Kline, error) {
+	sourceTF, err := shared.SourceTimeframe(timeframe)
 	if err != nil {
-		return nil, fmt.Errorf("Binance SDK error: %w", err)
+		return nil, err
 	}
 
-	// Convertir
-	klines := make([]Kline, len(binanceKlines))
-	for i, k := range binanceKlines {
+	var data []shared.KlineData
+	if sourceTF == "" {
+		data, err = app.fetchKlinesFromAggTrades(symbol, timeframe, limit)
+	} else {
+		data, err = app.fetchNativeKlines(symbol, timeframe, sourceTF, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > limit {
+		data = data[len(data)-limit:]
+	}
+
+	klines := make([]Kline, len(data))
+	for i, k := range data {
 		klines[i] = Kline{
 			Timestamp:        k.OpenTime,
+			Open:             k.Open,
+			High:             k.High,
+			Low:              k.Low,
+			Close:            k.Close,
+			Volume:           k.Volume,
+			QuoteAssetVolume: k.QuoteAssetVolume,
+		}
+	}
+
+	return klines, nil
+}
+
+// fetchNativeKlines récupère les klines sourceTF et les agrège vers timeframe si nécessaire
+func (app *ScalpingLiveApp) fetchNativeKlines(symbol, timeframe, sourceTF string, limit int) ([]shared.KlineData, error) {
+	targetMs, _ := shared.ParseTimeframe(timeframe)
+	sourceMs, _ := shared.ParseTimeframe(sourceTF)
+	ratio := int(targetMs / sourceMs)
+
+	// +1 bucket pour que la première bougie agrégée soit complète
+	sourceLimit := (limit + 1) * ratio
+
+	// Pagination à rebours par endTime au-delà de la limite par requête
+	const maxPerRequest = 1000 // Limite API Binance Spot
+	var binanceKlines []*binance.Kline
+	var endTime int64
+	for len(binanceKlines) < sourceLimit {
+		pageLimit := sourceLimit - len(binanceKlines)
+		if pageLimit > maxPerRequest {
+			pageLimit = maxPerRequest
+		}
+		service := app.binanceClient.NewKlinesService().
+			Symbol(symbol).
+			Interval(sourceTF).
+			Limit(pageLimit)
+		if endTime > 0 {
+			service = service.EndTime(endTime)
+		}
+		page, err := service.Do(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("Binance SDK error: %w", err)
+		}
+		binanceKlines = append(page, binanceKlines...)
+		if len(page) < pageLimit {
+			break // Début de l'historique disponible
+		}
+		endTime = page[0].OpenTime - 1
+	}
+
+	data := make([]shared.KlineData, len(binanceKlines))
+	for i, k := range binanceKlines {
+		data[i] = shared.KlineData{
+			OpenTime:         k.OpenTime,
+			CloseTime:        k.CloseTime,
 			Open:             parseFloat(k.Open),
 			High:             parseFloat(k.High),
 			Low:              parseFloat(k.Low),
@@ -360,7 +417,72 @@ func (app *ScalpingLiveApp) fetchLatestKlines() ([]Kline, error) {
 		}
 	}
 
-	return klines, nil
+	if sourceTF == timeframe || len(data) == 0 {
+		return data, nil
+	}
+
+	aggregator, err := binancedata.NewTimeframeAggregator(shared.AggregationConfig{})
+	if err != nil {
+		return nil, err
+	}
+	aggregated, err := aggregator.AggregateKlinesToTimeframe(data, timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	// Écarter la première bougie si elle est partielle (début de fenêtre au milieu d'un bucket)
+	if len(aggregated) > 1 && aggregated[0].OpenTime < data[0].OpenTime {
+		aggregated = aggregated[1:]
+	}
+
+	return aggregated, nil
+}
+
+// fetchKlinesFromAggTrades construit des bougies sub-minute depuis les aggTrades récents
+func (app *ScalpingLiveApp) fetchKlinesFromAggTrades(symbol, timeframe string, limit int) ([]shared.KlineData, error) {
+	intervalMs, _ := shared.ParseTimeframe(timeframe)
+	now := time.Now().UnixMilli()
+	startTime := shared.TimeframeBucketStart(now, intervalMs) - int64(limit-1)*intervalMs
+
+	builder, err := binancedata.NewTradeBarBuilder(timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []shared.KlineData
+	const maxPages = 200
+	service := app.binanceClient.NewAggTradesService().Symbol(symbol).StartTime(startTime).Limit(1000)
+	for page := 0; page < maxPages; page++ {
+		trades, err := service.Do(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("Binance SDK error: %w", err)
+		}
+		for _, tr := range trades {
+			price := parseFloat(tr.Price)
+			qty := parseFloat(tr.Quantity)
+			closed, ok := builder.AddTrade(shared.TradeData{
+				ID:           tr.AggTradeID,
+				Price:        price,
+				Quantity:     qty,
+				QuoteQty:     price * qty,
+				Time:         tr.Timestamp,
+				IsBuyerMaker: tr.IsBuyerMaker,
+			})
+			if ok {
+				data = append(data, *closed)
+			}
+		}
+		if len(trades) < 1000 {
+			break
+		}
+		service = app.binanceClient.NewAggTradesService().Symbol(symbol).FromID(trades[len(trades)-1].AggTradeID + 1).Limit(1000)
+	}
+
+	if last, ok := builder.Flush(); ok {
+		data = append(data, *last)
+	}
+
+	return data, nil
 }
 
 // detectNewCompletedCandles détecte les nouvelles bougies fermées
@@ -82,13 +82,13 @@ func (app *ScalpingApp) processTemporalTrades() error {
     // helper to process marker (minute boundary): use closed Vision kline at prevMinute
     processMarker := func(prevMinute int64) {
         idxPrev, ok := app.kIndex[prevMinute]
-        if !ok || idxPrev < 299 || idxPrev >= len(app.klines)-1 {
+        if !ok || idxPrev < app.windowSize-1 || idxPrev >= len(app.klines)-1 {
             return
         }
-        // Build window of 300 closed Vision klines up to idxPrev
+        // Build window of windowSize closed Vision klines up to idxPrev
         // plus one synthetic forming candle (to align generator lastClosedIdx on idxPrev)
-        win := make([]signals.Kline, 0, 301)
-        for j := idxPrev - 299; j <= idxPrev; j++ {
+        win := make([]signals.Kline, 0, app.windowSize+1)
+        for j := idxPrev - app.windowSize + 1; j <= idxPrev; j++ {
             k := app.klines[j]
             win = append(win, signals.Kline{
                 OpenTime: time.Unix(0, k.Timestamp*1e6),
@@ -260,6 +260,7 @@ type ScalpingApp struct {
     dates        []string
     scalpCfg     ScalpingConfig
     generator    *momentium.Generator
+    windowSize   int // Closed klines per window (generator MinHistorySize, 300 otherwise)
     klines       []Kline
     signals      []signals.Signal
     currentPos   *Position
@@ -416,6 +417,7 @@ func (app *ScalpingApp) initializeGenerator() error {
         CCIOverbought:    app.scalpCfg.CCIOverbought,
     }
     app.generator = momentium.NewGenerator(cfg)
+    app.windowSize = signals.RequiredHistorySize(app.generator, 300)
 	return app.generator.Initialize(signals.GeneratorConfig{
 		Symbol:    app.config.BinanceData.Symbols[0],
 		Timeframe: app.scalpCfg.Timeframe,
@@ -425,8 +427,8 @@ func (app *ScalpingApp) initializeGenerator() error {
 
 func (app *ScalpingApp) processLoop() error {
 	// Indexer mapping timestamp->index pour accès signal
-	// Fenêtre de 300 bougies maximum
-	windowSize := 300
+	// Fenêtre = historique requis par le générateur (300 par défaut)
+	windowSize := app.windowSize
 	for i := range app.klines {
 		// Construire fenêtre [max(0,i-windowSize+1) .. i]
 		start := i - windowSize + 1
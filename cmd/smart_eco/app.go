@@ -138,15 +138,16 @@ func (app *ScalpingApp) processTemporalTrades() error {
 	var currentMinute int64 = -1 // ms epoch truncated to minute
 
 	// helper to process marker (minute boundary): use closed Vision kline at prevMinute
-	processMarker := func(prevMinute int64) {
+	// Only generator construction errors are returned: they repeat on every marker
+	processMarker := func(prevMinute int64) error {
 		idxPrev, ok := app.kIndex[prevMinute]
-		if !ok || idxPrev < 299 || idxPrev >= len(app.klines)-1 {
-			return
+		if !ok || idxPrev < app.windowSize-1 || idxPrev >= len(app.klines)-1 {
+			return nil
 		}
-		// Build window of 300 closed Vision klines up to idxPrev
+		// Build window of windowSize closed Vision klines up to idxPrev
 		// plus one synthetic forming candle (to align generator lastClosedIdx on idxPrev)
-		win := make([]signals.Kline, 0, 301)
-		for j := idxPrev - 299; j <= idxPrev; j++ {
+		win := make([]signals.Kline, 0, app.windowSize+1)
+		for j := idxPrev - app.windowSize + 1; j <= idxPrev; j++ {
 			k := app.klines[j]
 			win = append(win, signals.Kline{
 				OpenTime: time.Unix(0, k.Timestamp*1e6),
@@ -161,43 +162,26 @@ func (app *ScalpingApp) processTemporalTrades() error {
 			Open:     lastClose, High: lastClose, Low: lastClose, Close: lastClose, Volume: 0,
 		})
 		// Use a fresh generator per marker to avoid lastProcessedIdx mismatch
-		mg := smarteco.NewGenerator(smarteco.Config{
-			ATRPeriod:                 app.scalpCfg.ATRPeriod,
-			BodyPctMin:                app.scalpCfg.BodyPctMin,
-			BodyATRMin:                app.scalpCfg.BodyATRMin,
-			StochKPeriod:              app.scalpCfg.StochKPeriod,
-			StochKSmooth:              app.scalpCfg.StochKSmooth,
-			StochDPeriod:              app.scalpCfg.StochDPeriod,
-			StochKLongMax:             app.scalpCfg.StochKLongMax,
-			StochKShortMin:            app.scalpCfg.StochKShortMin,
-			EnableVwmaCross:           app.scalpCfg.EnableVwmaCross,
-			VwmaFast:                  app.scalpCfg.VwmaFast,
-			VwmaSlow:                  app.scalpCfg.VwmaSlow,
-			EnableStochExtremes:       app.scalpCfg.EnableStochExtremes,
-			EnableStochCross:          app.scalpCfg.EnableStochCross,
-			EnableDMICross:            app.scalpCfg.EnableDMICross,
-			DMIPeriod:                 app.scalpCfg.DMIPeriod,
-			EnableMFIFilter:           app.scalpCfg.EnableMFIFilter,
-			MFIPeriod:                 app.scalpCfg.MFIPeriod,
-			MFIOversold:               app.scalpCfg.MFIOversold,
-			MFIOverbought:             app.scalpCfg.MFIOverbought,
-			EnableCCIFilter:           app.scalpCfg.EnableCCIFilter,
-			CCIPeriod:                 app.scalpCfg.CCIPeriod,
-			CCIOversold:               app.scalpCfg.CCIOversold,
-			CCIOverbought:             app.scalpCfg.CCIOverbought,
-			EnableMacdHistogramFilter: app.scalpCfg.EnableMacdHistogramFilter,
-			EnableMacdSigneFilter:     app.scalpCfg.EnableMacdSigneFilter,
-			MacdFast:                  app.scalpCfg.MacdFast,
-			MacdSlow:                  app.scalpCfg.MacdSlow,
-			MacdSignalPeriod:          app.scalpCfg.MacdSignalPeriod,
-		})
-		_ = mg.Initialize(signals.GeneratorConfig{Symbol: app.config.BinanceData.Symbols[0], Timeframe: app.scalpCfg.Timeframe, HistorySize: 1000})
+		mg, err := app.newGenerator()
+		if err != nil {
+			return fmt.Errorf("marker %s: %w", time.Unix(0, prevMinute*1e6).UTC().Format(time.RFC3339), err)
+		}
+		if app.explain != nil {
+			// Fresh generator re-evaluates the whole window: keep only the marker bar
+			markerTime := time.Unix(0, prevMinute*1e6)
+			signals.EnableExplain(mg, signals.ExplainSinkFunc(func(exp signals.BarExplanation) error {
+				if !exp.Time.Equal(markerTime) {
+					return nil
+				}
+				return app.explain.Record(exp)
+			}))
+		}
 		if err := mg.CalculateIndicators(win); err != nil {
-			return
+			return nil
 		}
 		sigs, err := mg.DetectSignals(win)
 		if err != nil {
-			return
+			return nil
 		}
 		// Log all signals like demo and collect for export
 		for _, s := range sigs {
@@ -247,6 +231,7 @@ func (app *ScalpingApp) processTemporalTrades() error {
 			}
 		}
 		// Signals already collected above
+		return nil
 	}
 
 	for _, date := range app.dates {
@@ -272,7 +257,7 @@ func (app *ScalpingApp) processTemporalTrades() error {
 			if minute != currentMinute {
 				prev := currentMinute
 				currentMinute = minute
-				processMarker(prev)
+				return processMarker(prev)
 			}
 			return nil
 		})
@@ -281,7 +266,9 @@ func (app *ScalpingApp) processTemporalTrades() error {
 		}
 		// Process last minute of the day if any trade was seen
 		if currentMinute != -1 {
-			processMarker(currentMinute)
+			if err := processMarker(currentMinute); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -350,7 +337,14 @@ type ScalpingApp struct {
 	config     *shared.Config
 	dates      []string
 	scalpCfg   ScalpingConfig
-	generator  *smarteco.Generator
+	generator  signals.Generator
+	// Optional `generator: {name, params}` section: any registered generator replaces smart_eco
+	generatorSpec *signals.GeneratorSpec
+	// Factory and params decoded once from generatorSpec (newGenerator runs on every marker)
+	generatorFactory *signals.GeneratorFactory
+	generatorConfig  interface{}
+	// Closed klines per evaluation window (generator MinHistorySize, 300 otherwise)
+	windowSize int
 	klines     []Kline
 	signals    []signals.Signal
 	currentPos *Position
@@ -358,6 +352,9 @@ type ScalpingApp struct {
 	kIndex     map[int64]int // map OpenTime(ms) -> index in klines
 	outDir     string
 	logFile    *os.File
+	// Explain mode: per-bar rule records exported to explain.jsonl
+	explainEnabled bool
+	explain        signals.ExplainSink
 	sumLong    float64
 	sumShort   float64
 }
@@ -461,6 +458,19 @@ func (app *ScalpingApp) Run() error {
 	}
 	app.logFile = lf
 	defer app.logFile.Close()
+	if app.explainEnabled && !signals.SupportsExplain(app.generator) {
+		// Only smart_eco records explanations: say so once instead of writing an empty explain.jsonl
+		fmt.Printf("⚠️ -explain ignoré: le générateur %s ne produit pas d'enregistrements explain (smart_eco uniquement)\n", app.generator.Name())
+		app.explainEnabled = false
+	}
+	if app.explainEnabled {
+		ef, err := os.Create(filepath.Join(app.outDir, "explain.jsonl"))
+		if err != nil {
+			return fmt.Errorf("create explain: %w", err)
+		}
+		defer ef.Close()
+		app.explain = signals.NewJSONLExplainWriter(ef)
+	}
 	// Log bundle/output directory path for user visibility
 	fmt.Printf("\n📁 Dossier bundle: %s\n", app.outDir)
 
@@ -536,7 +546,36 @@ func (app *ScalpingApp) loadKlines() error {
 	return nil
 }
 
+// defaultWindowSize window used when the generator does not declare its history
+const defaultWindowSize = 300
+
 func (app *ScalpingApp) initializeGenerator() error {
+	if app.generatorSpec != nil {
+		factory, config, err := signals.DecodeGeneratorSpec(*app.generatorSpec)
+		if err != nil {
+			return err
+		}
+		app.generatorFactory, app.generatorConfig = factory, config
+	}
+	g, err := app.newGenerator()
+	if err != nil {
+		return err
+	}
+	app.generator = g
+	app.windowSize = signals.RequiredHistorySize(g, defaultWindowSize)
+	return nil
+}
+
+// newGenerator builds the generator from the decoded `generator:` section when present, otherwise smart_eco from scalpCfg
+func (app *ScalpingApp) newGenerator() (signals.Generator, error) {
+	base := signals.GeneratorConfig{
+		Symbol:      app.config.BinanceData.Symbols[0],
+		Timeframe:   app.scalpCfg.Timeframe,
+		HistorySize: 1000,
+	}
+	if app.generatorFactory != nil {
+		return app.generatorFactory.NewGenerator(app.generatorConfig, base)
+	}
 	cfg := smarteco.Config{
 		ATRPeriod:                 app.scalpCfg.ATRPeriod,
 		BodyPctMin:                app.scalpCfg.BodyPctMin,
@@ -567,18 +606,17 @@ func (app *ScalpingApp) initializeGenerator() error {
 		MacdSlow:                  app.scalpCfg.MacdSlow,
 		MacdSignalPeriod:          app.scalpCfg.MacdSignalPeriod,
 	}
-	app.generator = smarteco.NewGenerator(cfg)
-	return app.generator.Initialize(signals.GeneratorConfig{
-		Symbol:      app.config.BinanceData.Symbols[0],
-		Timeframe:   app.scalpCfg.Timeframe,
-		HistorySize: 1000,
-	})
+	g := smarteco.NewGenerator(cfg)
+	if err := g.Initialize(base); err != nil {
+		return nil, err
+	}
+	return g, nil
 }
 
 func (app *ScalpingApp) processLoop() error {
 	// Indexer mapping timestamp->index pour accès signal
-	// Fenêtre de 300 bougies maximum
-	windowSize := 300
+	// Fenêtre = historique requis par le générateur (300 par défaut)
+	windowSize := app.windowSize
 	for i := range app.klines {
 		// Construire fenêtre [max(0,i-windowSize+1) .. i]
 		start := i - windowSize + 1
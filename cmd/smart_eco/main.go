@@ -7,6 +7,8 @@ import (
 	"log"
 
 	"agent-economique/internal/shared"
+	"agent-economique/internal/signals"
+	_ "agent-economique/internal/signals/all"
 )
 
 func main() {
@@ -19,6 +21,7 @@ func main() {
 	startDate := flag.String("start", "", "Date de début (YYYY-MM-DD) - override config")
 	endDate := flag.String("end", "", "Date de fin (YYYY-MM-DD) - override config")
 	symbol := flag.String("symbol", "", "Symbole (ex: SOLUSDT) - override config")
+	explain := flag.Bool("explain", false, "Exporte explain.jsonl (règles évaluées par bougie et par sens, générateur smart_eco uniquement)")
 	flag.Parse()
 
 	// 2) Charger configuration
@@ -32,8 +35,19 @@ func main() {
 	if *endDate != "" { config.DataPeriod.EndDate = *endDate }
 	if *symbol != "" { config.BinanceData.Symbols = []string{*symbol} }
 
+	// Section optionnelle `generator: {name, params}` (tout générateur du registre)
+	spec, err := signals.LoadGeneratorSpec(*configPath)
+	if err != nil {
+		log.Fatalf("❌ Erreur section generator: %v", err)
+	}
+
 	// 3) Créer app
 	app := NewScalpingApp(config, nil)
+	app.explainEnabled = *explain
+	app.generatorSpec = spec
+	if spec != nil {
+		fmt.Printf("   • Générateur: %s (registre)\n", spec.Name)
+	}
 
 	// 4) Générer liste de dates
 	dates, err := generateDateRange(config.DataPeriod.StartDate, config.DataPeriod.EndDate)
@@ -169,6 +169,7 @@ type AnchoredApp struct {
     dates        []string
     cfg          AnchoredConfig
     generator    *anchored.Generator
+    windowSize   int // Closed klines per marker window (generator MinHistorySize, 300 otherwise)
     klines       []Kline
     signals      []signals.Signal
     currentPos   *Position
@@ -293,11 +294,11 @@ func (app *AnchoredApp) processTemporalTrades() error {
 
     processMarker := func(prevMinute int64) {
         idxPrev, ok := app.kIndex[prevMinute]
-        if !ok || idxPrev < 299 || idxPrev >= len(app.klines)-1 {
+        if !ok || idxPrev < app.windowSize-1 || idxPrev >= len(app.klines)-1 {
             return
         }
-        win := make([]signals.Kline, 0, 301)
-        for j := idxPrev - 299; j <= idxPrev; j++ {
+        win := make([]signals.Kline, 0, app.windowSize+1)
+        for j := idxPrev - app.windowSize + 1; j <= idxPrev; j++ {
             k := app.klines[j]
             win = append(win, signals.Kline{
                 OpenTime: time.Unix(0, k.Timestamp*1e6),
@@ -491,6 +492,7 @@ func (app *AnchoredApp) initializeGenerator() error {
         AnchorByCrossOnly: app.cfg.AnchorByCrossOnly,
     }
     app.generator = anchored.NewGenerator(cfg)
+    app.windowSize = signals.RequiredHistorySize(app.generator, 300)
     return app.generator.Initialize(signals.GeneratorConfig{
         Symbol:    app.config.BinanceData.Symbols[0],
         Timeframe: app.cfg.Timeframe,
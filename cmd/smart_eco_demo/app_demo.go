@@ -126,8 +126,8 @@ func (a *SmartEcoDemoApp) Run() error {
 
 	tf := DEFAULT_TIMEFRAME
 	// Boucle sur les bougies: rolling window et détection à chaque i
-	// Fenêtre max 300 (comme backtest)
-	windowSize := 300
+	// Fenêtre = historique requis par le générateur (300 par défaut, comme backtest)
+	windowSize := signals.RequiredHistorySize(smarteco.NewGenerator(cfg), 300)
 	totalSignals := 0
 	for i := range a.klines {
 		start := i - windowSize + 1
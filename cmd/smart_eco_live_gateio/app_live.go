@@ -83,6 +83,11 @@ type SmartEcoLiveGateIOApp struct {
     // Paramètres N
     initN   int
     updateN int
+    // État générateur (snapshot) repris à chaque marqueur et persisté dans statePath
+    statePath string
+    snapshot  []byte
+    // Taille de la fenêtre glissante (historique requis par le générateur, initN si plus grand)
+    maxKlines int
 }
 
 func NewSmartEcoLiveGateIOApp(config *shared.Config, initN, updateN int) *SmartEcoLiveGateIOApp {
@@ -96,9 +101,23 @@ func NewSmartEcoLiveGateIOApp(config *shared.Config, initN, updateN int) *SmartE
 }
 
 func (app *SmartEcoLiveGateIOApp) Run(ctx context.Context) error {
-    // 1) Historique initial N (300) dernières klines
+    // 0) Reprise de l'état générateur après redémarrage
+    if app.statePath != "" {
+        g := app.newGenerator()
+        restored, err := signals.LoadSnapshot(g, app.statePath)
+        if err != nil {
+            fmt.Printf("⚠️  État ignoré (%s): %v\n", app.statePath, err)
+        } else if restored {
+            app.snapshot, _ = g.Snapshot()
+            fmt.Printf("♻️  État générateur restauré: %s\n", app.statePath)
+        }
+    }
+
+    // 1) Historique initial N dernières klines (par défaut l'historique requis par le générateur)
+    app.maxKlines = signals.RequiredHistorySize(app.newGenerator(), 300)
     n := app.initN
-    if n <= 0 { n = 300 }
+    if n <= 0 { n = app.maxKlines }
+    if n > app.maxKlines { app.maxKlines = n }
     if err := app.loadInitialKlines(n); err != nil {
         return err
     }
@@ -234,7 +253,7 @@ func (app *SmartEcoLiveGateIOApp) detectNewCompletedCandles(newKlines []Kline) [
         if !updated && nk.Timestamp > app.lastKnownTimestamp {
             completed = append(completed, nk.Timestamp)
             app.klines = append(app.klines, nk)
-            if len(app.klines) > 300 { app.klines = app.klines[len(app.klines)-300:] }
+            if len(app.klines) > app.maxKlines { app.klines = app.klines[len(app.klines)-app.maxKlines:] }
             app.lastKnownTimestamp = nk.Timestamp
         }
     }
@@ -242,7 +261,7 @@ func (app *SmartEcoLiveGateIOApp) detectNewCompletedCandles(newKlines []Kline) [
 }
 
 func (app *SmartEcoLiveGateIOApp) processMarker(timestamp int64) error {
-    // Construire fenêtre complète (jusqu'à 300 dernières)
+    // Construire fenêtre complète (jusqu'à maxKlines dernières)
     win := make([]signals.Kline, 0, len(app.klines))
     for _, k := range app.klines {
         win = append(win, signals.Kline{
@@ -263,7 +282,37 @@ func (app *SmartEcoLiveGateIOApp) processMarker(timestamp int64) error {
         })
     }
 
-    // Créer un générateur frais par marqueur (évite mismatch d'index)
+    // Générateur frais par marqueur (évite mismatch d'index), repris depuis le snapshot précédent
+    g := app.newGenerator()
+    if app.snapshot != nil {
+        if err := g.Restore(app.snapshot); err != nil { return fmt.Errorf("restore: %w", err) }
+    }
+
+    if err := g.CalculateIndicators(win); err != nil { return err }
+    sigs, err := g.DetectSignals(win)
+    if err != nil { return err }
+
+    // Persister l'état après chaque marqueur (reprise après crash)
+    snapshot, err := g.Snapshot()
+    if err != nil { return err }
+    app.snapshot = snapshot
+    if app.statePath != "" {
+        if err := signals.WriteSnapshotFile(app.statePath, snapshot); err != nil { fmt.Printf("⚠️  %v\n", err) }
+    }
+
+    // Afficher les signaux sur la bougie qui vient de se fermer (et les suivantes déjà fermées :
+    // le générateur repris ne les réémettra pas au marqueur suivant)
+    tsPrev := time.Unix(0, timestamp*1e6)
+    for _, s := range sigs {
+        if !s.Timestamp.Before(tsPrev) {
+            fmt.Printf("[SIG] %s | %s | price=%.6f | conf=%.2f\n", s.Timestamp.Format(time.RFC3339), s.Type, s.Price, s.Confidence)
+        }
+    }
+    return nil
+}
+
+// newGenerator crée le générateur smart_eco depuis la config (défauts alignés à smart_eco app)
+func (app *SmartEcoLiveGateIOApp) newGenerator() *smarteco.Generator {
     cm := app.config.Strategy.ScalpingMomentium
     g := smarteco.NewGenerator(smarteco.Config{
         ATRPeriod:                 ifZeroInt(cm.ATRPeriod, DEFAULT_ATR_PERIOD),
@@ -295,24 +344,10 @@ func (app *SmartEcoLiveGateIOApp) processMarker(timestamp int64) error {
         MacdSlow:                  DEFAULT_MACD_SLOW,
         MacdSignalPeriod:          DEFAULT_MACD_SIGNAL,
     })
-
-    cm = app.config.Strategy.ScalpingMomentium
     tf := cm.Timeframe
     if tf == "" { tf = DEFAULT_TIMEFRAME }
     _ = g.Initialize(signals.GeneratorConfig{Symbol: app.config.BinanceData.Symbols[0], Timeframe: tf, HistorySize: 1000})
-
-    if err := g.CalculateIndicators(win); err != nil { return err }
-    sigs, err := g.DetectSignals(win)
-    if err != nil { return err }
-
-    // Afficher les signaux sur la bougie qui vient de se fermer
-    tsPrev := time.Unix(0, timestamp*1e6)
-    for _, s := range sigs {
-        if s.Timestamp.Equal(tsPrev) {
-            fmt.Printf("[SIG] %s | %s | price=%.6f | conf=%.2f\n", s.Timestamp.Format(time.RFC3339), s.Type, s.Price, s.Confidence)
-        }
-    }
-    return nil
+    return g
 }
 
 // Helpers
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"agent-economique/internal/shared"
@@ -20,8 +21,9 @@ func main() {
 	// 1) CLI flags
 	configPath := flag.String("config", "config/config.yaml", "Chemin vers le fichier de configuration")
 	symbol := flag.String("symbol", "", "Symbole (ex: SOL_USDT ou SOLUSDT)")
-	nInit := flag.Int("ninit", 300, "Nombre de klines initiales à charger")
+	nInit := flag.Int("ninit", 0, "Nombre de klines initiales à charger (0 = historique requis par le générateur)")
 	nUpdate := flag.Int("nupdate", 10, "Nombre de klines à rafraîchir à chaque tick")
+	statePath := flag.String("state", "", "Fichier d'état du générateur (défaut: state/smart_eco_live_gateio_<SYMBOL>.json, \"none\" pour désactiver)")
 	flag.Parse()
 
 	// 2) Load config
@@ -50,6 +52,13 @@ func main() {
 
 	// 3) Create app
 	app := NewSmartEcoLiveGateIOApp(config, *nInit, *nUpdate)
+	switch *statePath {
+	case "":
+		app.statePath = filepath.Join("state", "smart_eco_live_gateio_"+config.BinanceData.Symbols[0]+".json")
+	case "none":
+	default:
+		app.statePath = *statePath
+	}
 
 	// 4) Graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"agent-economique/internal/datasource/gateio"
+	"agent-economique/internal/signals"
+	"agent-economique/internal/signals/declarative"
+)
+
+// Runner unique pour les stratégies déclaratives (config/strategies/*.yaml)
+//
+// Usage:
+//
+//	go run ./cmd/strategy_runner -strategy config/strategies/vwma_cross_dmi.yaml
+//	go run ./cmd/strategy_runner -strategy config/strategies/stoch_anchored.yaml -symbol BTC_USDT -timeframe 15m
+//	go run ./cmd/strategy_runner -strategy config/strategies/vwma_cross_dmi.yaml -regime -trades out/trades.csv
+
+// trade trade reconstitué (ENTRY puis EXIT)
+type trade struct {
+	Type       signals.SignalType
+	EntryTime  time.Time
+	ExitTime   time.Time
+	EntryPrice float64
+	ExitPrice  float64
+	Variation  float64 // % dans le sens de la position
+	Regime     string  // Régime de marché à l'entrée (-regime)
+}
+
+func main() {
+	strategyPath := flag.String("strategy", "config/strategies/vwma_cross_dmi.yaml", "Fichier de stratégie YAML")
+	symbol := flag.String("symbol", "SOL_USDT", "Symbole Gate.io")
+	timeframe := flag.String("timeframe", "5m", "Timeframe")
+	candles := flag.Int("candles", 1000, "Nombre de bougies")
+	lastSignals := flag.Int("last", 20, "Nombre de signaux affichés")
+	withRegime := flag.Bool("regime", false, "Étiqueter les trades avec le régime de marché")
+	tradesPath := flag.String("trades", "", "Export CSV des trades")
+	flag.Parse()
+
+	strategy, err := declarative.LoadFile(*strategyPath)
+	if err != nil {
+		log.Fatalf("❌ Stratégie invalide: %v", err)
+	}
+	var gen signals.Generator = strategy
+	if *withRegime {
+		if gen, err = signals.NewRegimeFilter(strategy, signals.RegimeFilterConfig{TagOnly: true}); err != nil {
+			log.Fatalf("❌ Filtre de régime: %v", err)
+		}
+	}
+
+	minHistory := signals.RequiredHistorySize(gen, 0)
+	if *candles < minHistory {
+		log.Fatalf("❌ %d bougies insuffisantes, la stratégie %s demande au moins %d", *candles, gen.Name(), minHistory)
+	}
+
+	fmt.Printf("=== STRATÉGIE %s ===\n", strings.ToUpper(gen.Name()))
+	if desc := strategy.Spec().Description; desc != "" {
+		fmt.Printf("%s\n", desc)
+	}
+	fmt.Printf("Fichier               : %s\n", *strategyPath)
+	fmt.Printf("Symbole / Timeframe   : %s / %s\n", *symbol, *timeframe)
+	fmt.Printf("Historique minimal    : %d bougies\n", minHistory)
+
+	ctx := context.Background()
+	raw, err := gateio.NewClient().GetKlines(ctx, *symbol, *timeframe, *candles)
+	if err != nil {
+		log.Fatalf("❌ Erreur récupération klines: %v", err)
+	}
+	sort.Slice(raw, func(i, j int) bool { return raw[i].OpenTime.Before(raw[j].OpenTime) })
+	fmt.Printf("Klines récupérées     : %d\n", len(raw))
+
+	klines := make([]signals.Kline, len(raw))
+	for i, k := range raw {
+		klines[i] = signals.Kline{OpenTime: k.OpenTime, Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume}
+	}
+
+	if err := gen.Initialize(signals.GeneratorConfig{Symbol: *symbol, Timeframe: *timeframe, HistorySize: len(klines)}); err != nil {
+		log.Fatalf("❌ Initialisation: %v", err)
+	}
+	if err := gen.CalculateIndicators(klines); err != nil {
+		log.Fatalf("❌ Calcul indicateurs: %v", err)
+	}
+	sigs, err := gen.DetectSignals(klines)
+	if err != nil {
+		log.Fatalf("❌ Détection signaux: %v", err)
+	}
+
+	trades := buildTrades(sigs)
+	displaySignals(sigs, *lastSignals)
+	displayTrades(trades)
+	if *withRegime {
+		displayRegimeStats(trades)
+	}
+	if *tradesPath != "" {
+		if err := exportTrades(*tradesPath, trades); err != nil {
+			log.Fatalf("❌ Export trades: %v", err)
+		}
+		fmt.Printf("\nTrades exportés      : %s\n", *tradesPath)
+	}
+
+	m := gen.GetMetrics()
+	fmt.Printf("\nSignaux: %d (entrées %d, sorties %d, LONG %d, SHORT %d, confiance moyenne %.2f)\n",
+		m.TotalSignals, m.EntrySignals, m.ExitSignals, m.LongSignals, m.ShortSignals, m.AvgConfidence)
+}
+
+// displaySignals affiche les derniers signaux avec leurs métadonnées
+func displaySignals(sigs []signals.Signal, limit int) {
+	fmt.Println("\n" + strings.Repeat("=", 110))
+	fmt.Println("SIGNAUX")
+	fmt.Println(strings.Repeat("=", 110))
+
+	start := len(sigs) - limit
+	if start < 0 {
+		start = 0
+	}
+	for _, sig := range sigs[start:] {
+		fmt.Printf("%s | %-5s | %-5s | %10.4f | %5.1f%% | %-11v | %s\n",
+			sig.Timestamp.Format("2006-01-02 15:04"), sig.Action, sig.Type, sig.Price, sig.Confidence*100,
+			sig.Metadata["rule"], formatMetadata(sig.Metadata))
+	}
+}
+
+// formatMetadata formate les champs déclarés (hors champs techniques)
+func formatMetadata(metadata map[string]interface{}) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		switch key {
+		case "generator", "rule", "anchor_time", "regime", "entry_regime":
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		if value, ok := metadata[key].(float64); ok {
+			parts[i] = fmt.Sprintf("%s=%.4g", key, value)
+		} else {
+			parts[i] = fmt.Sprintf("%s=%v", key, metadata[key])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildTrades associe chaque EXIT à l'ENTRY qu'il clôture
+func buildTrades(sigs []signals.Signal) []trade {
+	var trades []trade
+	for _, sig := range sigs {
+		if sig.Action != signals.SignalActionExit || sig.EntryPrice == nil || sig.EntryTime == nil {
+			continue
+		}
+		variation := (sig.Price - *sig.EntryPrice) / *sig.EntryPrice * 100
+		if sig.Type == signals.SignalTypeShort {
+			variation = -variation
+		}
+		trades = append(trades, trade{
+			Type:       sig.Type,
+			EntryTime:  *sig.EntryTime,
+			ExitTime:   sig.Timestamp,
+			EntryPrice: *sig.EntryPrice,
+			ExitPrice:  sig.Price,
+			Variation:  variation,
+			Regime:     signals.SignalRegime(sig),
+		})
+	}
+	return trades
+}
+
+// displayTrades affiche les trades et leurs statistiques
+func displayTrades(trades []trade) {
+	fmt.Println("\n" + strings.Repeat("=", 110))
+	fmt.Println("TRADES")
+	fmt.Println(strings.Repeat("=", 110))
+
+	total, winners := 0.0, 0
+	for i, t := range trades {
+		fmt.Printf("%-4d | %-5s | %s → %s | %10.4f → %10.4f | %+7.2f%%\n",
+			i+1, t.Type, t.EntryTime.Format("01-02 15:04"), t.ExitTime.Format("01-02 15:04"),
+			t.EntryPrice, t.ExitPrice, t.Variation)
+		total += t.Variation
+		if t.Variation > 0 {
+			winners++
+		}
+	}
+
+	if len(trades) == 0 {
+		fmt.Println("Aucun trade clôturé")
+		return
+	}
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("Trades: %d | Gagnants: %d (%.1f%%) | Variation totale: %+.2f%% | Moyenne: %+.2f%%\n",
+		len(trades), winners, float64(winners)/float64(len(trades))*100, total, total/float64(len(trades)))
+}
+
+// displayRegimeStats regroupe les performances par régime d'entrée
+func displayRegimeStats(trades []trade) {
+	type stats struct {
+		count, winners int
+		total          float64
+	}
+	byRegime := make(map[string]*stats)
+	var regimes []string
+	for _, t := range trades {
+		s, ok := byRegime[t.Regime]
+		if !ok {
+			s = &stats{}
+			byRegime[t.Regime] = s
+			regimes = append(regimes, t.Regime)
+		}
+		s.count++
+		s.total += t.Variation
+		if t.Variation > 0 {
+			s.winners++
+		}
+	}
+	sort.Strings(regimes)
+
+	fmt.Println("\n" + strings.Repeat("=", 110))
+	fmt.Println("PERFORMANCE PAR RÉGIME")
+	fmt.Println(strings.Repeat("=", 110))
+	for _, regime := range regimes {
+		s := byRegime[regime]
+		fmt.Printf("%-16s | Trades: %3d | Gagnants: %5.1f%% | Total: %+7.2f%% | Moyenne: %+6.2f%%\n",
+			regime, s.count, float64(s.winners)/float64(s.count)*100, s.total, s.total/float64(s.count))
+	}
+}
+
+// exportTrades écrit les trades en CSV (une ligne par trade, régime inclus)
+func exportTrades(path string, trades []trade) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"type", "entry_time", "exit_time", "entry_price", "exit_price", "variation_pct", "regime"})
+	for _, t := range trades {
+		w.Write([]string{
+			string(t.Type),
+			t.EntryTime.UTC().Format(time.RFC3339),
+			t.ExitTime.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(t.EntryPrice, 'f', -1, 64),
+			strconv.FormatFloat(t.ExitPrice, 'f', -1, 64),
+			strconv.FormatFloat(t.Variation, 'f', 4, 64),
+			t.Regime,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
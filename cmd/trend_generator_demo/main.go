@@ -45,6 +45,12 @@ const (
 	BODY_ATR_MIN             = 0.60
 	ENFORCE_CANDLE_DIRECTION = true
 
+	// Filtres squeeze (BB dans KC) et cassure Donchian
+	SQUEEZE_FILTER   = false
+	BREAKOUT_FILTER  = false
+	DONCHIAN_PERIODE = 20
+	BREAKOUT_WINDOW  = 3
+
 	// Sorties indépendantes
 	EXIT_VWMA          = true
 	EXIT_TRAILING      = false
@@ -82,6 +88,8 @@ func main() {
 	fmt.Printf("Body%% min           : %.2f\n", BODY_PCT_MIN)
 	fmt.Printf("Body/ATR min        : %.2f\n", BODY_ATR_MIN)
 	fmt.Printf("Bougie cohérente    : %v\n", ENFORCE_CANDLE_DIRECTION)
+	fmt.Printf("Filtre squeeze      : %v (BB 20/2 dans KC 20/1.5)\n", SQUEEZE_FILTER)
+	fmt.Printf("Filtre cassure      : %v (Donchian %d, fenetre %d)\n", BREAKOUT_FILTER, DONCHIAN_PERIODE, BREAKOUT_WINDOW)
 	fmt.Printf("Exit VWMA           : %v\n", EXIT_VWMA)
 	fmt.Printf("Exit Trailing       : %v (ATR coeff=%.2f, cap=%.3f)\n", EXIT_TRAILING, TRAILING_ATR_COEFF, TRAILING_CAP_PCT)
 
@@ -142,6 +150,11 @@ func main() {
 		BodyPctMin:             BODY_PCT_MIN,
 		BodyATRMin:             BODY_ATR_MIN,
 		EnforceCandleDirection: ENFORCE_CANDLE_DIRECTION,
+		// Filtres squeeze / cassure
+		EnableSqueezeFilter:  SQUEEZE_FILTER,
+		EnableBreakoutFilter: BREAKOUT_FILTER,
+		DonchianPeriode:      DONCHIAN_PERIODE,
+		BreakoutWindow:       BREAKOUT_WINDOW,
 		// New exit controls
 		EnableExitVWMA:     EXIT_VWMA,
 		EnableExitTrailing: EXIT_TRAILING,
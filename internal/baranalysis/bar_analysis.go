@@ -87,6 +87,9 @@ type AggregatedBarStats struct {
 	SumSignedNonBodyATRRatio   float64
 	AvgSignedBodyATRRatio      float64
 	AvgSignedNonBodyATRRatio   float64
+
+	// Candlestick patterns completed inside the list (consecutive bars, default thresholds)
+	Patterns PatternStats
 }
 
 func AggregateAnalyses(list []BarAnalysis) AggregatedBarStats {
@@ -157,5 +160,6 @@ func AggregateAnalyses(list []BarAnalysis) AggregatedBarStats {
 		s.AvgSignedBodyATRRatio = s.SumSignedBodyATRRatio / float64(ratioCount)
 		s.AvgSignedNonBodyATRRatio = s.SumSignedNonBodyATRRatio / float64(ratioCount)
 	}
+	s.Patterns = AggregatePatterns(DetectPatterns(list, DefaultPatternConfig()))
 	return s
 }
@@ -0,0 +1,326 @@
+package baranalysis
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// PatternKind candlestick pattern label
+type PatternKind string
+
+const (
+	PatternBullishEngulfing   PatternKind = "ENGULFING_HAUSSIER"
+	PatternBearishEngulfing   PatternKind = "ENGULFING_BAISSIER"
+	PatternHammer             PatternKind = "MARTEAU"        // Bullish pin bar (long lower wick)
+	PatternShootingStar       PatternKind = "ETOILE_FILANTE" // Bearish pin bar (long upper wick)
+	PatternInsideBar          PatternKind = "INSIDE_BAR"
+	PatternOutsideBar         PatternKind = "OUTSIDE_BAR"
+	PatternThreeWhiteSoldiers PatternKind = "TROIS_SOLDATS"
+	PatternThreeBlackCrows    PatternKind = "TROIS_CORBEAUX"
+	PatternDoji               PatternKind = "DOJI"
+	PatternDragonflyDoji      PatternKind = "DOJI_LIBELLULE"
+	PatternGravestoneDoji     PatternKind = "DOJI_PIERRE_TOMBALE"
+	PatternLongLeggedDoji     PatternKind = "DOJI_PATTES_LONGUES"
+	PatternMorningStar        PatternKind = "ETOILE_DU_MATIN"
+	PatternEveningStar        PatternKind = "ETOILE_DU_SOIR"
+)
+
+// PatternKinds every detectable pattern
+var PatternKinds = []PatternKind{
+	PatternBullishEngulfing, PatternBearishEngulfing, PatternHammer, PatternShootingStar,
+	PatternInsideBar, PatternOutsideBar, PatternThreeWhiteSoldiers, PatternThreeBlackCrows,
+	PatternDoji, PatternDragonflyDoji, PatternGravestoneDoji, PatternLongLeggedDoji,
+	PatternMorningStar, PatternEveningStar,
+}
+
+// ParsePatternKind returns the pattern of a label (case-insensitive, ok = false if unknown)
+func ParsePatternKind(label string) (PatternKind, bool) {
+	label = strings.ToUpper(strings.TrimSpace(label))
+	for _, k := range PatternKinds {
+		if string(k) == label {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// IsNeutral reports a pattern that never carries a direction (indecision / compression)
+func (k PatternKind) IsNeutral() bool {
+	return k == PatternInsideBar || k == PatternDoji || k == PatternLongLeggedDoji
+}
+
+// PatternEvent pattern completed on bar Index (bars StartIndex..Index)
+type PatternEvent struct {
+	Kind       PatternKind
+	Index      int
+	StartIndex int
+	Time       time.Time // Time of the completing bar
+	Bias       int       // +1 bullish, -1 bearish, 0 neutral
+	Strength   float64   // 0-1
+}
+
+// PatternConfig ATR-normalised thresholds (zero values = defaults)
+type PatternConfig struct {
+	DojiBodyATR      float64 `yaml:"doji_body_atr"`      // Max body of a doji (default 0.05 ATR)
+	LongWickATR      float64 `yaml:"long_wick_atr"`      // Min long wick of shaped dojis (default 0.5 ATR)
+	MinBodyATR       float64 `yaml:"min_body_atr"`       // Min real body of engulfing/soldiers/stars (default 0.3 ATR)
+	StrongBodyATR    float64 `yaml:"strong_body_atr"`    // Body scored at full strength (default 1 ATR)
+	PinWickRatio     float64 `yaml:"pin_wick_ratio"`     // Pin bar wick >= ratio x body (default 2)
+	PinWickATR       float64 `yaml:"pin_wick_atr"`       // Pin bar wick >= N ATR (default 0.5)
+	PinOppositeRatio float64 `yaml:"pin_opposite_ratio"` // Opposite wick <= ratio x wick (default 0.25)
+	StrongWickATR    float64 `yaml:"strong_wick_atr"`    // Wick scored at full strength (default 1.5 ATR)
+	MinRangeATR      float64 `yaml:"min_range_atr"`      // Min range of inside mother / outside bars (default 0.5 ATR)
+	SoldierWickRatio float64 `yaml:"soldier_wick_ratio"` // Max closing wick of soldiers/crows vs body (default 0.5)
+	StarBodyATR      float64 `yaml:"star_body_atr"`      // Max body of the star bar (default 0.3 ATR)
+}
+
+// DefaultPatternConfig returns the default thresholds
+func DefaultPatternConfig() PatternConfig {
+	return PatternConfig{
+		DojiBodyATR:      0.05,
+		LongWickATR:      0.5,
+		MinBodyATR:       0.3,
+		StrongBodyATR:    1,
+		PinWickRatio:     2,
+		PinWickATR:       0.5,
+		PinOppositeRatio: 0.25,
+		StrongWickATR:    1.5,
+		MinRangeATR:      0.5,
+		SoldierWickRatio: 0.5,
+		StarBodyATR:      0.3,
+	}
+}
+
+func (c PatternConfig) withDefaults() PatternConfig {
+	def := DefaultPatternConfig()
+	fields := []struct{ v, d *float64 }{
+		{&c.DojiBodyATR, &def.DojiBodyATR}, {&c.LongWickATR, &def.LongWickATR},
+		{&c.MinBodyATR, &def.MinBodyATR}, {&c.StrongBodyATR, &def.StrongBodyATR},
+		{&c.PinWickRatio, &def.PinWickRatio}, {&c.PinWickATR, &def.PinWickATR},
+		{&c.PinOppositeRatio, &def.PinOppositeRatio}, {&c.StrongWickATR, &def.StrongWickATR},
+		{&c.MinRangeATR, &def.MinRangeATR}, {&c.SoldierWickRatio, &def.SoldierWickRatio},
+		{&c.StarBodyATR, &def.StarBodyATR},
+	}
+	for _, f := range fields {
+		if *f.v <= 0 || math.IsNaN(*f.v) {
+			*f.v = *f.d
+		}
+	}
+	return c
+}
+
+// DetectPatterns scans consecutive bar analyses and returns the patterns ordered by
+// completing bar. Thresholds are scaled by the ATR of the completing bar, so bars
+// without a valid ATR complete no pattern. Each event only uses bars <= Index.
+func DetectPatterns(bars []BarAnalysis, config PatternConfig) []PatternEvent {
+	cfg := config.withDefaults()
+	var events []PatternEvent
+	for i := range bars {
+		atr := bars[i].ATR
+		if math.IsNaN(atr) || atr <= 0 {
+			continue
+		}
+		add := func(kind PatternKind, start, bias int, strength float64) {
+			events = append(events, PatternEvent{
+				Kind: kind, Index: i, StartIndex: start, Time: bars[i].Time,
+				Bias: bias, Strength: clamp01(strength),
+			})
+		}
+		detectSingle(bars[i], atr, cfg, func(kind PatternKind, bias int, strength float64) { add(kind, i, bias, strength) })
+		if i >= 1 {
+			detectDouble(bars[i-1], bars[i], atr, cfg, func(kind PatternKind, bias int, strength float64) { add(kind, i-1, bias, strength) })
+		}
+		if i >= 2 {
+			detectTriple(bars[i-2], bars[i-1], bars[i], atr, cfg, func(kind PatternKind, bias int, strength float64) { add(kind, i-2, bias, strength) })
+		}
+	}
+	return events
+}
+
+// detectSingle doji families and pin bars (mutually exclusive)
+func detectSingle(b BarAnalysis, atr float64, cfg PatternConfig, add func(PatternKind, int, float64)) {
+	if b.Range <= 0 {
+		return
+	}
+	upper, lower := upperWick(b), lowerWick(b)
+
+	dojiMax := cfg.DojiBodyATR * atr
+	if b.Body <= dojiMax {
+		bodyScore := 1 - b.Body/dojiMax
+		longWick := cfg.LongWickATR * atr
+		switch {
+		case upper <= dojiMax && lower >= longWick:
+			add(PatternDragonflyDoji, 1, (bodyScore+clamp01(lower/atr/cfg.StrongWickATR))/2)
+		case lower <= dojiMax && upper >= longWick:
+			add(PatternGravestoneDoji, -1, (bodyScore+clamp01(upper/atr/cfg.StrongWickATR))/2)
+		case upper >= longWick && lower >= longWick:
+			add(PatternLongLeggedDoji, 0, (bodyScore+clamp01(math.Min(upper, lower)/atr/cfg.StrongWickATR))/2)
+		default:
+			add(PatternDoji, 0, bodyScore)
+		}
+		return
+	}
+
+	isPin := func(wick, opposite float64) bool {
+		return wick >= cfg.PinWickRatio*b.Body && wick >= cfg.PinWickATR*atr && opposite <= cfg.PinOppositeRatio*wick
+	}
+	switch {
+	case isPin(lower, upper):
+		add(PatternHammer, 1, lower/atr/cfg.StrongWickATR)
+	case isPin(upper, lower):
+		add(PatternShootingStar, -1, upper/atr/cfg.StrongWickATR)
+	}
+}
+
+// detectDouble engulfing, inside and outside bars
+func detectDouble(prev, cur BarAnalysis, atr float64, cfg PatternConfig, add func(PatternKind, int, float64)) {
+	if cur.Body >= cfg.MinBodyATR*atr && cur.Body > prev.Body &&
+		bodyTop(cur) >= bodyTop(prev) && bodyBottom(cur) <= bodyBottom(prev) {
+		strength := (clamp01(cur.Body/atr/cfg.StrongBodyATR) + 1 - prev.Body/cur.Body) / 2
+		switch {
+		case cur.Type == "VERT" && prev.Type == "ROUGE":
+			add(PatternBullishEngulfing, 1, strength)
+		case cur.Type == "ROUGE" && prev.Type == "VERT":
+			add(PatternBearishEngulfing, -1, strength)
+		}
+	}
+
+	if prev.Range < cfg.MinRangeATR*atr {
+		return
+	}
+	switch {
+	case cur.High <= prev.High && cur.Low >= prev.Low && cur.Range < prev.Range:
+		add(PatternInsideBar, 0, 1-cur.Range/prev.Range)
+	case cur.High > prev.High && cur.Low < prev.Low:
+		add(PatternOutsideBar, colorBias(cur), cur.Range/prev.Range-1)
+	}
+}
+
+// detectTriple three soldiers/crows and morning/evening stars
+func detectTriple(first, second, third BarAnalysis, atr float64, cfg PatternConfig, add func(PatternKind, int, float64)) {
+	minBody := cfg.MinBodyATR * atr
+	bars := []BarAnalysis{first, second, third}
+
+	soldiers, crows := true, true
+	for j, b := range bars {
+		if b.Body < minBody {
+			soldiers, crows = false, false
+			break
+		}
+		soldiers = soldiers && b.Type == "VERT" && upperWick(b) <= cfg.SoldierWickRatio*b.Body
+		crows = crows && b.Type == "ROUGE" && lowerWick(b) <= cfg.SoldierWickRatio*b.Body
+		if j > 0 {
+			p := bars[j-1]
+			// Each bar opens inside the previous body and closes beyond it
+			soldiers = soldiers && b.Open >= p.Open && b.Open <= p.Close && b.Close > p.Close
+			crows = crows && b.Open <= p.Open && b.Open >= p.Close && b.Close < p.Close
+		}
+	}
+	if soldiers || crows {
+		strength := (first.Body + second.Body + third.Body) / 3 / atr / cfg.StrongBodyATR
+		if soldiers {
+			add(PatternThreeWhiteSoldiers, 1, strength)
+		} else {
+			add(PatternThreeBlackCrows, -1, strength)
+		}
+	}
+
+	// Stars: large first body, small star body beyond the first body midpoint,
+	// third bar closing back past that midpoint
+	if first.Body < minBody || second.Body > cfg.StarBodyATR*atr || third.Body < minBody {
+		return
+	}
+	mid := (first.Open + first.Close) / 2
+	starMid := (second.Open + second.Close) / 2
+	half := first.Body / 2
+	switch {
+	case first.Type == "ROUGE" && third.Type == "VERT" && starMid < mid && third.Close > mid:
+		add(PatternMorningStar, 1, (clamp01((third.Close-mid)/half)+clamp01(third.Body/atr/cfg.StrongBodyATR))/2)
+	case first.Type == "VERT" && third.Type == "ROUGE" && starMid > mid && third.Close < mid:
+		add(PatternEveningStar, -1, (clamp01((mid-third.Close)/half)+clamp01(third.Body/atr/cfg.StrongBodyATR))/2)
+	}
+}
+
+// LatestPattern returns the most recent event completed within [index-maxAge, index]
+// whose kind is in kinds (any kind if empty) and that satisfies accept (nil = any)
+func LatestPattern(events []PatternEvent, index, maxAge int, kinds map[PatternKind]bool, accept func(PatternEvent) bool) (PatternEvent, bool) {
+	for j := len(events) - 1; j >= 0; j-- {
+		ev := events[j]
+		if ev.Index > index {
+			continue
+		}
+		if ev.Index < index-maxAge {
+			break
+		}
+		if len(kinds) > 0 && !kinds[ev.Kind] {
+			continue
+		}
+		if accept == nil || accept(ev) {
+			return ev, true
+		}
+	}
+	return PatternEvent{}, false
+}
+
+// PatternStats pattern statistics of a bar list
+type PatternStats struct {
+	Count             int
+	CountBullish      int
+	CountBearish      int
+	AvgStrength       float64
+	ByKind            map[PatternKind]int
+	AvgStrengthByKind map[PatternKind]float64
+}
+
+// AggregatePatterns computes pattern counts and average strengths
+func AggregatePatterns(events []PatternEvent) PatternStats {
+	s := PatternStats{
+		ByKind:            make(map[PatternKind]int),
+		AvgStrengthByKind: make(map[PatternKind]float64),
+	}
+	for _, ev := range events {
+		s.Count++
+		switch {
+		case ev.Bias > 0:
+			s.CountBullish++
+		case ev.Bias < 0:
+			s.CountBearish++
+		}
+		s.AvgStrength += ev.Strength
+		s.ByKind[ev.Kind]++
+		s.AvgStrengthByKind[ev.Kind] += ev.Strength
+	}
+	if s.Count > 0 {
+		s.AvgStrength /= float64(s.Count)
+	}
+	for kind, n := range s.ByKind {
+		s.AvgStrengthByKind[kind] /= float64(n)
+	}
+	return s
+}
+
+func upperWick(b BarAnalysis) float64  { return b.High - bodyTop(b) }
+func lowerWick(b BarAnalysis) float64  { return bodyBottom(b) - b.Low }
+func bodyTop(b BarAnalysis) float64    { return math.Max(b.Open, b.Close) }
+func bodyBottom(b BarAnalysis) float64 { return math.Min(b.Open, b.Close) }
+
+func colorBias(b BarAnalysis) int {
+	switch b.Type {
+	case "VERT":
+		return 1
+	case "ROUGE":
+		return -1
+	}
+	return 0
+}
+
+func clamp01(v float64) float64 {
+	if math.IsNaN(v) || v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
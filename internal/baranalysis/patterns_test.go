@@ -0,0 +1,95 @@
+package baranalysis
+
+import (
+	"testing"
+	"time"
+)
+
+// bars builds analyses with a constant ATR from (open, high, low, close) rows
+func bars(atr float64, rows ...[4]float64) []BarAnalysis {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]BarAnalysis, len(rows))
+	for i, r := range rows {
+		out[i] = AnalyzeBar(r[0], r[1], r[2], r[3], atr, start.Add(time.Duration(i)*time.Hour))
+	}
+	return out
+}
+
+// kindsAt returns the patterns completed on bar index
+func kindsAt(events []PatternEvent, index int) map[PatternKind]PatternEvent {
+	out := make(map[PatternKind]PatternEvent)
+	for _, ev := range events {
+		if ev.Index == index {
+			out[ev.Kind] = ev
+		}
+	}
+	return out
+}
+
+func TestDetectPatterns_Families(t *testing.T) {
+	cases := []struct {
+		name  string
+		rows  [][4]float64
+		index int
+		kind  PatternKind
+		bias  int
+	}{
+		{"bullish engulfing", [][4]float64{{105, 106, 101, 102}, {101, 108, 100, 107}}, 1, PatternBullishEngulfing, 1},
+		{"bearish engulfing", [][4]float64{{100, 104, 99, 103}, {104, 105, 97, 98}}, 1, PatternBearishEngulfing, -1},
+		{"hammer", [][4]float64{{100, 101, 92, 100.8}}, 0, PatternHammer, 1},
+		{"shooting star", [][4]float64{{100, 108, 99.9, 99.2}}, 0, PatternShootingStar, -1},
+		{"inside bar", [][4]float64{{100, 110, 95, 108}, {107, 109, 100, 103}}, 1, PatternInsideBar, 0},
+		{"outside bar", [][4]float64{{100, 103, 99, 102}, {102, 105, 97, 98}}, 1, PatternOutsideBar, -1},
+		{"three soldiers", [][4]float64{{100, 104.5, 99.5, 104}, {103, 108.5, 102.5, 108}, {107, 112.5, 106.5, 112}}, 2, PatternThreeWhiteSoldiers, 1},
+		{"three crows", [][4]float64{{112, 112.5, 107.5, 108}, {109, 109.5, 103.5, 104}, {105, 105.5, 99.5, 100}}, 2, PatternThreeBlackCrows, -1},
+		{"doji", [][4]float64{{100, 100.5, 99.5, 100.1}}, 0, PatternDoji, 0},
+		{"dragonfly doji", [][4]float64{{100, 100.1, 95, 100.05}}, 0, PatternDragonflyDoji, 1},
+		{"gravestone doji", [][4]float64{{100, 105, 99.95, 100.05}}, 0, PatternGravestoneDoji, -1},
+		{"long-legged doji", [][4]float64{{100, 104, 96, 100.1}}, 0, PatternLongLeggedDoji, 0},
+		{"morning star", [][4]float64{{110, 110.5, 101.5, 102}, {101.5, 102, 99.5, 101}, {101, 108.5, 100.5, 108}}, 2, PatternMorningStar, 1},
+		{"evening star", [][4]float64{{100, 108.5, 99.5, 108}, {108.5, 110.5, 108, 109}, {109, 109.5, 101.5, 102}}, 2, PatternEveningStar, -1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			events := DetectPatterns(bars(4, tc.rows...), PatternConfig{})
+			ev, ok := kindsAt(events, tc.index)[tc.kind]
+			if !ok {
+				t.Fatalf("expected %s on bar %d, got %+v", tc.kind, tc.index, events)
+			}
+			if ev.Bias != tc.bias || ev.Strength <= 0 || ev.Strength > 1 {
+				t.Errorf("unexpected event %+v", ev)
+			}
+			if ev.StartIndex != tc.index+1-len(tc.rows) && ev.StartIndex != tc.index {
+				t.Errorf("unexpected start index %d", ev.StartIndex)
+			}
+		})
+	}
+}
+
+// TestDetectPatterns_ATRNormalised the same shape is ignored when small relative to ATR
+func TestDetectPatterns_ATRNormalised(t *testing.T) {
+	rows := [][4]float64{{105, 106, 101, 102}, {101, 108, 100, 107}}
+	if _, ok := kindsAt(DetectPatterns(bars(4, rows...), PatternConfig{}), 1)[PatternBullishEngulfing]; !ok {
+		t.Fatalf("expected engulfing with ATR 4")
+	}
+	if _, ok := kindsAt(DetectPatterns(bars(40, rows...), PatternConfig{}), 1)[PatternBullishEngulfing]; ok {
+		t.Errorf("engulfing body of 0.15 ATR must be ignored")
+	}
+	if events := DetectPatterns(bars(0, rows...), PatternConfig{}); len(events) != 0 {
+		t.Errorf("bars without ATR must not complete patterns, got %+v", events)
+	}
+}
+
+func TestAggregateAnalyses_PatternStats(t *testing.T) {
+	list := bars(4, [4]float64{105, 106, 101, 102}, [4]float64{101, 108, 100, 107}, [4]float64{100, 100.5, 99.5, 100.1})
+	stats := AggregateAnalyses(list).Patterns
+	if stats.ByKind[PatternBullishEngulfing] != 1 || stats.ByKind[PatternDoji] != 1 {
+		t.Fatalf("unexpected pattern counts %v", stats.ByKind)
+	}
+	if stats.CountBullish < 1 || stats.Count != stats.CountBullish+stats.CountBearish+stats.ByKind[PatternDoji]+stats.ByKind[PatternInsideBar] {
+		t.Errorf("unexpected bias counts %+v", stats)
+	}
+	if ev, ok := LatestPattern(DetectPatterns(list, PatternConfig{}), 2, 1, map[PatternKind]bool{PatternBullishEngulfing: true, PatternHammer: true}, func(ev PatternEvent) bool { return ev.Bias > 0 }); !ok || ev.Kind != PatternBullishEngulfing {
+		t.Errorf("LatestPattern: got %+v, %v", ev, ok)
+	}
+}
@@ -63,7 +63,7 @@ func (ta *TimeframeAggregator) AggregateKlinesToTimeframe(sourceKlines []shared.
 			// Start new aggregation
 			currentAgg = &shared.KlineData{
 				OpenTime:                 bucketStart,
-				CloseTime:                bucketStart + intervalMs - 1,
+				CloseTime:                shared.TimeframeBucketEnd(bucketStart, intervalMs),
 				Open:                     kline.Open,
 				High:                     kline.High,
 				Low:                      kline.Low,
@@ -101,6 +101,9 @@ func (ta *TimeframeAggregator) AggregateKlinesToTimeframe(sourceKlines []shared.
 }
 
 // AggregateTradestoKlines converts trades data into klines for a specific timeframe
+//
+// Taker buy volumes count trades where the buyer is not the maker, like Binance klines.
+// This used to count IsBuyerMaker trades (aggressive sells): see CHANGELOG.md.
 func (ta *TimeframeAggregator) AggregateTradestoKlines(trades []shared.TradeData, timeframe string) ([]shared.KlineData, error) {
 	if len(trades) == 0 {
 		return nil, fmt.Errorf("trades cannot be empty")
@@ -134,7 +137,7 @@ func (ta *TimeframeAggregator) AggregateTradestoKlines(trades []shared.TradeData
 			// Start new kline from this trade
 			currentKline = &shared.KlineData{
 				OpenTime:                 bucketStart,
-				CloseTime:                bucketStart + intervalMs - 1,
+				CloseTime:                shared.TimeframeBucketEnd(bucketStart, intervalMs),
 				Open:                     trade.Price,
 				High:                     trade.Price,
 				Low:                      trade.Price,
@@ -147,8 +150,8 @@ func (ta *TimeframeAggregator) AggregateTradestoKlines(trades []shared.TradeData
 				Ignore:                   "0",
 			}
 
-			// Add to taker buy volumes if this is a buyer maker trade
-			if trade.IsBuyerMaker {
+			// Taker buy volume: the buyer is the aggressor when it is not the maker
+			if !trade.IsBuyerMaker {
 				currentKline.TakerBuyBaseAssetVolume = trade.Quantity
 				currentKline.TakerBuyQuoteAssetVolume = trade.QuoteQty
 			}
@@ -165,8 +168,8 @@ func (ta *TimeframeAggregator) AggregateTradestoKlines(trades []shared.TradeData
 			currentKline.QuoteAssetVolume += trade.QuoteQty
 			currentKline.NumberOfTrades++
 
-			// Add to taker buy volumes if this is a buyer maker trade
-			if trade.IsBuyerMaker {
+			// Taker buy volume: the buyer is the aggressor when it is not the maker
+			if !trade.IsBuyerMaker {
 				currentKline.TakerBuyBaseAssetVolume += trade.Quantity
 				currentKline.TakerBuyQuoteAssetVolume += trade.QuoteQty
 			}
@@ -181,6 +184,93 @@ func (ta *TimeframeAggregator) AggregateTradestoKlines(trades []shared.TradeData
 	return klines, nil
 }
 
+// TradeBarBuilder incrementally builds klines of any timeframe (including sub-minute) from a trade stream
+type TradeBarBuilder struct {
+	timeframe  string
+	intervalMs int64
+	current    *shared.KlineData
+}
+
+// NewTradeBarBuilder creates a TradeBarBuilder for the given timeframe
+func NewTradeBarBuilder(timeframe string) (*TradeBarBuilder, error) {
+	intervalMs, err := shared.ParseTimeframe(timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeframe: %w", err)
+	}
+
+	return &TradeBarBuilder{
+		timeframe:  timeframe,
+		intervalMs: intervalMs,
+	}, nil
+}
+
+// AddTrade adds a trade and returns the previous bar when this trade opens a new bucket
+func (b *TradeBarBuilder) AddTrade(trade shared.TradeData) (*shared.KlineData, bool) {
+	bucketStart := shared.TimeframeBucketStart(trade.Time, b.intervalMs)
+
+	if b.current != nil && bucketStart < b.current.OpenTime {
+		// Out-of-order trade: fold it into the current bar rather than reopening a closed one
+		bucketStart = b.current.OpenTime
+	}
+
+	var closed *shared.KlineData
+	if b.current == nil || b.current.OpenTime != bucketStart {
+		closed = b.current
+		b.current = &shared.KlineData{
+			OpenTime:  bucketStart,
+			CloseTime: shared.TimeframeBucketEnd(bucketStart, b.intervalMs),
+			Open:      trade.Price,
+			High:      trade.Price,
+			Low:       trade.Price,
+			Ignore:    "0",
+		}
+	}
+
+	bar := b.current
+	if trade.Price > bar.High {
+		bar.High = trade.Price
+	}
+	if trade.Price < bar.Low {
+		bar.Low = trade.Price
+	}
+	bar.Close = trade.Price
+	bar.Volume += trade.Quantity
+	bar.QuoteAssetVolume += trade.QuoteQty
+	bar.NumberOfTrades++
+	// Taker buy volume: the buyer is the aggressor when it is not the maker
+	if !trade.IsBuyerMaker {
+		bar.TakerBuyBaseAssetVolume += trade.Quantity
+		bar.TakerBuyQuoteAssetVolume += trade.QuoteQty
+	}
+
+	return closed, closed != nil
+}
+
+// Current returns a copy of the forming bar, if any
+func (b *TradeBarBuilder) Current() (shared.KlineData, bool) {
+	if b.current == nil {
+		return shared.KlineData{}, false
+	}
+	return *b.current, true
+}
+
+// CloseUntil returns the forming bar if its bucket has ended at timestamp (timer-driven close in live mode)
+func (b *TradeBarBuilder) CloseUntil(timestamp int64) (*shared.KlineData, bool) {
+	if b.current == nil || timestamp <= b.current.CloseTime {
+		return nil, false
+	}
+	closed := b.current
+	b.current = nil
+	return closed, true
+}
+
+// Flush returns the forming bar and resets the builder
+func (b *TradeBarBuilder) Flush() (*shared.KlineData, bool) {
+	closed := b.current
+	b.current = nil
+	return closed, closed != nil
+}
+
 // ValidateTimeframeContinuity checks if klines have proper time continuity
 func (ta *TimeframeAggregator) ValidateTimeframeContinuity(klines []shared.KlineData, timeframe string) error {
 	if len(klines) < 2 {
@@ -198,7 +288,7 @@ func (ta *TimeframeAggregator) ValidateTimeframeContinuity(klines []shared.Kline
 	})
 
 	for i := 1; i < len(klines); i++ {
-		expectedOpenTime := klines[i-1].OpenTime + intervalMs
+		expectedOpenTime := shared.TimeframeBucketEnd(klines[i-1].OpenTime, intervalMs) + 1
 		actualOpenTime := klines[i].OpenTime
 
 		// Allow some tolerance for small gaps, but detect major issues
@@ -280,44 +370,19 @@ func (ta *TimeframeAggregator) GetAggregationStatistics(originalCount, aggregate
 	intervalMs, _ := ta.getTimeframeInterval(timeframe)
 	stats["interval_ms"] = intervalMs
 	stats["interval_minutes"] = intervalMs / (60 * 1000)
+	stats["interval_seconds"] = intervalMs / 1000
 	
 	return stats
 }
 
 // Helper functions
 
-// getTimeframeInterval converts timeframe string to milliseconds
+// getTimeframeInterval converts timeframe string to milliseconds (custom timeframes like 15s, 7m, 3h included)
 func (ta *TimeframeAggregator) getTimeframeInterval(timeframe string) (int64, error) {
-	switch timeframe {
-	case "1m":
-		return 60 * 1000, nil
-	case "5m":
-		return 5 * 60 * 1000, nil
-	case "15m":
-		return 15 * 60 * 1000, nil
-	case "30m":
-		return 30 * 60 * 1000, nil
-	case "1h":
-		return 60 * 60 * 1000, nil
-	case "2h":
-		return 2 * 60 * 60 * 1000, nil
-	case "4h":
-		return 4 * 60 * 60 * 1000, nil
-	case "6h":
-		return 6 * 60 * 60 * 1000, nil
-	case "8h":
-		return 8 * 60 * 60 * 1000, nil
-	case "12h":
-		return 12 * 60 * 60 * 1000, nil
-	case "1d":
-		return 24 * 60 * 60 * 1000, nil
-	default:
-		return 0, fmt.Errorf("unsupported timeframe: %s", timeframe)
-	}
+	return shared.ParseTimeframe(timeframe)
 }
 
 // getBucketStartTime calculates the start time of the bucket for a given timestamp
 func (ta *TimeframeAggregator) getBucketStartTime(timestamp, intervalMs int64) int64 {
-	// Round down to the nearest interval boundary
-	return (timestamp / intervalMs) * intervalMs
+	return shared.TimeframeBucketStart(timestamp, intervalMs)
 }
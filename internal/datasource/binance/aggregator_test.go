@@ -278,6 +278,24 @@ func TestTradeAggregation_Logic(t *testing.T) {
 		len(trades), kline.Open, kline.High, kline.Low, kline.Close, kline.Volume)
 }
 
+// Test taker buy volume - the aggressor buys when the buyer is not the maker
+func TestTradeAggregation_TakerBuySide(t *testing.T) {
+	aggregator, _ := NewTimeframeAggregator(shared.AggregationConfig{})
+	base := int64(1700000040000)
+	trades := []shared.TradeData{
+		{ID: 1, Price: 10, Quantity: 3, QuoteQty: 30, Time: base + 1000, IsBuyerMaker: false}, // Market buy
+		{ID: 2, Price: 10, Quantity: 1, QuoteQty: 10, Time: base + 2000, IsBuyerMaker: true},  // Market sell
+	}
+
+	batch, err := aggregator.AggregateTradestoKlines(trades, "1m")
+	if err != nil || len(batch) != 1 {
+		t.Fatalf("AggregateTradestoKlines(1m) failed: %v (%d bars)", err, len(batch))
+	}
+	if batch[0].TakerBuyBaseAssetVolume != 3 || batch[0].TakerBuyQuoteAssetVolume != 30 {
+		t.Errorf("Expected taker buy 3 / 30, got %v / %v", batch[0].TakerBuyBaseAssetVolume, batch[0].TakerBuyQuoteAssetVolume)
+	}
+}
+
 // Test CompressionRatio - Calcul statistique
 func TestCompressionRatio_Calculation(t *testing.T) {
 	sourceCount := 300    // 300 klines 5m
@@ -347,3 +365,86 @@ func BenchmarkKlineAggregation(b *testing.B) {
 		_ = len(klines)
 	}
 }
+
+// Test custom timeframes (2m from 1m klines, 15s from trades)
+func TestAggregator_CustomTimeframes(t *testing.T) {
+	aggregator, _ := NewTimeframeAggregator(shared.AggregationConfig{})
+
+	// 4 x 1m klines -> 2 x 2m klines
+	base := int64(1700000040000) // Aligned on a 2m boundary
+	var oneMinute []shared.KlineData
+	for i := 0; i < 4; i++ {
+		openTime := base + int64(i)*60000
+		oneMinute = append(oneMinute, shared.KlineData{
+			OpenTime: openTime, CloseTime: openTime + 59999,
+			Open: 100 + float64(i), High: 101 + float64(i), Low: 99 + float64(i), Close: 100.5 + float64(i), Volume: 10,
+		})
+	}
+
+	twoMinute, err := aggregator.AggregateKlinesToTimeframe(oneMinute, "2m")
+	if err != nil {
+		t.Fatalf("AggregateKlinesToTimeframe(2m) failed: %v", err)
+	}
+	if len(twoMinute) != 2 {
+		t.Fatalf("Expected 2 klines, got %d", len(twoMinute))
+	}
+	if twoMinute[0].OpenTime != base || twoMinute[0].CloseTime != base+119999 {
+		t.Errorf("Unexpected 2m bucket bounds: %d-%d", twoMinute[0].OpenTime, twoMinute[0].CloseTime)
+	}
+	if twoMinute[0].Volume != 20 || twoMinute[0].Close != 101.5 || twoMinute[0].High != 102 {
+		t.Errorf("Unexpected 2m OHLCV: %+v", twoMinute[0])
+	}
+	if err := aggregator.ValidateTimeframeContinuity(twoMinute, "2m"); err != nil {
+		t.Errorf("Expected continuous 2m klines: %v", err)
+	}
+
+	// Trades -> 15s bars with batch and incremental builder giving identical results
+	trades := []shared.TradeData{
+		{ID: 1, Price: 10, Quantity: 1, QuoteQty: 10, Time: base + 1000},
+		{ID: 2, Price: 12, Quantity: 1, QuoteQty: 12, Time: base + 14999},
+		{ID: 3, Price: 11, Quantity: 2, QuoteQty: 22, Time: base + 15000, IsBuyerMaker: true},
+		{ID: 4, Price: 9, Quantity: 1, QuoteQty: 9, Time: base + 46000},
+	}
+
+	batch, err := aggregator.AggregateTradestoKlines(trades, "15s")
+	if err != nil {
+		t.Fatalf("AggregateTradestoKlines(15s) failed: %v", err)
+	}
+	if len(batch) != 3 {
+		t.Fatalf("Expected 3 bars (empty bucket skipped), got %d", len(batch))
+	}
+
+	builder, err := NewTradeBarBuilder("15s")
+	if err != nil {
+		t.Fatalf("NewTradeBarBuilder failed: %v", err)
+	}
+	var streamed []shared.KlineData
+	for _, tr := range trades {
+		if closed, ok := builder.AddTrade(tr); ok {
+			streamed = append(streamed, *closed)
+		}
+	}
+	if _, ok := builder.CloseUntil(base + 50000); ok {
+		t.Error("Forming bar should not close before its end")
+	}
+	if last, ok := builder.CloseUntil(base + 60000); ok {
+		streamed = append(streamed, *last)
+	}
+
+	if len(streamed) != len(batch) {
+		t.Fatalf("Expected %d streamed bars, got %d", len(batch), len(streamed))
+	}
+	// Taker buys come from trades where the buyer is not the maker
+	if streamed[0].TakerBuyBaseAssetVolume != 2 || streamed[1].TakerBuyBaseAssetVolume != 0 {
+		t.Errorf("Expected streamed taker buy 2 / 0, got %v / %v", streamed[0].TakerBuyBaseAssetVolume, streamed[1].TakerBuyBaseAssetVolume)
+	}
+	for i := range batch {
+		if streamed[i] != batch[i] {
+			t.Errorf("Bar %d mismatch: streamed %+v, batch %+v", i, streamed[i], batch[i])
+		}
+	}
+
+	if _, err := NewTradeBarBuilder("bogus"); err == nil {
+		t.Error("Expected error for invalid timeframe")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"time"
 
+	"agent-economique/internal/shared"
+
 	"github.com/adshao/go-binance/v2/futures"
 )
 
@@ -102,3 +104,292 @@ func (c *FuturesClient) ConvertToStandardKline(fklines []FuturesKline) []Kline {
 	}
 	return result
 }
+
+// FuturesFundingRate represents one settled funding rate
+type FuturesFundingRate struct {
+	Symbol      string
+	FundingRate float64
+	FundingTime time.Time
+	MarkPrice   float64
+}
+
+// FuturesPremiumIndex represents the current mark price and predicted funding
+type FuturesPremiumIndex struct {
+	Symbol           string
+	MarkPrice        float64
+	IndexPrice       float64
+	PredictedFunding float64 // lastFundingRate: estimate applied at NextFundingTime
+	NextFundingTime  time.Time
+	Time             time.Time
+}
+
+// FuturesOpenInterest represents one open interest statistic
+type FuturesOpenInterest struct {
+	Symbol            string
+	OpenInterest      float64 // En base asset
+	OpenInterestValue float64 // En USDT
+	Timestamp         time.Time
+}
+
+// FuturesLongShortRatio represents one global long/short account ratio statistic
+type FuturesLongShortRatio struct {
+	Symbol         string
+	LongShortRatio float64
+	LongAccount    float64
+	ShortAccount   float64
+	Timestamp      time.Time
+}
+
+// GetFundingRateHistory retrieves settled funding rates (max 1000 per call)
+// startTime/endTime are optional (nil = most recent)
+func (c *FuturesClient) GetFundingRateHistory(ctx context.Context, symbol string, startTime, endTime *time.Time, limit int) ([]FuturesFundingRate, error) {
+	service := c.client.NewFundingRateService().Symbol(symbol)
+	if startTime != nil {
+		service = service.StartTime(startTime.UnixMilli())
+	}
+	if endTime != nil {
+		service = service.EndTime(endTime.UnixMilli())
+	}
+	if limit > 0 {
+		service = service.Limit(limit)
+	}
+
+	rates, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding rate history: %v", err)
+	}
+
+	result := make([]FuturesFundingRate, 0, len(rates))
+	for _, rate := range rates {
+		fundingRate, _ := strconv.ParseFloat(rate.FundingRate, 64)
+		markPrice, _ := strconv.ParseFloat(rate.MarkPrice, 64)
+
+		result = append(result, FuturesFundingRate{
+			Symbol:      rate.Symbol,
+			FundingRate: fundingRate,
+			FundingTime: time.UnixMilli(rate.FundingTime),
+			MarkPrice:   markPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// GetPremiumIndex retrieves the current mark price and predicted funding rate
+func (c *FuturesClient) GetPremiumIndex(ctx context.Context, symbol string) (*FuturesPremiumIndex, error) {
+	indexes, err := c.client.NewPremiumIndexService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get premium index: %v", err)
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no premium index returned for %s", symbol)
+	}
+
+	index := indexes[0]
+	markPrice, _ := strconv.ParseFloat(index.MarkPrice, 64)
+	indexPrice, _ := strconv.ParseFloat(index.IndexPrice, 64)
+	predicted, _ := strconv.ParseFloat(index.LastFundingRate, 64)
+
+	return &FuturesPremiumIndex{
+		Symbol:           index.Symbol,
+		MarkPrice:        markPrice,
+		IndexPrice:       indexPrice,
+		PredictedFunding: predicted,
+		NextFundingTime:  time.UnixMilli(index.NextFundingTime),
+		Time:             time.UnixMilli(index.Time),
+	}, nil
+}
+
+// GetOpenInterestHistory retrieves open interest statistics
+// period: "5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d" (Binance keeps 30 days)
+func (c *FuturesClient) GetOpenInterestHistory(ctx context.Context, symbol, period string, startTime, endTime *time.Time, limit int) ([]FuturesOpenInterest, error) {
+	service := c.client.NewOpenInterestStatisticsService().Symbol(symbol).Period(period)
+	if startTime != nil {
+		service = service.StartTime(startTime.UnixMilli())
+	}
+	if endTime != nil {
+		service = service.EndTime(endTime.UnixMilli())
+	}
+	if limit > 0 {
+		service = service.Limit(limit)
+	}
+
+	stats, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open interest history: %v", err)
+	}
+
+	result := make([]FuturesOpenInterest, 0, len(stats))
+	for _, stat := range stats {
+		openInterest, _ := strconv.ParseFloat(stat.SumOpenInterest, 64)
+		openInterestValue, _ := strconv.ParseFloat(stat.SumOpenInterestValue, 64)
+
+		result = append(result, FuturesOpenInterest{
+			Symbol:            stat.Symbol,
+			OpenInterest:      openInterest,
+			OpenInterestValue: openInterestValue,
+			Timestamp:         time.UnixMilli(stat.Timestamp),
+		})
+	}
+
+	return result, nil
+}
+
+// GetLongShortAccountRatio retrieves the global long/short account ratio
+// period: same values as GetOpenInterestHistory
+func (c *FuturesClient) GetLongShortAccountRatio(ctx context.Context, symbol, period string, startTime, endTime *time.Time, limit int) ([]FuturesLongShortRatio, error) {
+	service := c.client.NewLongShortRatioService().Symbol(symbol).Period(period)
+	if startTime != nil {
+		service = service.StartTime(startTime.UnixMilli())
+	}
+	if endTime != nil {
+		service = service.EndTime(endTime.UnixMilli())
+	}
+	if limit > 0 {
+		service = service.Limit(limit)
+	}
+
+	ratios, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get long/short ratio: %v", err)
+	}
+
+	result := make([]FuturesLongShortRatio, 0, len(ratios))
+	for _, r := range ratios {
+		ratio, _ := strconv.ParseFloat(r.LongShortRatio, 64)
+		longAccount, _ := strconv.ParseFloat(r.LongAccount, 64)
+		shortAccount, _ := strconv.ParseFloat(r.ShortAccount, 64)
+
+		result = append(result, FuturesLongShortRatio{
+			Symbol:         r.Symbol,
+			LongShortRatio: ratio,
+			LongAccount:    longAccount,
+			ShortAccount:   shortAccount,
+			Timestamp:      time.UnixMilli(r.Timestamp),
+		})
+	}
+
+	return result, nil
+}
+
+// GetDerivativesSeries fetches funding, predicted funding, open interest and long/short
+// ratio for a window and returns them as a shared.DerivativesSeries.
+// Points keep their exchange timestamp (snapshot or settlement time).
+func (c *FuturesClient) GetDerivativesSeries(ctx context.Context, symbol, period string, startTime, endTime time.Time) (*shared.DerivativesSeries, error) {
+	periodMs, err := shared.ParseTimeframe(period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period: %w", err)
+	}
+
+	series := &shared.DerivativesSeries{Symbol: symbol}
+
+	// Funding: settlement interval is not fixed (1h/4h/8h), pages follow the last point
+	series.FundingRates, err = fetchDerivativesPages(startTime, endTime, fundingPageLimit, 0,
+		func(from, to time.Time) ([]shared.DerivativesPoint, error) {
+			rates, err := c.GetFundingRateHistory(ctx, symbol, &from, &to, fundingPageLimit)
+			if err != nil {
+				return nil, err
+			}
+			points := make([]shared.DerivativesPoint, 0, len(rates))
+			for _, r := range rates {
+				points = append(points, shared.DerivativesPoint{Time: r.FundingTime.UnixMilli(), Value: r.FundingRate})
+			}
+			return points, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	series.OpenInterest, err = fetchDerivativesPages(startTime, endTime, statisticsPageLimit, periodMs,
+		func(from, to time.Time) ([]shared.DerivativesPoint, error) {
+			oi, err := c.GetOpenInterestHistory(ctx, symbol, period, &from, &to, statisticsPageLimit)
+			if err != nil {
+				return nil, err
+			}
+			points := make([]shared.DerivativesPoint, 0, len(oi))
+			for _, o := range oi {
+				points = append(points, shared.DerivativesPoint{Time: o.Timestamp.UnixMilli(), Value: o.OpenInterest})
+			}
+			return points, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	series.LongShortRatio, err = fetchDerivativesPages(startTime, endTime, statisticsPageLimit, periodMs,
+		func(from, to time.Time) ([]shared.DerivativesPoint, error) {
+			ratios, err := c.GetLongShortAccountRatio(ctx, symbol, period, &from, &to, statisticsPageLimit)
+			if err != nil {
+				return nil, err
+			}
+			points := make([]shared.DerivativesPoint, 0, len(ratios))
+			for _, r := range ratios {
+				points = append(points, shared.DerivativesPoint{Time: r.Timestamp.UnixMilli(), Value: r.LongShortRatio})
+			}
+			return points, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// Le funding prédit n'existe qu'en temps réel
+	if time.Since(endTime) < time.Duration(periodMs)*time.Millisecond {
+		premium, err := c.GetPremiumIndex(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		series.PredictedFunding = append(series.PredictedFunding, shared.DerivativesPoint{Time: premium.Time.UnixMilli(), Value: premium.PredictedFunding})
+	}
+
+	return series, nil
+}
+
+// Page sizes of the derivatives endpoints (Binance maximums)
+const (
+	fundingPageLimit    = 1000
+	statisticsPageLimit = 500
+)
+
+// fetchDerivativesPages pages a derivatives endpoint from startTime until endTime.
+// When periodMs > 0 each request window is capped to limit periods so a page cannot be
+// truncated; a full page always continues after its last point. Points are returned sorted.
+func fetchDerivativesPages(startTime, endTime time.Time, limit int, periodMs int64,
+	fetch func(from, to time.Time) ([]shared.DerivativesPoint, error)) ([]shared.DerivativesPoint, error) {
+	var points []shared.DerivativesPoint
+	from := startTime
+	for !from.After(endTime) {
+		to := endTime
+		if periodMs > 0 {
+			if windowEnd := from.Add(time.Duration(periodMs*int64(limit)-1) * time.Millisecond); windowEnd.Before(to) {
+				to = windowEnd
+			}
+		}
+
+		page, err := fetch(from, to)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, page...)
+
+		next := to.Add(time.Millisecond)
+		if len(page) >= limit {
+			last := page[0].Time
+			for _, p := range page {
+				if p.Time > last {
+					last = p.Time
+				}
+			}
+			if last < from.UnixMilli() {
+				return nil, fmt.Errorf("derivatives page ends at %d, before its start %d", last, from.UnixMilli())
+			}
+			next = time.UnixMilli(last + 1)
+		}
+		if !next.After(from) {
+			return nil, fmt.Errorf("derivatives pagination stalled at %d", from.UnixMilli())
+		}
+		from = next
+	}
+
+	shared.SortDerivativesPoints(points)
+	return points, nil
+}
@@ -0,0 +1,58 @@
+// Package binance provides tests for futures derivatives pagination
+package binance
+
+import (
+	"testing"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// fakeDerivatives serves points every step ms, ascending, at most limit per call
+func fakeDerivatives(first, last, step int64, limit int, calls *int) func(from, to time.Time) ([]shared.DerivativesPoint, error) {
+	return func(from, to time.Time) ([]shared.DerivativesPoint, error) {
+		*calls++
+		var page []shared.DerivativesPoint
+		for ts := first; ts <= last && len(page) < limit; ts += step {
+			if ts >= from.UnixMilli() && ts <= to.UnixMilli() {
+				page = append(page, shared.DerivativesPoint{Time: ts, Value: float64(ts)})
+			}
+		}
+		return page, nil
+	}
+}
+
+// Test fetchDerivativesPages - full pages continue until endTime
+func TestFetchDerivativesPages_Paginates(t *testing.T) {
+	const step = int64(300000) // 5m
+	start := time.UnixMilli(1700000000000 - 1700000000000%step)
+	end := start.Add(2500 * 5 * time.Minute)
+
+	for _, periodMs := range []int64{0, step} {
+		calls := 0
+		fetch := fakeDerivatives(start.UnixMilli(), end.UnixMilli(), step, 500, &calls)
+		points, err := fetchDerivativesPages(start, end, 500, periodMs, fetch)
+		if err != nil {
+			t.Fatalf("period %d: %v", periodMs, err)
+		}
+		if len(points) != 2501 || calls < 6 {
+			t.Errorf("period %d: expected 2501 points over several pages, got %d in %d calls", periodMs, len(points), calls)
+		}
+		for i := 1; i < len(points); i++ {
+			if points[i].Time != points[i-1].Time+step {
+				t.Fatalf("period %d: gap or duplicate at %d", periodMs, i)
+			}
+		}
+	}
+}
+
+// Test fetchDerivativesPages - a page that does not advance is an error
+func TestFetchDerivativesPages_Stalled(t *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	stale := func(from, to time.Time) ([]shared.DerivativesPoint, error) {
+		return []shared.DerivativesPoint{{Time: start.UnixMilli() - 1}, {Time: start.UnixMilli() - 2}}, nil
+	}
+	if _, err := fetchDerivativesPages(start, start.Add(time.Hour), 2, 0, stale); err == nil {
+		t.Error("Expected error for a full page ending before its start")
+	}
+}
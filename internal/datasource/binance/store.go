@@ -0,0 +1,410 @@
+// Package binance provides a compact binary store for klines and trades
+package binance
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// Binary store file layout
+//
+// Each file holds one day of one data type and starts with a 16 bytes header:
+//
+//	magic [4]byte | version uint16 | recordSize uint16 | reserved [8]byte
+//
+// followed by fixed-width little-endian records. Files are append-only: a
+// truncated trailing record (crash during write) is ignored by the reader.
+const (
+	storeHeaderSize   = 16
+	storeVersion      = 1
+	klineRecordSize   = 88 // 11 x 8 bytes (Ignore is not stored)
+	tradeRecordSize   = 48 // 5 x 8 bytes + isBuyerMaker + 7 bytes padding
+	storeFileExt      = ".bin"
+	storeWriterBuffer = 256 * 1024
+)
+
+var (
+	klineMagic = [4]byte{'A', 'E', 'K', 'L'}
+	tradeMagic = [4]byte{'A', 'E', 'T', 'R'}
+)
+
+// BinaryStore manages the on-disk layout of compiled klines/trades files
+type BinaryStore struct {
+	rootPath string
+	useMmap  bool
+	metrics  *shared.MemoryMetrics
+}
+
+// NewBinaryStore creates a new BinaryStore rooted at rootPath
+func NewBinaryStore(rootPath string, useMmap bool) (*BinaryStore, error) {
+	if rootPath == "" {
+		return nil, fmt.Errorf("root path cannot be empty")
+	}
+
+	if err := os.MkdirAll(rootPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	return &BinaryStore{
+		rootPath: rootPath,
+		useMmap:  useMmap,
+		metrics:  &shared.MemoryMetrics{},
+	}, nil
+}
+
+// GetFilePath returns the store file path for given parameters, mirroring the cache layout
+func (bs *BinaryStore) GetFilePath(symbol, dataType, date string, timeframe ...string) string {
+	basePath := filepath.Join(bs.rootPath, "binance", "futures_um")
+
+	if dataType == "klines" && len(timeframe) > 0 {
+		fileName := fmt.Sprintf("%s-%s-%s%s", symbol, timeframe[0], date, storeFileExt)
+		return filepath.Join(basePath, "klines", symbol, timeframe[0], fileName)
+	} else if dataType == "trades" {
+		fileName := fmt.Sprintf("%s-trades-%s%s", symbol, date, storeFileExt)
+		return filepath.Join(basePath, "trades", symbol, fileName)
+	}
+
+	return ""
+}
+
+// FileExists checks if a compiled file exists for given parameters
+func (bs *BinaryStore) FileExists(symbol, dataType, date string, timeframe ...string) bool {
+	path := bs.GetFilePath(symbol, dataType, date, timeframe...)
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Size() >= storeHeaderSize
+}
+
+// StreamKlines streams klines from a compiled store file
+func (bs *BinaryStore) StreamKlines(filePath string, callback func(shared.KlineData) error) error {
+	return bs.streamRecords(filePath, klineMagic, klineRecordSize, func(rec []byte) error {
+		return callback(decodeKline(rec))
+	})
+}
+
+// StreamTrades streams trades from a compiled store file
+func (bs *BinaryStore) StreamTrades(filePath string, callback func(shared.TradeData) error) error {
+	return bs.streamRecords(filePath, tradeMagic, tradeRecordSize, func(rec []byte) error {
+		return callback(decodeTrade(rec))
+	})
+}
+
+// GetMemoryMetrics returns current memory usage metrics
+func (bs *BinaryStore) GetMemoryMetrics() *shared.MemoryMetrics {
+	bs.updateMemoryMetrics()
+
+	return &shared.MemoryMetrics{
+		CurrentUsageMB: bs.metrics.CurrentUsageMB,
+		PeakUsageMB:    bs.metrics.PeakUsageMB,
+		BuffersActive:  bs.metrics.BuffersActive,
+		LastUpdateTime: bs.metrics.LastUpdateTime,
+	}
+}
+
+// ValidateMemoryConstraints always succeeds: the store never holds more than one record buffer
+func (bs *BinaryStore) ValidateMemoryConstraints() (bool, error) {
+	bs.updateMemoryMetrics()
+	return true, nil
+}
+
+// streamRecords validates the header then invokes fn for each complete record
+func (bs *BinaryStore) streamRecords(filePath string, magic [4]byte, recordSize int, fn func([]byte) error) error {
+	if filePath == "" {
+		return fmt.Errorf("file path cannot be empty")
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open store file: %w", err)
+	}
+	defer file.Close()
+
+	bs.metrics.BuffersActive++
+	defer func() { bs.metrics.BuffersActive-- }()
+
+	if bs.useMmap {
+		data, release, err := mmapFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to mmap store file: %w", err)
+		}
+		defer release()
+
+		if err := checkStoreHeader(data, magic, recordSize); err != nil {
+			return err
+		}
+
+		body := data[storeHeaderSize:]
+		count := len(body) / recordSize
+		for i := 0; i < count; i++ {
+			if err := fn(body[i*recordSize : (i+1)*recordSize]); err != nil {
+				return fmt.Errorf("callback failed at record %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	reader := bufio.NewReaderSize(file, storeWriterBuffer)
+
+	header := make([]byte, storeHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return fmt.Errorf("failed to read store header: %w", err)
+	}
+	if err := checkStoreHeader(header, magic, recordSize); err != nil {
+		return err
+	}
+
+	record := make([]byte, recordSize)
+	for i := 0; ; i++ {
+		if _, err := io.ReadFull(reader, record); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read record %d: %w", i, err)
+		}
+		if err := fn(record); err != nil {
+			return fmt.Errorf("callback failed at record %d: %w", i, err)
+		}
+	}
+}
+
+// updateMemoryMetrics updates current memory usage statistics
+func (bs *BinaryStore) updateMemoryMetrics() {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	currentMB := float64(memStats.Alloc) / (1024 * 1024)
+
+	bs.metrics.CurrentUsageMB = currentMB
+	if currentMB > bs.metrics.PeakUsageMB {
+		bs.metrics.PeakUsageMB = currentMB
+	}
+	bs.metrics.LastUpdateTime = time.Now()
+}
+
+// StoreWriter appends fixed-width records to a store file
+type StoreWriter struct {
+	file       *os.File
+	writer     *bufio.Writer
+	magic      [4]byte
+	recordSize int
+	buf        []byte
+	count      int64
+}
+
+// OpenKlineWriter opens (or creates) a klines store file for appending
+func OpenKlineWriter(filePath string) (*StoreWriter, error) {
+	return openStoreWriter(filePath, klineMagic, klineRecordSize)
+}
+
+// OpenTradeWriter opens (or creates) a trades store file for appending
+func OpenTradeWriter(filePath string) (*StoreWriter, error) {
+	return openStoreWriter(filePath, tradeMagic, tradeRecordSize)
+}
+
+// openStoreWriter opens a store file, writing the header when the file is new
+func openStoreWriter(filePath string, magic [4]byte, recordSize int) (*StoreWriter, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("file path cannot be empty")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat store file: %w", err)
+	}
+
+	var count int64
+	if info.Size() == 0 {
+		header := encodeStoreHeader(magic, recordSize)
+		if _, err := file.Write(header); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to write store header: %w", err)
+		}
+	} else {
+		header := make([]byte, storeHeaderSize)
+		if _, err := io.ReadFull(file, header); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read store header: %w", err)
+		}
+		if err := checkStoreHeader(header, magic, recordSize); err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		// Drop a partial trailing record left by an interrupted write
+		count = (info.Size() - storeHeaderSize) / int64(recordSize)
+		validSize := storeHeaderSize + count*int64(recordSize)
+		if validSize != info.Size() {
+			if err := file.Truncate(validSize); err != nil {
+				file.Close()
+				return nil, fmt.Errorf("failed to truncate partial record: %w", err)
+			}
+		}
+		if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to seek store file: %w", err)
+		}
+	}
+
+	return &StoreWriter{
+		file:       file,
+		writer:     bufio.NewWriterSize(file, storeWriterBuffer),
+		magic:      magic,
+		recordSize: recordSize,
+		buf:        make([]byte, recordSize),
+		count:      count,
+	}, nil
+}
+
+// WriteKline appends one kline record
+func (sw *StoreWriter) WriteKline(kline shared.KlineData) error {
+	if sw.magic != klineMagic {
+		return fmt.Errorf("store writer does not accept klines")
+	}
+	encodeKline(sw.buf, kline)
+	return sw.writeRecord()
+}
+
+// WriteTrade appends one trade record
+func (sw *StoreWriter) WriteTrade(trade shared.TradeData) error {
+	if sw.magic != tradeMagic {
+		return fmt.Errorf("store writer does not accept trades")
+	}
+	encodeTrade(sw.buf, trade)
+	return sw.writeRecord()
+}
+
+// Count returns the number of records in the file, including existing ones
+func (sw *StoreWriter) Count() int64 {
+	return sw.count
+}
+
+// Close flushes buffered records and closes the file
+func (sw *StoreWriter) Close() error {
+	if err := sw.writer.Flush(); err != nil {
+		sw.file.Close()
+		return fmt.Errorf("failed to flush store file: %w", err)
+	}
+	return sw.file.Close()
+}
+
+// writeRecord writes the encoded buffer
+func (sw *StoreWriter) writeRecord() error {
+	if _, err := sw.writer.Write(sw.buf); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	sw.count++
+	return nil
+}
+
+// Encoding helpers
+
+// encodeStoreHeader builds a file header for the given record type
+func encodeStoreHeader(magic [4]byte, recordSize int) []byte {
+	header := make([]byte, storeHeaderSize)
+	copy(header[0:4], magic[:])
+	binary.LittleEndian.PutUint16(header[4:6], storeVersion)
+	binary.LittleEndian.PutUint16(header[6:8], uint16(recordSize))
+	return header
+}
+
+// checkStoreHeader validates magic, version and record size
+func checkStoreHeader(header []byte, magic [4]byte, recordSize int) error {
+	if len(header) < storeHeaderSize {
+		return fmt.Errorf("store file too short: %d bytes", len(header))
+	}
+	if [4]byte(header[0:4]) != magic {
+		return fmt.Errorf("invalid store magic: %q (expected %q)", header[0:4], magic[:])
+	}
+	if version := binary.LittleEndian.Uint16(header[4:6]); version != storeVersion {
+		return fmt.Errorf("unsupported store version: %d", version)
+	}
+	if size := int(binary.LittleEndian.Uint16(header[6:8])); size != recordSize {
+		return fmt.Errorf("invalid record size: %d (expected %d)", size, recordSize)
+	}
+	return nil
+}
+
+// encodeKline writes a kline into a klineRecordSize buffer
+func encodeKline(buf []byte, k shared.KlineData) {
+	le := binary.LittleEndian
+	le.PutUint64(buf[0:], uint64(k.OpenTime))
+	le.PutUint64(buf[8:], math.Float64bits(k.Open))
+	le.PutUint64(buf[16:], math.Float64bits(k.High))
+	le.PutUint64(buf[24:], math.Float64bits(k.Low))
+	le.PutUint64(buf[32:], math.Float64bits(k.Close))
+	le.PutUint64(buf[40:], math.Float64bits(k.Volume))
+	le.PutUint64(buf[48:], uint64(k.CloseTime))
+	le.PutUint64(buf[56:], math.Float64bits(k.QuoteAssetVolume))
+	le.PutUint64(buf[64:], uint64(k.NumberOfTrades))
+	le.PutUint64(buf[72:], math.Float64bits(k.TakerBuyBaseAssetVolume))
+	le.PutUint64(buf[80:], math.Float64bits(k.TakerBuyQuoteAssetVolume))
+}
+
+// decodeKline reads a kline from a klineRecordSize buffer
+func decodeKline(buf []byte) shared.KlineData {
+	le := binary.LittleEndian
+	return shared.KlineData{
+		OpenTime:                 int64(le.Uint64(buf[0:])),
+		Open:                     math.Float64frombits(le.Uint64(buf[8:])),
+		High:                     math.Float64frombits(le.Uint64(buf[16:])),
+		Low:                      math.Float64frombits(le.Uint64(buf[24:])),
+		Close:                    math.Float64frombits(le.Uint64(buf[32:])),
+		Volume:                   math.Float64frombits(le.Uint64(buf[40:])),
+		CloseTime:                int64(le.Uint64(buf[48:])),
+		QuoteAssetVolume:         math.Float64frombits(le.Uint64(buf[56:])),
+		NumberOfTrades:           int64(le.Uint64(buf[64:])),
+		TakerBuyBaseAssetVolume:  math.Float64frombits(le.Uint64(buf[72:])),
+		TakerBuyQuoteAssetVolume: math.Float64frombits(le.Uint64(buf[80:])),
+		Ignore:                   "0",
+	}
+}
+
+// encodeTrade writes a trade into a tradeRecordSize buffer
+func encodeTrade(buf []byte, t shared.TradeData) {
+	le := binary.LittleEndian
+	le.PutUint64(buf[0:], uint64(t.ID))
+	le.PutUint64(buf[8:], math.Float64bits(t.Price))
+	le.PutUint64(buf[16:], math.Float64bits(t.Quantity))
+	le.PutUint64(buf[24:], math.Float64bits(t.QuoteQty))
+	le.PutUint64(buf[32:], uint64(t.Time))
+	for i := 40; i < tradeRecordSize; i++ {
+		buf[i] = 0
+	}
+	if t.IsBuyerMaker {
+		buf[40] = 1
+	}
+}
+
+// decodeTrade reads a trade from a tradeRecordSize buffer
+func decodeTrade(buf []byte) shared.TradeData {
+	le := binary.LittleEndian
+	return shared.TradeData{
+		ID:           int64(le.Uint64(buf[0:])),
+		Price:        math.Float64frombits(le.Uint64(buf[8:])),
+		Quantity:     math.Float64frombits(le.Uint64(buf[16:])),
+		QuoteQty:     math.Float64frombits(le.Uint64(buf[24:])),
+		Time:         int64(le.Uint64(buf[32:])),
+		IsBuyerMaker: buf[40] == 1,
+	}
+}
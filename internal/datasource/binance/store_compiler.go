@@ -0,0 +1,173 @@
+// Package binance provides compilation of Vision ZIP files into the binary store
+package binance
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// CompileResult contains the result of compiling one Vision file
+type CompileResult struct {
+	Request     shared.DownloadRequest `json:"request"`
+	SourcePath  string                 `json:"source_path"`
+	StorePath   string                 `json:"store_path"`
+	RecordCount int64                  `json:"record_count"`
+	Skipped     bool                   `json:"skipped"` // Store file already up to date
+	Duration    time.Duration          `json:"duration"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// StoreCompiler converts cached Vision ZIP files into BinaryStore files
+type StoreCompiler struct {
+	cache         *CacheManager
+	streaming     *StreamingReader
+	store         *BinaryStore
+	maxConcurrent int
+}
+
+// NewStoreCompiler creates a new StoreCompiler instance
+func NewStoreCompiler(cache *CacheManager, streaming *StreamingReader, store *BinaryStore, maxConcurrent int) (*StoreCompiler, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("cache manager cannot be nil")
+	}
+	if streaming == nil {
+		return nil, fmt.Errorf("streaming reader cannot be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("binary store cannot be nil")
+	}
+	if maxConcurrent <= 0 {
+		maxConcurrent = 2
+	}
+
+	return &StoreCompiler{
+		cache:         cache,
+		streaming:     streaming,
+		store:         store,
+		maxConcurrent: maxConcurrent,
+	}, nil
+}
+
+// CompileFile compiles one Vision ZIP into the store unless the store file is newer
+func (sc *StoreCompiler) CompileFile(request shared.DownloadRequest) *CompileResult {
+	startTime := time.Now()
+	result := &CompileResult{Request: request}
+
+	var timeframe []string
+	if request.DataType == "klines" {
+		if request.Timeframe == "" {
+			result.Error = "timeframe is required for klines"
+			return result
+		}
+		timeframe = []string{request.Timeframe}
+	} else if request.DataType != "trades" {
+		result.Error = fmt.Sprintf("unsupported data type: %s", request.DataType)
+		return result
+	}
+
+	result.SourcePath = sc.cache.GetFilePath(request.Symbol, request.DataType, request.Date, timeframe...)
+	result.StorePath = sc.store.GetFilePath(request.Symbol, request.DataType, request.Date, timeframe...)
+
+	sourceInfo, err := os.Stat(result.SourcePath)
+	if err != nil {
+		result.Error = fmt.Sprintf("source file not available: %v", err)
+		return result
+	}
+
+	if storeInfo, err := os.Stat(result.StorePath); err == nil && !storeInfo.ModTime().Before(sourceInfo.ModTime()) {
+		result.Skipped = true
+		result.Duration = time.Since(startTime)
+		return result
+	}
+
+	count, err := sc.compile(request.DataType, result.SourcePath, result.StorePath)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.RecordCount = count
+	result.Duration = time.Since(startTime)
+	return result
+}
+
+// CompileAll compiles all requests sequentially and returns their results
+func (sc *StoreCompiler) CompileAll(requests []shared.DownloadRequest) []*CompileResult {
+	results := make([]*CompileResult, 0, len(requests))
+	for _, request := range requests {
+		results = append(results, sc.CompileFile(request))
+	}
+	return results
+}
+
+// StartBackground compiles requests with a bounded worker pool; the channel is closed when done
+func (sc *StoreCompiler) StartBackground(requests []shared.DownloadRequest) <-chan *CompileResult {
+	results := make(chan *CompileResult, len(requests))
+	jobs := make(chan shared.DownloadRequest)
+
+	var wg sync.WaitGroup
+	for i := 0; i < sc.maxConcurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for request := range jobs {
+				results <- sc.CompileFile(request)
+			}
+		}()
+	}
+
+	go func() {
+		for _, request := range requests {
+			jobs <- request
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// compile streams the ZIP into a temporary store file then renames it atomically
+func (sc *StoreCompiler) compile(dataType, sourcePath, storePath string) (int64, error) {
+	tmpPath := storePath + ".tmp"
+	os.Remove(tmpPath)
+
+	var writer *StoreWriter
+	var err error
+	if dataType == "klines" {
+		writer, err = OpenKlineWriter(tmpPath)
+	} else {
+		writer, err = OpenTradeWriter(tmpPath)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if dataType == "klines" {
+		err = sc.streaming.StreamKlines(sourcePath, writer.WriteKline)
+	} else {
+		err = sc.streaming.StreamTrades(sourcePath, writer.WriteTrade)
+	}
+	if err != nil {
+		writer.Close()
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("failed to compile %s: %w", sourcePath, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+
+	if err := os.Rename(tmpPath, storePath); err != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("failed to finalize store file: %w", err)
+	}
+
+	return writer.Count(), nil
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+// Package binance provides a read-all fallback where mmap is unavailable
+package binance
+
+import (
+	"io"
+	"os"
+)
+
+// mmapFile reads the whole file in memory on platforms without mmap
+func mmapFile(file *os.File) ([]byte, func(), error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() {}, nil
+}
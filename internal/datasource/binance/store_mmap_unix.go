@@ -0,0 +1,28 @@
+//go:build unix
+
+// Package binance provides memory-mapped access to store files
+package binance
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the whole file read-only and returns a release function
+func mmapFile(file *os.File) ([]byte, func(), error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return nil, nil, fmt.Errorf("cannot mmap empty file")
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() { syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,231 @@
+// Package binance provides tests for the binary store
+package binance
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"agent-economique/internal/shared"
+)
+
+// Test round trip klines through StoreWriter and BinaryStore (buffered and mmap)
+func TestBinaryStore_KlinesRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+
+	klines := []shared.KlineData{
+		{OpenTime: 1623024000000, Open: 100.0, High: 101.0, Low: 99.0, Close: 100.5, Volume: 1000.0, CloseTime: 1623024059999, QuoteAssetVolume: 100500.0, NumberOfTrades: 50, TakerBuyBaseAssetVolume: 500.0, TakerBuyQuoteAssetVolume: 50250.0, Ignore: "0"},
+		{OpenTime: 1623024060000, Open: 100.5, High: 102.0, Low: 100.0, Close: 101.0, Volume: 1200.0, CloseTime: 1623024119999, QuoteAssetVolume: 121200.0, NumberOfTrades: 60, TakerBuyBaseAssetVolume: 600.0, TakerBuyQuoteAssetVolume: 60600.0, Ignore: "0"},
+	}
+
+	for _, useMmap := range []bool{false, true} {
+		store, err := NewBinaryStore(filepath.Join(tempDir, "store"), useMmap)
+		if err != nil {
+			t.Fatalf("NewBinaryStore failed: %v", err)
+		}
+
+		path := store.GetFilePath("SOLUSDT", "klines", "2021-06-07", "1m")
+		os.Remove(path)
+
+		writer, err := OpenKlineWriter(path)
+		if err != nil {
+			t.Fatalf("OpenKlineWriter failed: %v", err)
+		}
+		for _, k := range klines {
+			if err := writer.WriteKline(k); err != nil {
+				t.Fatalf("WriteKline failed: %v", err)
+			}
+		}
+		if err := writer.WriteTrade(shared.TradeData{}); err == nil {
+			t.Error("Expected error writing trade into klines file")
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		if !store.FileExists("SOLUSDT", "klines", "2021-06-07", "1m") {
+			t.Error("Expected store file to exist")
+		}
+
+		var got []shared.KlineData
+		err = store.StreamKlines(path, func(k shared.KlineData) error {
+			got = append(got, k)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamKlines failed (mmap=%v): %v", useMmap, err)
+		}
+
+		if len(got) != len(klines) {
+			t.Fatalf("Expected %d klines, got %d (mmap=%v)", len(klines), len(got), useMmap)
+		}
+		for i := range klines {
+			if got[i] != klines[i] {
+				t.Errorf("Kline %d mismatch (mmap=%v): got %+v, want %+v", i, useMmap, got[i], klines[i])
+			}
+		}
+
+		// Reading a klines file as trades must fail on header check
+		if err := store.StreamTrades(path, func(shared.TradeData) error { return nil }); err == nil {
+			t.Errorf("Expected header error reading klines as trades (mmap=%v)", useMmap)
+		}
+	}
+}
+
+// Test append-only behaviour and recovery from a truncated trailing record
+func TestStoreWriter_AppendAndRecover(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "trades.bin")
+
+	trades := []shared.TradeData{
+		{ID: 1, Price: 100.0, Quantity: 1.5, QuoteQty: 150.0, Time: 1623024000100, IsBuyerMaker: true},
+		{ID: 2, Price: 100.2, Quantity: 0.5, QuoteQty: 50.1, Time: 1623024000200, IsBuyerMaker: false},
+		{ID: 3, Price: 100.1, Quantity: 2.0, QuoteQty: 200.2, Time: 1623024000300, IsBuyerMaker: true},
+	}
+
+	writer, err := OpenTradeWriter(path)
+	if err != nil {
+		t.Fatalf("OpenTradeWriter failed: %v", err)
+	}
+	writer.WriteTrade(trades[0])
+	writer.WriteTrade(trades[1])
+	writer.Close()
+
+	// Simulate an interrupted write: append half a record
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	file.Write(make([]byte, tradeRecordSize/2))
+	file.Close()
+
+	writer, err = OpenTradeWriter(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if writer.Count() != 2 {
+		t.Errorf("Expected 2 existing records, got %d", writer.Count())
+	}
+	writer.WriteTrade(trades[2])
+	writer.Close()
+
+	store, _ := NewBinaryStore(tempDir, false)
+	var got []shared.TradeData
+	if err := store.StreamTrades(path, func(tr shared.TradeData) error {
+		got = append(got, tr)
+		return nil
+	}); err != nil {
+		t.Fatalf("StreamTrades failed: %v", err)
+	}
+
+	if len(got) != len(trades) {
+		t.Fatalf("Expected %d trades, got %d", len(trades), len(got))
+	}
+	for i := range trades {
+		if got[i] != trades[i] {
+			t.Errorf("Trade %d mismatch: got %+v, want %+v", i, got[i], trades[i])
+		}
+	}
+
+	// Reopening with the wrong record type must be rejected
+	if _, err := OpenKlineWriter(path); err == nil {
+		t.Error("Expected error opening trades file as klines")
+	}
+}
+
+// Test StoreCompiler converts a cached Vision ZIP and skips up-to-date files
+func TestStoreCompiler_CompileFile(t *testing.T) {
+	tempDir := t.TempDir()
+	cache, err := InitializeCache(filepath.Join(tempDir, "cache"))
+	if err != nil {
+		t.Fatalf("Failed to initialize cache: %v", err)
+	}
+	reader, _ := NewStreamingReader(cache, shared.StreamingConfig{})
+	store, _ := NewBinaryStore(filepath.Join(tempDir, "store"), false)
+
+	compiler, err := NewStoreCompiler(cache, reader, store, 0)
+	if err != nil {
+		t.Fatalf("NewStoreCompiler failed: %v", err)
+	}
+
+	request := shared.DownloadRequest{Symbol: "SOLUSDT", DataType: "trades", Date: "2021-06-07"}
+	zipPath := cache.GetFilePath(request.Symbol, request.DataType, request.Date)
+	if err := createMockTradesZIP(zipPath); err != nil {
+		t.Fatalf("Failed to create mock ZIP: %v", err)
+	}
+
+	var expected []shared.TradeData
+	reader.StreamTrades(zipPath, func(tr shared.TradeData) error {
+		expected = append(expected, tr)
+		return nil
+	})
+
+	results := compiler.StartBackground([]shared.DownloadRequest{request})
+	var result *CompileResult
+	for r := range results {
+		result = r
+	}
+	if result == nil || result.Error != "" {
+		t.Fatalf("Compile failed: %+v", result)
+	}
+	if result.RecordCount != int64(len(expected)) {
+		t.Errorf("Expected %d records, got %d", len(expected), result.RecordCount)
+	}
+
+	var got []shared.TradeData
+	store.StreamTrades(result.StorePath, func(tr shared.TradeData) error {
+		got = append(got, tr)
+		return nil
+	})
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d trades from store, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Trade %d mismatch: got %+v, want %+v", i, got[i], expected[i])
+		}
+	}
+
+	// Second compile is a no-op
+	again := compiler.CompileFile(request)
+	if !again.Skipped {
+		t.Errorf("Expected second compile to be skipped, got %+v", again)
+	}
+
+	// Missing source and invalid requests are reported, not panicking
+	missing := compiler.CompileFile(shared.DownloadRequest{Symbol: "SOLUSDT", DataType: "trades", Date: "2021-06-08"})
+	if missing.Error == "" {
+		t.Error("Expected error for missing source file")
+	}
+	invalid := compiler.CompileFile(shared.DownloadRequest{Symbol: "SOLUSDT", DataType: "klines", Date: "2021-06-07"})
+	if invalid.Error == "" {
+		t.Error("Expected error for klines request without timeframe")
+	}
+}
+
+// Helper function pour créer ZIP mock avec données trades
+func createMockTradesZIP(zipPath string) error {
+	if err := os.MkdirAll(filepath.Dir(zipPath), 0755); err != nil {
+		return err
+	}
+	zipFile, err := os.Create(zipPath)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+
+	zipWriter := zip.NewWriter(zipFile)
+	defer zipWriter.Close()
+
+	csvWriter, err := zipWriter.Create("SOLUSDT-trades-2021-06-07.csv")
+	if err != nil {
+		return err
+	}
+
+	csvData := `id,price,qty,quote_qty,time,is_buyer_maker
+1,100.0,1.5,150.0,1623024000100,true
+2,100.2,0.5,50.1,1623024000200,false
+3,100.1,2.0,200.2,1623024000300,true
+`
+
+	_, err = csvWriter.Write([]byte(csvData))
+	return err
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"agent-economique/internal/shared"
 )
 
 // MarketDataService provides market data operations for BingX
@@ -233,6 +235,179 @@ func (m *MarketDataService) GetFuturesTicker24hr(ctx context.Context, symbol str
 	return &ticker, nil
 }
 
+// GetFundingRateHistory retrieves settled funding rates for a perpetual contract
+func (m *MarketDataService) GetFundingRateHistory(ctx context.Context, symbol string, limit int, startTime, endTime *time.Time) ([]FundingRate, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+
+	params := map[string]string{
+		"symbol": symbol,
+	}
+
+	if limit > 0 {
+		if limit > 1000 {
+			limit = 1000 // BingX maximum limit
+		}
+		params["limit"] = strconv.Itoa(limit)
+	}
+
+	if startTime != nil {
+		params["startTime"] = strconv.FormatInt(startTime.UnixMilli(), 10)
+	}
+
+	if endTime != nil {
+		params["endTime"] = strconv.FormatInt(endTime.UnixMilli(), 10)
+	}
+
+	resp, err := m.client.DoRequest(ctx, http.MethodGet, "/openApi/swap/v2/quote/fundingRate", params, EndpointTypeMarketData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding rate history for %s: %w", symbol, err)
+	}
+
+	var raw []struct {
+		Symbol      string      `json:"symbol"`
+		FundingRate interface{} `json:"fundingRate"`
+		FundingTime int64       `json:"fundingTime"`
+	}
+	if err := m.parseDataArray(resp.Data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse funding rate response: %w", err)
+	}
+
+	rates := make([]FundingRate, 0, len(raw))
+	for _, r := range raw {
+		rate, err := m.parseFloat64(r.FundingRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid funding rate: %w", err)
+		}
+		rates = append(rates, FundingRate{
+			Symbol:      r.Symbol,
+			FundingRate: rate,
+			FundingTime: time.UnixMilli(r.FundingTime),
+		})
+	}
+
+	return rates, nil
+}
+
+// GetPremiumIndex retrieves mark price, index price and predicted funding rate
+func (m *MarketDataService) GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndex, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+
+	params := map[string]string{
+		"symbol": symbol,
+	}
+
+	resp, err := m.client.DoRequest(ctx, http.MethodGet, "/openApi/swap/v2/quote/premiumIndex", params, EndpointTypeMarketData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get premium index for %s: %w", symbol, err)
+	}
+
+	var raw struct {
+		Symbol          string      `json:"symbol"`
+		MarkPrice       interface{} `json:"markPrice"`
+		IndexPrice      interface{} `json:"indexPrice"`
+		LastFundingRate interface{} `json:"lastFundingRate"`
+		NextFundingTime int64       `json:"nextFundingTime"`
+	}
+	if err := m.parseDataObject(resp.Data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse premium index response: %w", err)
+	}
+
+	markPrice, _ := m.parseFloat64(raw.MarkPrice)
+	indexPrice, _ := m.parseFloat64(raw.IndexPrice)
+	predicted, err := m.parseFloat64(raw.LastFundingRate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding rate: %w", err)
+	}
+
+	return &PremiumIndex{
+		Symbol:           symbol,
+		MarkPrice:        markPrice,
+		IndexPrice:       indexPrice,
+		PredictedFunding: predicted,
+		NextFundingTime:  time.UnixMilli(raw.NextFundingTime),
+		Timestamp:        time.Now(),
+	}, nil
+}
+
+// GetOpenInterest retrieves the current open interest of a perpetual contract
+func (m *MarketDataService) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterest, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+
+	params := map[string]string{
+		"symbol": symbol,
+	}
+
+	resp, err := m.client.DoRequest(ctx, http.MethodGet, "/openApi/swap/v2/quote/openInterest", params, EndpointTypeMarketData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open interest for %s: %w", symbol, err)
+	}
+
+	var raw struct {
+		Symbol       string      `json:"symbol"`
+		OpenInterest interface{} `json:"openInterest"`
+		Time         int64       `json:"time"`
+	}
+	if err := m.parseDataObject(resp.Data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse open interest response: %w", err)
+	}
+
+	openInterest, err := m.parseFloat64(raw.OpenInterest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid open interest: %w", err)
+	}
+
+	timestamp := time.Now()
+	if raw.Time > 0 {
+		timestamp = time.UnixMilli(raw.Time)
+	}
+
+	return &OpenInterest{
+		Symbol:       symbol,
+		OpenInterest: openInterest,
+		Timestamp:    timestamp,
+	}, nil
+}
+
+// AppendDerivativesSnapshot polls predicted funding and open interest and appends them to series.
+// Open interest is converted from USDT to base asset with the mark price of the same poll.
+// BingX has no open interest or long/short ratio history: live runners build it by calling
+// this method once per bar.
+func (m *MarketDataService) AppendDerivativesSnapshot(ctx context.Context, series *shared.DerivativesSeries) error {
+	if series == nil || series.Symbol == "" {
+		return fmt.Errorf("series with symbol is required")
+	}
+
+	premium, err := m.GetPremiumIndex(ctx, series.Symbol)
+	if err != nil {
+		return err
+	}
+	series.PredictedFunding = append(series.PredictedFunding, shared.DerivativesPoint{
+		Time:  premium.Timestamp.UnixMilli(),
+		Value: premium.PredictedFunding,
+	})
+
+	oi, err := m.GetOpenInterest(ctx, series.Symbol)
+	if err != nil {
+		return err
+	}
+	// BingX reports open interest in USDT; the series holds base asset like Binance
+	if premium.MarkPrice <= 0 {
+		return fmt.Errorf("invalid mark price %v for %s: cannot convert open interest to base asset", premium.MarkPrice, series.Symbol)
+	}
+	series.OpenInterest = append(series.OpenInterest, shared.DerivativesPoint{
+		Time:  oi.Timestamp.UnixMilli(),
+		Value: oi.OpenInterest / premium.MarkPrice,
+	})
+
+	return nil
+}
+
 // parseKlinesData converts raw API response to Kline structs
 // BingX spot kline format: [timestamp, open, high, low, close, volume_base, end_timestamp, volume_quote]
 func (m *MarketDataService) parseKlinesData(rawKlines [][]interface{}) ([]Kline, error) {
@@ -2,7 +2,13 @@ package bingx
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"agent-economique/internal/shared"
 )
 
 func TestNewMarketDataService(t *testing.T) {
@@ -573,3 +579,63 @@ func TestMarketDataServiceGetFuturesTicker24hrValidation(t *testing.T) {
 		t.Error("Expected error for empty symbol")
 	}
 }
+
+// Test funding, premium index and open interest parsing against a local stand-in
+func TestMarketDataServiceDerivatives(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openApi/swap/v2/quote/fundingRate":
+			fmt.Fprint(w, `{"code":0,"msg":"","data":[{"symbol":"SOL-USDT","fundingRate":"0.0001","fundingTime":1700000000000},{"symbol":"SOL-USDT","fundingRate":"-0.0003","fundingTime":1700028800000}]}`)
+		case "/openApi/swap/v2/quote/premiumIndex":
+			fmt.Fprint(w, `{"code":0,"msg":"","data":{"symbol":"SOL-USDT","markPrice":"101.5","indexPrice":"101.4","lastFundingRate":"0.0012","nextFundingTime":1700057600000}}`)
+		case "/openApi/swap/v2/quote/openInterest":
+			fmt.Fprint(w, `{"code":0,"msg":"","data":{"symbol":"SOL-USDT","openInterest":"123456.7","time":1700000100000}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{
+		Environment: DemoEnvironment,
+		Credentials: testCredentials,
+		BaseURL:     server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewMarketDataService(client)
+	ctx := context.Background()
+
+	rates, err := service.GetFundingRateHistory(ctx, "SOL-USDT", 10, nil, nil)
+	if err != nil {
+		t.Fatalf("GetFundingRateHistory failed: %v", err)
+	}
+	if len(rates) != 2 || rates[1].FundingRate != -0.0003 || rates[0].FundingTime.UnixMilli() != 1700000000000 {
+		t.Errorf("Unexpected funding rates: %+v", rates)
+	}
+
+	premium, err := service.GetPremiumIndex(ctx, "SOL-USDT")
+	if err != nil {
+		t.Fatalf("GetPremiumIndex failed: %v", err)
+	}
+	if premium.PredictedFunding != 0.0012 || premium.MarkPrice != 101.5 {
+		t.Errorf("Unexpected premium index: %+v", premium)
+	}
+
+	series := &shared.DerivativesSeries{Symbol: "SOL-USDT"}
+	if err := service.AppendDerivativesSnapshot(ctx, series); err != nil {
+		t.Fatalf("AppendDerivativesSnapshot failed: %v", err)
+	}
+	// 123456.7 USDT at mark price 101.5 -> base asset, like the Binance series
+	if len(series.OpenInterest) != 1 || math.Abs(series.OpenInterest[0].Value-123456.7/101.5) > 1e-9 || series.OpenInterest[0].Time != 1700000100000 {
+		t.Errorf("Unexpected open interest: %+v", series.OpenInterest)
+	}
+	if len(series.PredictedFunding) != 1 || series.PredictedFunding[0].Value != 0.0012 {
+		t.Errorf("Unexpected predicted funding: %+v", series.PredictedFunding)
+	}
+
+	if _, err := service.GetFundingRateHistory(ctx, "", 10, nil, nil); err == nil {
+		t.Error("Expected error for empty symbol")
+	}
+}
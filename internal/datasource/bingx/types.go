@@ -117,6 +117,30 @@ type Ticker struct {
 	Timestamp          time.Time
 }
 
+// FundingRate represents one settled funding rate of a perpetual contract
+type FundingRate struct {
+	Symbol      string
+	FundingRate float64
+	FundingTime time.Time
+}
+
+// PremiumIndex represents the current mark price and predicted funding rate
+type PremiumIndex struct {
+	Symbol           string
+	MarkPrice        float64
+	IndexPrice       float64
+	PredictedFunding float64
+	NextFundingTime  time.Time
+	Timestamp        time.Time
+}
+
+// OpenInterest represents an open interest snapshot (BingX only publishes the current value)
+type OpenInterest struct {
+	Symbol       string
+	OpenInterest float64 // En USDT
+	Timestamp    time.Time
+}
+
 // Balance represents account balance information
 type Balance struct {
 	Asset  string
@@ -0,0 +1,361 @@
+// Package multisource provides timestamp alignment, cross-exchange validation and priority merge
+package multisource
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// MergerConfig holds configuration for the multi-source merger
+type MergerConfig struct {
+	Timeframe string   `yaml:"timeframe"` // Timeframe used to align open times across sources
+	Priority  []string `yaml:"priority"`  // Source names, highest priority first
+
+	// Validation thresholds (percent) are applied against the cross-source median of each bar:
+	// MaxPriceDeviation on close, MaxVolumeDeviation on volume, MaxTimeGap between merged bars
+	Validation shared.ValidationConfig `yaml:"validation"`
+
+	// SkipFlaggedSources makes the merge fall back to the next source in priority when the
+	// preferred source is flagged on that bar
+	SkipFlaggedSources bool `yaml:"skip_flagged_sources"`
+}
+
+// SourceDeviation holds the deviation of one source from the bar consensus
+type SourceDeviation struct {
+	Close         float64 `json:"close"`
+	Volume        float64 `json:"volume"`
+	ClosePct      float64 `json:"close_pct"`  // (close - median) / median * 100
+	VolumePct     float64 `json:"volume_pct"` // (volume - median) / median * 100
+	CloseFlagged  bool    `json:"close_flagged"`
+	VolumeFlagged bool    `json:"volume_flagged"`
+}
+
+// Flagged reports whether the source breached any threshold on this bar
+func (d SourceDeviation) Flagged() bool {
+	return d.CloseFlagged || d.VolumeFlagged
+}
+
+// MergedKline is one merged bar with its provenance
+type MergedKline struct {
+	shared.KlineData
+	Source     string                     `json:"source"`     // Source the bar was taken from
+	Sources    []string                   `json:"sources"`    // Sources that had this bar
+	Deviations map[string]SourceDeviation `json:"deviations"` // Per-source deviation from consensus
+	Flagged    bool                       `json:"flagged"`    // At least one source deviates beyond thresholds
+}
+
+// SourceStats aggregates the deviations of one source across merged bars
+type SourceStats struct {
+	Bars            int     `json:"bars"`
+	Missing         int     `json:"missing"` // Bars present elsewhere but not in this source
+	Selected        int     `json:"selected"`
+	CloseFlagged    int     `json:"close_flagged"`
+	VolumeFlagged   int     `json:"volume_flagged"`
+	MeanAbsClosePct float64 `json:"mean_abs_close_pct"`
+	MaxAbsClosePct  float64 `json:"max_abs_close_pct"`
+	MeanAbsVolPct   float64 `json:"mean_abs_volume_pct"`
+	MaxAbsVolPct    float64 `json:"max_abs_volume_pct"`
+}
+
+// TimeGap describes a gap between consecutive merged bars beyond MaxTimeGap
+type TimeGap struct {
+	From  int64 `json:"from"`
+	To    int64 `json:"to"`
+	GapMs int64 `json:"gap_ms"`
+}
+
+// MergeResult contains the merged series and its quality report
+type MergeResult struct {
+	Klines      []MergedKline           `json:"klines"`
+	Stats       map[string]*SourceStats `json:"stats"`
+	Gaps        []TimeGap               `json:"gaps,omitempty"`
+	FlaggedBars int                     `json:"flagged_bars"`
+}
+
+// Merger aligns and merges klines from several sources
+type Merger struct {
+	config     MergerConfig
+	intervalMs int64
+
+	// Cumulative statistics across successive Merge calls (live monitoring)
+	cumulative  map[string]*SourceStats
+	lastCounted int64
+	now         func() time.Time // Clock used to leave forming bars out of cumulative stats
+}
+
+// NewMerger creates a new Merger instance
+func NewMerger(config MergerConfig) (*Merger, error) {
+	if config.Timeframe == "" {
+		return nil, fmt.Errorf("timeframe cannot be empty")
+	}
+	intervalMs, err := shared.ParseTimeframe(config.Timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeframe: %w", err)
+	}
+	if len(config.Priority) == 0 {
+		return nil, fmt.Errorf("source priority cannot be empty")
+	}
+
+	return &Merger{
+		config:     config,
+		intervalMs: intervalMs,
+		cumulative: make(map[string]*SourceStats),
+		now:        time.Now,
+	}, nil
+}
+
+// Align snaps open times onto the timeframe grid, sets CloseTime and de-duplicates bars
+func (m *Merger) Align(klines []shared.KlineData) []shared.KlineData {
+	byTime := make(map[int64]shared.KlineData, len(klines))
+	for _, k := range klines {
+		k.OpenTime = shared.TimeframeBucketStart(k.OpenTime, m.intervalMs)
+		k.CloseTime = shared.TimeframeBucketEnd(k.OpenTime, m.intervalMs)
+		byTime[k.OpenTime] = k // Last occurrence wins (most recent update of a bar)
+	}
+
+	aligned := make([]shared.KlineData, 0, len(byTime))
+	for _, k := range byTime {
+		aligned = append(aligned, k)
+	}
+	sort.Slice(aligned, func(i, j int) bool {
+		return aligned[i].OpenTime < aligned[j].OpenTime
+	})
+	return aligned
+}
+
+// Merge aligns every source, validates each bar against the cross-source median and
+// produces the merged series following the priority policy
+func (m *Merger) Merge(sources map[string][]shared.KlineData) (*MergeResult, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("sources cannot be empty")
+	}
+
+	// Align each source and index by open time
+	indexed := make(map[string]map[int64]shared.KlineData, len(sources))
+	timeSet := make(map[int64]struct{})
+	for name, klines := range sources {
+		bars := make(map[int64]shared.KlineData)
+		for _, k := range m.Align(klines) {
+			bars[k.OpenTime] = k
+			timeSet[k.OpenTime] = struct{}{}
+		}
+		indexed[name] = bars
+	}
+
+	times := make([]int64, 0, len(timeSet))
+	for ts := range timeSet {
+		times = append(times, ts)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	order := m.sourceOrder(sources)
+	result := &MergeResult{
+		Klines: make([]MergedKline, 0, len(times)),
+		Stats:  make(map[string]*SourceStats, len(order)),
+	}
+	for _, name := range order {
+		result.Stats[name] = &SourceStats{}
+	}
+	now := m.now().UnixMilli()
+
+	for _, ts := range times {
+		merged := m.mergeBar(ts, order, indexed)
+		if merged == nil {
+			continue
+		}
+
+		if merged.Flagged {
+			result.FlaggedBars++
+		}
+
+		if n := len(result.Klines); n > 0 && m.config.Validation.MaxTimeGap > 0 {
+			prev := result.Klines[n-1]
+			if gap := merged.OpenTime - prev.CloseTime - 1; gap > m.config.Validation.MaxTimeGap {
+				result.Gaps = append(result.Gaps, TimeGap{From: prev.OpenTime, To: merged.OpenTime, GapMs: gap})
+			}
+		}
+
+		for _, name := range order {
+			accumulateStats(result.Stats[name], name, merged)
+		}
+		// A forming bar is counted on a later merge, once closed
+		if ts > m.lastCounted && merged.CloseTime < now {
+			for _, name := range order {
+				if m.cumulative[name] == nil {
+					m.cumulative[name] = &SourceStats{}
+				}
+				accumulateStats(m.cumulative[name], name, merged)
+			}
+			m.lastCounted = ts
+		}
+
+		result.Klines = append(result.Klines, *merged)
+	}
+
+	for _, stats := range result.Stats {
+		finalizeStats(stats)
+	}
+
+	return result, nil
+}
+
+// CumulativeStats returns deviation statistics accumulated across all Merge calls
+// (bars already counted are not counted twice when windows overlap, forming bars are
+// only counted once closed)
+func (m *Merger) CumulativeStats() map[string]SourceStats {
+	out := make(map[string]SourceStats, len(m.cumulative))
+	for name, stats := range m.cumulative {
+		copyStats := *stats
+		finalizeStats(&copyStats)
+		out[name] = copyStats
+	}
+	return out
+}
+
+// mergeBar validates one timestamp across sources and selects the output bar
+func (m *Merger) mergeBar(ts int64, order []string, indexed map[string]map[int64]shared.KlineData) *MergedKline {
+	var present []string
+	var closes, volumes []float64
+	for _, name := range order {
+		if k, ok := indexed[name][ts]; ok {
+			present = append(present, name)
+			closes = append(closes, k.Close)
+			volumes = append(volumes, k.Volume)
+		}
+	}
+	if len(present) == 0 {
+		return nil
+	}
+
+	medianClose := median(closes)
+	medianVolume := median(volumes)
+
+	merged := &MergedKline{
+		Sources:    present,
+		Deviations: make(map[string]SourceDeviation, len(present)),
+	}
+
+	for _, name := range present {
+		k := indexed[name][ts]
+		dev := SourceDeviation{
+			Close:     k.Close,
+			Volume:    k.Volume,
+			ClosePct:  percentDeviation(k.Close, medianClose),
+			VolumePct: percentDeviation(k.Volume, medianVolume),
+		}
+		// A single source has nothing to be compared with
+		if len(present) > 1 {
+			if m.config.Validation.MaxPriceDeviation > 0 && math.Abs(dev.ClosePct) > m.config.Validation.MaxPriceDeviation {
+				dev.CloseFlagged = true
+			}
+			if m.config.Validation.MaxVolumeDeviation > 0 && math.Abs(dev.VolumePct) > m.config.Validation.MaxVolumeDeviation {
+				dev.VolumeFlagged = true
+			}
+		}
+		if dev.Flagged() {
+			merged.Flagged = true
+		}
+		merged.Deviations[name] = dev
+	}
+
+	// Priority selection, optionally skipping flagged sources
+	selected := present[0]
+	if m.config.SkipFlaggedSources {
+		for _, name := range present {
+			if !merged.Deviations[name].Flagged() {
+				selected = name
+				break
+			}
+		}
+	}
+
+	merged.KlineData = indexed[selected][ts]
+	merged.Source = selected
+	return merged
+}
+
+// sourceOrder returns source names in priority order, unknown sources last (alphabetically)
+func (m *Merger) sourceOrder(sources map[string][]shared.KlineData) []string {
+	var order []string
+	seen := make(map[string]bool)
+	for _, name := range m.config.Priority {
+		if _, ok := sources[name]; ok && !seen[name] {
+			order = append(order, name)
+			seen[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range sources {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(order, rest...)
+}
+
+// Helper functions
+
+// accumulateStats adds one merged bar to the statistics of a source
+func accumulateStats(stats *SourceStats, name string, merged *MergedKline) {
+	dev, ok := merged.Deviations[name]
+	if !ok {
+		stats.Missing++
+		return
+	}
+
+	stats.Bars++
+	if merged.Source == name {
+		stats.Selected++
+	}
+	if dev.CloseFlagged {
+		stats.CloseFlagged++
+	}
+	if dev.VolumeFlagged {
+		stats.VolumeFlagged++
+	}
+
+	absClose := math.Abs(dev.ClosePct)
+	absVol := math.Abs(dev.VolumePct)
+	stats.MeanAbsClosePct += absClose // Sum until finalizeStats
+	stats.MeanAbsVolPct += absVol
+	if absClose > stats.MaxAbsClosePct {
+		stats.MaxAbsClosePct = absClose
+	}
+	if absVol > stats.MaxAbsVolPct {
+		stats.MaxAbsVolPct = absVol
+	}
+}
+
+// finalizeStats converts accumulated sums into means
+func finalizeStats(stats *SourceStats) {
+	if stats.Bars > 0 {
+		stats.MeanAbsClosePct /= float64(stats.Bars)
+		stats.MeanAbsVolPct /= float64(stats.Bars)
+	}
+}
+
+// median returns the median of values without modifying them
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// percentDeviation returns (value - reference) / reference * 100, 0 when reference is 0
+func percentDeviation(value, reference float64) float64 {
+	if reference == 0 {
+		return 0
+	}
+	return (value - reference) / reference * 100
+}
@@ -0,0 +1,194 @@
+// Package multisource provides tests for the multi-source merger
+package multisource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// makeBar builds a 5m kline at index i from base time
+func makeBar(base int64, i int, closePrice, volume float64) shared.KlineData {
+	return shared.KlineData{
+		OpenTime: base + int64(i)*300000,
+		Open:     closePrice,
+		High:     closePrice,
+		Low:      closePrice,
+		Close:    closePrice,
+		Volume:   volume,
+	}
+}
+
+// Test NewMerger validation
+func TestNewMerger_Validation(t *testing.T) {
+	if _, err := NewMerger(MergerConfig{Priority: []string{"binance"}}); err == nil {
+		t.Error("Expected error for empty timeframe")
+	}
+	if _, err := NewMerger(MergerConfig{Timeframe: "bogus", Priority: []string{"binance"}}); err == nil {
+		t.Error("Expected error for invalid timeframe")
+	}
+	if _, err := NewMerger(MergerConfig{Timeframe: "5m"}); err == nil {
+		t.Error("Expected error for empty priority")
+	}
+}
+
+// Test alignment of second-based exchange timestamps onto the ms grid
+func TestMerger_Align(t *testing.T) {
+	merger, _ := NewMerger(MergerConfig{Timeframe: "5m", Priority: []string{"gateio"}})
+	base := int64(1700000100000) // 5m boundary
+
+	aligned := merger.Align([]shared.KlineData{
+		makeBar(base, 1, 101, 10),
+		{OpenTime: base + 250, Close: 100}, // Off-grid by 250ms
+		makeBar(base, 1, 102, 12),          // Duplicate update of bar 1
+	})
+
+	if len(aligned) != 2 {
+		t.Fatalf("Expected 2 aligned bars, got %d", len(aligned))
+	}
+	if aligned[0].OpenTime != base || aligned[0].CloseTime != base+299999 {
+		t.Errorf("Unexpected alignment: %d-%d", aligned[0].OpenTime, aligned[0].CloseTime)
+	}
+	if aligned[1].Close != 102 {
+		t.Errorf("Expected last update to win, got close %.2f", aligned[1].Close)
+	}
+}
+
+// Test priority merge, deviation flags, fallback and provenance
+func TestMerger_Merge(t *testing.T) {
+	base := int64(1700000100000)
+	merger, err := NewMerger(MergerConfig{
+		Timeframe: "5m",
+		Priority:  []string{"binance", "bybit", "gateio"},
+		Validation: shared.ValidationConfig{
+			MaxPriceDeviation:  0.5,
+			MaxVolumeDeviation: 50,
+			MaxTimeGap:         60000,
+		},
+		SkipFlaggedSources: true,
+	})
+	if err != nil {
+		t.Fatalf("NewMerger failed: %v", err)
+	}
+
+	sources := map[string][]shared.KlineData{
+		"binance": {makeBar(base, 0, 100, 1000), makeBar(base, 1, 110, 1000), makeBar(base, 4, 100, 1000)},
+		"bybit":   {makeBar(base, 0, 100.1, 900), makeBar(base, 1, 100.2, 950), makeBar(base, 2, 100, 800)},
+		"gateio":  {makeBar(base, 0, 99.9, 1100), makeBar(base, 1, 100.1, 1000), makeBar(base, 2, 100.05, 5000)},
+	}
+
+	result, err := merger.Merge(sources)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	if len(result.Klines) != 4 {
+		t.Fatalf("Expected 4 merged bars, got %d", len(result.Klines))
+	}
+
+	// Bar 0: all agree, binance has priority
+	if result.Klines[0].Source != "binance" || result.Klines[0].Flagged {
+		t.Errorf("Bar 0: expected clean binance bar, got %s flagged=%v", result.Klines[0].Source, result.Klines[0].Flagged)
+	}
+	// Bar 1: binance close is off by ~10% -> flagged, fallback to bybit
+	if !result.Klines[1].Deviations["binance"].CloseFlagged {
+		t.Error("Bar 1: expected binance close to be flagged")
+	}
+	if result.Klines[1].Source != "bybit" || result.Klines[1].Close != 100.2 {
+		t.Errorf("Bar 1: expected fallback to bybit, got %s close %.2f", result.Klines[1].Source, result.Klines[1].Close)
+	}
+	// Bar 2: binance missing, gateio volume flagged, bybit selected
+	if result.Klines[2].Source != "bybit" || !result.Klines[2].Deviations["gateio"].VolumeFlagged {
+		t.Errorf("Bar 2: unexpected merge %+v", result.Klines[2])
+	}
+	if len(result.Klines[2].Sources) != 2 {
+		t.Errorf("Bar 2: expected 2 sources, got %v", result.Klines[2].Sources)
+	}
+	// Bar 4 only in binance: single source never flagged, gap reported
+	if result.Klines[3].Flagged || result.Klines[3].Source != "binance" {
+		t.Errorf("Bar 4: unexpected merge %+v", result.Klines[3])
+	}
+	if len(result.Gaps) != 1 || result.Gaps[0].GapMs != 300000 {
+		t.Errorf("Expected one 5m gap, got %+v", result.Gaps)
+	}
+
+	if result.FlaggedBars != 2 {
+		t.Errorf("Expected 2 flagged bars, got %d", result.FlaggedBars)
+	}
+	if stats := result.Stats["binance"]; stats.Missing != 1 || stats.CloseFlagged != 1 || stats.Selected != 2 {
+		t.Errorf("Unexpected binance stats: %+v", stats)
+	}
+
+	// Overlapping second merge must not double count cumulative stats
+	if _, err := merger.Merge(sources); err != nil {
+		t.Fatalf("Second merge failed: %v", err)
+	}
+	if cumulative := merger.CumulativeStats()["binance"]; cumulative.Bars != 3 {
+		t.Errorf("Expected 3 cumulative binance bars, got %d", cumulative.Bars)
+	}
+}
+
+// Test the forming bar is left out of cumulative stats until it is closed
+func TestMerger_CumulativeSkipsFormingBar(t *testing.T) {
+	base := int64(1700000100000)
+	merger, err := NewMerger(MergerConfig{Timeframe: "5m", Priority: []string{"binance", "bybit"}})
+	if err != nil {
+		t.Fatalf("NewMerger failed: %v", err)
+	}
+	now := time.UnixMilli(base + 2*300000 + 1000) // Bar 2 forming
+	merger.now = func() time.Time { return now }
+
+	sources := map[string][]shared.KlineData{
+		"binance": {makeBar(base, 0, 100, 1000), makeBar(base, 1, 100, 1000), makeBar(base, 2, 100, 1000)},
+		"bybit":   {makeBar(base, 0, 100, 1000), makeBar(base, 1, 100, 1000), makeBar(base, 2, 101, 1000)},
+	}
+	if _, err := merger.Merge(sources); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if cumulative := merger.CumulativeStats()["binance"]; cumulative.Bars != 2 {
+		t.Errorf("Expected forming bar left out, got %d cumulative bars", cumulative.Bars)
+	}
+
+	// Bar 2 closes with its final values, bar 3 is forming
+	now = time.UnixMilli(base + 3*300000 + 1000)
+	sources["bybit"][2] = makeBar(base, 2, 100, 1000)
+	sources["binance"] = append(sources["binance"], makeBar(base, 3, 100, 1000))
+	if _, err := merger.Merge(sources); err != nil {
+		t.Fatalf("Second merge failed: %v", err)
+	}
+	cumulative := merger.CumulativeStats()["binance"]
+	if cumulative.Bars != 3 || cumulative.MaxAbsClosePct != 0 {
+		t.Errorf("Expected closed bar 2 counted with final values, got %+v", cumulative)
+	}
+}
+
+// Test FetchAll with static sources and priority ordering of unknown sources
+func TestFetchAll_StaticSources(t *testing.T) {
+	base := int64(1700000100000)
+	sources := []KlineSource{
+		NewStaticSource("vision", []shared.KlineData{makeBar(base, 0, 100, 1), makeBar(base, 1, 101, 1)}),
+		NewStaticSource("other", []shared.KlineData{makeBar(base, 1, 101, 1)}),
+	}
+
+	data, errs := FetchAll(context.Background(), sources, "5m", 1)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if len(data["vision"]) != 1 || data["vision"][0].Close != 101 {
+		t.Errorf("Expected limit to keep the last bar, got %+v", data["vision"])
+	}
+
+	merger, _ := NewMerger(MergerConfig{Timeframe: "5m", Priority: []string{"vision"}})
+	result, err := merger.Merge(data)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(result.Klines) != 1 || result.Klines[0].Source != "vision" {
+		t.Errorf("Expected vision to win, got %+v", result.Klines)
+	}
+	if _, err := merger.Merge(nil); err == nil {
+		t.Error("Expected error for empty sources")
+	}
+}
@@ -0,0 +1,149 @@
+// Package multisource ingests the same symbol from several exchanges, aligns and merges the klines
+package multisource
+
+import (
+	"context"
+	"time"
+
+	"agent-economique/internal/datasource/binance"
+	"agent-economique/internal/datasource/bybit"
+	"agent-economique/internal/datasource/gateio"
+	"agent-economique/internal/datasource/kucoin"
+	"agent-economique/internal/shared"
+)
+
+// KlineSource fetches klines for one symbol from one exchange
+type KlineSource interface {
+	Name() string
+	FetchKlines(ctx context.Context, interval string, limit int) ([]shared.KlineData, error)
+}
+
+// NewBinanceFuturesSource creates a source for Binance USDT-M futures ("SOLUSDT")
+func NewBinanceFuturesSource(client *binance.FuturesClient, symbol string) KlineSource {
+	return &funcSource{name: "binance", fetch: func(ctx context.Context, interval string, limit int) ([]shared.KlineData, error) {
+		klines, err := client.GetKlines(ctx, symbol, interval, limit)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]shared.KlineData, len(klines))
+		for i, k := range klines {
+			result[i] = toKlineData(k.OpenTime, k.Open, k.High, k.Low, k.Close, k.Volume)
+			result[i].QuoteAssetVolume = k.QuoteAssetVolume
+		}
+		return result, nil
+	}}
+}
+
+// NewGateIOSource creates a source for Gate.io USDT futures ("SOL_USDT")
+func NewGateIOSource(client *gateio.Client, symbol string) KlineSource {
+	return &funcSource{name: "gateio", fetch: func(ctx context.Context, interval string, limit int) ([]shared.KlineData, error) {
+		klines, err := client.GetKlines(ctx, symbol, interval, limit)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]shared.KlineData, len(klines))
+		for i, k := range klines {
+			result[i] = toKlineData(k.OpenTime, k.Open, k.High, k.Low, k.Close, k.Volume)
+		}
+		return result, nil
+	}}
+}
+
+// NewBybitSource creates a source for Bybit linear perpetuals ("SOLUSDT")
+func NewBybitSource(client *bybit.Client, symbol string) KlineSource {
+	return &funcSource{name: "bybit", fetch: func(ctx context.Context, interval string, limit int) ([]shared.KlineData, error) {
+		klines, err := client.GetKlines(ctx, symbol, interval, limit)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]shared.KlineData, len(klines))
+		for i, k := range klines {
+			result[i] = toKlineData(k.OpenTime, k.Open, k.High, k.Low, k.Close, k.Volume)
+		}
+		return result, nil
+	}}
+}
+
+// NewKucoinSource creates a source for KuCoin spot ("SOL-USDT")
+func NewKucoinSource(client *kucoin.Client, symbol string) KlineSource {
+	return &funcSource{name: "kucoin", fetch: func(ctx context.Context, interval string, limit int) ([]shared.KlineData, error) {
+		klines, err := client.GetKlines(ctx, symbol, interval, limit)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]shared.KlineData, len(klines))
+		for i, k := range klines {
+			result[i] = toKlineData(k.OpenTime, k.Open, k.High, k.Low, k.Close, k.Volume)
+		}
+		return result, nil
+	}}
+}
+
+// NewStaticSource wraps already loaded klines (Vision files, tests) as a source
+func NewStaticSource(name string, klines []shared.KlineData) KlineSource {
+	return &funcSource{name: name, fetch: func(ctx context.Context, interval string, limit int) ([]shared.KlineData, error) {
+		if limit > 0 && len(klines) > limit {
+			return klines[len(klines)-limit:], nil
+		}
+		return klines, nil
+	}}
+}
+
+// FetchAll fetches klines from every source concurrently; failed sources are reported in errs
+func FetchAll(ctx context.Context, sources []KlineSource, interval string, limit int) (map[string][]shared.KlineData, map[string]error) {
+	type fetchResult struct {
+		name   string
+		klines []shared.KlineData
+		err    error
+	}
+
+	results := make(chan fetchResult, len(sources))
+	for _, source := range sources {
+		go func(s KlineSource) {
+			klines, err := s.FetchKlines(ctx, interval, limit)
+			results <- fetchResult{name: s.Name(), klines: klines, err: err}
+		}(source)
+	}
+
+	data := make(map[string][]shared.KlineData)
+	errs := make(map[string]error)
+	for range sources {
+		r := <-results
+		if r.err != nil {
+			errs[r.name] = r.err
+			continue
+		}
+		data[r.name] = r.klines
+	}
+
+	return data, errs
+}
+
+// funcSource adapts a fetch function to KlineSource
+type funcSource struct {
+	name  string
+	fetch func(ctx context.Context, interval string, limit int) ([]shared.KlineData, error)
+}
+
+// Name returns the source name used for priority and provenance
+func (s *funcSource) Name() string {
+	return s.name
+}
+
+// FetchKlines fetches klines from the underlying client
+func (s *funcSource) FetchKlines(ctx context.Context, interval string, limit int) ([]shared.KlineData, error) {
+	return s.fetch(ctx, interval, limit)
+}
+
+// toKlineData converts exchange OHLCV fields to shared.KlineData (CloseTime is set during alignment)
+func toKlineData(openTime time.Time, open, high, low, close, volume float64) shared.KlineData {
+	return shared.KlineData{
+		OpenTime: openTime.UnixMilli(),
+		Open:     open,
+		High:     high,
+		Low:      low,
+		Close:    close,
+		Volume:   volume,
+		Ignore:   "0",
+	}
+}
@@ -0,0 +1,248 @@
+// Package orderbook provides L2 depth recording, compact storage and replay
+package orderbook
+
+import (
+	"math"
+	"sort"
+)
+
+// Level is one price level of the book
+type Level struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// Snapshot is a full L2 depth snapshot
+type Snapshot struct {
+	Symbol       string  `json:"symbol"`
+	Time         int64   `json:"time"` // Unix ms
+	LastUpdateID int64   `json:"last_update_id"`
+	Bids         []Level `json:"bids"` // Best (highest) first
+	Asks         []Level `json:"asks"` // Best (lowest) first
+}
+
+// Diff is an incremental depth update; a zero quantity removes the level
+type Diff struct {
+	Symbol            string  `json:"symbol"`
+	Time              int64   `json:"time"` // Unix ms (event time)
+	FirstUpdateID     int64   `json:"first_update_id"`
+	FinalUpdateID     int64   `json:"final_update_id"`
+	PrevFinalUpdateID int64   `json:"prev_final_update_id"` // 0 when the exchange does not provide it
+	Bids              []Level `json:"bids"`
+	Asks              []Level `json:"asks"`
+}
+
+// Book is an in-memory L2 order book kept sorted on both sides
+type Book struct {
+	Time         int64
+	LastUpdateID int64
+	bids         []Level // Descending price
+	asks         []Level // Ascending price
+}
+
+// NewBook creates an empty book
+func NewBook() *Book {
+	return &Book{}
+}
+
+// ApplySnapshot replaces the book content with a snapshot
+func (b *Book) ApplySnapshot(s *Snapshot) {
+	b.Time = s.Time
+	b.LastUpdateID = s.LastUpdateID
+	b.bids = b.bids[:0]
+	b.asks = b.asks[:0]
+	for _, l := range s.Bids {
+		b.setLevel(true, l)
+	}
+	for _, l := range s.Asks {
+		b.setLevel(false, l)
+	}
+}
+
+// ApplyDiff applies an incremental update (sequence checks are the caller's job)
+func (b *Book) ApplyDiff(d *Diff) {
+	for _, l := range d.Bids {
+		b.setLevel(true, l)
+	}
+	for _, l := range d.Asks {
+		b.setLevel(false, l)
+	}
+	b.Time = d.Time
+	b.LastUpdateID = d.FinalUpdateID
+}
+
+// Snapshot returns a copy of the book limited to maxLevels per side (0 = all)
+func (b *Book) Snapshot(symbol string, maxLevels int) *Snapshot {
+	return &Snapshot{
+		Symbol:       symbol,
+		Time:         b.Time,
+		LastUpdateID: b.LastUpdateID,
+		Bids:         copyLevels(b.bids, maxLevels),
+		Asks:         copyLevels(b.asks, maxLevels),
+	}
+}
+
+// Bids returns up to n best bids (0 = all); the slice must not be modified
+func (b *Book) Bids(n int) []Level {
+	if n <= 0 || n > len(b.bids) {
+		return b.bids
+	}
+	return b.bids[:n]
+}
+
+// Asks returns up to n best asks (0 = all); the slice must not be modified
+func (b *Book) Asks(n int) []Level {
+	if n <= 0 || n > len(b.asks) {
+		return b.asks
+	}
+	return b.asks[:n]
+}
+
+// BestBid returns the best bid level
+func (b *Book) BestBid() (Level, bool) {
+	if len(b.bids) == 0 {
+		return Level{}, false
+	}
+	return b.bids[0], true
+}
+
+// BestAsk returns the best ask level
+func (b *Book) BestAsk() (Level, bool) {
+	if len(b.asks) == 0 {
+		return Level{}, false
+	}
+	return b.asks[0], true
+}
+
+// MidPrice returns (best bid + best ask) / 2
+func (b *Book) MidPrice() (float64, bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return (bid.Price + ask.Price) / 2, true
+}
+
+// Spread returns best ask - best bid
+func (b *Book) Spread() (float64, bool) {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0, false
+	}
+	return ask.Price - bid.Price, true
+}
+
+// SpreadBps returns the spread in basis points of the mid price
+func (b *Book) SpreadBps() (float64, bool) {
+	spread, ok := b.Spread()
+	mid, okMid := b.MidPrice()
+	if !ok || !okMid || mid == 0 {
+		return 0, false
+	}
+	return spread / mid * 10000, true
+}
+
+// DepthWithin returns the base quantity resting within pct percent of the mid price on each side
+func (b *Book) DepthWithin(pct float64) (bidQty, askQty float64) {
+	mid, ok := b.MidPrice()
+	if !ok {
+		return 0, 0
+	}
+	minBid := mid * (1 - pct/100)
+	maxAsk := mid * (1 + pct/100)
+	for _, l := range b.bids {
+		if l.Price < minBid {
+			break
+		}
+		bidQty += l.Quantity
+	}
+	for _, l := range b.asks {
+		if l.Price > maxAsk {
+			break
+		}
+		askQty += l.Quantity
+	}
+	return bidQty, askQty
+}
+
+// Imbalance returns (bidQty - askQty) / (bidQty + askQty) over the n best levels, in [-1, 1]
+func (b *Book) Imbalance(n int) float64 {
+	var bidQty, askQty float64
+	for _, l := range b.Bids(n) {
+		bidQty += l.Quantity
+	}
+	for _, l := range b.Asks(n) {
+		askQty += l.Quantity
+	}
+	if bidQty+askQty == 0 {
+		return 0
+	}
+	return (bidQty - askQty) / (bidQty + askQty)
+}
+
+// EstimateFill walks the book for a market order of quantity and returns the average fill
+// price and the slippage versus mid in basis points. filled < quantity when the book is too thin.
+func (b *Book) EstimateFill(buy bool, quantity float64) (avgPrice, slippageBps, filled float64) {
+	levels := b.bids
+	if buy {
+		levels = b.asks
+	}
+
+	var notional float64
+	for _, l := range levels {
+		take := math.Min(l.Quantity, quantity-filled)
+		notional += take * l.Price
+		filled += take
+		if filled >= quantity {
+			break
+		}
+	}
+	if filled == 0 {
+		return 0, 0, 0
+	}
+
+	avgPrice = notional / filled
+	if mid, ok := b.MidPrice(); ok && mid > 0 {
+		slippageBps = math.Abs(avgPrice-mid) / mid * 10000
+	}
+	return avgPrice, slippageBps, filled
+}
+
+// setLevel inserts, updates or removes (quantity 0) a price level keeping the side sorted
+func (b *Book) setLevel(bid bool, l Level) {
+	side := &b.asks
+	less := func(i int) bool { return (*side)[i].Price >= l.Price }
+	if bid {
+		side = &b.bids
+		less = func(i int) bool { return (*side)[i].Price <= l.Price }
+	}
+
+	i := sort.Search(len(*side), less)
+	exists := i < len(*side) && (*side)[i].Price == l.Price
+
+	switch {
+	case l.Quantity == 0 && exists:
+		*side = append((*side)[:i], (*side)[i+1:]...)
+	case l.Quantity == 0:
+		// Removing an unknown level is a no-op
+	case exists:
+		(*side)[i].Quantity = l.Quantity
+	default:
+		*side = append(*side, Level{})
+		copy((*side)[i+1:], (*side)[i:])
+		(*side)[i] = l
+	}
+}
+
+// copyLevels copies up to maxLevels levels (0 = all)
+func copyLevels(levels []Level, maxLevels int) []Level {
+	n := len(levels)
+	if maxLevels > 0 && maxLevels < n {
+		n = maxLevels
+	}
+	out := make([]Level, n)
+	copy(out, levels[:n])
+	return out
+}
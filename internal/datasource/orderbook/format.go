@@ -0,0 +1,275 @@
+// Package orderbook provides the compact depth file format
+package orderbook
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// Depth file layout
+//
+// A 16 bytes header (magic "AEOB" | version uint16 | reserved) followed by
+// variable-size little-endian records:
+//
+//	kind uint8 | reserved uint8 | nBids uint16 | nAsks uint16 | reserved uint16 |
+//	time int64 | firstUpdateID int64 | finalUpdateID int64 | prevFinalUpdateID int64 |
+//	(price float64, quantity float64) x (nBids + nAsks)
+//
+// Snapshots store LastUpdateID in finalUpdateID. Files are append-only: a
+// truncated trailing record (crash during write) is ignored and overwritten.
+const (
+	depthHeaderSize       = 16
+	depthVersion          = 1
+	depthRecordHeaderSize = 40
+	depthLevelSize        = 16
+	depthFileExt          = ".depth"
+	depthWriterBuffer     = 64 * 1024
+
+	recordSnapshot = 1
+	recordDiff     = 2
+)
+
+var depthMagic = [4]byte{'A', 'E', 'O', 'B'}
+
+// record is one decoded depth record
+type record struct {
+	kind              uint8
+	time              int64
+	firstUpdateID     int64
+	finalUpdateID     int64
+	prevFinalUpdateID int64
+	bids              []Level
+	asks              []Level
+}
+
+// DepthFilePath returns the path of the depth file of symbol for the UTC day of timestamp (ms)
+func DepthFilePath(rootDir, symbol string, timestamp int64) string {
+	day := unixMilliUTC(timestamp).Format("2006-01-02")
+	return filepath.Join(rootDir, symbol, day+depthFileExt)
+}
+
+// DepthWriter appends snapshot and diff records to a depth file
+type DepthWriter struct {
+	file    *os.File
+	writer  *bufio.Writer
+	path    string
+	records int64
+}
+
+// OpenDepthWriter opens (or creates) a depth file for appending
+func OpenDepthWriter(path string) (*DepthWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create depth directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open depth file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat depth file: %w", err)
+	}
+
+	var records int64
+	end := int64(depthHeaderSize)
+	if info.Size() == 0 {
+		header := make([]byte, depthHeaderSize)
+		copy(header[0:4], depthMagic[:])
+		binary.LittleEndian.PutUint16(header[4:6], depthVersion)
+		if _, err := file.Write(header); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to write depth header: %w", err)
+		}
+	} else {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read depth file: %w", err)
+		}
+		if err := checkDepthHeader(data); err != nil {
+			file.Close()
+			return nil, err
+		}
+		// Find the end of the last complete record and drop any partial one
+		err = scanRecords(data, func(offset int, _ record) error {
+			records++
+			return nil
+		}, func(next int) { end = int64(next) })
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if err := file.Truncate(end); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to truncate partial record: %w", err)
+		}
+	}
+
+	if _, err := file.Seek(end, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to seek depth file: %w", err)
+	}
+
+	return &DepthWriter{
+		file:    file,
+		writer:  bufio.NewWriterSize(file, depthWriterBuffer),
+		path:    path,
+		records: records,
+	}, nil
+}
+
+// WriteSnapshot appends a snapshot record
+func (w *DepthWriter) WriteSnapshot(s *Snapshot) error {
+	return w.write(record{
+		kind:          recordSnapshot,
+		time:          s.Time,
+		finalUpdateID: s.LastUpdateID,
+		bids:          s.Bids,
+		asks:          s.Asks,
+	})
+}
+
+// WriteDiff appends a diff record
+func (w *DepthWriter) WriteDiff(d *Diff) error {
+	return w.write(record{
+		kind:              recordDiff,
+		time:              d.Time,
+		firstUpdateID:     d.FirstUpdateID,
+		finalUpdateID:     d.FinalUpdateID,
+		prevFinalUpdateID: d.PrevFinalUpdateID,
+		bids:              d.Bids,
+		asks:              d.Asks,
+	})
+}
+
+// Records returns the number of records in the file
+func (w *DepthWriter) Records() int64 {
+	return w.records
+}
+
+// Path returns the file path
+func (w *DepthWriter) Path() string {
+	return w.path
+}
+
+// Flush writes buffered records to disk
+func (w *DepthWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// Close flushes and closes the file
+func (w *DepthWriter) Close() error {
+	if err := w.writer.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to flush depth file: %w", err)
+	}
+	return w.file.Close()
+}
+
+// write encodes one record
+func (w *DepthWriter) write(r record) error {
+	if len(r.bids) > math.MaxUint16 || len(r.asks) > math.MaxUint16 {
+		return fmt.Errorf("too many levels: %d bids, %d asks", len(r.bids), len(r.asks))
+	}
+
+	buf := make([]byte, depthRecordHeaderSize+depthLevelSize*(len(r.bids)+len(r.asks)))
+	buf[0] = r.kind
+	binary.LittleEndian.PutUint16(buf[2:4], uint16(len(r.bids)))
+	binary.LittleEndian.PutUint16(buf[4:6], uint16(len(r.asks)))
+	binary.LittleEndian.PutUint64(buf[8:16], uint64(r.time))
+	binary.LittleEndian.PutUint64(buf[16:24], uint64(r.firstUpdateID))
+	binary.LittleEndian.PutUint64(buf[24:32], uint64(r.finalUpdateID))
+	binary.LittleEndian.PutUint64(buf[32:40], uint64(r.prevFinalUpdateID))
+
+	offset := depthRecordHeaderSize
+	for _, levels := range [][]Level{r.bids, r.asks} {
+		for _, l := range levels {
+			binary.LittleEndian.PutUint64(buf[offset:], math.Float64bits(l.Price))
+			binary.LittleEndian.PutUint64(buf[offset+8:], math.Float64bits(l.Quantity))
+			offset += depthLevelSize
+		}
+	}
+
+	if _, err := w.writer.Write(buf); err != nil {
+		return fmt.Errorf("failed to write depth record: %w", err)
+	}
+	w.records++
+	return nil
+}
+
+// checkDepthHeader validates the file header
+func checkDepthHeader(data []byte) error {
+	if len(data) < depthHeaderSize {
+		return fmt.Errorf("depth file too short: %d bytes", len(data))
+	}
+	if [4]byte(data[0:4]) != depthMagic {
+		return fmt.Errorf("invalid depth file magic: %q", data[0:4])
+	}
+	if version := binary.LittleEndian.Uint16(data[4:6]); version != depthVersion {
+		return fmt.Errorf("unsupported depth file version: %d", version)
+	}
+	return nil
+}
+
+// scanRecords decodes every complete record after the header; end receives the offset
+// following the last complete record
+func scanRecords(data []byte, fn func(offset int, r record) error, end func(next int)) error {
+	offset := depthHeaderSize
+	for {
+		r, next, ok := decodeRecord(data, offset)
+		if !ok {
+			break
+		}
+		if err := fn(offset, r); err != nil {
+			return err
+		}
+		offset = next
+	}
+	if end != nil {
+		end(offset)
+	}
+	return nil
+}
+
+// decodeRecord decodes the record at offset; ok is false on a truncated or invalid record
+func decodeRecord(data []byte, offset int) (r record, next int, ok bool) {
+	if offset+depthRecordHeaderSize > len(data) {
+		return r, offset, false
+	}
+	h := data[offset:]
+	r.kind = h[0]
+	if r.kind != recordSnapshot && r.kind != recordDiff {
+		return r, offset, false
+	}
+	nBids := int(binary.LittleEndian.Uint16(h[2:4]))
+	nAsks := int(binary.LittleEndian.Uint16(h[4:6]))
+	size := depthRecordHeaderSize + depthLevelSize*(nBids+nAsks)
+	if offset+size > len(data) {
+		return r, offset, false
+	}
+
+	r.time = int64(binary.LittleEndian.Uint64(h[8:16]))
+	r.firstUpdateID = int64(binary.LittleEndian.Uint64(h[16:24]))
+	r.finalUpdateID = int64(binary.LittleEndian.Uint64(h[24:32]))
+	r.prevFinalUpdateID = int64(binary.LittleEndian.Uint64(h[32:40]))
+
+	levels := make([]Level, nBids+nAsks)
+	p := depthRecordHeaderSize
+	for i := range levels {
+		levels[i].Price = math.Float64frombits(binary.LittleEndian.Uint64(h[p:]))
+		levels[i].Quantity = math.Float64frombits(binary.LittleEndian.Uint64(h[p+8:]))
+		p += depthLevelSize
+	}
+	r.bids = levels[:nBids]
+	r.asks = levels[nBids:]
+
+	return r, offset + size, true
+}
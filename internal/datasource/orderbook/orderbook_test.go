@@ -0,0 +1,309 @@
+// Package orderbook provides tests for the depth recorder and replay
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testStart = int64(1704067200000) // 2024-01-01 00:00:00 UTC
+
+// newStandInServer serves a Binance-like REST depth snapshot and diff stream
+func newStandInServer(t *testing.T, events []string) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/fapi/v1/depth":
+			fmt.Fprintf(w, `{"lastUpdateId":100,"E":%d,"bids":[["100.0","1.0"],["99.0","2.0"]],"asks":[["101.0","1.0"],["102.0","3.0"]]}`, testStart)
+		case strings.HasPrefix(r.URL.Path, "/ws/solusdt@depth"):
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Errorf("upgrade failed: %v", err)
+				return
+			}
+			defer conn.Close()
+			for _, event := range events {
+				conn.WriteMessage(websocket.TextMessage, []byte(event))
+			}
+			// Keep the stream open until the client leaves
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// Test recording against a local REST/WebSocket stand-in then replaying the file
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	events := []string{
+		fmt.Sprintf(`{"e":"depthUpdate","E":%d,"s":"SOLUSDT","U":95,"u":99,"pu":94,"b":[["98.0","5.0"]],"a":[]}`, testStart-500),
+		fmt.Sprintf(`{"e":"depthUpdate","E":%d,"s":"SOLUSDT","U":98,"u":102,"pu":97,"b":[["100.0","0"]],"a":[]}`, testStart+1000),
+		fmt.Sprintf(`{"e":"depthUpdate","E":%d,"s":"SOLUSDT","U":103,"u":105,"pu":102,"b":[],"a":[["101.0","0.5"],["101.5","2.0"]]}`, testStart+2000),
+	}
+	server := newStandInServer(t, events)
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	source := NewBinanceDepthSource(server.URL, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+	recorder, err := NewRecorder(RecorderConfig{
+		Symbols:          []string{"solusdt"},
+		OutputDir:        outputDir,
+		DepthLimit:       100,
+		SnapshotInterval: time.Hour,
+	}, source)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		recorder.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for recorder.Stats()["SOLUSDT"].Diffs < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	stats := recorder.Stats()["SOLUSDT"]
+	if stats.Diffs != 2 || stats.Snapshots != 1 || stats.Resyncs != 0 {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+
+	replayer, err := NewReplayerForRange(outputDir, "SOLUSDT", time.UnixMilli(testStart), time.UnixMilli(testStart+3000))
+	if err != nil {
+		t.Fatalf("NewReplayerForRange failed: %v", err)
+	}
+
+	bid, ask, err := replayer.TopOfBook(testStart)
+	if err != nil || bid.Price != 100 || ask.Price != 101 || ask.Quantity != 1 {
+		t.Errorf("At snapshot: got bid %+v ask %+v err %v", bid, ask, err)
+	}
+
+	bid, _, _ = replayer.TopOfBook(testStart + 1500)
+	if bid.Price != 99 {
+		t.Errorf("After first diff: expected best bid 99, got %+v", bid)
+	}
+
+	_, ask, _ = replayer.TopOfBook(testStart + 5000)
+	if ask.Price != 101 || ask.Quantity != 0.5 {
+		t.Errorf("After second diff: expected ask 101 x 0.5, got %+v", ask)
+	}
+	if spread, _ := replayer.Spread(testStart + 5000); spread != 2 {
+		t.Errorf("Expected spread 2, got %v", spread)
+	}
+
+	// Going back in time restarts from the snapshot
+	bid, _, _ = replayer.TopOfBook(testStart + 10)
+	if bid.Price != 100 {
+		t.Errorf("Back in time: expected best bid 100, got %+v", bid)
+	}
+
+	if _, err := replayer.BookAt(testStart - 1); err == nil {
+		t.Error("Expected error before first snapshot")
+	}
+}
+
+// sequenceSource replays fixed diffs without network
+type sequenceSource struct {
+	diffs []*Diff
+}
+
+func (s *sequenceSource) Name() string { return "sequence" }
+
+func (s *sequenceSource) FetchSnapshot(ctx context.Context, symbol string, limit int) (*Snapshot, error) {
+	return &Snapshot{Symbol: symbol, Time: testStart, LastUpdateID: 10, Bids: []Level{{Price: 10, Quantity: 1}}, Asks: []Level{{Price: 11, Quantity: 1}}}, nil
+}
+
+func (s *sequenceSource) StreamDiffs(ctx context.Context, symbol string, handler func(*Diff) error) error {
+	for _, d := range s.diffs {
+		if err := handler(d); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Test a sequence gap triggers a resync with a fresh snapshot
+func TestRecorder_ResyncOnGap(t *testing.T) {
+	source := &sequenceSource{diffs: []*Diff{
+		{Time: testStart + 100, FirstUpdateID: 9, FinalUpdateID: 12, PrevFinalUpdateID: 8},
+		{Time: testStart + 200, FirstUpdateID: 20, FinalUpdateID: 21, PrevFinalUpdateID: 19}, // Gap
+	}}
+	recorder, err := NewRecorder(RecorderConfig{
+		Symbols:    []string{"TEST"},
+		OutputDir:  t.TempDir(),
+		RetryDelay: 10 * time.Millisecond,
+	}, source)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go recorder.Run(ctx)
+
+	for recorder.Stats()["TEST"].Resyncs < 2 && ctx.Err() == nil {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	stats := recorder.Stats()["TEST"]
+	if stats.Resyncs < 2 || !strings.Contains(stats.LastError, "gap") {
+		t.Errorf("Expected resyncs after gap, got %+v", stats)
+	}
+}
+
+// Test the first futures diff must contain the snapshot update ID (U <= lastUpdateId <= u)
+func TestRecorder_FuturesFirstDiff(t *testing.T) {
+	source := &sequenceSource{diffs: []*Diff{
+		{Time: testStart + 100, FirstUpdateID: 11, FinalUpdateID: 12, PrevFinalUpdateID: 10}, // Spot rule only
+	}}
+	recorder, err := NewRecorder(RecorderConfig{
+		Symbols:    []string{"TEST"},
+		OutputDir:  t.TempDir(),
+		RetryDelay: 10 * time.Millisecond,
+	}, source)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go recorder.Run(ctx)
+
+	for recorder.Stats()["TEST"].Resyncs < 1 && ctx.Err() == nil {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	stats := recorder.Stats()["TEST"]
+	if stats.Diffs != 0 || !strings.Contains(stats.LastError, "does not bridge") {
+		t.Errorf("Expected first diff rejected, got %+v", stats)
+	}
+}
+
+// Test book maintenance, depth, imbalance and fill estimation
+func TestBook_Queries(t *testing.T) {
+	book := NewBook()
+	book.ApplySnapshot(&Snapshot{
+		Bids: []Level{{Price: 99, Quantity: 2}, {Price: 100, Quantity: 1}, {Price: 90, Quantity: 10}},
+		Asks: []Level{{Price: 102, Quantity: 3}, {Price: 101, Quantity: 1}},
+	})
+
+	if bid, _ := book.BestBid(); bid.Price != 100 {
+		t.Errorf("Expected best bid 100, got %+v", bid)
+	}
+	if mid, _ := book.MidPrice(); mid != 100.5 {
+		t.Errorf("Expected mid 100.5, got %v", mid)
+	}
+
+	bidQty, askQty := book.DepthWithin(2)
+	if bidQty != 3 || askQty != 4 {
+		t.Errorf("Expected depth 3/4 within 2%%, got %v/%v", bidQty, askQty)
+	}
+	if imb := book.Imbalance(2); imb != (3.0-4.0)/7.0 {
+		t.Errorf("Unexpected imbalance %v", imb)
+	}
+
+	avg, slippage, filled := book.EstimateFill(true, 2)
+	if filled != 2 || avg != 101.5 || slippage <= 0 {
+		t.Errorf("Unexpected fill: avg %v slippage %v filled %v", avg, slippage, filled)
+	}
+
+	book.ApplyDiff(&Diff{FinalUpdateID: 5, Bids: []Level{{Price: 100, Quantity: 0}, {Price: 99.5, Quantity: 4}}})
+	if bid, _ := book.BestBid(); bid.Price != 99.5 || bid.Quantity != 4 {
+		t.Errorf("Expected best bid 99.5 x 4 after diff, got %+v", bid)
+	}
+	if len(book.Bids(0)) != 3 {
+		t.Errorf("Expected 3 bid levels, got %d", len(book.Bids(0)))
+	}
+}
+
+// Test append-only writer recovery from a truncated trailing record
+func TestDepthWriter_Recover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TEST", "2024-01-01.depth")
+
+	writer, err := OpenDepthWriter(path)
+	if err != nil {
+		t.Fatalf("OpenDepthWriter failed: %v", err)
+	}
+	writer.WriteSnapshot(&Snapshot{Time: testStart, LastUpdateID: 1, Bids: []Level{{Price: 1, Quantity: 1}}})
+	writer.WriteDiff(&Diff{Time: testStart + 1, FirstUpdateID: 2, FinalUpdateID: 2, Asks: []Level{{Price: 2, Quantity: 1}}})
+	writer.Close()
+
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	file.Write([]byte{recordDiff, 0, 1, 0, 0, 0, 0, 0, 1, 2, 3})
+	file.Close()
+
+	writer, err = OpenDepthWriter(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if writer.Records() != 2 {
+		t.Errorf("Expected 2 records, got %d", writer.Records())
+	}
+	writer.WriteDiff(&Diff{Time: testStart + 2, FirstUpdateID: 3, FinalUpdateID: 3, Asks: []Level{{Price: 2, Quantity: 0}}})
+	writer.Close()
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	book, _ := replayer.BookAt(testStart + 2)
+	if len(book.Asks(0)) != 0 || book.LastUpdateID != 3 {
+		t.Errorf("Expected empty asks at update 3, got %+v (id %d)", book.Asks(0), book.LastUpdateID)
+	}
+}
+
+// Test a bridging diff with an event time before its snapshot is replayed after it
+func TestReplayer_BridgingDiffBeforeSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TEST", "2024-01-01.depth")
+
+	writer, err := OpenDepthWriter(path)
+	if err != nil {
+		t.Fatalf("OpenDepthWriter failed: %v", err)
+	}
+	writer.WriteSnapshot(&Snapshot{Time: testStart, LastUpdateID: 100, Bids: []Level{{Price: 100, Quantity: 1}}, Asks: []Level{{Price: 101, Quantity: 1}}})
+	writer.WriteDiff(&Diff{Time: testStart - 50, FirstUpdateID: 98, FinalUpdateID: 102, PrevFinalUpdateID: 97, Bids: []Level{{Price: 100, Quantity: 3}}})
+	writer.WriteDiff(&Diff{Time: testStart + 100, FirstUpdateID: 103, FinalUpdateID: 104, PrevFinalUpdateID: 102, Asks: []Level{{Price: 101, Quantity: 2}}})
+	writer.Close()
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+
+	book, err := replayer.BookAt(testStart)
+	if err != nil {
+		t.Fatalf("BookAt failed: %v", err)
+	}
+	if bid, _ := book.BestBid(); bid.Quantity != 3 || book.LastUpdateID != 102 {
+		t.Errorf("Expected bridging diff applied at snapshot time, got bid %+v (id %d)", bid, book.LastUpdateID)
+	}
+
+	book, _ = replayer.BookAt(testStart + 100)
+	if bid, _ := book.BestBid(); bid.Quantity != 3 || book.LastUpdateID != 104 {
+		t.Errorf("Expected bridging diff kept after the next diff, got bid %+v (id %d)", bid, book.LastUpdateID)
+	}
+}
@@ -0,0 +1,300 @@
+// Package orderbook provides the depth recorder (snapshots + diffs to depth files)
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errSequenceGap signals a missing diff; the recorder resynchronizes from a new snapshot
+var errSequenceGap = errors.New("depth sequence gap")
+
+// RecorderConfig holds configuration for the depth recorder
+type RecorderConfig struct {
+	Symbols          []string      `yaml:"symbols"`
+	OutputDir        string        `yaml:"output_dir"`
+	DepthLimit       int           `yaml:"depth_limit"`       // REST snapshot depth (default 1000)
+	SnapshotInterval time.Duration `yaml:"snapshot_interval"` // Periodic snapshot of the local book (default 1m)
+	SnapshotLevels   int           `yaml:"snapshot_levels"`   // Levels per side in periodic snapshots (0 = all)
+	RetryDelay       time.Duration `yaml:"retry_delay"`       // Delay before resync after an error (default 5s)
+	BufferSize       int           `yaml:"buffer_size"`       // Diff buffer while syncing (default 1000)
+}
+
+// RecorderStats holds per-symbol recording statistics
+type RecorderStats struct {
+	Snapshots     int64  `json:"snapshots"`
+	Diffs         int64  `json:"diffs"`
+	Resyncs       int64  `json:"resyncs"`
+	LastEventTime int64  `json:"last_event_time"`
+	CurrentFile   string `json:"current_file"`
+	LastError     string `json:"last_error,omitempty"`
+}
+
+// Recorder captures depth snapshots and diffs for configured symbols
+type Recorder struct {
+	config RecorderConfig
+	source DepthSource
+
+	mutex sync.RWMutex
+	stats map[string]*RecorderStats
+}
+
+// symbolRecorder holds the state owned by one symbol goroutine
+type symbolRecorder struct {
+	symbol       string
+	book         *Book
+	writer       *DepthWriter
+	lastSnapshot int64
+}
+
+// NewRecorder creates a new Recorder instance
+func NewRecorder(config RecorderConfig, source DepthSource) (*Recorder, error) {
+	if source == nil {
+		return nil, fmt.Errorf("depth source cannot be nil")
+	}
+	if len(config.Symbols) == 0 {
+		return nil, fmt.Errorf("symbols cannot be empty")
+	}
+	if config.OutputDir == "" {
+		return nil, fmt.Errorf("output directory cannot be empty")
+	}
+	if config.DepthLimit <= 0 {
+		config.DepthLimit = 1000
+	}
+	if config.SnapshotInterval <= 0 {
+		config.SnapshotInterval = time.Minute
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = 5 * time.Second
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1000
+	}
+
+	stats := make(map[string]*RecorderStats, len(config.Symbols))
+	for i, symbol := range config.Symbols {
+		config.Symbols[i] = strings.ToUpper(symbol)
+		stats[config.Symbols[i]] = &RecorderStats{}
+	}
+
+	return &Recorder{
+		config: config,
+		source: source,
+		stats:  stats,
+	}, nil
+}
+
+// Run records all symbols until ctx is cancelled
+func (r *Recorder) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, symbol := range r.config.Symbols {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			r.recordSymbol(ctx, symbol)
+		}(symbol)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// Stats returns a copy of the per-symbol statistics
+func (r *Recorder) Stats() map[string]RecorderStats {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	out := make(map[string]RecorderStats, len(r.stats))
+	for symbol, stats := range r.stats {
+		out[symbol] = *stats
+	}
+	return out
+}
+
+// recordSymbol runs sessions for one symbol, resynchronizing after each failure
+func (r *Recorder) recordSymbol(ctx context.Context, symbol string) {
+	state := &symbolRecorder{symbol: symbol, book: NewBook()}
+	defer func() {
+		if state.writer != nil {
+			state.writer.Close()
+		}
+	}()
+
+	for {
+		err := r.session(ctx, state)
+		if ctx.Err() != nil {
+			return
+		}
+
+		r.updateStats(symbol, func(s *RecorderStats) {
+			s.Resyncs++
+			if err != nil {
+				s.LastError = err.Error()
+			}
+		})
+		if state.writer != nil {
+			state.writer.Flush()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.config.RetryDelay):
+		}
+	}
+}
+
+// session follows the Binance futures sync procedure: buffer the stream, fetch a snapshot,
+// drop stale diffs, then apply diffs while checking update ID continuity (the first diff
+// must satisfy U <= lastUpdateId <= u, the next ones pu == previous u)
+func (r *Recorder) session(ctx context.Context, state *symbolRecorder) error {
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	diffs := make(chan *Diff, r.config.BufferSize)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- r.source.StreamDiffs(sessionCtx, state.symbol, func(d *Diff) error {
+			select {
+			case diffs <- d:
+				return nil
+			case <-sessionCtx.Done():
+				return sessionCtx.Err()
+			}
+		})
+	}()
+
+	// Wait for the stream to deliver before taking the snapshot
+	var first *Diff
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-streamErr:
+		return err
+	case first = <-diffs:
+	}
+
+	snapshot, err := r.source.FetchSnapshot(sessionCtx, state.symbol, r.config.DepthLimit)
+	if err != nil {
+		return err
+	}
+	state.book.ApplySnapshot(snapshot)
+	if err := r.writeSnapshot(state, snapshot); err != nil {
+		return err
+	}
+
+	synced := false
+	next := first
+	for {
+		if next == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-streamErr:
+				return err
+			case next = <-diffs:
+			}
+		}
+		d := next
+		next = nil
+
+		if d.FinalUpdateID < state.book.LastUpdateID {
+			continue // Already included in the snapshot
+		}
+		if !synced {
+			// Streams without pu follow the spot rule U <= lastUpdateId+1 <= u
+			first := state.book.LastUpdateID
+			if d.PrevFinalUpdateID == 0 {
+				first++
+			}
+			if d.FirstUpdateID > first || d.FinalUpdateID < first {
+				return fmt.Errorf("%w: first diff %d-%d does not bridge snapshot %d", errSequenceGap, d.FirstUpdateID, d.FinalUpdateID, state.book.LastUpdateID)
+			}
+			synced = true
+		} else if (d.PrevFinalUpdateID != 0 && d.PrevFinalUpdateID != state.book.LastUpdateID) ||
+			(d.PrevFinalUpdateID == 0 && d.FirstUpdateID != state.book.LastUpdateID+1) {
+			return fmt.Errorf("%w: diff %d-%d after %d", errSequenceGap, d.FirstUpdateID, d.FinalUpdateID, state.book.LastUpdateID)
+		}
+
+		if err := r.writeDiff(state, d); err != nil {
+			return err
+		}
+	}
+}
+
+// writeSnapshot writes a snapshot, rotating the daily file if needed
+func (r *Recorder) writeSnapshot(state *symbolRecorder, snapshot *Snapshot) error {
+	if err := r.rotate(state, snapshot.Time, false); err != nil {
+		return err
+	}
+	if err := state.writer.WriteSnapshot(snapshot); err != nil {
+		return err
+	}
+	state.lastSnapshot = snapshot.Time
+
+	r.updateStats(state.symbol, func(s *RecorderStats) {
+		s.Snapshots++
+		s.LastEventTime = snapshot.Time
+	})
+	return state.writer.Flush()
+}
+
+// writeDiff writes and applies a diff, adding periodic snapshots of the local book
+func (r *Recorder) writeDiff(state *symbolRecorder, d *Diff) error {
+	if err := r.rotate(state, d.Time, true); err != nil {
+		return err
+	}
+	if err := state.writer.WriteDiff(d); err != nil {
+		return err
+	}
+	state.book.ApplyDiff(d)
+
+	r.updateStats(state.symbol, func(s *RecorderStats) {
+		s.Diffs++
+		s.LastEventTime = d.Time
+	})
+
+	if d.Time-state.lastSnapshot >= r.config.SnapshotInterval.Milliseconds() {
+		return r.writeSnapshot(state, state.book.Snapshot(state.symbol, r.config.SnapshotLevels))
+	}
+	return nil
+}
+
+// rotate opens the daily file for timestamp; a new file starts with a snapshot of the
+// current book so that each file can be replayed on its own
+func (r *Recorder) rotate(state *symbolRecorder, timestamp int64, withSnapshot bool) error {
+	path := DepthFilePath(r.config.OutputDir, state.symbol, timestamp)
+	if state.writer != nil && state.writer.Path() == path {
+		return nil
+	}
+
+	if state.writer != nil {
+		if err := state.writer.Close(); err != nil {
+			return err
+		}
+	}
+
+	writer, err := OpenDepthWriter(path)
+	if err != nil {
+		return err
+	}
+	state.writer = writer
+	r.updateStats(state.symbol, func(s *RecorderStats) { s.CurrentFile = path })
+
+	if withSnapshot {
+		if err := writer.WriteSnapshot(state.book.Snapshot(state.symbol, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStats applies fn to the statistics of symbol under lock
+func (r *Recorder) updateStats(symbol string, fn func(*RecorderStats)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	fn(r.stats[symbol])
+}
@@ -0,0 +1,201 @@
+// Package orderbook provides replay of recorded depth files
+package orderbook
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// replayEntry locates one record in the loaded files
+type replayEntry struct {
+	time   int64
+	kind   uint8
+	file   int
+	offset int
+}
+
+// Replayer rebuilds the book at any timestamp from recorded depth files.
+// Queries with increasing timestamps only apply the records in between; going
+// back in time restarts from the closest snapshot.
+type Replayer struct {
+	files     [][]byte
+	entries   []replayEntry
+	snapshots []int // Indices in entries of snapshot records
+
+	book *Book
+	pos  int // Next entry to apply; -1 when the book holds no state
+}
+
+// NewReplayer loads the given depth files
+func NewReplayer(paths ...string) (*Replayer, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("at least one depth file is required")
+	}
+
+	r := &Replayer{book: NewBook(), pos: -1}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read depth file: %w", err)
+		}
+		if err := checkDepthHeader(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		fileIdx := len(r.files)
+		r.files = append(r.files, data)
+		var last int64
+		scanRecords(data, func(offset int, rec record) error {
+			// Bridging diffs are written after their snapshot but may carry an earlier event
+			// time: stamp records with the running max so the sort keeps the file order
+			t := rec.time
+			if t < last {
+				t = last
+			}
+			last = t
+			r.entries = append(r.entries, replayEntry{time: t, kind: rec.kind, file: fileIdx, offset: offset})
+			return nil
+		}, nil)
+	}
+
+	sort.SliceStable(r.entries, func(i, j int) bool {
+		return r.entries[i].time < r.entries[j].time
+	})
+	for i, e := range r.entries {
+		if e.kind == recordSnapshot {
+			r.snapshots = append(r.snapshots, i)
+		}
+	}
+	if len(r.snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshot found in depth files")
+	}
+
+	return r, nil
+}
+
+// NewReplayerForRange loads the files of symbol covering [start, end] from a recorder directory
+func NewReplayerForRange(rootDir, symbol string, start, end time.Time) (*Replayer, error) {
+	var paths []string
+	for day := start.UTC().Truncate(24 * time.Hour); !day.After(end); day = day.Add(24 * time.Hour) {
+		path := DepthFilePath(rootDir, symbol, day.UnixMilli())
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no depth file for %s in %s", symbol, filepath.Join(rootDir, symbol))
+	}
+	return NewReplayer(paths...)
+}
+
+// StartTime returns the time of the first snapshot (first queryable timestamp)
+func (r *Replayer) StartTime() int64 {
+	return r.entries[r.snapshots[0]].time
+}
+
+// EndTime returns the time of the last record
+func (r *Replayer) EndTime() int64 {
+	return r.entries[len(r.entries)-1].time
+}
+
+// BookAt returns the book as it was at timestamp (ms). The returned book is owned by
+// the replayer and only valid until the next query.
+func (r *Replayer) BookAt(timestamp int64) (*Book, error) {
+	// Last snapshot at or before timestamp
+	s := sort.Search(len(r.snapshots), func(i int) bool {
+		return r.entries[r.snapshots[i]].time > timestamp
+	}) - 1
+	if s < 0 {
+		return nil, fmt.Errorf("no depth data before %d", timestamp)
+	}
+	snapshotIdx := r.snapshots[s]
+
+	// Restart from the snapshot when going back in time or when it is ahead of the current position
+	if r.pos < 0 || snapshotIdx >= r.pos || (r.pos > 0 && r.entries[r.pos-1].time > timestamp) {
+		r.pos = snapshotIdx
+	}
+
+	for r.pos < len(r.entries) && r.entries[r.pos].time <= timestamp {
+		e := r.entries[r.pos]
+		rec, _, _ := decodeRecord(r.files[e.file], e.offset)
+		if rec.kind == recordSnapshot {
+			r.book.ApplySnapshot(&Snapshot{Time: rec.time, LastUpdateID: rec.finalUpdateID, Bids: rec.bids, Asks: rec.asks})
+		} else {
+			r.book.ApplyDiff(&Diff{
+				Time:              rec.time,
+				FirstUpdateID:     rec.firstUpdateID,
+				FinalUpdateID:     rec.finalUpdateID,
+				PrevFinalUpdateID: rec.prevFinalUpdateID,
+				Bids:              rec.bids,
+				Asks:              rec.asks,
+			})
+		}
+		r.pos++
+	}
+
+	return r.book, nil
+}
+
+// TopOfBook returns the best bid and ask at timestamp
+func (r *Replayer) TopOfBook(timestamp int64) (bid, ask Level, err error) {
+	book, err := r.BookAt(timestamp)
+	if err != nil {
+		return Level{}, Level{}, err
+	}
+	bid, okBid := book.BestBid()
+	ask, okAsk := book.BestAsk()
+	if !okBid || !okAsk {
+		return bid, ask, fmt.Errorf("empty book side at %d", timestamp)
+	}
+	return bid, ask, nil
+}
+
+// Spread returns the spread (ask - bid) at timestamp
+func (r *Replayer) Spread(timestamp int64) (float64, error) {
+	book, err := r.BookAt(timestamp)
+	if err != nil {
+		return 0, err
+	}
+	spread, ok := book.Spread()
+	if !ok {
+		return 0, fmt.Errorf("empty book side at %d", timestamp)
+	}
+	return spread, nil
+}
+
+// DepthWithin returns bid/ask base quantity within pct percent of mid at timestamp
+func (r *Replayer) DepthWithin(timestamp int64, pct float64) (bidQty, askQty float64, err error) {
+	book, err := r.BookAt(timestamp)
+	if err != nil {
+		return 0, 0, err
+	}
+	bidQty, askQty = book.DepthWithin(pct)
+	return bidQty, askQty, nil
+}
+
+// Imbalance returns the book imbalance over the n best levels at timestamp
+func (r *Replayer) Imbalance(timestamp int64, n int) (float64, error) {
+	book, err := r.BookAt(timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return book.Imbalance(n), nil
+}
+
+// EstimateFill estimates a market order fill at timestamp (see Book.EstimateFill)
+func (r *Replayer) EstimateFill(timestamp int64, buy bool, quantity float64) (avgPrice, slippageBps, filled float64, err error) {
+	book, err := r.BookAt(timestamp)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	avgPrice, slippageBps, filled = book.EstimateFill(buy, quantity)
+	return avgPrice, slippageBps, filled, nil
+}
+
+// unixMilliUTC converts a Unix ms timestamp to UTC time
+func unixMilliUTC(timestamp int64) time.Time {
+	return time.UnixMilli(timestamp).UTC()
+}
@@ -0,0 +1,205 @@
+// Package orderbook provides exchange depth sources (REST snapshot + WebSocket diffs)
+package orderbook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DepthSource provides depth snapshots and a diff stream for one exchange
+type DepthSource interface {
+	Name() string
+	// FetchSnapshot retrieves a REST depth snapshot
+	FetchSnapshot(ctx context.Context, symbol string, limit int) (*Snapshot, error)
+	// StreamDiffs blocks and calls handler for each diff until ctx is done or the stream fails
+	StreamDiffs(ctx context.Context, symbol string, handler func(*Diff) error) error
+}
+
+// Binance futures endpoints
+const (
+	BinanceFuturesRESTURL = "https://fapi.binance.com"
+	BinanceFuturesWSURL   = "wss://fstream.binance.com/ws"
+)
+
+// BinanceDepthSource reads USDⓈ-M futures depth (REST /fapi/v1/depth + <symbol>@depth@<speed> stream)
+type BinanceDepthSource struct {
+	RESTBaseURL string
+	WSBaseURL   string
+	Speed       string // "100ms", "250ms" or "500ms"
+
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+}
+
+// NewBinanceDepthSource creates a Binance futures depth source; empty URLs use production endpoints
+func NewBinanceDepthSource(restBaseURL, wsBaseURL string) *BinanceDepthSource {
+	if restBaseURL == "" {
+		restBaseURL = BinanceFuturesRESTURL
+	}
+	if wsBaseURL == "" {
+		wsBaseURL = BinanceFuturesWSURL
+	}
+	return &BinanceDepthSource{
+		RESTBaseURL: strings.TrimRight(restBaseURL, "/"),
+		WSBaseURL:   strings.TrimRight(wsBaseURL, "/"),
+		Speed:       "100ms",
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		dialer:      websocket.DefaultDialer,
+	}
+}
+
+// Name returns the source name
+func (s *BinanceDepthSource) Name() string {
+	return "binance_futures"
+}
+
+// binanceDepthResponse is the REST depth payload
+type binanceDepthResponse struct {
+	LastUpdateID int64       `json:"lastUpdateId"`
+	EventTime    int64       `json:"E"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
+// binanceDepthEvent is the WebSocket diff payload
+type binanceDepthEvent struct {
+	EventType         string      `json:"e"`
+	EventTime         int64       `json:"E"`
+	Symbol            string      `json:"s"`
+	FirstUpdateID     int64       `json:"U"`
+	FinalUpdateID     int64       `json:"u"`
+	PrevFinalUpdateID int64       `json:"pu"`
+	Bids              [][2]string `json:"b"`
+	Asks              [][2]string `json:"a"`
+}
+
+// FetchSnapshot retrieves a REST depth snapshot (limit: 5, 10, 20, 50, 100, 500, 1000)
+func (s *BinanceDepthSource) FetchSnapshot(ctx context.Context, symbol string, limit int) (*Snapshot, error) {
+	url := fmt.Sprintf("%s/fapi/v1/depth?symbol=%s&limit=%d", s.RESTBaseURL, strings.ToUpper(symbol), limit)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build depth request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get depth snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("depth snapshot HTTP error %d", resp.StatusCode)
+	}
+
+	var payload binanceDepthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to parse depth snapshot: %w", err)
+	}
+
+	bids, err := parseLevels(payload.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseLevels(payload.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	eventTime := payload.EventTime
+	if eventTime == 0 {
+		eventTime = time.Now().UnixMilli()
+	}
+
+	return &Snapshot{
+		Symbol:       strings.ToUpper(symbol),
+		Time:         eventTime,
+		LastUpdateID: payload.LastUpdateID,
+		Bids:         bids,
+		Asks:         asks,
+	}, nil
+}
+
+// StreamDiffs connects to the diff depth stream and forwards events to handler
+func (s *BinanceDepthSource) StreamDiffs(ctx context.Context, symbol string, handler func(*Diff) error) error {
+	url := fmt.Sprintf("%s/%s@depth@%s", s.WSBaseURL, strings.ToLower(symbol), s.Speed)
+	conn, _, err := s.dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect depth stream: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock ReadMessage when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("depth stream read failed: %w", err)
+		}
+
+		var event binanceDepthEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			return fmt.Errorf("failed to parse depth event: %w", err)
+		}
+		if event.EventType != "depthUpdate" {
+			continue
+		}
+
+		bids, err := parseLevels(event.Bids)
+		if err != nil {
+			return err
+		}
+		asks, err := parseLevels(event.Asks)
+		if err != nil {
+			return err
+		}
+
+		diff := &Diff{
+			Symbol:            event.Symbol,
+			Time:              event.EventTime,
+			FirstUpdateID:     event.FirstUpdateID,
+			FinalUpdateID:     event.FinalUpdateID,
+			PrevFinalUpdateID: event.PrevFinalUpdateID,
+			Bids:              bids,
+			Asks:              asks,
+		}
+		if err := handler(diff); err != nil {
+			return err
+		}
+	}
+}
+
+// parseLevels converts [price, quantity] string pairs
+func parseLevels(raw [][2]string) ([]Level, error) {
+	levels := make([]Level, 0, len(raw))
+	for _, pair := range raw {
+		price, err := strconv.ParseFloat(pair[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level price %q: %w", pair[0], err)
+		}
+		quantity, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level quantity %q: %w", pair[1], err)
+		}
+		levels = append(levels, Level{Price: price, Quantity: quantity})
+	}
+	return levels, nil
+}
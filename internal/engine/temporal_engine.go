@@ -191,7 +191,7 @@ func (e *TemporalEngine) ProcessTrade(trade Trade) error {
 	}
 	
 	// Check for candle markers and trigger calculations
-	if IsMarkerTimestamp(trade.Timestamp) {
+	if IsMarkerTimestampFor(trade.Timestamp, e.timeframe()) {
 		if err := e.processMarkerEvent(trade.Timestamp); err != nil {
 			return fmt.Errorf("marker processing failed: %w", err)
 		}
@@ -550,7 +550,7 @@ func (e *TemporalEngine) calculateIndicators() (*indicators.CalculationResponse,
 	// Preparation request with Engine data
 	request := &indicators.CalculationRequest{
 		Symbol:       "SOLUSDT", // TODO: Add to config if needed
-		Timeframe:    "5m",      // TODO: Add to config if needed
+		Timeframe:    e.timeframe(),
 		CurrentTime:  e.currentTimestamp,
 		CandleWindow: e.getCandleWindow(), // Use existing window
 		RequestID:    fmt.Sprintf("engine-%d", e.currentTimestamp),
@@ -588,14 +588,14 @@ func (e *TemporalEngine) getPositionContext() *indicators.PositionContext {
 }
 
 // getCandleWindow converts Engine klines to indicators format
-// Uses primary timeframe (5m) for marker-based calculations
+// Uses primary timeframe (config, default 5m) for marker-based calculations
 func (e *TemporalEngine) getCandleWindow() []indicators.Kline {
 	var sourceKlines []Kline
 	
 	// Prioritize multi-TF data - use primary timeframe for calculations
 	if len(e.historicalKlinesMultiTF) > 0 {
 		// Find primary timeframe (usually 5m) for marker calculations
-		primaryTF := "5m"
+		primaryTF := e.timeframe()
 		if klines, exists := e.historicalKlinesMultiTF[primaryTF]; exists {
 			sourceKlines = klines
 		} else {
@@ -634,6 +634,14 @@ func (e *TemporalEngine) getCandleWindow() []indicators.Kline {
 	return window
 }
 
+// timeframe returns the marker timeframe, defaulting to 5m
+func (e *TemporalEngine) timeframe() string {
+	if e.config.Timeframe == "" {
+		return "5m"
+	}
+	return e.config.Timeframe
+}
+
 // convertCCIZone converts string CCI zone to indicators.CCIZone enum
 func (e *TemporalEngine) convertCCIZone(zoneStr string) indicators.CCIZone {
 	switch zoneStr {
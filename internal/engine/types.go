@@ -3,7 +3,8 @@ package engine
 
 import (
 	"errors"
-	"time"
+
+	"agent-economique/internal/shared"
 )
 
 // ExecutionMode defines the execution mode of the temporal engine
@@ -167,6 +168,7 @@ type ZoneEvent struct {
 // EngineConfig holds configuration for the temporal engine
 type EngineConfig struct {
 	WindowSize       int                    `json:"window_size"`
+	Timeframe        string                `json:"timeframe,omitempty"` // Marker timeframe (default 5m, custom like 15s/7m allowed)
 	AntiLookAhead    bool                  `json:"anti_lookahead"`
 	TrailingStop     TrailingStopConfig    `json:"trailing_stop"`
 	AdjustmentGrid   []AdjustmentLevel     `json:"adjustment_grid"`
@@ -229,6 +231,12 @@ func ValidateConfiguration(config EngineConfig) error {
 	if config.WindowSize <= 0 {
 		return errors.New("window_size must be positive")
 	}
+
+	if config.Timeframe != "" {
+		if _, err := shared.ParseTimeframe(config.Timeframe); err != nil {
+			return errors.New("timeframe must be a valid interval (e.g. 15s, 5m, 7m, 3h)")
+		}
+	}
 	
 	if config.TrailingStop.TrendPercent <= 0 || config.TrailingStop.TrendPercent > 100 {
 		return errors.New("trend_percent must be between 0 and 100")
@@ -251,47 +259,21 @@ func ValidateConfiguration(config EngineConfig) error {
 // IsMarkerTimestamp checks if timestamp represents a 5m candle marker
 // Markers occur at 00:00, 00:05, 00:10, 00:15, etc. (every 5 minutes with seconds = 0)
 func IsMarkerTimestamp(timestamp int64) bool {
-	t := time.Unix(timestamp/1000, (timestamp%1000)*1000000).UTC()
-	
-	// Must be at exact minute start (seconds = 0)
-	if t.Second() != 0 {
-		return false
-	}
-	
-	// Must be on 5-minute boundary (00, 05, 10, 15, 20, 25, 30, 35, 40, 45, 50, 55)
-	return t.Minute()%5 == 0
+	return IsMarkerTimestampFor(timestamp, "5m")
+}
+
+// IsMarkerTimestampFor checks if timestamp opens a candle of the given timeframe
+// Supports custom timeframes (15s, 2m, 7m, 3h...) with the same bucket alignment as the aggregator
+func IsMarkerTimestampFor(timestamp int64, timeframe string) bool {
+	return shared.IsTimeframeBoundary(timestamp, timeframe)
 }
 
 // GetTimeframeAlignment checks alignment with specific timeframes
 func GetTimeframeAlignment(timestamp int64, timeframes []string) []string {
-	t := time.Unix(timestamp/1000, (timestamp%1000)*1000000).UTC()
-	
-	if t.Second() != 0 {
-		return nil
-	}
-	
 	var aligned []string
-	minute := t.Minute()
-	hour := t.Hour()
-	
 	for _, tf := range timeframes {
-		switch tf {
-		case "5m":
-			if minute%5 == 0 {
-				aligned = append(aligned, tf)
-			}
-		case "15m":
-			if minute%15 == 0 {
-				aligned = append(aligned, tf)
-			}
-		case "1h":
-			if minute == 0 {
-				aligned = append(aligned, tf)
-			}
-		case "4h":
-			if minute == 0 && hour%4 == 0 {
-				aligned = append(aligned, tf)
-			}
+		if IsMarkerTimestampFor(timestamp, tf) {
+			aligned = append(aligned, tf)
 		}
 	}
 	
@@ -302,6 +284,7 @@ func GetTimeframeAlignment(timestamp int64, timeframes []string) []string {
 func DefaultEngineConfig() EngineConfig {
 	return EngineConfig{
 		WindowSize:    300,
+		Timeframe:     "5m",
 		AntiLookAhead: true,
 		TrailingStop: TrailingStopConfig{
 			TrendPercent:        2.0,
@@ -333,3 +333,32 @@ func TestDefaultEngineConfig(t *testing.T) {
 		t.Error("expected non-empty AdjustmentGrid")
 	}
 }
+
+func TestIsMarkerTimestampFor_CustomTimeframes(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		timeframe string
+		expected  bool
+	}{
+		{"15s boundary", time.Date(2023, 6, 1, 10, 0, 45, 0, time.UTC), "15s", true},
+		{"15s off boundary", time.Date(2023, 6, 1, 10, 0, 50, 0, time.UTC), "15s", false},
+		{"2m boundary", time.Date(2023, 6, 1, 10, 2, 0, 0, time.UTC), "2m", true},
+		{"2m off boundary", time.Date(2023, 6, 1, 10, 3, 0, 0, time.UTC), "2m", false},
+		{"7m boundary", time.Date(2023, 6, 1, 0, 49, 0, 0, time.UTC), "7m", true},
+		{"7m resets at midnight", time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC), "7m", true},
+		{"7m off boundary", time.Date(2023, 6, 1, 0, 50, 0, 0, time.UTC), "7m", false},
+		{"3h boundary", time.Date(2023, 6, 1, 15, 0, 0, 0, time.UTC), "3h", true},
+		{"3h off boundary", time.Date(2023, 6, 1, 16, 0, 0, 0, time.UTC), "3h", false},
+		{"invalid timeframe", time.Date(2023, 6, 1, 15, 0, 0, 0, time.UTC), "bogus", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsMarkerTimestampFor(tt.timestamp.UnixMilli(), tt.timeframe)
+			if result != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
@@ -58,6 +58,60 @@ func (t *Trailing) Hit(close float64) (bool, float64) {
 	return false, 0
 }
 
+// StructureTrailing place le stop juste au-delà de la structure de marché : sous le support
+// le plus proche pour un LONG, au-dessus de la résistance la plus proche pour un SHORT, avec
+// une marge Buffer. Sans niveau exploitable, il suit le prix à Offset (comme Trailing).
+// Le stop ne recule jamais.
+type StructureTrailing struct {
+	Side   Side
+	Buffer float64
+	Offset float64
+	Trail  float64
+}
+
+// NewStructureTrailing crée un trailing de structure : offset de repli = ATR d'entrée plafonné
+// à capPct du prix, marge = bufferATR × ATR d'entrée
+func NewStructureTrailing(side Side, entryPrice float64, atrAtEntry float64, capPct float64, bufferATR float64) *StructureTrailing {
+	base := NewTrailing(side, entryPrice, atrAtEntry, capPct)
+	return &StructureTrailing{Side: side, Buffer: atrAtEntry * bufferATR, Offset: base.Offset, Trail: base.Trail}
+}
+
+// Update met à jour le stop ; level = support (LONG) ou résistance (SHORT) le plus proche,
+// NaN ou du mauvais côté du prix => repli sur l'offset.
+func (t *StructureTrailing) Update(close float64, level float64) {
+	if t.Side == SideLong {
+		cand := close - t.Offset
+		if !math.IsNaN(level) && level < close {
+			cand = level - t.Buffer
+		}
+		if cand > t.Trail && cand < close {
+			t.Trail = cand
+		}
+	} else {
+		cand := close + t.Offset
+		if !math.IsNaN(level) && level > close {
+			cand = level + t.Buffer
+		}
+		if cand < t.Trail && cand > close {
+			t.Trail = cand
+		}
+	}
+}
+
+// Hit vérifie si la CLOSE a touché le stop de structure.
+func (t *StructureTrailing) Hit(close float64) (bool, float64) {
+	if t.Side == SideLong {
+		if close <= t.Trail {
+			return true, t.Trail
+		}
+	} else {
+		if close >= t.Trail {
+			return true, t.Trail
+		}
+	}
+	return false, 0
+}
+
 // PercentTrailing implémente un trailing stop standard en pourcentage du prix.
 // LONG : le stop suit le plus haut atteint en restant Pct en dessous.
 // SHORT : le stop suit le plus bas atteint en restant Pct au-dessus.
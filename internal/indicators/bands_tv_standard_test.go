@@ -0,0 +1,117 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// TestBollinger_KnownValues vérifie basis, écart-type de population, %B et bandwidth
+func TestBollinger_KnownValues(t *testing.T) {
+	src := []float64{1, 2, 3, 4, 6}
+	bb := NewBollingerTVStandard(3, 2)
+	basis, upper, lower := bb.Calculate(src)
+
+	if !math.IsNaN(basis[1]) || !math.IsNaN(upper[1]) {
+		t.Fatalf("Expected NaN during warmup, got %v %v", basis[1], upper[1])
+	}
+	// Fenêtre [2,3,4] : moyenne 3, écart-type population sqrt(2/3)
+	dev := 2 * math.Sqrt(2.0/3.0)
+	if !almostEqual(basis[3], 3) || !almostEqual(upper[3], 3+dev) || !almostEqual(lower[3], 3-dev) {
+		t.Errorf("Unexpected bands at 3: %v %v %v", basis[3], upper[3], lower[3])
+	}
+
+	percentB := bb.CalculatePercentB(src, upper, lower)
+	if !almostEqual(percentB[3], (4-lower[3])/(upper[3]-lower[3])) {
+		t.Errorf("Unexpected %%B %v", percentB[3])
+	}
+	bandwidth := bb.CalculateBandwidth(basis, upper, lower)
+	if !almostEqual(bandwidth[3], 2*dev/3*100) {
+		t.Errorf("Unexpected bandwidth %v", bandwidth[3])
+	}
+
+	// Série constante : bandes confondues, %B inconnu
+	flat := []float64{5, 5, 5}
+	_, u, l := bb.Calculate(flat)
+	if pb := bb.CalculatePercentB(flat, u, l); !math.IsNaN(pb[2]) {
+		t.Errorf("Expected NaN %%B on flat bands, got %v", pb[2])
+	}
+}
+
+// TestDonchian_Breakout vérifie le canal et la cassure sur le canal précédent
+func TestDonchian_Breakout(t *testing.T) {
+	high := []float64{10, 12, 11, 11, 15}
+	low := []float64{8, 9, 7, 9, 10}
+	closes := []float64{9, 11, 8, 10, 14}
+	dc := NewDonchianTVStandard(3)
+	upper, lower, basis := dc.Calculate(high, low)
+
+	if upper[2] != 12 || lower[2] != 7 || basis[2] != 9.5 || !math.IsNaN(upper[1]) {
+		t.Errorf("Unexpected channel %v %v %v", upper, lower, basis)
+	}
+	if got := dc.DetecterCassure(closes, upper, lower, 4); got != "HAUSSIER" {
+		t.Errorf("Expected bullish breakout, got %q", got)
+	}
+	if got := dc.DetecterCassure(closes, upper, lower, 3); got != "" {
+		t.Errorf("Expected no breakout, got %q", got)
+	}
+}
+
+// TestSupertrend_Direction vérifie la convention TV et le retournement
+func TestSupertrend_Direction(t *testing.T) {
+	var high, low, closes []float64
+	price := 100.0
+	for i := 0; i < 60; i++ {
+		if i < 30 {
+			price += 1
+		} else {
+			price -= 2
+		}
+		high = append(high, price+0.5)
+		low = append(low, price-0.5)
+		closes = append(closes, price)
+	}
+
+	st := NewSupertrendTVStandard(10, 3)
+	line, direction := st.Calculate(high, low, closes)
+
+	if !math.IsNaN(direction[8]) || direction[9] != 1 {
+		t.Fatalf("Expected first direction +1 at bar 9, got %v / %v", direction[8], direction[9])
+	}
+	if direction[29] != -1 || line[29] >= closes[29] {
+		t.Errorf("Expected uptrend below price at bar 29, got dir %v line %v close %v", direction[29], line[29], closes[29])
+	}
+	if direction[59] != 1 || line[59] <= closes[59] {
+		t.Errorf("Expected downtrend above price at bar 59, got dir %v line %v close %v", direction[59], line[59], closes[59])
+	}
+
+	flips := 0
+	for i := range direction {
+		if st.DetecterRetournement(direction, i) == "BAISSIER" {
+			flips++
+		}
+	}
+	if flips != 1 {
+		t.Errorf("Expected one bearish reversal, got %d", flips)
+	}
+}
+
+// TestSqueeze_BollingerInsideKeltner vérifie la détection et la sortie du squeeze
+func TestSqueeze_BollingerInsideKeltner(t *testing.T) {
+	nan := math.NaN()
+	bbUpper := []float64{nan, 10, 10, 12}
+	bbLower := []float64{nan, 8, 8, 6}
+	kcUpper := []float64{nan, 11, 11, 11}
+	kcLower := []float64{nan, 7, 7, 7}
+
+	squeeze := DetecterSqueeze(bbUpper, bbLower, kcUpper, kcLower)
+	if squeeze[0] || !squeeze[1] || !squeeze[2] || squeeze[3] {
+		t.Errorf("Unexpected squeeze %v", squeeze)
+	}
+	if DetecterSortieSqueeze(squeeze, 2) || !DetecterSortieSqueeze(squeeze, 3) {
+		t.Errorf("Expected squeeze release at bar 3 only")
+	}
+}
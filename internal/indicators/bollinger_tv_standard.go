@@ -0,0 +1,132 @@
+package indicators
+
+import (
+	"math"
+)
+
+// BollingerTVStandard - Implémentation Bollinger Bands TradingView Standard
+// Formules Pine: basis = ta.sma(src, length), dev = mult * ta.stdev(src, length)
+// upper = basis + dev, lower = basis - dev
+// ta.stdev est l'écart-type de population (biased = true, division par length)
+type BollingerTVStandard struct {
+	period int
+	mult   float64
+}
+
+// NewBollingerTVStandard crée une nouvelle instance Bollinger TV Standard (TV: 20, 2.0)
+func NewBollingerTVStandard(period int, mult float64) *BollingerTVStandard {
+	return &BollingerTVStandard{
+		period: period,
+		mult:   mult,
+	}
+}
+
+// Calculate calcule basis, bande haute et bande basse selon TradingView
+func (bb *BollingerTVStandard) Calculate(src []float64) (basis, upper, lower []float64) {
+	n := len(src)
+	basis = NewSMATVStandard(bb.period).Calculate(src)
+	upper = make([]float64, n)
+	lower = make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		upper[i] = math.NaN()
+		lower[i] = math.NaN()
+		if math.IsNaN(basis[i]) {
+			continue
+		}
+		dev := bb.mult * bb.stdev(src, i, basis[i])
+		upper[i] = basis[i] + dev
+		lower[i] = basis[i] - dev
+	}
+
+	return basis, upper, lower
+}
+
+// stdev écart-type de population sur [i-period+1 .. i] (ta.stdev biased)
+func (bb *BollingerTVStandard) stdev(src []float64, i int, mean float64) float64 {
+	sumSq := 0.0
+	for j := i - bb.period + 1; j <= i; j++ {
+		d := src[j] - mean
+		sumSq += d * d
+	}
+	return math.Sqrt(sumSq / float64(bb.period))
+}
+
+// CalculatePercentB calcule %B = (src - lower) / (upper - lower)
+// Bandes confondues (écart-type nul) : NaN, comme la division par zéro Pine
+func (bb *BollingerTVStandard) CalculatePercentB(src, upper, lower []float64) []float64 {
+	out := make([]float64, len(src))
+	for i := range src {
+		width := upper[i] - lower[i]
+		if math.IsNaN(width) || width == 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = (src[i] - lower[i]) / width
+	}
+	return out
+}
+
+// CalculateBandwidth calcule la largeur relative ((upper - lower) / basis) * 100 (indicateur BBW TradingView)
+func (bb *BollingerTVStandard) CalculateBandwidth(basis, upper, lower []float64) []float64 {
+	out := make([]float64, len(basis))
+	for i := range basis {
+		if math.IsNaN(basis[i]) || basis[i] == 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = (upper[i] - lower[i]) / basis[i] * 100
+	}
+	return out
+}
+
+// CalculateFromKlines calcule les bandes sur les closes
+func (bb *BollingerTVStandard) CalculateFromKlines(klines []Kline) (basis, upper, lower []float64) {
+	closes := make([]float64, len(klines))
+	for i, k := range klines {
+		closes[i] = k.Close
+	}
+	return bb.Calculate(closes)
+}
+
+// GetLastValues retourne les dernières valeurs valides (basis, upper, lower)
+func (bb *BollingerTVStandard) GetLastValues(basis, upper, lower []float64) (float64, float64, float64) {
+	for i := len(basis) - 1; i >= 0; i-- {
+		if !math.IsNaN(upper[i]) {
+			return basis[i], upper[i], lower[i]
+		}
+	}
+	return math.NaN(), math.NaN(), math.NaN()
+}
+
+// GetSignal retourne la position du prix par rapport aux bandes
+func (bb *BollingerTVStandard) GetSignal(price, upper, lower float64) string {
+	if math.IsNaN(upper) || math.IsNaN(lower) {
+		return "INCONNU"
+	}
+	if price > upper {
+		return "CASSURE_HAUTE"
+	}
+	if price < lower {
+		return "CASSURE_BASSE"
+	}
+	return "DANS_BANDES"
+}
+
+// IsBandwidthLow indique une compression : largeur au plus bas sur lookback bougies
+// (fenêtre [i-lookback+1 .. i], n'utilise aucune bougie future)
+func (bb *BollingerTVStandard) IsBandwidthLow(bandwidth []float64, index, lookback int) bool {
+	if index < 0 || index >= len(bandwidth) || lookback <= 0 || index-lookback+1 < 0 {
+		return false
+	}
+	current := bandwidth[index]
+	if math.IsNaN(current) {
+		return false
+	}
+	for j := index - lookback + 1; j < index; j++ {
+		if math.IsNaN(bandwidth[j]) || bandwidth[j] < current {
+			return false
+		}
+	}
+	return true
+}
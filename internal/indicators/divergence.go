@@ -0,0 +1,217 @@
+package indicators
+
+import (
+	"math"
+)
+
+// DivergenceKind type de divergence prix / oscillateur
+type DivergenceKind string
+
+const (
+	// DivergenceRegularBullish prix plus bas, oscillateur plus haut (retournement haussier)
+	DivergenceRegularBullish DivergenceKind = "HAUSSIERE_REGULIERE"
+	// DivergenceHiddenBullish prix plus haut, oscillateur plus bas (continuation haussière)
+	DivergenceHiddenBullish DivergenceKind = "HAUSSIERE_CACHEE"
+	// DivergenceRegularBearish prix plus haut, oscillateur plus bas (retournement baissier)
+	DivergenceRegularBearish DivergenceKind = "BAISSIERE_REGULIERE"
+	// DivergenceHiddenBearish prix plus bas, oscillateur plus haut (continuation baissière)
+	DivergenceHiddenBearish DivergenceKind = "BAISSIERE_CACHEE"
+)
+
+// IsBullish indique une divergence haussière (régulière ou cachée)
+func (k DivergenceKind) IsBullish() bool {
+	return k == DivergenceRegularBullish || k == DivergenceHiddenBullish
+}
+
+// IsHidden indique une divergence cachée
+func (k DivergenceKind) IsHidden() bool {
+	return k == DivergenceHiddenBullish || k == DivergenceHiddenBearish
+}
+
+// DivergenceConfig paramètres du détecteur (défauts de l'indicateur "RSI Divergence" TradingView)
+type DivergenceConfig struct {
+	PivotLeft    int  // Bougies à gauche du pivot (lbL, défaut 5)
+	PivotRight   int  // Bougies à droite du pivot (lbR, défaut 5) = délai de confirmation
+	RangeMin     int  // Écart minimal entre deux pivots (défaut 5)
+	RangeMax     int  // Écart maximal entre deux pivots (défaut 60)
+	PivotOnPrice bool // Pivots cherchés sur le prix (low/high) au lieu de l'oscillateur (défaut TV)
+	Hidden       bool // Détecte aussi les divergences cachées
+}
+
+// DefaultDivergenceConfig retourne la configuration TradingView par défaut (régulières + cachées)
+func DefaultDivergenceConfig() DivergenceConfig {
+	return DivergenceConfig{PivotLeft: 5, PivotRight: 5, RangeMin: 5, RangeMax: 60, Hidden: true}
+}
+
+// Divergence divergence détectée entre deux pivots
+type Divergence struct {
+	Kind           DivergenceKind
+	PrevPivotIndex int // Pivot précédent
+	PivotIndex     int // Pivot récent
+	ConfirmIndex   int // Première bougie où la divergence est connue (PivotIndex + PivotRight)
+	PricePrev      float64
+	Price          float64
+	OscPrev        float64
+	Osc            float64
+}
+
+// DivergenceDetector détecteur de divergences à pivots (sans look-ahead)
+//
+// Un pivot en i n'est confirmé qu'à la clôture de i + PivotRight : chaque divergence
+// porte son ConfirmIndex et n'utilise aucune bougie postérieure à celui-ci.
+type DivergenceDetector struct {
+	config DivergenceConfig
+}
+
+// NewDivergenceDetector crée un détecteur (valeurs <= 0 remplacées par les défauts TV)
+func NewDivergenceDetector(config DivergenceConfig) *DivergenceDetector {
+	def := DefaultDivergenceConfig()
+	if config.PivotLeft <= 0 {
+		config.PivotLeft = def.PivotLeft
+	}
+	if config.PivotRight <= 0 {
+		config.PivotRight = def.PivotRight
+	}
+	if config.RangeMin <= 0 {
+		config.RangeMin = def.RangeMin
+	}
+	if config.RangeMax <= 0 {
+		config.RangeMax = def.RangeMax
+	}
+	return &DivergenceDetector{config: config}
+}
+
+// Config retourne la configuration effective
+func (d *DivergenceDetector) Config() DivergenceConfig {
+	return d.config
+}
+
+// Detect détecte toutes les divergences, triées par ConfirmIndex
+func (d *DivergenceDetector) Detect(high, low, osc []float64) []Divergence {
+	n := len(osc)
+	if n != len(high) || n != len(low) {
+		return nil
+	}
+
+	lowSrc, highSrc := osc, osc
+	if d.config.PivotOnPrice {
+		lowSrc, highSrc = low, high
+	}
+	pivotLows := PivotLow(lowSrc, d.config.PivotLeft, d.config.PivotRight)
+	pivotHighs := PivotHigh(highSrc, d.config.PivotLeft, d.config.PivotRight)
+
+	var out []Divergence
+	prevLow, prevHigh := -1, -1
+	for i := 0; i < n; i++ {
+		if pivotLows[i] && !math.IsNaN(osc[i]) {
+			if prevLow >= 0 && d.inRange(i-prevLow) {
+				if div, ok := d.compare(prevLow, i, low, osc, true); ok {
+					out = append(out, div)
+				}
+			}
+			prevLow = i
+		}
+		if pivotHighs[i] && !math.IsNaN(osc[i]) {
+			if prevHigh >= 0 && d.inRange(i-prevHigh) {
+				if div, ok := d.compare(prevHigh, i, high, osc, false); ok {
+					out = append(out, div)
+				}
+			}
+			prevHigh = i
+		}
+	}
+
+	return out
+}
+
+// inRange vérifie l'écart entre pivots (_inRange TradingView)
+func (d *DivergenceDetector) inRange(bars int) bool {
+	return bars >= d.config.RangeMin && bars <= d.config.RangeMax
+}
+
+// compare classe deux pivots bas (bullish) ou hauts (bearish)
+func (d *DivergenceDetector) compare(prev, cur int, price, osc []float64, lows bool) (Divergence, bool) {
+	div := Divergence{
+		PrevPivotIndex: prev,
+		PivotIndex:     cur,
+		ConfirmIndex:   cur + d.config.PivotRight,
+		PricePrev:      price[prev],
+		Price:          price[cur],
+		OscPrev:        osc[prev],
+		Osc:            osc[cur],
+	}
+
+	priceUp := div.Price > div.PricePrev
+	priceDown := div.Price < div.PricePrev
+	oscUp := div.Osc > div.OscPrev
+	oscDown := div.Osc < div.OscPrev
+
+	switch {
+	case lows && priceDown && oscUp:
+		div.Kind = DivergenceRegularBullish
+	case lows && priceUp && oscDown && d.config.Hidden:
+		div.Kind = DivergenceHiddenBullish
+	case !lows && priceUp && oscDown:
+		div.Kind = DivergenceRegularBearish
+	case !lows && priceDown && oscUp && d.config.Hidden:
+		div.Kind = DivergenceHiddenBearish
+	default:
+		return div, false
+	}
+	return div, true
+}
+
+// DetectFromKlines détecte les divergences entre les klines et un oscillateur aligné
+func (d *DivergenceDetector) DetectFromKlines(klines []Kline, osc []float64) []Divergence {
+	high, low, _, _ := klineColumns(klines)
+	return d.Detect(high, low, osc)
+}
+
+// LatestDivergenceAt retourne la divergence la plus récente connue à la bougie index
+// (ConfirmIndex <= index et index - ConfirmIndex <= maxAge), filtrée par sens
+func LatestDivergenceAt(divergences []Divergence, index, maxAge int, bullish bool) (Divergence, bool) {
+	for k := len(divergences) - 1; k >= 0; k-- {
+		div := divergences[k]
+		if div.ConfirmIndex > index || div.Kind.IsBullish() != bullish {
+			continue
+		}
+		if index-div.ConfirmIndex > maxAge {
+			return Divergence{}, false
+		}
+		return div, true
+	}
+	return Divergence{}, false
+}
+
+// PivotHigh pivots hauts (ta.pivothigh) : valeur strictement supérieure aux left bougies
+// précédentes et supérieure ou égale aux right suivantes. Marqué à l'index du pivot,
+// connu seulement à index + right.
+func PivotHigh(series []float64, left, right int) []bool {
+	return pivots(series, left, right, func(a, b float64) bool { return a > b })
+}
+
+// PivotLow pivots bas (ta.pivotlow), mêmes règles que PivotHigh en sens inverse
+func PivotLow(series []float64, left, right int) []bool {
+	return pivots(series, left, right, func(a, b float64) bool { return a < b })
+}
+
+// pivots détecte les pivots selon l'ordre better(a, b) = a plus extrême que b
+func pivots(series []float64, left, right int, better func(a, b float64) bool) []bool {
+	n := len(series)
+	out := make([]bool, n)
+	for i := left; i+right < n; i++ {
+		v := series[i]
+		if math.IsNaN(v) {
+			continue
+		}
+		ok := true
+		for j := i - left; j < i && ok; j++ {
+			ok = !math.IsNaN(series[j]) && better(v, series[j])
+		}
+		for j := i + 1; j <= i+right && ok; j++ {
+			ok = !math.IsNaN(series[j]) && !better(series[j], v)
+		}
+		out[i] = ok
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package indicators
+
+import (
+	"testing"
+)
+
+// TestDivergence_RegularBullishNoLookAhead vérifie la classification et la confirmation après PivotRight
+func TestDivergence_RegularBullishNoLookAhead(t *testing.T) {
+	osc := []float64{50, 40, 30, 40, 50, 60, 50, 35, 45, 55, 60, 65, 70}
+	low := make([]float64, len(osc))
+	high := make([]float64, len(osc))
+	for i := range osc {
+		low[i], high[i] = 100, 110
+	}
+	low[7] = 95 // Plus bas inférieur pendant que l'oscillateur fait un plus bas supérieur
+
+	d := NewDivergenceDetector(DivergenceConfig{PivotLeft: 2, PivotRight: 2, RangeMin: 2, RangeMax: 20})
+
+	// Pivot bas en 7 inconnu tant que la bougie 9 n'est pas clôturée
+	if divs := d.Detect(high[:9], low[:9], osc[:9]); len(divs) != 0 {
+		t.Fatalf("Expected no divergence before confirmation, got %+v", divs)
+	}
+
+	divs := d.Detect(high, low, osc)
+	if len(divs) != 1 {
+		t.Fatalf("Expected one divergence, got %+v", divs)
+	}
+	div := divs[0]
+	if div.Kind != DivergenceRegularBullish || div.PrevPivotIndex != 2 || div.PivotIndex != 7 || div.ConfirmIndex != 9 {
+		t.Errorf("Unexpected divergence %+v", div)
+	}
+	if prefix := d.Detect(high[:10], low[:10], osc[:10]); len(prefix) != 1 || prefix[0] != div {
+		t.Errorf("Divergence must be identical once confirmed, got %+v", prefix)
+	}
+
+	if _, ok := LatestDivergenceAt(divs, 8, 3, true); ok {
+		t.Errorf("Divergence must not be visible before its confirmation bar")
+	}
+	if _, ok := LatestDivergenceAt(divs, 12, 3, true); !ok {
+		t.Errorf("Expected divergence within max age")
+	}
+	if _, ok := LatestDivergenceAt(divs, 13, 3, true); ok {
+		t.Errorf("Expected divergence expired after max age")
+	}
+	if _, ok := LatestDivergenceAt(divs, 10, 3, false); ok {
+		t.Errorf("Expected no bearish divergence")
+	}
+}
+
+// TestDivergence_Hidden vérifie les divergences cachées (désactivables)
+func TestDivergence_Hidden(t *testing.T) {
+	osc := []float64{10, 20, 60, 20, 10, 20, 70, 20, 10}
+	low := []float64{90, 90, 90, 90, 90, 90, 90, 90, 90}
+	high := []float64{100, 100, 110, 100, 100, 100, 105, 100, 100}
+
+	config := DivergenceConfig{PivotLeft: 2, PivotRight: 2, RangeMin: 2, RangeMax: 20, Hidden: true}
+	divs := NewDivergenceDetector(config).Detect(high, low, osc)
+	if len(divs) != 1 || divs[0].Kind != DivergenceHiddenBearish || divs[0].Kind.IsBullish() || !divs[0].Kind.IsHidden() {
+		t.Fatalf("Expected hidden bearish divergence, got %+v", divs)
+	}
+
+	config.Hidden = false
+	if divs := NewDivergenceDetector(config).Detect(high, low, osc); len(divs) != 0 {
+		t.Errorf("Expected hidden divergences disabled, got %+v", divs)
+	}
+}
@@ -0,0 +1,88 @@
+package indicators
+
+import (
+	"math"
+)
+
+// DonchianTVStandard - Implémentation Donchian Channels TradingView Standard
+// Formules Pine: upper = ta.highest(length), lower = ta.lowest(length), basis = math.avg(upper, lower)
+// Défaut TV: length 20
+type DonchianTVStandard struct {
+	period int
+}
+
+// NewDonchianTVStandard crée une nouvelle instance Donchian TV Standard
+func NewDonchianTVStandard(period int) *DonchianTVStandard {
+	return &DonchianTVStandard{
+		period: period,
+	}
+}
+
+// Calculate calcule upper, lower et basis sur [i-period+1 .. i] (bougie courante incluse, comme TV)
+func (dc *DonchianTVStandard) Calculate(high, low []float64) (upper, lower, basis []float64) {
+	n := len(high)
+	if n != len(low) {
+		return nil, nil, nil
+	}
+
+	upper = make([]float64, n)
+	lower = make([]float64, n)
+	basis = make([]float64, n)
+	for i := 0; i < n; i++ {
+		upper[i] = math.NaN()
+		lower[i] = math.NaN()
+		basis[i] = math.NaN()
+	}
+
+	if dc.period <= 0 || dc.period > n {
+		return upper, lower, basis
+	}
+
+	for i := dc.period - 1; i < n; i++ {
+		hi, lo := high[i], low[i]
+		for j := i - dc.period + 1; j < i; j++ {
+			hi = math.Max(hi, high[j])
+			lo = math.Min(lo, low[j])
+		}
+		upper[i] = hi
+		lower[i] = lo
+		basis[i] = (hi + lo) / 2
+	}
+
+	return upper, lower, basis
+}
+
+// CalculateFromKlines calcule le canal depuis des klines
+func (dc *DonchianTVStandard) CalculateFromKlines(klines []Kline) (upper, lower, basis []float64) {
+	high, low, _, _ := klineColumns(klines)
+	return dc.Calculate(high, low)
+}
+
+// GetLastValues retourne les dernières valeurs valides (upper, lower, basis)
+func (dc *DonchianTVStandard) GetLastValues(upper, lower, basis []float64) (float64, float64, float64) {
+	for i := len(upper) - 1; i >= 0; i-- {
+		if !math.IsNaN(upper[i]) {
+			return upper[i], lower[i], basis[i]
+		}
+	}
+	return math.NaN(), math.NaN(), math.NaN()
+}
+
+// DetecterCassure détecte une cassure du canal de la bougie précédente
+// Le canal en index inclut la bougie courante : on compare le close au canal [index-1]
+// (pas de look-ahead). Retourne "HAUSSIER", "BAISSIER" ou "".
+func (dc *DonchianTVStandard) DetecterCassure(close, upper, lower []float64, index int) string {
+	if index < 1 || index >= len(close) || index >= len(upper) || index >= len(lower) {
+		return ""
+	}
+	if math.IsNaN(upper[index-1]) || math.IsNaN(lower[index-1]) {
+		return ""
+	}
+	if close[index] > upper[index-1] {
+		return "HAUSSIER"
+	}
+	if close[index] < lower[index-1] {
+		return "BAISSIER"
+	}
+	return ""
+}
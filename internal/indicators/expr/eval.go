@@ -0,0 +1,456 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"agent-economique/internal/indicators"
+)
+
+// Logique à trois valeurs : 1 = vrai, 0 = faux, NaN = inconnu (warmup, valeur manquante).
+// Une condition inconnue est traitée comme fausse par Bools/True.
+
+// node noeud typé de l'arbre, évalué sur toute la série
+type node interface {
+	typ() valueType
+	pos() int
+	eval(ctx *Context) []float64
+	history() int // Bougies nécessaires avant une valeur stabilisée
+}
+
+// Context série de klines partagée entre expressions (cache des indicateurs)
+type Context struct {
+	klines []indicators.Kline
+	series map[string][]float64
+}
+
+// NewContext crée un contexte d'évaluation pour une série de klines clôturées
+func NewContext(klines []indicators.Kline) *Context {
+	return &Context{klines: klines, series: make(map[string][]float64)}
+}
+
+// Len nombre de bougies du contexte
+func (c *Context) Len() int {
+	return len(c.klines)
+}
+
+// cached retourne une série calculée une seule fois par contexte
+func (c *Context) cached(key string, compute func() []float64) []float64 {
+	if values, ok := c.series[key]; ok {
+		return values
+	}
+	values := compute()
+	if len(values) != len(c.klines) {
+		// Série absente ou tronquée : inconnue partout
+		values = nanSeries(len(c.klines))
+	}
+	c.series[key] = values
+	return values
+}
+
+// Expr expression compilée
+type Expr struct {
+	source string
+	root   node
+	refs   []*indicators.IndicatorRef
+}
+
+// Compile compile une expression avec le registre d'indicateurs par défaut
+func Compile(source string) (*Expr, error) {
+	return CompileWith(source, indicators.DefaultRegistry())
+}
+
+// CompileWith compile une expression avec un registre donné
+func CompileWith(source string, registry *indicators.Registry) (*Expr, error) {
+	p := &parser{lex: &lexer{src: source}, registry: registry, refs: make(map[string]*indicators.IndicatorRef)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEOF {
+		return nil, p.errorf(0, "empty expression")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf(p.tok.pos, "unexpected %s", p.tok)
+	}
+
+	keys := make([]string, 0, len(p.refs))
+	for key := range p.refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	refs := make([]*indicators.IndicatorRef, len(keys))
+	for i, key := range keys {
+		refs[i] = p.refs[key]
+	}
+
+	return &Expr{source: source, root: root, refs: refs}, nil
+}
+
+// CompileCondition compile une expression qui doit produire une condition
+func CompileCondition(source string) (*Expr, error) {
+	e, err := Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsCondition() {
+		return nil, &ParseError{Source: source, Pos: e.root.pos(), Msg: "expression must be a condition, got number"}
+	}
+	return e, nil
+}
+
+// String retourne la source de l'expression
+func (e *Expr) String() string {
+	return e.source
+}
+
+// IsCondition indique si l'expression est booléenne
+func (e *Expr) IsCondition() bool {
+	return e.root.typ() == typeBool
+}
+
+// Refs retourne les références d'indicateurs utilisées (forme canonique)
+func (e *Expr) Refs() []string {
+	out := make([]string, len(e.refs))
+	for i, ref := range e.refs {
+		out[i] = ref.String()
+	}
+	return out
+}
+
+// MinHistory bougies nécessaires : warmup stabilisé des indicateurs + lookbacks imbriqués
+func (e *Expr) MinHistory() int {
+	return e.root.history()
+}
+
+// Values évalue l'expression sur toute la série (conditions : 1/0/NaN)
+func (e *Expr) Values(ctx *Context) []float64 {
+	return ctx.cached("expr:"+e.source, func() []float64 { return e.root.eval(ctx) })
+}
+
+// Bools évalue une condition sur toute la série (inconnu = faux)
+func (e *Expr) Bools(ctx *Context) []bool {
+	values := e.Values(ctx)
+	out := make([]bool, len(values))
+	for i, v := range values {
+		out[i] = v == 1
+	}
+	return out
+}
+
+// True évalue la condition à la bougie index
+func (e *Expr) True(ctx *Context, index int) bool {
+	values := e.Values(ctx)
+	return index >= 0 && index < len(values) && values[index] == 1
+}
+
+// Value retourne la valeur à la bougie index (NaN hors bornes)
+func (e *Expr) Value(ctx *Context, index int) float64 {
+	values := e.Values(ctx)
+	if index < 0 || index >= len(values) {
+		return math.NaN()
+	}
+	return values[index]
+}
+
+// nanSeries série entièrement inconnue
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// boolValue encode un booléen
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// numberNode constante numérique
+type numberNode struct {
+	at    int
+	value float64
+}
+
+func (n *numberNode) typ() valueType { return typeNumber }
+func (n *numberNode) pos() int       { return n.at }
+func (n *numberNode) history() int   { return 0 }
+func (n *numberNode) eval(ctx *Context) []float64 {
+	out := make([]float64, ctx.Len())
+	for i := range out {
+		out[i] = n.value
+	}
+	return out
+}
+
+// boolNode constante booléenne
+type boolNode struct {
+	at    int
+	value bool
+}
+
+func (n *boolNode) typ() valueType { return typeBool }
+func (n *boolNode) pos() int       { return n.at }
+func (n *boolNode) history() int   { return 0 }
+func (n *boolNode) eval(ctx *Context) []float64 {
+	out := make([]float64, ctx.Len())
+	for i := range out {
+		out[i] = boolValue(n.value)
+	}
+	return out
+}
+
+// fieldNode série de prix (close, high, ...)
+type fieldNode struct {
+	at    int
+	name  string
+	value func(indicators.Kline) float64
+}
+
+func (n *fieldNode) typ() valueType { return typeNumber }
+func (n *fieldNode) pos() int       { return n.at }
+func (n *fieldNode) history() int   { return 1 }
+func (n *fieldNode) eval(ctx *Context) []float64 {
+	return ctx.cached("field:"+n.name, func() []float64 {
+		out := make([]float64, ctx.Len())
+		for i, k := range ctx.klines {
+			out[i] = n.value(k)
+		}
+		return out
+	})
+}
+
+// refNode sortie d'indicateur du registre
+type refNode struct {
+	at  int
+	ref *indicators.IndicatorRef
+	key string
+}
+
+func (n *refNode) typ() valueType { return typeNumber }
+func (n *refNode) pos() int       { return n.at }
+func (n *refNode) history() int   { return n.ref.StableWarmup() }
+func (n *refNode) eval(ctx *Context) []float64 {
+	return ctx.cached(n.key, func() []float64 { return n.ref.Compute(ctx.klines) })
+}
+
+// unaryNode négation numérique ou logique
+type unaryNode struct {
+	at      int
+	op      string
+	operand node
+}
+
+func (n *unaryNode) typ() valueType { return n.operand.typ() }
+func (n *unaryNode) pos() int       { return n.at }
+func (n *unaryNode) history() int   { return n.operand.history() }
+func (n *unaryNode) eval(ctx *Context) []float64 {
+	values := n.operand.eval(ctx)
+	out := make([]float64, len(values))
+	for i, v := range values {
+		if n.op == "not" {
+			out[i] = 1 - v // NaN reste NaN
+		} else {
+			out[i] = -v
+		}
+	}
+	return out
+}
+
+// binaryNode opérateurs arithmétiques, comparaisons et logiques
+type binaryNode struct {
+	at          int
+	op          string
+	left, right node
+	boolean     bool // Comparaison (résultat booléen)
+}
+
+func (n *binaryNode) typ() valueType {
+	if n.boolean || n.op == "and" || n.op == "or" {
+		return typeBool
+	}
+	return typeNumber
+}
+func (n *binaryNode) pos() int { return n.left.pos() } // Début de l'expression
+func (n *binaryNode) history() int {
+	return maxInt(n.left.history(), n.right.history())
+}
+func (n *binaryNode) eval(ctx *Context) []float64 {
+	left, right := n.left.eval(ctx), n.right.eval(ctx)
+	out := make([]float64, len(left))
+	for i := range out {
+		out[i] = n.apply(left[i], right[i])
+	}
+	return out
+}
+
+// apply applique l'opérateur à deux valeurs
+func (n *binaryNode) apply(a, b float64) float64 {
+	switch n.op {
+	case "and":
+		if a == 0 || b == 0 {
+			return 0
+		}
+		if a == 1 && b == 1 {
+			return 1
+		}
+		return math.NaN()
+	case "or":
+		if a == 1 || b == 1 {
+			return 1
+		}
+		if a == 0 && b == 0 {
+			return 0
+		}
+		return math.NaN()
+	}
+
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	switch n.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		if b == 0 {
+			return math.NaN()
+		}
+		return a / b
+	case "<":
+		return boolValue(a < b)
+	case "<=":
+		return boolValue(a <= b)
+	case ">":
+		return boolValue(a > b)
+	case ">=":
+		return boolValue(a >= b)
+	case "==":
+		return boolValue(a == b)
+	case "!=":
+		return boolValue(a != b)
+	}
+	panic(fmt.Sprintf("expr: unknown operator %s", n.op))
+}
+
+// callNode fonction intégrée
+type callNode struct {
+	at       int
+	name     string
+	args     []node
+	result   valueType
+	lookback int
+}
+
+func (n *callNode) typ() valueType { return n.result }
+func (n *callNode) pos() int       { return n.at }
+func (n *callNode) history() int {
+	inner := 0
+	for _, arg := range n.args {
+		inner = maxInt(inner, arg.history())
+	}
+	switch n.name {
+	case "crossover", "crossunder", "cross":
+		return inner + 1
+	case "any", "held":
+		return inner + n.lookback - 1
+	case "slope", "prev":
+		return inner + n.lookback
+	}
+	return inner
+}
+
+func (n *callNode) eval(ctx *Context) []float64 {
+	a := n.args[0].eval(ctx)
+	out := make([]float64, len(a))
+
+	switch n.name {
+	case "crossover", "crossunder", "cross":
+		b := n.args[1].eval(ctx)
+		for i := range out {
+			if i == 0 || math.IsNaN(a[i]) || math.IsNaN(b[i]) || math.IsNaN(a[i-1]) || math.IsNaN(b[i-1]) {
+				out[i] = math.NaN()
+				continue
+			}
+			up := a[i] > b[i] && a[i-1] <= b[i-1]
+			down := a[i] < b[i] && a[i-1] >= b[i-1]
+			switch n.name {
+			case "crossover":
+				out[i] = boolValue(up)
+			case "crossunder":
+				out[i] = boolValue(down)
+			default:
+				out[i] = boolValue(up || down)
+			}
+		}
+	case "any", "held":
+		for i := range out {
+			out[i] = window(a, i, n.lookback, n.name == "any")
+		}
+	case "slope", "prev":
+		for i := range out {
+			if i < n.lookback {
+				out[i] = math.NaN()
+			} else if n.name == "prev" {
+				out[i] = a[i-n.lookback]
+			} else {
+				out[i] = (a[i] - a[i-n.lookback]) / float64(n.lookback)
+			}
+		}
+	case "abs":
+		for i, v := range a {
+			out[i] = math.Abs(v)
+		}
+	case "min", "max":
+		b := n.args[1].eval(ctx)
+		for i := range out {
+			switch {
+			case math.IsNaN(a[i]) || math.IsNaN(b[i]):
+				out[i] = math.NaN()
+			case n.name == "min":
+				out[i] = math.Min(a[i], b[i])
+			default:
+				out[i] = math.Max(a[i], b[i])
+			}
+		}
+	}
+	return out
+}
+
+// window évalue any (au moins une vraie) ou held (toutes vraies) sur les n dernières bougies
+func window(values []float64, index, n int, any bool) float64 {
+	unknown := index-n+1 < 0
+	for j := maxInt(0, index-n+1); j <= index; j++ {
+		switch {
+		case values[j] == 1 && any:
+			return 1
+		case values[j] == 0 && !any:
+			return 0
+		case math.IsNaN(values[j]):
+			unknown = true
+		}
+	}
+	if unknown {
+		return math.NaN()
+	}
+	return boolValue(!any)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,149 @@
+package expr
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"agent-economique/internal/indicators"
+)
+
+// closesToKlines construit des bougies à partir de clôtures (volume constant)
+func closesToKlines(closes ...float64) []indicators.Kline {
+	klines := make([]indicators.Kline, len(closes))
+	for i, c := range closes {
+		klines[i] = indicators.Kline{Timestamp: int64(i) * 60000, Open: c, High: c + 1, Low: c - 1, Close: c, Volume: 100}
+	}
+	return klines
+}
+
+// mustBools compile et évalue une condition
+func mustBools(t *testing.T, source string, ctx *Context) []bool {
+	t.Helper()
+	e, err := CompileCondition(source)
+	if err != nil {
+		t.Fatalf("Compile(%q) failed: %v", source, err)
+	}
+	return e.Bools(ctx)
+}
+
+// expectBools compare une série booléenne
+func expectBools(t *testing.T, source string, got []bool, want ...bool) {
+	t.Helper()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: bar %d got %v, want %v (series %v)", source, i, got[i], want[i], got)
+		}
+	}
+}
+
+// TestExpr_Operators vérifie croisements, lookbacks, slope et logique
+func TestExpr_Operators(t *testing.T) {
+	ctx := NewContext(closesToKlines(10, 9, 11, 12, 8, 8, 13, 14))
+
+	expectBools(t, "crossover(close, 10)", mustBools(t, "crossover(close, 10)", ctx),
+		false, false, true, false, false, false, true, false)
+	expectBools(t, "crossunder(close, 10)", mustBools(t, "crossunder(close, 10)", ctx),
+		false, true, false, false, true, false, false, false)
+	expectBools(t, "any", mustBools(t, "any(crossover(close, 10), 3)", ctx),
+		false, false, true, true, true, false, true, true)
+	expectBools(t, "held", mustBools(t, "held(close > 10, 2)", ctx),
+		false, false, false, true, false, false, false, true)
+	expectBools(t, "slope", mustBools(t, "slope(close, 2) > 1 and not (close < 12)", ctx),
+		false, false, false, true, false, false, true, true)
+	expectBools(t, "arith", mustBools(t, "(high - low) / 2 == 1 && prev(close, 1) != close || false", ctx),
+		false, true, true, true, true, false, true, true)
+	expectBools(t, "abs/min/max", mustBools(t, "abs(close - 10) >= 2 and max(close, 12) == close and min(close, 0) < 1", ctx),
+		false, false, false, true, false, false, true, true)
+
+	// Inconnu pendant le warmup : ni vrai ni faux, not(inconnu) reste faux
+	e, _ := Compile("not (close > sma(3))")
+	values := e.Values(ctx)
+	if !math.IsNaN(values[1]) || math.IsNaN(values[2]) {
+		t.Errorf("Expected unknown until sma warmup, got %v", values)
+	}
+}
+
+// TestExpr_Indicators vérifie la résolution des indicateurs, le cache et l'historique
+func TestExpr_Indicators(t *testing.T) {
+	closes := make([]float64, 120)
+	for i := range closes {
+		closes[i] = 100 + 10*math.Sin(float64(i)/6)
+	}
+	klines := closesToKlines(closes...)
+	ctx := NewContext(klines)
+
+	e, err := CompileCondition("crossover(vwma(6), vwma(20)) and dmi(14, 14).adx > 0 or any(crossover(vwma(period=6), vwma(20)), 3)")
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if refs := e.Refs(); len(refs) != 3 || refs[0] != "dmi(14,14).adx" || refs[1] != "vwma(20).value" || refs[2] != "vwma(6).value" {
+		t.Errorf("Unexpected refs %v", refs)
+	}
+
+	fast, _ := indicators.ParseIndicatorRef("vwma(6)")
+	slow, _ := indicators.ParseIndicatorRef("vwma(20)")
+	f, s := fast.Compute(klines), slow.Compute(klines)
+	got := e.Bools(ctx)
+	crosses := 0
+	for i := 1; i < len(klines); i++ {
+		want := f[i] > s[i] && f[i-1] <= s[i-1]
+		if want {
+			crosses++
+			if !got[i] {
+				t.Errorf("Expected condition true at cross bar %d", i)
+			}
+		}
+	}
+	if crosses == 0 {
+		t.Fatal("Test series should contain VWMA crosses")
+	}
+
+	adx, _ := indicators.ParseIndicatorRef("dmi(14,14).adx")
+	if e.MinHistory() != adx.StableWarmup() {
+		t.Errorf("Expected history %d, got %d", adx.StableWarmup(), e.MinHistory())
+	}
+	lookback, _ := Compile("held(slope(sma(10), 3) > 0, 4)")
+	if lookback.MinHistory() != 10+3+3 {
+		t.Errorf("Expected nested lookback history 16, got %d", lookback.MinHistory())
+	}
+}
+
+// TestExpr_Errors vérifie les positions des erreurs de syntaxe et de typage
+func TestExpr_Errors(t *testing.T) {
+	cases := []struct {
+		source string
+		column int
+	}{
+		{"", 1},
+		{"close >", 8},
+		{"close > 10 and", 15},
+		{"crossover(close, 10", 20},
+		{"(close > 1", 11},
+		{"close > 1 )", 11},
+		{"close # 2", 7},
+		{"foo > 1", 1},
+		{"close > vwma(0)", 9},
+		{"close > dmi(14).pdi", 9},
+		{"close and close > 1", 1},
+		{"any(close > 1, x)", 16},
+		{"any(close > 1, 0)", 16},
+		{"held(close, 2)", 6},
+		{"crossover(close)", 16},
+		{"not close", 5},
+		{"close + (close > 1)", 10},
+		{"close + 1", 1},
+	}
+
+	for _, tc := range cases {
+		_, err := CompileCondition(tc.source)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%q: expected ParseError, got %v", tc.source, err)
+			continue
+		}
+		if perr.Pos+1 != tc.column {
+			t.Errorf("%q: expected column %d, got %d (%v)", tc.source, tc.column, perr.Pos+1, perr)
+		}
+	}
+}
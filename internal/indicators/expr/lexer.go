@@ -0,0 +1,122 @@
+// Package expr implémente un langage d'expressions de conditions évaluées par bougie
+// sur les sorties du registre d'indicateurs, ex:
+//
+//	crossover(vwma(6), vwma(20)) and dmi(14,14).adx > 20 and held(close > ema(50), 2)
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind type de lexème
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokLParen
+	tokRParen
+	tokComma
+	tokDot
+	tokOp
+)
+
+// token lexème avec sa position (offset en octets dans la source)
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// String retourne une description lisible du lexème pour les erreurs
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// ParseError erreur de syntaxe ou de typage avec position
+type ParseError struct {
+	Source string
+	Pos    int // Offset en octets (0 = premier caractère)
+	Msg    string
+}
+
+// Error retourne le message avec la colonne (1-based)
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos+1, e.Msg)
+}
+
+// Caret retourne la source soulignée à la position de l'erreur
+func (e *ParseError) Caret() string {
+	return e.Source + "\n" + strings.Repeat(" ", e.Pos) + "^"
+}
+
+// lexer découpe la source à la demande (le parser peut repositionner l'offset)
+type lexer struct {
+	src string
+	pos int
+}
+
+// next retourne le lexème suivant
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && isSpace(l.src[l.pos]) {
+		l.pos++
+	}
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case isDigit(c) || (c == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1])):
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		// Exposant éventuel (1e-4)
+		if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+			l.pos++
+			if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+				l.pos++
+			}
+			for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+				l.pos++
+			}
+		}
+		return token{kind: tokNumber, text: l.src[start:l.pos], pos: start}, nil
+	case isIdentStart(c):
+		for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: l.src[start:l.pos], pos: start}, nil
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokComma, text: ",", pos: start}, nil
+	case c == '.':
+		l.pos++
+		return token{kind: tokDot, text: ".", pos: start}, nil
+	}
+
+	for _, op := range []string{"&&", "||", ">=", "<=", "==", "!=", ">", "<", "+", "-", "*", "/", "!", "="} {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+	return token{}, &ParseError{Source: l.src, Pos: start, Msg: fmt.Sprintf("unexpected character %q", c)}
+}
+
+func isSpace(c byte) bool      { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+func isDigit(c byte) bool      { return c >= '0' && c <= '9' }
+func isIdentStart(c byte) bool { return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isIdentPart(c byte) bool  { return isIdentStart(c) || isDigit(c) }
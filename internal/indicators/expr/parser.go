@@ -0,0 +1,458 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"agent-economique/internal/indicators"
+)
+
+// valueType type statique d'un noeud
+type valueType int
+
+const (
+	typeNumber valueType = iota
+	typeBool
+)
+
+// String retourne le nom du type pour les erreurs
+func (t valueType) String() string {
+	if t == typeBool {
+		return "condition"
+	}
+	return "number"
+}
+
+// builtin décrit une fonction intégrée
+type builtin struct {
+	args     []valueType
+	result   valueType
+	lookback bool // Dernier argument = lookback constant entier
+}
+
+// builtins fonctions disponibles
+var builtins = map[string]builtin{
+	"crossover":  {args: []valueType{typeNumber, typeNumber}, result: typeBool},
+	"crossunder": {args: []valueType{typeNumber, typeNumber}, result: typeBool},
+	"cross":      {args: []valueType{typeNumber, typeNumber}, result: typeBool},
+	"any":        {args: []valueType{typeBool, typeNumber}, result: typeBool, lookback: true},
+	"held":       {args: []valueType{typeBool, typeNumber}, result: typeBool, lookback: true},
+	"slope":      {args: []valueType{typeNumber, typeNumber}, result: typeNumber, lookback: true},
+	"prev":       {args: []valueType{typeNumber, typeNumber}, result: typeNumber, lookback: true},
+	"abs":        {args: []valueType{typeNumber}, result: typeNumber},
+	"min":        {args: []valueType{typeNumber, typeNumber}, result: typeNumber},
+	"max":        {args: []valueType{typeNumber, typeNumber}, result: typeNumber},
+}
+
+// priceFields séries de prix accessibles directement
+var priceFields = map[string]func(indicators.Kline) float64{
+	"open":   func(k indicators.Kline) float64 { return k.Open },
+	"high":   func(k indicators.Kline) float64 { return k.High },
+	"low":    func(k indicators.Kline) float64 { return k.Low },
+	"close":  func(k indicators.Kline) float64 { return k.Close },
+	"volume": func(k indicators.Kline) float64 { return k.Volume },
+	"hl2":    func(k indicators.Kline) float64 { return (k.High + k.Low) / 2 },
+	"hlc3":   func(k indicators.Kline) float64 { return (k.High + k.Low + k.Close) / 3 },
+}
+
+// parser descente récursive :
+//
+//	or      := and (("or" | "||") and)*
+//	and     := not (("and" | "&&") not)*
+//	not     := ("not" | "!") not | compare
+//	compare := sum (("<" | "<=" | ">" | ">=" | "==" | "!=") sum)?
+//	sum     := product (("+" | "-") product)*
+//	product := unary (("*" | "/") unary)*
+//	unary   := "-" unary | primary
+//	primary := number | "true" | "false" | "(" or ")" | call | field | indicator
+type parser struct {
+	lex      *lexer
+	tok      token
+	registry *indicators.Registry
+	refs     map[string]*indicators.IndicatorRef
+}
+
+// errorf crée une erreur positionnée
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	return &ParseError{Source: p.lex.src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// advance lit le lexème suivant
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// isKeyword teste un mot-clé ou opérateur équivalent
+func (p *parser) isKeyword(words ...string) bool {
+	if p.tok.kind != tokIdent && p.tok.kind != tokOp {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(p.tok.text, w) && (p.tok.kind == tokOp) == !isIdentStart(w[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// expect consomme un lexème du type attendu
+func (p *parser) expect(kind tokenKind, text string) error {
+	if p.tok.kind != kind {
+		return p.errorf(p.tok.pos, "expected %q, found %s", text, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or", "||") {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.logical(op, "or", left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and", "&&") {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.logical(op, "and", left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// logical vérifie les opérandes booléens d'un opérateur logique
+func (p *parser) logical(op token, name string, left, right node) (node, error) {
+	if left.typ() != typeBool {
+		return nil, p.errorf(left.pos(), "left operand of %q must be a condition, got %s", name, left.typ())
+	}
+	if right.typ() != typeBool {
+		return nil, p.errorf(right.pos(), "right operand of %q must be a condition, got %s", name, right.typ())
+	}
+	return &binaryNode{at: op.pos, op: name, left: left, right: right}, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isKeyword("not", "!") {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if operand.typ() != typeBool {
+			return nil, p.errorf(operand.pos(), "operand of \"not\" must be a condition, got %s", operand.typ())
+		}
+		return &unaryNode{at: op.pos, op: "not", operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokOp {
+		return left, nil
+	}
+	switch p.tok.text {
+	case "<", "<=", ">", ">=", "==", "!=":
+	default:
+		return left, nil
+	}
+
+	op := p.tok
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.numeric(op, left, right); err != nil {
+		return nil, err
+	}
+	return &binaryNode{at: op.pos, op: op.text, left: left, right: right, boolean: true}, nil
+}
+
+// numeric vérifie les opérandes numériques d'un opérateur
+func (p *parser) numeric(op token, left, right node) error {
+	if left.typ() != typeNumber {
+		return p.errorf(left.pos(), "left operand of %q must be a number, got %s", op.text, left.typ())
+	}
+	if right.typ() != typeNumber {
+		return p.errorf(right.pos(), "right operand of %q must be a number, got %s", op.text, right.typ())
+	}
+	return nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.numeric(op, left, right); err != nil {
+			return nil, err
+		}
+		left = &binaryNode{at: op.pos, op: op.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "*" || p.tok.text == "/") {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.numeric(op, left, right); err != nil {
+			return nil, err
+		}
+		left = &binaryNode{at: op.pos, op: op.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.tok.kind == tokOp && p.tok.text == "-" {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if operand.typ() != typeNumber {
+			return nil, p.errorf(operand.pos(), "operand of \"-\" must be a number, got %s", operand.typ())
+		}
+		if n, ok := operand.(*numberNode); ok {
+			return &numberNode{at: op.pos, value: -n.value}, nil
+		}
+		return &unaryNode{at: op.pos, op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok.pos, "invalid number %q", tok.text)
+		}
+		return &numberNode{at: tok.pos, value: value}, p.advance()
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf(p.tok.pos, "expected \")\" to close \"(\" at column %d, found %s", tok.pos+1, p.tok)
+		}
+		return inner, p.advance()
+	case tokIdent:
+		return p.parseIdent()
+	}
+	return nil, p.errorf(tok.pos, "unexpected %s", tok)
+}
+
+// peekIsCall indique si l'identifiant courant est suivi de "("
+func (p *parser) peekIsCall() bool {
+	saved := p.lex.pos
+	defer func() { p.lex.pos = saved }()
+	next, err := p.lex.next()
+	return err == nil && next.kind == tokLParen
+}
+
+// parseIdent résout booléens, fonctions, champs de prix et indicateurs
+func (p *parser) parseIdent() (node, error) {
+	tok := p.tok
+	name := strings.ToLower(tok.text)
+
+	switch name {
+	case "true", "false":
+		return &boolNode{at: tok.pos, value: name == "true"}, p.advance()
+	case "and", "or", "not":
+		return nil, p.errorf(tok.pos, "unexpected keyword %q", tok.text)
+	}
+
+	if fn, ok := builtins[name]; ok && p.peekIsCall() {
+		return p.parseCall(tok, name, fn)
+	}
+	if field, ok := priceFields[name]; ok && !p.peekIsCall() {
+		return &fieldNode{at: tok.pos, name: name, value: field}, p.advance()
+	}
+	if _, ok := p.registry.Lookup(name); ok {
+		return p.parseIndicator(tok)
+	}
+	return nil, p.errorf(tok.pos, "unknown identifier %q", tok.text)
+}
+
+// parseCall lit les arguments d'une fonction intégrée et vérifie leurs types
+func (p *parser) parseCall(tok token, name string, fn builtin) (node, error) {
+	if err := p.advance(); err != nil { // Nom
+		return nil, err
+	}
+	open := p.tok
+	if err := p.advance(); err != nil { // "("
+		return nil, err
+	}
+
+	var args []node
+	for p.tok.kind != tokRParen {
+		if len(args) > 0 {
+			if err := p.expect(tokComma, ","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.tok.kind == tokEOF {
+			return nil, p.errorf(p.tok.pos, "expected \")\" to close %s( at column %d", name, open.pos+1)
+		}
+	}
+	closing := p.tok
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if len(args) != len(fn.args) {
+		return nil, p.errorf(closing.pos, "%s expects %d arguments, got %d", name, len(fn.args), len(args))
+	}
+	call := &callNode{at: tok.pos, name: name, args: args, result: fn.result}
+	for i, arg := range args {
+		if fn.lookback && i == len(args)-1 {
+			n, ok := arg.(*numberNode)
+			if !ok || n.value < 1 || n.value != math.Trunc(n.value) {
+				return nil, p.errorf(arg.pos(), "%s lookback must be a positive integer constant", name)
+			}
+			call.lookback = int(n.value)
+			continue
+		}
+		if arg.typ() != fn.args[i] {
+			return nil, p.errorf(arg.pos(), "argument %d of %s must be a %s, got %s", i+1, name, fn.args[i], arg.typ())
+		}
+	}
+	return call, nil
+}
+
+// parseIndicator lit une référence brute nom(args).sortie et la résout via le registre
+func (p *parser) parseIndicator(tok token) (node, error) {
+	src := p.lex.src
+	end := tok.pos + len(tok.text)
+
+	cursor := skipSpaces(src, end)
+	if cursor < len(src) && src[cursor] == '(' {
+		depth := 0
+		closing := -1
+		for i := cursor; i < len(src); i++ {
+			if src[i] == '(' {
+				depth++
+			} else if src[i] == ')' {
+				depth--
+				if depth == 0 {
+					closing = i
+					break
+				}
+			}
+		}
+		if closing < 0 {
+			return nil, p.errorf(cursor, "expected \")\" to close %s(", tok.text)
+		}
+		end = closing + 1
+		cursor = skipSpaces(src, end)
+	}
+	if cursor < len(src) && src[cursor] == '.' {
+		outStart := skipSpaces(src, cursor+1)
+		outEnd := outStart
+		for outEnd < len(src) && isIdentPart(src[outEnd]) {
+			outEnd++
+		}
+		if outEnd == outStart {
+			return nil, p.errorf(outStart, "expected output name after \".\"")
+		}
+		end = outEnd
+	}
+
+	text := src[tok.pos:end]
+	ref, err := p.registry.Parse(text)
+	if err != nil {
+		return nil, p.errorf(tok.pos, "%v", err)
+	}
+	key := ref.String()
+	if existing, ok := p.refs[key]; ok {
+		ref = existing
+	} else {
+		p.refs[key] = ref
+	}
+
+	p.lex.pos = end
+	return &refNode{at: tok.pos, ref: ref, key: key}, p.advance()
+}
+
+// skipSpaces retourne l'offset du prochain caractère non blanc
+func skipSpaces(src string, pos int) int {
+	for pos < len(src) && isSpace(src[pos]) {
+		pos++
+	}
+	return pos
+}
@@ -0,0 +1,160 @@
+package indicators
+
+import (
+	"math"
+)
+
+// Styles de bandes Keltner (paramètre "Bands Style" TradingView)
+const (
+	KeltnerBandsATR       = "Average True Range" // ta.atr(atrLength) — défaut TV
+	KeltnerBandsTrueRange = "True Range"         // ta.tr(true)
+	KeltnerBandsRange     = "Range"              // ta.rma(high - low, length)
+)
+
+// KeltnerTVStandard - Implémentation Keltner Channels TradingView Standard
+// Formules Pine (indicateur intégré KC):
+//
+//	ma = exp ? ta.ema(src, length) : ta.sma(src, length)
+//	rangema = ta.atr(atrlength) | ta.tr(true) | ta.rma(high - low, length)
+//	upper = ma + rangema * mult, lower = ma - rangema * mult
+//
+// Défauts TV: length 20, mult 2, EMA, style "Average True Range", atrlength 10
+type KeltnerTVStandard struct {
+	period     int
+	mult       float64
+	atrPeriod  int
+	useEMA     bool
+	bandsStyle string
+}
+
+// NewKeltnerTVStandard crée une instance Keltner TV Standard (EMA, bandes ATR)
+func NewKeltnerTVStandard(period int, mult float64, atrPeriod int) *KeltnerTVStandard {
+	return &KeltnerTVStandard{
+		period:     period,
+		mult:       mult,
+		atrPeriod:  atrPeriod,
+		useEMA:     true,
+		bandsStyle: KeltnerBandsATR,
+	}
+}
+
+// NewKeltnerTVStandardWithOptions crée une instance avec moyenne et style de bandes explicites
+func NewKeltnerTVStandardWithOptions(period int, mult float64, atrPeriod int, useEMA bool, bandsStyle string) *KeltnerTVStandard {
+	return &KeltnerTVStandard{
+		period:     period,
+		mult:       mult,
+		atrPeriod:  atrPeriod,
+		useEMA:     useEMA,
+		bandsStyle: bandsStyle,
+	}
+}
+
+// Calculate calcule middle, upper et lower (source = close)
+func (kc *KeltnerTVStandard) Calculate(high, low, close []float64) (middle, upper, lower []float64) {
+	n := len(high)
+	if n != len(low) || n != len(close) {
+		return nil, nil, nil
+	}
+
+	if kc.useEMA {
+		middle = NewEMATVStandard(kc.period).Calculate(close)
+	} else {
+		middle = NewSMATVStandard(kc.period).Calculate(close)
+	}
+
+	rangeMA := kc.calculateRange(high, low, close)
+
+	upper = make([]float64, n)
+	lower = make([]float64, n)
+	for i := 0; i < n; i++ {
+		if math.IsNaN(middle[i]) || math.IsNaN(rangeMA[i]) {
+			upper[i] = math.NaN()
+			lower[i] = math.NaN()
+			continue
+		}
+		upper[i] = middle[i] + rangeMA[i]*kc.mult
+		lower[i] = middle[i] - rangeMA[i]*kc.mult
+	}
+
+	return middle, upper, lower
+}
+
+// calculateRange calcule la largeur de base selon le style de bandes
+func (kc *KeltnerTVStandard) calculateRange(high, low, close []float64) []float64 {
+	switch kc.bandsStyle {
+	case KeltnerBandsTrueRange:
+		tr := make([]float64, len(high))
+		for i := range high {
+			if i == 0 {
+				// ta.tr(true) : pas de close précédent => H-L
+				tr[i] = high[i] - low[i]
+				continue
+			}
+			tr[i] = math.Max(high[i]-low[i], math.Max(math.Abs(high[i]-close[i-1]), math.Abs(low[i]-close[i-1])))
+		}
+		return tr
+	case KeltnerBandsRange:
+		hl := make([]float64, len(high))
+		for i := range high {
+			hl[i] = high[i] - low[i]
+		}
+		return NewRMATVStandard(kc.period).Calculate(hl)
+	default:
+		return NewATRTVStandard(kc.atrPeriod).Calculate(high, low, close)
+	}
+}
+
+// CalculateFromKlines calcule le canal depuis des klines
+func (kc *KeltnerTVStandard) CalculateFromKlines(klines []Kline) (middle, upper, lower []float64) {
+	high, low, closes, _ := klineColumns(klines)
+	return kc.Calculate(high, low, closes)
+}
+
+// GetLastValues retourne les dernières valeurs valides (middle, upper, lower)
+func (kc *KeltnerTVStandard) GetLastValues(middle, upper, lower []float64) (float64, float64, float64) {
+	for i := len(middle) - 1; i >= 0; i-- {
+		if !math.IsNaN(upper[i]) {
+			return middle[i], upper[i], lower[i]
+		}
+	}
+	return math.NaN(), math.NaN(), math.NaN()
+}
+
+// GetSignal retourne la position du prix par rapport au canal
+func (kc *KeltnerTVStandard) GetSignal(price, upper, lower float64) string {
+	if math.IsNaN(upper) || math.IsNaN(lower) {
+		return "INCONNU"
+	}
+	if price > upper {
+		return "CASSURE_HAUTE"
+	}
+	if price < lower {
+		return "CASSURE_BASSE"
+	}
+	return "DANS_CANAL"
+}
+
+// DetecterSqueeze squeeze TTM : bandes de Bollinger entièrement à l'intérieur du canal de Keltner
+// Valeurs inconnues (warmup) : false
+func DetecterSqueeze(bbUpper, bbLower, kcUpper, kcLower []float64) []bool {
+	n := len(bbUpper)
+	out := make([]bool, n)
+	if len(bbLower) != n || len(kcUpper) != n || len(kcLower) != n {
+		return out
+	}
+	for i := 0; i < n; i++ {
+		if math.IsNaN(bbUpper[i]) || math.IsNaN(bbLower[i]) || math.IsNaN(kcUpper[i]) || math.IsNaN(kcLower[i]) {
+			continue
+		}
+		out[i] = bbUpper[i] < kcUpper[i] && bbLower[i] > kcLower[i]
+	}
+	return out
+}
+
+// DetecterSortieSqueeze squeeze relâché sur la bougie index (squeeze en index-1, plus en index)
+func DetecterSortieSqueeze(squeeze []bool, index int) bool {
+	if index < 1 || index >= len(squeeze) {
+		return false
+	}
+	return squeeze[index-1] && !squeeze[index]
+}
@@ -0,0 +1,165 @@
+package indicators
+
+import (
+	"math"
+
+	"agent-economique/internal/shared"
+)
+
+// OrderFlow - Flux agresseur par bougie
+// Le volume acheteur (vendeur) est celui des ordres market qui ont pris la liquidité côté
+// achat (vente). Trois sources, de la plus exacte à la plus approchée :
+//
+//	trades      : IsBuyerMaker = true => l'agresseur est le vendeur
+//	klines      : TakerBuyBaseAssetVolume (exact par bougie, sans détail intra-bougie)
+//	bougies     : approximation par la position de la clôture dans le range
+//	              buy = volume * (close - low) / (high - low)
+type OrderFlow struct {
+	BuyVolume  []float64 // Volume agresseur acheteur
+	SellVolume []float64 // Volume agresseur vendeur
+	Delta      []float64 // BuyVolume - SellVolume
+	DeltaPct   []float64 // Delta / volume * 100 (NaN si volume nul)
+	CVD        []float64 // Cumul du delta depuis la première bougie de la série
+}
+
+// newOrderFlow construit delta, delta % et CVD depuis les volumes agresseurs
+func newOrderFlow(buy, sell []float64) *OrderFlow {
+	n := len(buy)
+	flow := &OrderFlow{
+		BuyVolume:  buy,
+		SellVolume: sell,
+		Delta:      make([]float64, n),
+		DeltaPct:   make([]float64, n),
+		CVD:        make([]float64, n),
+	}
+	cvd := 0.0
+	for i := 0; i < n; i++ {
+		flow.Delta[i] = buy[i] - sell[i]
+		flow.DeltaPct[i] = math.NaN()
+		if total := buy[i] + sell[i]; total > 0 {
+			flow.DeltaPct[i] = flow.Delta[i] / total * 100
+		}
+		cvd += flow.Delta[i]
+		flow.CVD[i] = cvd
+	}
+	return flow
+}
+
+// OrderFlowFromTakerBuy calcule le flux depuis le volume total et le volume taker buy
+func OrderFlowFromTakerBuy(volume, takerBuy []float64) *OrderFlow {
+	n := len(volume)
+	if len(takerBuy) != n {
+		return nil
+	}
+	buy, sell := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		buy[i] = math.Min(math.Max(takerBuy[i], 0), volume[i])
+		sell[i] = volume[i] - buy[i]
+	}
+	return newOrderFlow(buy, sell)
+}
+
+// OrderFlowFromKlineData calcule le flux depuis les klines Binance (TakerBuyBaseAssetVolume)
+func OrderFlowFromKlineData(klines []shared.KlineData) *OrderFlow {
+	volume, takerBuy := make([]float64, len(klines)), make([]float64, len(klines))
+	for i, k := range klines {
+		volume[i], takerBuy[i] = k.Volume, k.TakerBuyBaseAssetVolume
+	}
+	return OrderFlowFromTakerBuy(volume, takerBuy)
+}
+
+// OrderFlowFromCandles approxime le flux sans information d'agresseur (position de la clôture)
+// Une bougie sans range répartit son volume à parts égales.
+func OrderFlowFromCandles(high, low, close, volume []float64) *OrderFlow {
+	n := len(close)
+	if n != len(high) || n != len(low) || n != len(volume) {
+		return nil
+	}
+	buy, sell := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		share := 0.5
+		if rng := high[i] - low[i]; rng > 0 {
+			share = (close[i] - low[i]) / rng
+		}
+		buy[i] = volume[i] * share
+		sell[i] = volume[i] - buy[i]
+	}
+	return newOrderFlow(buy, sell)
+}
+
+// OrderFlowFromKlines approxime le flux depuis des klines (OrderFlowFromCandles)
+func OrderFlowFromKlines(klines []Kline) *OrderFlow {
+	high, low, closes, volume := klineColumns(klines)
+	return OrderFlowFromCandles(high, low, closes, volume)
+}
+
+// OrderFlowFromTrades calcule le flux exact depuis les trades
+// barTimestamps = ouverture des bougies en ms (croissant), trades triés par temps.
+// Même découpage que VWAPTVStandard.CalculateFromTrades : la bougie i reçoit les trades
+// jusqu'à l'ouverture de la bougie i+1, les trades antérieurs à la première bougie sont ignorés.
+func OrderFlowFromTrades(barTimestamps []int64, trades []shared.TradeData) *OrderFlow {
+	n := len(barTimestamps)
+	buy, sell := make([]float64, n), make([]float64, n)
+	t := 0
+	for t < len(trades) && n > 0 && trades[t].Time < barTimestamps[0] {
+		t++
+	}
+	for i := 0; i < n; i++ {
+		for t < len(trades) && (i == n-1 || trades[t].Time < barTimestamps[i+1]) {
+			if trades[t].IsBuyerMaker {
+				sell[i] += trades[t].Quantity
+			} else {
+				buy[i] += trades[t].Quantity
+			}
+			t++
+		}
+	}
+	return newOrderFlow(buy, sell)
+}
+
+// Imbalance déséquilibre acheteur/vendeur pondéré par le volume sur period bougies :
+// (Σ buy - Σ sell) / (Σ buy + Σ sell), entre -1 et 1. NaN pendant le warmup ou sans volume.
+func (f *OrderFlow) Imbalance(period int) []float64 {
+	n := len(f.Delta)
+	out := make([]float64, n)
+	sumDelta, sumVolume := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		sumDelta += f.Delta[i]
+		sumVolume += f.BuyVolume[i] + f.SellVolume[i]
+		if i >= period {
+			sumDelta -= f.Delta[i-period]
+			sumVolume -= f.BuyVolume[i-period] + f.SellVolume[i-period]
+		}
+		out[i] = math.NaN()
+		if period > 0 && i >= period-1 && sumVolume > 0 {
+			out[i] = sumDelta / sumVolume
+		}
+	}
+	return out
+}
+
+// BarDeltaDivergence divergence delta / couleur de la bougie :
+// 1 bougie rouge avec delta positif (absorption vendeuse, haussier),
+// -1 bougie verte avec delta négatif (absorption acheteuse, baissier), 0 sinon.
+// minDeltaPct |delta %| minimal pour retenir la divergence.
+func (f *OrderFlow) BarDeltaDivergence(open, close []float64, minDeltaPct float64) []float64 {
+	out := make([]float64, len(f.Delta))
+	for i := range out {
+		if i >= len(open) || i >= len(close) || math.IsNaN(f.DeltaPct[i]) || math.Abs(f.DeltaPct[i]) < minDeltaPct {
+			continue
+		}
+		switch {
+		case close[i] < open[i] && f.Delta[i] > 0:
+			out[i] = 1
+		case close[i] > open[i] && f.Delta[i] < 0:
+			out[i] = -1
+		}
+	}
+	return out
+}
+
+// CVDDivergences divergences entre les pivots du prix et le CVD (DivergenceDetector) :
+// un plus bas plus bas du prix avec un CVD plus haut signale des vendeurs absorbés.
+func (f *OrderFlow) CVDDivergences(high, low []float64, config DivergenceConfig) []Divergence {
+	return NewDivergenceDetector(config).Detect(high, low, f.CVD)
+}
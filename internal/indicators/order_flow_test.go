@@ -0,0 +1,79 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"agent-economique/internal/shared"
+)
+
+// TestOrderFlow_Sources vérifie le flux exact (trades, taker buy) et l'approximation bougie
+func TestOrderFlow_Sources(t *testing.T) {
+	timestamps := []int64{0, 60000, 120000}
+	trades := []shared.TradeData{
+		{Time: -1, Quantity: 100},                    // Avant la série : ignoré
+		{Time: 10, Quantity: 3, IsBuyerMaker: false}, // Achat agresseur
+		{Time: 20, Quantity: 1, IsBuyerMaker: true},  // Vente agresseur
+		{Time: 60000, Quantity: 2, IsBuyerMaker: true},
+		{Time: 130000, Quantity: 4, IsBuyerMaker: false},
+	}
+	flow := OrderFlowFromTrades(timestamps, trades)
+	if flow.Delta[0] != 2 || flow.Delta[1] != -2 || flow.Delta[2] != 4 {
+		t.Errorf("Unexpected trade delta %v", flow.Delta)
+	}
+	if flow.CVD[2] != 4 || !almostEqual(flow.DeltaPct[0], 50) || flow.DeltaPct[1] != -100 {
+		t.Errorf("Unexpected CVD %v / delta %% %v", flow.CVD, flow.DeltaPct)
+	}
+
+	klines := []shared.KlineData{{Volume: 4, TakerBuyBaseAssetVolume: 3}, {Volume: 2}, {Volume: 0}}
+	exact := OrderFlowFromKlineData(klines)
+	if exact.Delta[0] != 2 || exact.Delta[1] != -2 || !math.IsNaN(exact.DeltaPct[2]) {
+		t.Errorf("Unexpected taker buy flow %v / %v", exact.Delta, exact.DeltaPct)
+	}
+
+	// Clôture aux 3/4 du range : 75 % du volume à l'achat ; sans range : moitié-moitié
+	approx := OrderFlowFromCandles([]float64{12, 10}, []float64{8, 10}, []float64{11, 10}, []float64{100, 50})
+	if !almostEqual(approx.BuyVolume[0], 75) || !almostEqual(approx.Delta[0], 50) || approx.Delta[1] != 0 {
+		t.Errorf("Unexpected candle approximation %v / %v", approx.BuyVolume, approx.Delta)
+	}
+}
+
+// TestOrderFlow_ImbalanceAndDivergence vérifie le déséquilibre glissant et la divergence delta/couleur
+func TestOrderFlow_ImbalanceAndDivergence(t *testing.T) {
+	flow := OrderFlowFromTakerBuy([]float64{10, 10, 20, 10}, []float64{10, 0, 15, 5})
+	imbalance := flow.Imbalance(2)
+	if !math.IsNaN(imbalance[0]) || !almostEqual(imbalance[1], 0) || !almostEqual(imbalance[2], 0) || !almostEqual(imbalance[3], 10.0/30) {
+		t.Errorf("Unexpected imbalance %v", imbalance)
+	}
+
+	// Bougie 1 verte vendue (baissier), bougie 2 rouge achetée (haussier)
+	open := []float64{100, 100, 102, 100}
+	closes := []float64{101, 102, 101, 100}
+	div := flow.BarDeltaDivergence(open, closes, 10)
+	if div[0] != 0 || div[1] != -1 || div[2] != 1 || div[3] != 0 {
+		t.Errorf("Unexpected bar delta divergence %v", div)
+	}
+	if div := flow.BarDeltaDivergence(open, closes, 60); div[2] != 0 {
+		t.Errorf("Delta of 50%% must be ignored with a 60%% threshold, got %v", div)
+	}
+}
+
+// TestVolumeReversal_FlowMode vérifie que le mode flux classe les bougies par delta
+func TestVolumeReversal_FlowMode(t *testing.T) {
+	// Bougies vertes en prix mais vendues, puis un achat agresseur massif
+	volume := []float64{10, 10, 10, 30}
+	flow := OrderFlowFromTakerBuy(volume, []float64{2, 3, 2, 28})
+	klines := make([]Kline, len(volume))
+	for i := range klines {
+		klines[i] = Kline{Open: 100, Close: 101, High: 102, Low: 99, Volume: volume[i]}
+	}
+
+	analyzer := NewVolumeReversalAnalyzer()
+	if candle := analyzer.AnalyzeVolumeReversal(klines, 3, 3, 2); candle.AverageInverseVolume != 0 {
+		t.Errorf("Candle mode should find no inverse (red) bar, got %+v", candle)
+	}
+	result := analyzer.AnalyzeFlowReversal(flow, 3, 3, 2)
+	if !result.IsReversalWithHighVolume || result.CurrentCandleColor != "verte" || !almostEqual(result.AverageInverseVolume, 23.0/3) {
+		t.Errorf("Unexpected flow reversal %+v", result)
+	}
+}
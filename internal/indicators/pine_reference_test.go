@@ -0,0 +1,425 @@
+package indicators
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Harnais de référence croisée hors ligne (Go vs transcription Python des ta.* Pine v5)
+//
+// ⚠️ Ce n'est PAS une parité TradingView : les bougies de testdata/pine_reference sont une
+// marche aléatoire déterministe (graine fixe) et les valeurs attendues sont calculées par
+// pine_reference.py, réécriture indépendante des built-ins Pine v5. Le harnais détecte
+// les divergences entre deux implémentations de la même spécification, pas un écart au
+// moteur TradingView.
+//
+// Les CSV reprennent la disposition "Export chart data" de TradingView :
+// time (unix s, ouverture), open, high, low, close, Volume, puis une colonne par plot.
+// Les valeurs sont comparées bougie par bougie après le warmup stabilisé du registre.
+
+const referenceDir = "testdata/pine_reference"
+
+// Tolérances relatives (échelle = max(|attendu|, moyenne des |attendu| de la colonne))
+const (
+	parityExactTolerance     = 1e-9                         // Fenêtres glissantes : calcul identique
+	parityRecursiveTolerance = registryConvergenceTolerance // Lissages récursifs : poids résiduel de l'amorçage
+)
+
+// parityFixture bougies et plots d'une fixture de référence
+type parityFixture struct {
+	name       string
+	timestamps []int64 // ms
+	open       []float64
+	high       []float64
+	low        []float64
+	close      []float64
+	volume     []float64
+	plots      map[string][]float64
+}
+
+// parityCase implémentation *_tv_standard comparée à sa référence Python
+type parityCase struct {
+	source    string   // Fichier couvert (sans _tv_standard.go)
+	fixture   string   // Fichier CSV
+	ref       string   // Référence du registre (sortie la plus lente) : warmup à ignorer
+	plots     []string // Titres des colonnes, dans l'ordre des séries calculées
+	tolerance float64
+	compute   func(f *parityFixture) [][]float64
+}
+
+// parityCases une entrée par implémentation TV Standard
+var parityCases = []parityCase{
+	{source: "sma", fixture: "sma_20.csv", ref: "sma(20)", plots: []string{"MA"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewSMATVStandard(20).Calculate(f.close)}
+		}},
+	{source: "ema", fixture: "ema_20.csv", ref: "ema(20)", plots: []string{"EMA"}, tolerance: parityRecursiveTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewEMATVStandard(20).Calculate(f.close)}
+		}},
+	{source: "rma", fixture: "rma_14.csv", ref: "rma(14)", plots: []string{"RMA"}, tolerance: parityRecursiveTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewRMATVStandard(14).Calculate(f.close)}
+		}},
+	{source: "hma", fixture: "hma_20.csv", ref: "hma(20)", plots: []string{"HMA"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewHMATVStandard(20).Calculate(f.close)}
+		}},
+	{source: "vwma", fixture: "vwma_20.csv", ref: "vwma(20)", plots: []string{"VWMA"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewVWMATVStandard(20).Calculate(f.close, f.volume)}
+		}},
+	{source: "atr", fixture: "atr_14.csv", ref: "atr(14)", plots: []string{"ATR"}, tolerance: parityRecursiveTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewATRTVStandard(14).Calculate(f.high, f.low, f.close)}
+		}},
+	{source: "dmi", fixture: "dmi_14_14.csv", ref: "dmi(14,14).adx", plots: []string{"+DI", "-DI", "ADX"}, tolerance: parityRecursiveTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			plus, minus, adx := NewDMITVStandardWithPeriods(14, 14).Calculate(f.high, f.low, f.close)
+			return [][]float64{plus, minus, adx}
+		}},
+	{source: "mfi", fixture: "mfi_14.csv", ref: "mfi(14)", plots: []string{"MF"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewMFITVStandard(14).Calculate(f.high, f.low, f.close, f.volume)}
+		}},
+	{source: "cci", fixture: "cci_20.csv", ref: "cci(20)", plots: []string{"CCI"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewCCITVStandard(20).Calculate(f.high, f.low, f.close)}
+		}},
+	{source: "chop", fixture: "chop_14.csv", ref: "chop(14)", plots: []string{"CHOP"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			return [][]float64{NewCHOPTVStandard(14).Calculate(f.high, f.low, f.close)}
+		}},
+	{source: "stoch", fixture: "stoch_14_3_3.csv", ref: "stoch(14,3,3).d", plots: []string{"%K", "%D"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			k, d := NewStochTVStandard(14, 3, 3).Calculate(f.high, f.low, f.close)
+			return [][]float64{k, d}
+		}},
+	{source: "macd", fixture: "macd_12_26_9.csv", ref: "macd(12,26,9).signal", plots: []string{"MACD", "Signal", "Histogram"}, tolerance: parityRecursiveTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			line, signal, hist := NewMACDTVStandard(12, 26, 9).Calculate(f.close)
+			return [][]float64{line, signal, hist}
+		}},
+	{source: "bollinger", fixture: "bb_20_2.csv", ref: "bb(20,2).upper", plots: []string{"Basis", "Upper", "Lower"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			basis, upper, lower := NewBollingerTVStandard(20, 2).Calculate(f.close)
+			return [][]float64{basis, upper, lower}
+		}},
+	{source: "keltner", fixture: "kc_20_2_10.csv", ref: "kc(20,2,10).upper", plots: []string{"Basis", "Upper", "Lower"}, tolerance: parityRecursiveTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			middle, upper, lower := NewKeltnerTVStandard(20, 2, 10).Calculate(f.high, f.low, f.close)
+			return [][]float64{middle, upper, lower}
+		}},
+	{source: "donchian", fixture: "donchian_20.csv", ref: "donchian(20).upper", plots: []string{"Upper", "Lower", "Basis"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			upper, lower, basis := NewDonchianTVStandard(20).Calculate(f.high, f.low)
+			return [][]float64{upper, lower, basis}
+		}},
+	{source: "supertrend", fixture: "supertrend_10_3.csv", ref: "supertrend(10,3).value", plots: []string{"Supertrend", "Direction"}, tolerance: parityRecursiveTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			line, direction := NewSupertrendTVStandard(10, 3).Calculate(f.high, f.low, f.close)
+			return [][]float64{line, direction}
+		}},
+	{source: "vwap", fixture: "vwap_session_1.csv", ref: "vwap(0,1).value", plots: []string{"VWAP", "Upper Band #1", "Lower Band #1"}, tolerance: parityExactTolerance,
+		compute: func(f *parityFixture) [][]float64 {
+			vwap, stdev := NewVWAPTVStandard(DailyUTCSession()).Calculate(f.timestamps, f.high, f.low, f.close, f.volume)
+			upper, lower := VWAPBands(vwap, stdev, 1)
+			return [][]float64{vwap, upper, lower}
+		}},
+}
+
+// TestPineReference compare chaque implémentation TV Standard à la référence Python
+func TestPineReference(t *testing.T) {
+	for _, tc := range parityCases {
+		tc := tc
+		t.Run(tc.source, func(t *testing.T) {
+			checkParityCase(t, tc, filepath.Join(referenceDir, tc.fixture), precisionRules)
+		})
+	}
+}
+
+// checkParityCase compare une implémentation à une fixture après vérification des règles
+func checkParityCase(t *testing.T, tc parityCase, path string, rules []precisionRule) {
+	t.Helper()
+	f, err := loadParityFixture(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, rule := range rules {
+		if problems := rule.check(f); len(problems) > 0 {
+			t.Fatalf("%s: rule %s violated:\n  %s", f.name, rule.id, strings.Join(problems, "\n  "))
+		}
+	}
+
+	ref, err := ParseIndicatorRef(tc.ref)
+	if err != nil {
+		t.Fatalf("ref %q: %v", tc.ref, err)
+	}
+	skip := ref.StableWarmup()
+	if skip >= len(f.timestamps) {
+		t.Fatalf("%s: %d bars, need more than %d (stable warmup of %s)", f.name, len(f.timestamps), skip, tc.ref)
+	}
+
+	series := tc.compute(f)
+	if len(series) != len(tc.plots) {
+		t.Fatalf("compute returned %d series for %d plots", len(series), len(tc.plots))
+	}
+	for p, title := range tc.plots {
+		expected, ok := f.plots[title]
+		if !ok {
+			t.Fatalf("%s: missing plot column %q", f.name, title)
+		}
+		if report := compareParity(f, title, series[p], expected, skip, ref.Warmup(), tc.tolerance); report != "" {
+			t.Error(report)
+		}
+	}
+}
+
+// TestPineReference_CoversAllImplementations exige une fixture par fichier *_tv_standard.go
+func TestPineReference_CoversAllImplementations(t *testing.T) {
+	files, err := filepath.Glob("*_tv_standard.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	covered := make(map[string]bool)
+	for _, tc := range parityCases {
+		covered[tc.source] = true
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(file, "_tv_standard.go")
+		if !covered[name] {
+			t.Errorf("%s has no Pine reference case (add a fixture to %s)", file, referenceDir)
+		}
+	}
+}
+
+// precisionRule règle de docs/indicateurs/indicateur_precision_rules.md vérifiable sur les données
+//
+// Les règles 1️⃣ (endpoint futures/spot) et 6️⃣ (démo) ne portent pas sur une fixture hors
+// ligne ; 5️⃣ (formules) est la comparaison elle-même ; 7️⃣ (cas limites) est couverte par
+// compareParity (aucun NaN/Inf après le warmup) et TestPineReference_EdgeCases.
+type precisionRule struct {
+	id    string
+	check func(f *parityFixture) []string
+}
+
+// parityInterval intervalle des fixtures (5m = +300s)
+const parityInterval = int64(300 * 1000)
+
+var precisionRules = []precisionRule{
+	// 2️⃣ Format : time en secondes unix (pas en ms), une ligne par bougie
+	{id: "2-format", check: func(f *parityFixture) []string {
+		var problems []string
+		if len(f.timestamps) < 2 {
+			return []string{"need at least 2 bars"}
+		}
+		minTime := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+		maxTime := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+		for i, ts := range f.timestamps {
+			if ts < minTime || ts > maxTime {
+				problems = append(problems, fmt.Sprintf("bar %d: time %d is not a unix timestamp in seconds", i, ts/1000))
+			}
+			if len(problems) >= 10 {
+				break
+			}
+		}
+		return problems
+	}},
+	// 3️⃣ Volume et valeurs : OHLC cohérents, volume fini et positif
+	{id: "3-values", check: func(f *parityFixture) []string {
+		var problems []string
+		for i := 0; i < len(f.timestamps) && len(problems) < 10; i++ {
+			o, h, l, c, v := f.open[i], f.high[i], f.low[i], f.close[i], f.volume[i]
+			if !(l > 0 && l <= math.Min(o, c) && h >= math.Max(o, c)) {
+				problems = append(problems, fmt.Sprintf("bar %d: incoherent OHLC %v/%v/%v/%v", i, o, h, l, c))
+			}
+			if !(v >= 0) || math.IsInf(v, 0) {
+				problems = append(problems, fmt.Sprintf("bar %d: invalid volume %v", i, v))
+			}
+		}
+		return problems
+	}},
+	// 4️⃣ Timestamps : OpenTime aligné sur l'intervalle, pas constant de 300s
+	{id: "4-timestamps", check: func(f *parityFixture) []string {
+		var problems []string
+		if len(f.timestamps) > 0 && f.timestamps[0]%parityInterval != 0 {
+			problems = append(problems, fmt.Sprintf("time %d is not a bar open time (interval %ds)", f.timestamps[0]/1000, parityInterval/1000))
+		}
+		for i := 1; i < len(f.timestamps) && len(problems) < 10; i++ {
+			if step := f.timestamps[i] - f.timestamps[i-1]; step != parityInterval {
+				problems = append(problems, fmt.Sprintf("bar %d: step %ds, expected %ds", i, step/1000, parityInterval/1000))
+			}
+		}
+		return problems
+	}},
+}
+
+// TestPineReference_PrecisionRules vérifie chaque règle de précision sur chaque fixture
+func TestPineReference_PrecisionRules(t *testing.T) {
+	for _, tc := range parityCases {
+		f, err := loadParityFixture(filepath.Join(referenceDir, tc.fixture))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		for _, rule := range precisionRules {
+			t.Run(tc.source+"/"+rule.id, func(t *testing.T) {
+				if problems := rule.check(f); len(problems) > 0 {
+					t.Errorf("%s: rule %s violated:\n  %s", tc.fixture, rule.id, strings.Join(problems, "\n  "))
+				}
+			})
+		}
+	}
+}
+
+// TestPineReference_EdgeCases règle 7️⃣ : bougies plates et volume nul ne produisent jamais ±Inf
+func TestPineReference_EdgeCases(t *testing.T) {
+	const n = 120
+	f := &parityFixture{name: "flat"}
+	for i := 0; i < n; i++ {
+		f.timestamps = append(f.timestamps, 1709582400000+int64(i)*parityInterval)
+		f.open = append(f.open, 100)
+		f.high = append(f.high, 100)
+		f.low = append(f.low, 100)
+		f.close = append(f.close, 100)
+		f.volume = append(f.volume, 0)
+	}
+	for _, tc := range parityCases {
+		for p, values := range tc.compute(f) {
+			for i, v := range values {
+				if math.IsInf(v, 0) {
+					t.Errorf("%s %s: bar %d is %v on flat zero-volume bars", tc.source, tc.plots[p], i, v)
+					break
+				}
+			}
+		}
+	}
+}
+
+// compareParity compare une série à la colonne de référence et retourne un rapport lisible ("" si conforme)
+//
+// 7️⃣ Cas limites : toute valeur NaN/Inf calculée après le warmup du registre est une erreur.
+func compareParity(f *parityFixture, title string, got, want []float64, skip, warmup int, tolerance float64) string {
+	if len(got) != len(want) {
+		return fmt.Sprintf("%s %s: %d values, expected %d", f.name, title, len(got), len(want))
+	}
+
+	scale, count := 0.0, 0
+	for i := skip; i < len(want); i++ {
+		if !math.IsNaN(want[i]) {
+			scale += math.Abs(want[i])
+			count++
+		}
+	}
+	if count > 0 {
+		scale /= float64(count)
+	}
+
+	var lines []string
+	mismatches, maxDiff, maxAt := 0, 0.0, -1
+	for i := warmup - 1; i < len(got); i++ {
+		if i < 0 {
+			continue
+		}
+		if math.IsNaN(got[i]) || math.IsInf(got[i], 0) {
+			if i >= skip || !math.IsNaN(want[i]) {
+				mismatches++
+				lines = appendParityLine(lines, f, i, got[i], want[i], math.Inf(1))
+			}
+			continue
+		}
+		if i < skip || math.IsNaN(want[i]) {
+			continue
+		}
+		diff := math.Abs(got[i] - want[i])
+		if diff > maxDiff {
+			maxDiff, maxAt = diff, i
+		}
+		if diff > tolerance*math.Max(math.Abs(want[i]), scale) {
+			mismatches++
+			lines = appendParityLine(lines, f, i, got[i], want[i], diff)
+		}
+	}
+	if mismatches == 0 {
+		return ""
+	}
+
+	header := fmt.Sprintf("%s %s: %d/%d bars out of tolerance %.0e (compared from bar %d, max diff %.6g at bar %d)",
+		f.name, title, mismatches, len(want)-skip, tolerance, skip, maxDiff, maxAt)
+	if mismatches > len(lines) {
+		lines = append(lines, fmt.Sprintf("... %d more", mismatches-len(lines)))
+	}
+	return header + "\n  " + strings.Join(lines, "\n  ")
+}
+
+// appendParityLine ajoute une ligne de rapport (10 premières divergences)
+func appendParityLine(lines []string, f *parityFixture, i int, got, want, diff float64) []string {
+	if len(lines) >= 10 {
+		return lines
+	}
+	at := time.UnixMilli(f.timestamps[i]).UTC().Format("2006-01-02 15:04")
+	return append(lines, fmt.Sprintf("bar %4d %s UTC  got %-22.12g want %-22.12g diff %.3g", i, at, got, want, diff))
+}
+
+// loadParityFixture lit une fixture au format "Export chart data" (NaN ou vide = na)
+func loadParityFixture(path string) (*parityFixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture: %w", err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s: no data rows", path)
+	}
+
+	header := records[0]
+	columns := make(map[string]int, len(header))
+	for i, title := range header {
+		columns[strings.TrimSpace(title)] = i
+	}
+	for _, required := range []string{"time", "open", "high", "low", "close", "Volume"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%s: missing column %q", path, required)
+		}
+	}
+
+	f := &parityFixture{name: filepath.Base(path), plots: make(map[string][]float64)}
+	for row, record := range records[1:] {
+		values := make([]float64, len(record))
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if cell == "" || cell == "NaN" {
+				values[i] = math.NaN()
+				continue
+			}
+			v, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: row %d column %q: %w", path, row+2, header[i], err)
+			}
+			values[i] = v
+		}
+		f.timestamps = append(f.timestamps, int64(values[columns["time"]])*1000)
+		f.open = append(f.open, values[columns["open"]])
+		f.high = append(f.high, values[columns["high"]])
+		f.low = append(f.low, values[columns["low"]])
+		f.close = append(f.close, values[columns["close"]])
+		f.volume = append(f.volume, values[columns["Volume"]])
+		for i, title := range header {
+			if i < len(values) && i > columns["Volume"] {
+				f.plots[strings.TrimSpace(title)] = append(f.plots[strings.TrimSpace(title)], values[i])
+			}
+		}
+	}
+	return f, nil
+}
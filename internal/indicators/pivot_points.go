@@ -0,0 +1,121 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// PivotType méthode de calcul des points pivots
+type PivotType string
+
+const (
+	PivotFloor     PivotType = "floor"     // TV "Traditional"
+	PivotCamarilla PivotType = "camarilla" // TV "Camarilla"
+	PivotFibonacci PivotType = "fibonacci" // TV "Fibonacci"
+)
+
+// PivotLevels niveaux pivots d'une période (NaN si inconnus)
+type PivotLevels struct {
+	P  float64
+	R1 float64
+	R2 float64
+	R3 float64
+	R4 float64 // Camarilla uniquement
+	S1 float64
+	S2 float64
+	S3 float64
+	S4 float64 // Camarilla uniquement
+}
+
+// Levels retourne les niveaux connus (S4..R4) triés par prix croissant
+func (l PivotLevels) Levels() []float64 {
+	var out []float64
+	for _, v := range []float64{l.S4, l.S3, l.S2, l.S1, l.P, l.R1, l.R2, l.R3, l.R4} {
+		if !math.IsNaN(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// ComputePivotLevels calcule les pivots depuis le high, low et close de la période précédente
+//
+//	P = (H + L + C) / 3
+//	floor     : R1 = 2P - L, S1 = 2P - H, R2 = P + (H - L), S2 = P - (H - L),
+//	            R3 = H + 2(P - L), S3 = L - 2(H - P)
+//	camarilla : R/Sn = C ± 1.1 × (H - L) / {12, 6, 4, 2}
+//	fibonacci : R/Sn = P ± (H - L) × {0.382, φ⁻¹, 1.000} (ratios de
+//	            docs/indicateurs/fibonacci_tradingview_research.md)
+func ComputePivotLevels(kind PivotType, high, low, close float64) (PivotLevels, error) {
+	nan := math.NaN()
+	p := (high + low + close) / 3
+	rng := high - low
+	levels := PivotLevels{P: p, R4: nan, S4: nan}
+	switch kind {
+	case PivotFloor:
+		levels.R1, levels.S1 = 2*p-low, 2*p-high
+		levels.R2, levels.S2 = p+rng, p-rng
+		levels.R3, levels.S3 = high+2*(p-low), low-2*(high-p)
+	case PivotCamarilla:
+		levels.R1, levels.S1 = close+1.1*rng/12, close-1.1*rng/12
+		levels.R2, levels.S2 = close+1.1*rng/6, close-1.1*rng/6
+		levels.R3, levels.S3 = close+1.1*rng/4, close-1.1*rng/4
+		levels.R4, levels.S4 = close+1.1*rng/2, close-1.1*rng/2
+	case PivotFibonacci:
+		phiInv := 2 / (1 + math.Sqrt(5)) // 0.618033988749895
+		levels.R1, levels.S1 = p+rng*0.382, p-rng*0.382
+		levels.R2, levels.S2 = p+rng*phiInv, p-rng*phiInv
+		levels.R3, levels.S3 = p+rng, p-rng
+	default:
+		return PivotLevels{}, fmt.Errorf("unknown pivot type %q (floor, camarilla, fibonacci)", kind)
+	}
+	return levels, nil
+}
+
+// PivotPoints - Points pivots de session (TV "Pivot Points Standard")
+// Chaque bougie reçoit les niveaux calculés sur la session précédente complète (même
+// découpage que VWAPSession : DailyUTCSession par défaut). NaN pendant la première session,
+// et pendant la deuxième si la série commence en cours de session (première incomplète).
+type PivotPoints struct {
+	kind    PivotType
+	session VWAPSession
+}
+
+// NewPivotPoints crée un calcul de pivots (type validé)
+func NewPivotPoints(kind PivotType, session VWAPSession) (*PivotPoints, error) {
+	if _, err := ComputePivotLevels(kind, 0, 0, 0); err != nil {
+		return nil, err
+	}
+	return &PivotPoints{kind: kind, session: session}, nil
+}
+
+// Calculate calcule les pivots de chaque bougie (timestamps = ouvertures en ms)
+func (pp *PivotPoints) Calculate(timestamps []int64, high, low, close []float64) []PivotLevels {
+	n := len(timestamps)
+	out := make([]PivotLevels, n)
+	nan := math.NaN()
+	current := PivotLevels{P: nan, R1: nan, R2: nan, R3: nan, R4: nan, S1: nan, S2: nan, S3: nan, S4: nan}
+
+	session := int64(math.MinInt64)
+	sessHigh, sessLow, sessClose := nan, nan, nan
+	partial := n > 0 && pp.session.Start(timestamps[0]) != timestamps[0]
+	for i := 0; i < n; i++ {
+		if start := pp.session.Start(timestamps[i]); start != session {
+			if !math.IsNaN(sessClose) && !partial {
+				current, _ = ComputePivotLevels(pp.kind, sessHigh, sessLow, sessClose)
+			}
+			partial = partial && math.IsNaN(sessClose)
+			session = start
+			sessHigh, sessLow = high[i], low[i]
+		}
+		sessHigh, sessLow, sessClose = math.Max(sessHigh, high[i]), math.Min(sessLow, low[i]), close[i]
+		out[i] = current
+	}
+	return out
+}
+
+// CalculateFromKlines calcule les pivots depuis des klines
+func (pp *PivotPoints) CalculateFromKlines(klines []Kline) []PivotLevels {
+	high, low, closes, _ := klineColumns(klines)
+	return pp.Calculate(klineTimestamps(klines), high, low, closes)
+}
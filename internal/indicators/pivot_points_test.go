@@ -0,0 +1,60 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPivotLevels_Formulas vérifie floor, Camarilla et Fibonacci sur H=110, L=90, C=105
+func TestPivotLevels_Formulas(t *testing.T) {
+	floor, _ := ComputePivotLevels(PivotFloor, 110, 90, 105)
+	if !almostEqual(floor.P, 305.0/3) || !almostEqual(floor.R1, 2*305.0/3-90) || !almostEqual(floor.S2, 305.0/3-20) ||
+		!almostEqual(floor.R3, 110+2*(305.0/3-90)) || !math.IsNaN(floor.R4) {
+		t.Errorf("Unexpected floor pivots %+v", floor)
+	}
+
+	cam, _ := ComputePivotLevels(PivotCamarilla, 110, 90, 105)
+	if !almostEqual(cam.R1, 105+22.0/12) || !almostEqual(cam.S3, 105-5.5) || !almostEqual(cam.R4, 116) {
+		t.Errorf("Unexpected Camarilla pivots %+v", cam)
+	}
+
+	fib, _ := ComputePivotLevels(PivotFibonacci, 110, 90, 105)
+	if !almostEqual(fib.R1, 305.0/3+7.64) || !almostEqual(fib.S2, 305.0/3-20*0.618033988749895) || !almostEqual(fib.R3, 305.0/3+20) {
+		t.Errorf("Unexpected Fibonacci pivots %+v", fib)
+	}
+	if levels := fib.Levels(); len(levels) != 7 || levels[0] != fib.S3 || levels[6] != fib.R3 {
+		t.Errorf("Unexpected sorted levels %v", levels)
+	}
+
+	if _, err := NewPivotPoints("woodie", DailyUTCSession()); err == nil {
+		t.Errorf("Expected unknown pivot type error")
+	}
+}
+
+// TestPivotPoints_PreviousSession vérifie que chaque bougie utilise la session précédente complète
+func TestPivotPoints_PreviousSession(t *testing.T) {
+	const hour = int64(3600000)
+	day := 24 * hour
+	// Série commençant à 12:00 (session 0 incomplète), puis deux sessions complètes de 2 bougies 12h
+	timestamps := []int64{12 * hour, day, day + 12*hour, 2 * day, 2*day + 12*hour, 3 * day}
+	high := []float64{200, 110, 108, 120, 125, 130}
+	low := []float64{50, 95, 90, 100, 105, 110}
+	closes := []float64{100, 100, 105, 110, 115, 120}
+
+	pp, err := NewPivotPoints(PivotFloor, DailyUTCSession())
+	if err != nil {
+		t.Fatalf("NewPivotPoints failed: %v", err)
+	}
+	levels := pp.Calculate(timestamps, high, low, closes)
+	for i := 0; i < 3; i++ {
+		if !math.IsNaN(levels[i].P) {
+			t.Errorf("bar %d: expected NaN pivots (incomplete first session), got %v", i, levels[i].P)
+		}
+	}
+	if !almostEqual(levels[3].P, (110+90+105)/3.0) || levels[4].P != levels[3].P {
+		t.Errorf("Unexpected pivots of day 2: %v / %v", levels[3].P, levels[4].P)
+	}
+	if !almostEqual(levels[5].P, (125+100+115)/3.0) {
+		t.Errorf("Unexpected pivot of day 3: %v", levels[5].P)
+	}
+}
@@ -0,0 +1,292 @@
+package indicators
+
+import (
+	"math"
+)
+
+// Regime état de marché discret
+type Regime string
+
+const (
+	RegimeUnknown       Regime = "INCONNU"         // Warmup des indicateurs
+	RegimeTrendUp       Regime = "TENDANCE_HAUSSE" // ADX fort, CHOP directionnel, VWMA montante
+	RegimeTrendDown     Regime = "TENDANCE_BAISSE" // ADX fort, CHOP directionnel, VWMA descendante
+	RegimeRange         Regime = "RANGE"           // Pas de tendance, volatilité normale
+	RegimeHighVolChoppy Regime = "CHOP_VOLATIL"    // Pas de tendance, ATR dans le haut de sa distribution
+)
+
+// Regimes liste des régimes classables (hors INCONNU)
+var Regimes = []Regime{RegimeTrendUp, RegimeTrendDown, RegimeRange, RegimeHighVolChoppy}
+
+// IsTrend indique un régime de tendance
+func (r Regime) IsTrend() bool {
+	return r == RegimeTrendUp || r == RegimeTrendDown
+}
+
+// Code valeur numérique du régime (séries / expressions) : 1 hausse, -1 baisse, 0 range,
+// 2 chop volatil, NaN inconnu
+func (r Regime) Code() float64 {
+	switch r {
+	case RegimeTrendUp:
+		return 1
+	case RegimeTrendDown:
+		return -1
+	case RegimeRange:
+		return 0
+	case RegimeHighVolChoppy:
+		return 2
+	}
+	return math.NaN()
+}
+
+// ParseRegime retourne le régime d'un libellé (ok = false si inconnu)
+func ParseRegime(label string) (Regime, bool) {
+	for _, r := range Regimes {
+		if string(r) == label {
+			return r, true
+		}
+	}
+	return RegimeUnknown, false
+}
+
+// RegimeConfig paramètres du classificateur
+type RegimeConfig struct {
+	Period             int `yaml:"period"`              // CHOP, DMI et ATR (défaut 14)
+	ADXSmoothing       int `yaml:"adx_smoothing"`       // Lissage ADX (défaut 14)
+	VWMAPeriod         int `yaml:"vwma_period"`         // VWMA de pente (défaut 20)
+	SlopeBars          int `yaml:"slope_bars"`          // Pente VWMA sur N bougies (défaut 5)
+	PercentileLookback int `yaml:"percentile_lookback"` // Fenêtre du percentile ATR (défaut 100)
+
+	ADXTrend          float64 `yaml:"adx_trend"`           // ADX minimal pour une tendance (défaut 25)
+	ChopTrend         float64 `yaml:"chop_trend"`          // CHOP maximal pour une tendance (défaut 50)
+	MinSlope          float64 `yaml:"min_slope"`           // |pente VWMA| minimale en % par bougie (défaut 0.01)
+	HighVolPercentile float64 `yaml:"high_vol_percentile"` // Percentile ATR du chop volatil (défaut 80)
+
+	// Hystérésis : seuils relâchés pour rester dans le régime courant
+	ADXHysteresis        float64 `yaml:"adx_hysteresis"`        // Défaut 5
+	ChopHysteresis       float64 `yaml:"chop_hysteresis"`       // Défaut 5
+	PercentileHysteresis float64 `yaml:"percentile_hysteresis"` // Défaut 10
+	// ConfirmBars bougies consécutives exigées avant de changer de régime (défaut 3)
+	ConfirmBars int `yaml:"confirm_bars"`
+}
+
+// DefaultRegimeConfig retourne la configuration par défaut
+func DefaultRegimeConfig() RegimeConfig {
+	return RegimeConfig{
+		Period:               14,
+		ADXSmoothing:         14,
+		VWMAPeriod:           20,
+		SlopeBars:            5,
+		PercentileLookback:   100,
+		ADXTrend:             25,
+		ChopTrend:            50,
+		MinSlope:             0.01,
+		HighVolPercentile:    80,
+		ADXHysteresis:        5,
+		ChopHysteresis:       5,
+		PercentileHysteresis: 10,
+		ConfirmBars:          3,
+	}
+}
+
+// withDefaults remplace les valeurs nulles par les défauts
+func (c RegimeConfig) withDefaults() RegimeConfig {
+	def := DefaultRegimeConfig()
+	ints := []struct{ v, d *int }{
+		{&c.Period, &def.Period}, {&c.ADXSmoothing, &def.ADXSmoothing}, {&c.VWMAPeriod, &def.VWMAPeriod},
+		{&c.SlopeBars, &def.SlopeBars}, {&c.PercentileLookback, &def.PercentileLookback}, {&c.ConfirmBars, &def.ConfirmBars},
+	}
+	for _, f := range ints {
+		if *f.v <= 0 {
+			*f.v = *f.d
+		}
+	}
+	floats := []struct{ v, d *float64 }{
+		{&c.ADXTrend, &def.ADXTrend}, {&c.ChopTrend, &def.ChopTrend}, {&c.MinSlope, &def.MinSlope},
+		{&c.HighVolPercentile, &def.HighVolPercentile}, {&c.ADXHysteresis, &def.ADXHysteresis},
+		{&c.ChopHysteresis, &def.ChopHysteresis}, {&c.PercentileHysteresis, &def.PercentileHysteresis},
+	}
+	for _, f := range floats {
+		if *f.v <= 0 {
+			*f.v = *f.d
+		}
+	}
+	return c
+}
+
+// Warmup nombre de bougies avant le premier régime classé
+func (c RegimeConfig) Warmup() int {
+	c = c.withDefaults()
+	warmup := c.Period + c.ADXSmoothing - 1 // ADX
+	if w := c.Period + c.PercentileLookback - 1; w > warmup {
+		warmup = w // Percentile ATR
+	}
+	if w := c.VWMAPeriod + c.SlopeBars; w > warmup {
+		warmup = w // Pente VWMA
+	}
+	return warmup
+}
+
+// RegimeSeries régimes et composantes alignés sur les bougies
+type RegimeSeries struct {
+	Labels        []Regime
+	ADX           []float64
+	CHOP          []float64
+	ATRPercentile []float64 // Rang de l'ATR courant dans sa fenêtre (0-100)
+	Slope         []float64 // Pente VWMA en % par bougie
+}
+
+// At retourne le régime de la bougie i (INCONNU hors série)
+func (s *RegimeSeries) At(i int) Regime {
+	if s == nil || i < 0 || i >= len(s.Labels) {
+		return RegimeUnknown
+	}
+	return s.Labels[i]
+}
+
+// Codes retourne la série numérique des régimes (Regime.Code)
+func (s *RegimeSeries) Codes() []float64 {
+	out := make([]float64, len(s.Labels))
+	for i, r := range s.Labels {
+		out[i] = r.Code()
+	}
+	return out
+}
+
+// RegimeClassifier classificateur de régime CHOP + ADX + percentile ATR + pente VWMA
+//
+// Chaque bougie reçoit un régime brut : tendance si ADX >= ADXTrend, CHOP <= ChopTrend et
+// |pente VWMA| >= MinSlope (sens = signe de la pente), sinon chop volatil si le percentile ATR
+// >= HighVolPercentile, sinon range. Hystérésis : les seuils du régime courant sont relâchés
+// (ADX - ADXHysteresis, CHOP + ChopHysteresis, percentile - PercentileHysteresis) et un
+// nouveau régime doit se maintenir ConfirmBars bougies avant d'être adopté.
+// Chaque valeur n'utilise que les bougies <= i.
+type RegimeClassifier struct {
+	config RegimeConfig
+}
+
+// NewRegimeClassifier crée un classificateur (valeurs nulles remplacées par les défauts)
+func NewRegimeClassifier(config RegimeConfig) *RegimeClassifier {
+	return &RegimeClassifier{config: config.withDefaults()}
+}
+
+// Config retourne la configuration effective
+func (rc *RegimeClassifier) Config() RegimeConfig {
+	return rc.config
+}
+
+// Classify calcule les régimes et leurs composantes
+func (rc *RegimeClassifier) Classify(high, low, close, volume []float64) *RegimeSeries {
+	n := len(close)
+	if n != len(high) || n != len(low) || n != len(volume) {
+		return nil
+	}
+	cfg := rc.config
+
+	_, _, adx := NewDMITVStandardWithPeriods(cfg.Period, cfg.ADXSmoothing).Calculate(high, low, close)
+	series := &RegimeSeries{
+		Labels:        make([]Regime, n),
+		ADX:           adx,
+		CHOP:          NewCHOPTVStandard(cfg.Period).Calculate(high, low, close),
+		ATRPercentile: PercentileRank(NewATRTVStandard(cfg.Period).Calculate(high, low, close), cfg.PercentileLookback),
+		Slope:         relativeSlope(NewVWMATVStandard(cfg.VWMAPeriod).Calculate(close, volume), cfg.SlopeBars),
+	}
+
+	current, candidate, streak := RegimeUnknown, RegimeUnknown, 0
+	for i := 0; i < n; i++ {
+		raw := rc.rawRegime(current, series.ADX[i], series.CHOP[i], series.ATRPercentile[i], series.Slope[i])
+		switch {
+		case raw == RegimeUnknown:
+			current, candidate, streak = RegimeUnknown, RegimeUnknown, 0
+		case current == RegimeUnknown || raw == current:
+			current, candidate, streak = raw, RegimeUnknown, 0
+		case raw == candidate:
+			streak++
+		default:
+			candidate, streak = raw, 1
+		}
+		if candidate != RegimeUnknown && streak >= cfg.ConfirmBars {
+			current, candidate, streak = candidate, RegimeUnknown, 0
+		}
+		series.Labels[i] = current
+	}
+	return series
+}
+
+// ClassifyFromKlines calcule les régimes depuis des klines
+func (rc *RegimeClassifier) ClassifyFromKlines(klines []Kline) *RegimeSeries {
+	high, low, closes, volume := klineColumns(klines)
+	return rc.Classify(high, low, closes, volume)
+}
+
+// rawRegime régime instantané avec les seuils relâchés du régime courant
+func (rc *RegimeClassifier) rawRegime(current Regime, adx, chop, percentile, slope float64) Regime {
+	if math.IsNaN(adx) || math.IsNaN(chop) || math.IsNaN(percentile) || math.IsNaN(slope) {
+		return RegimeUnknown
+	}
+	cfg := rc.config
+
+	adxMin, chopMax, slopeMin := cfg.ADXTrend, cfg.ChopTrend, cfg.MinSlope
+	if current.IsTrend() {
+		adxMin -= cfg.ADXHysteresis
+		chopMax += cfg.ChopHysteresis
+	}
+	if adx >= adxMin && chop <= chopMax {
+		switch {
+		case slope >= slopeMin:
+			return RegimeTrendUp
+		case slope <= -slopeMin:
+			return RegimeTrendDown
+		case current.IsTrend():
+			return current // Pente plate : la tendance courante est conservée
+		}
+	}
+
+	highVol := cfg.HighVolPercentile
+	if current == RegimeHighVolChoppy {
+		highVol -= cfg.PercentileHysteresis
+	}
+	if percentile >= highVol {
+		return RegimeHighVolChoppy
+	}
+	return RegimeRange
+}
+
+// PercentileRank rang percentile (0-100) de chaque valeur parmi les lookback dernières,
+// valeur courante incluse ; NaN tant que la fenêtre contient une valeur inconnue
+func PercentileRank(values []float64, lookback int) []float64 {
+	out := make([]float64, len(values))
+	for i := range out {
+		out[i] = math.NaN()
+		if lookback <= 0 || i < lookback-1 || math.IsNaN(values[i]) {
+			continue
+		}
+		below, valid := 0, true
+		for j := i - lookback + 1; j <= i; j++ {
+			if math.IsNaN(values[j]) {
+				valid = false
+				break
+			}
+			if values[j] <= values[i] {
+				below++
+			}
+		}
+		if valid {
+			out[i] = 100 * float64(below) / float64(lookback)
+		}
+	}
+	return out
+}
+
+// relativeSlope pente moyenne en % par bougie sur bars bougies
+func relativeSlope(values []float64, bars int) []float64 {
+	out := make([]float64, len(values))
+	for i := range out {
+		out[i] = math.NaN()
+		if i < bars || math.IsNaN(values[i]) || math.IsNaN(values[i-bars]) || values[i-bars] == 0 {
+			continue
+		}
+		out[i] = (values[i] - values[i-bars]) / values[i-bars] * 100 / float64(bars)
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// regimeKlines tendance haussière, tendance baissière, range calme puis chop volatil
+func regimeKlines() []Kline {
+	var klines []Kline
+	price := 100.0
+	add := func(n int, step, swing float64) {
+		for i := 0; i < n; i++ {
+			open := price
+			price = price*(1+step) + swing*math.Sin(float64(len(klines))*1.7)
+			hi, lo := math.Max(open, price), math.Min(open, price)
+			pad := 0.1 + math.Abs(swing)/2
+			klines = append(klines, Kline{Timestamp: int64(len(klines)) * 300000, Open: open, High: hi + pad, Low: lo - pad, Close: price, Volume: 1000})
+		}
+	}
+	add(150, 0.004, 0)  // 0-149 hausse
+	add(150, -0.004, 0) // 150-299 baisse
+	add(150, 0, 0.2)    // 300-449 range calme
+	add(100, 0, 3)      // 450-549 chop volatil
+	return klines
+}
+
+// TestRegimeClassifier_Segments vérifie le régime en fin de chaque segment
+func TestRegimeClassifier_Segments(t *testing.T) {
+	cfg := DefaultRegimeConfig()
+	series := NewRegimeClassifier(cfg).ClassifyFromKlines(regimeKlines())
+
+	if series.At(cfg.Warmup()-2) != RegimeUnknown {
+		t.Errorf("expected INCONNU during warmup, got %s", series.At(cfg.Warmup()-2))
+	}
+	expected := map[int]Regime{140: RegimeTrendUp, 290: RegimeTrendDown, 440: RegimeRange, 540: RegimeHighVolChoppy}
+	for i, want := range expected {
+		if got := series.At(i); got != want {
+			t.Errorf("bar %d: got %s, want %s (adx %.1f chop %.1f atr%% %.0f slope %.4f)",
+				i, got, want, series.ADX[i], series.CHOP[i], series.ATRPercentile[i], series.Slope[i])
+		}
+	}
+	if code := series.Codes()[140]; code != 1 {
+		t.Errorf("expected code 1 for trend up, got %v", code)
+	}
+}
+
+// TestRegimeClassifier_Hysteresis vérifie que la confirmation réduit les changements de régime
+func TestRegimeClassifier_Hysteresis(t *testing.T) {
+	klines := regimeKlines()
+	changes := func(cfg RegimeConfig) int {
+		labels := NewRegimeClassifier(cfg).ClassifyFromKlines(klines).Labels
+		count := 0
+		for i := 1; i < len(labels); i++ {
+			if labels[i-1] != RegimeUnknown && labels[i] != labels[i-1] {
+				count++
+			}
+		}
+		return count
+	}
+
+	raw := DefaultRegimeConfig()
+	raw.ConfirmBars = 1
+	raw.ADXHysteresis, raw.ChopHysteresis, raw.PercentileHysteresis = 1e-9, 1e-9, 1e-9
+	smooth := DefaultRegimeConfig()
+
+	if changes(smooth) > changes(raw) {
+		t.Errorf("hysteresis increased regime changes: %d > %d", changes(smooth), changes(raw))
+	}
+	if changes(smooth) > 8 {
+		t.Errorf("regime flickers: %d changes over 4 segments", changes(smooth))
+	}
+}
+
+// TestPercentileRank vérifie le rang inclusif
+func TestPercentileRank(t *testing.T) {
+	rank := PercentileRank([]float64{1, 3, 2, 4}, 2)
+	if !math.IsNaN(rank[0]) || rank[1] != 100 || rank[2] != 50 || rank[3] != 100 {
+		t.Errorf("unexpected ranks %v", rank)
+	}
+}
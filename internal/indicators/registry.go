@@ -0,0 +1,756 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ParamKind type d'un paramètre d'indicateur
+type ParamKind int
+
+const (
+	ParamInt   ParamKind = iota // Entier (périodes)
+	ParamFloat                  // Réel (multiplicateurs)
+)
+
+// String retourne la représentation texte du type de paramètre
+func (k ParamKind) String() string {
+	if k == ParamInt {
+		return "int"
+	}
+	return "float"
+}
+
+// ParamSpec décrit un paramètre typé avec valeur par défaut et bornes incluses
+type ParamSpec struct {
+	Name        string
+	Kind        ParamKind
+	Default     float64
+	Min         float64
+	Max         float64
+	Description string
+}
+
+// validate vérifie type et bornes d'une valeur
+func (p ParamSpec) validate(indicator string, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("%s: parameter %s must be finite", indicator, p.Name)
+	}
+	if p.Kind == ParamInt && value != math.Trunc(value) {
+		return fmt.Errorf("%s: parameter %s must be an integer, got %v", indicator, p.Name, value)
+	}
+	if value < p.Min || value > p.Max {
+		return fmt.Errorf("%s: parameter %s=%v out of range [%v, %v]", indicator, p.Name, value, p.Min, p.Max)
+	}
+	return nil
+}
+
+// IndicatorSpec décrit un indicateur enregistré : paramètres, sorties, warmup et calcul
+//
+// Warmup retourne, pour chaque sortie, le nombre de bougies nécessaires avant la
+// première valeur valide. Settling retourne les bougies supplémentaires nécessaires
+// aux lissages récursifs (EMA/RMA) pour que l'amorçage devienne négligeable
+// (écart relatif < registryConvergenceTolerance) ; nil pour les indicateurs à fenêtre.
+type IndicatorSpec struct {
+	Name        string
+	Description string
+	Params      []ParamSpec
+	Outputs     []string
+
+	Warmup   func(params []float64) []int
+	Settling func(params []float64) int
+	Validate func(params []float64) error
+	Compute  func(klines []Kline, params []float64) [][]float64
+}
+
+// registryConvergenceTolerance poids résiduel de l'amorçage toléré pour les lissages récursifs
+const registryConvergenceTolerance = 1e-4
+
+// convergenceBars nombre de bougies pour qu'un lissage de facteur alpha oublie son amorçage
+func convergenceBars(alpha float64) int {
+	if alpha <= 0 || alpha >= 1 {
+		return 0
+	}
+	return int(math.Ceil(math.Log(registryConvergenceTolerance) / math.Log(1-alpha)))
+}
+
+// OutputIndex retourne l'index d'une sortie (-1 si inconnue)
+func (s *IndicatorSpec) OutputIndex(name string) int {
+	for i, output := range s.Outputs {
+		if output == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ResolveParams complète les paramètres positionnels avec les défauts et vérifie les bornes
+func (s *IndicatorSpec) ResolveParams(args []float64) ([]float64, error) {
+	if len(args) > len(s.Params) {
+		return nil, fmt.Errorf("%s: expected at most %d parameters, got %d", s.Name, len(s.Params), len(args))
+	}
+
+	params := make([]float64, len(s.Params))
+	for i, p := range s.Params {
+		params[i] = p.Default
+		if i < len(args) {
+			params[i] = args[i]
+		}
+	}
+	return params, s.check(params)
+}
+
+// ResolveNamedParams complète des paramètres nommés (config YAML) avec les défauts
+func (s *IndicatorSpec) ResolveNamedParams(named map[string]float64) ([]float64, error) {
+	params := make([]float64, len(s.Params))
+	for i, p := range s.Params {
+		params[i] = p.Default
+	}
+	for name, value := range named {
+		index := s.paramIndex(name)
+		if index < 0 {
+			return nil, fmt.Errorf("%s: unknown parameter %q", s.Name, name)
+		}
+		params[index] = value
+	}
+	return params, s.check(params)
+}
+
+// paramIndex retourne l'index d'un paramètre (-1 si inconnu)
+func (s *IndicatorSpec) paramIndex(name string) int {
+	for i, p := range s.Params {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// check valide chaque paramètre puis les contraintes croisées
+func (s *IndicatorSpec) check(params []float64) error {
+	for i, p := range s.Params {
+		if err := p.validate(s.Name, params[i]); err != nil {
+			return err
+		}
+	}
+	if s.Validate != nil {
+		return s.Validate(params)
+	}
+	return nil
+}
+
+// Registry table des indicateurs disponibles par nom
+type Registry struct {
+	mutex sync.RWMutex
+	specs map[string]*IndicatorSpec
+}
+
+// NewRegistry crée un registre vide
+func NewRegistry() *Registry {
+	return &Registry{specs: make(map[string]*IndicatorSpec)}
+}
+
+// Register ajoute un indicateur (le nom doit être unique)
+func (r *Registry) Register(spec *IndicatorSpec) error {
+	if spec == nil || spec.Name == "" {
+		return fmt.Errorf("indicator spec must have a name")
+	}
+	if len(spec.Outputs) == 0 || spec.Compute == nil || spec.Warmup == nil {
+		return fmt.Errorf("%s: outputs, warmup and compute are required", spec.Name)
+	}
+	for _, p := range spec.Params {
+		if err := p.validate(spec.Name, p.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	name := strings.ToLower(spec.Name)
+	if _, exists := r.specs[name]; exists {
+		return fmt.Errorf("indicator %s already registered", name)
+	}
+	r.specs[name] = spec
+	return nil
+}
+
+// Lookup retourne la spécification d'un indicateur
+func (r *Registry) Lookup(name string) (*IndicatorSpec, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	spec, ok := r.specs[strings.ToLower(name)]
+	return spec, ok
+}
+
+// Names retourne les noms enregistrés triés
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.specs))
+	for name := range r.specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MinHistory retourne l'historique minimal (warmup + convergence) couvrant toutes les références
+func (r *Registry) MinHistory(refs ...string) (int, error) {
+	history := 0
+	for _, text := range refs {
+		ref, err := r.Parse(text)
+		if err != nil {
+			return 0, err
+		}
+		if bars := ref.StableWarmup(); bars > history {
+			history = bars
+		}
+	}
+	return history, nil
+}
+
+// IndicatorRef référence résolue vers une sortie d'indicateur, ex: dmi(14,14).adx
+type IndicatorRef struct {
+	Spec   *IndicatorSpec
+	Params []float64
+	Output string
+}
+
+// Parse lit une référence "nom(args).sortie"
+//
+// Les parenthèses sont optionnelles (paramètres par défaut), les arguments peuvent
+// être positionnels ou nommés (dmi(period=14, adx=20)) et la sortie par défaut est
+// la première déclarée.
+func (r *Registry) Parse(text string) (*IndicatorRef, error) {
+	s := strings.TrimSpace(text)
+	if s == "" {
+		return nil, fmt.Errorf("empty indicator reference")
+	}
+
+	name, args, output := s, "", ""
+	hasArgs := false
+	if open := strings.IndexByte(s, '('); open >= 0 {
+		closing := strings.LastIndexByte(s, ')')
+		if closing < open {
+			return nil, fmt.Errorf("indicator reference %q: missing ')'", text)
+		}
+		name, args, hasArgs = s[:open], s[open+1:closing], true
+		rest := strings.TrimSpace(s[closing+1:])
+		if rest != "" {
+			if !strings.HasPrefix(rest, ".") {
+				return nil, fmt.Errorf("indicator reference %q: unexpected %q after ')'", text, rest)
+			}
+			output = strings.TrimSpace(rest[1:])
+		}
+	} else if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		name, output = s[:dot], strings.TrimSpace(s[dot+1:])
+	}
+
+	name = strings.TrimSpace(name)
+	spec, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("indicator reference %q: unknown indicator %q", text, name)
+	}
+
+	params, err := parseRefArgs(spec, args, hasArgs)
+	if err != nil {
+		return nil, fmt.Errorf("indicator reference %q: %w", text, err)
+	}
+
+	if output == "" {
+		output = spec.Outputs[0]
+	} else if spec.OutputIndex(output) < 0 {
+		return nil, fmt.Errorf("indicator reference %q: unknown output %q (available: %s)", text, output, strings.Join(spec.Outputs, ", "))
+	}
+
+	return &IndicatorRef{Spec: spec, Params: params, Output: output}, nil
+}
+
+// parseRefArgs lit les arguments positionnels ou nommés d'une référence
+func parseRefArgs(spec *IndicatorSpec, args string, hasArgs bool) ([]float64, error) {
+	if !hasArgs || strings.TrimSpace(args) == "" {
+		return spec.ResolveParams(nil)
+	}
+
+	var positional []float64
+	named := make(map[string]float64)
+	for _, part := range strings.Split(args, ",") {
+		part = strings.TrimSpace(part)
+		key := ""
+		if eq := strings.IndexByte(part, '='); eq >= 0 {
+			key, part = strings.TrimSpace(part[:eq]), strings.TrimSpace(part[eq+1:])
+		}
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter value %q", part)
+		}
+		if key == "" {
+			if len(named) > 0 {
+				return nil, fmt.Errorf("positional parameter after named parameter")
+			}
+			positional = append(positional, value)
+			continue
+		}
+		named[key] = value
+	}
+
+	params, err := spec.ResolveParams(positional)
+	if err != nil || len(named) == 0 {
+		return params, err
+	}
+	for key, value := range named {
+		index := spec.paramIndex(key)
+		if index < 0 {
+			return nil, fmt.Errorf("%s: unknown parameter %q", spec.Name, key)
+		}
+		if index < len(positional) {
+			return nil, fmt.Errorf("%s: parameter %q given twice", spec.Name, key)
+		}
+		params[index] = value
+	}
+	return params, spec.check(params)
+}
+
+// String retourne la forme canonique de la référence
+func (ref *IndicatorRef) String() string {
+	args := make([]string, len(ref.Params))
+	for i, value := range ref.Params {
+		args[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s(%s).%s", ref.Spec.Name, strings.Join(args, ","), ref.Output)
+}
+
+// Param retourne la valeur d'un paramètre par nom (NaN si inconnu)
+func (ref *IndicatorRef) Param(name string) float64 {
+	if index := ref.Spec.paramIndex(name); index >= 0 {
+		return ref.Params[index]
+	}
+	return math.NaN()
+}
+
+// Warmup nombre de bougies avant la première valeur valide de la sortie
+func (ref *IndicatorRef) Warmup() int {
+	return ref.Spec.Warmup(ref.Params)[ref.Spec.OutputIndex(ref.Output)]
+}
+
+// StableWarmup warmup augmenté de la convergence des lissages récursifs
+func (ref *IndicatorRef) StableWarmup() int {
+	bars := ref.Warmup()
+	if ref.Spec.Settling != nil {
+		bars += ref.Spec.Settling(ref.Params)
+	}
+	return bars
+}
+
+// Compute calcule la sortie référencée (NaN avant la fin du warmup)
+func (ref *IndicatorRef) Compute(klines []Kline) []float64 {
+	outputs := ref.Spec.Compute(klines, ref.Params)
+	index := ref.Spec.OutputIndex(ref.Output)
+	if index >= len(outputs) || outputs[index] == nil {
+		return nil
+	}
+
+	values := outputs[index]
+	for i := 0; i < ref.Warmup()-1 && i < len(values); i++ {
+		values[i] = math.NaN()
+	}
+	return values
+}
+
+// defaultRegistry registre des indicateurs TV Standard intégrés
+var defaultRegistry = newBuiltinRegistry()
+
+// DefaultRegistry retourne le registre partagé des indicateurs intégrés
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// ParseIndicatorRef lit une référence dans le registre par défaut
+func ParseIndicatorRef(text string) (*IndicatorRef, error) {
+	return defaultRegistry.Parse(text)
+}
+
+// MinHistory historique minimal pour des références du registre par défaut
+func MinHistory(refs ...string) (int, error) {
+	return defaultRegistry.MinHistory(refs...)
+}
+
+// klineColumns extrait les colonnes OHLCV
+func klineColumns(klines []Kline) (high, low, closes, volume []float64) {
+	n := len(klines)
+	high, low, closes, volume = make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for i, k := range klines {
+		high[i], low[i], closes[i], volume[i] = k.High, k.Low, k.Close, k.Volume
+	}
+	return high, low, closes, volume
+}
+
+// regimeRegistryConfig configuration du classificateur de régime depuis les paramètres du registre
+func regimeRegistryConfig(params []float64) RegimeConfig {
+	cfg := DefaultRegimeConfig()
+	cfg.Period, cfg.ADXSmoothing = int(params[0]), int(params[0])
+	cfg.VWMAPeriod = int(params[1])
+	cfg.PercentileLookback = int(params[2])
+	return cfg
+}
+
+// periodParam paramètre de période standard
+func periodParam(name string, def float64, description string) ParamSpec {
+	return ParamSpec{Name: name, Kind: ParamInt, Default: def, Min: 1, Max: 5000, Description: description}
+}
+
+// multParam paramètre multiplicateur réel
+func multParam(name string, def float64, description string) ParamSpec {
+	return ParamSpec{Name: name, Kind: ParamFloat, Default: def, Min: 0.01, Max: 100, Description: description}
+}
+
+// newBuiltinRegistry enregistre les indicateurs TV Standard
+func newBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	p := func(params []float64, i int) int { return int(params[i]) }
+
+	closeSeries := func(name, description string, def float64, alpha func(int) float64, calc func(period int, close []float64) []float64) *IndicatorSpec {
+		spec := &IndicatorSpec{
+			Name:        name,
+			Description: description,
+			Params:      []ParamSpec{periodParam("period", def, "Période")},
+			Outputs:     []string{"value"},
+			Warmup:      func(params []float64) []int { return []int{p(params, 0)} },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				_, _, closes, _ := klineColumns(klines)
+				return [][]float64{calc(p(params, 0), closes)}
+			},
+		}
+		if alpha != nil {
+			spec.Settling = func(params []float64) int { return convergenceBars(alpha(p(params, 0))) }
+		}
+		return spec
+	}
+
+	wilder := func(period int) float64 { return 1 / float64(period) }
+	ema := func(period int) float64 { return 2 / float64(period+1) }
+
+	specs := []*IndicatorSpec{
+		closeSeries("sma", "Simple Moving Average", 20, nil,
+			func(period int, c []float64) []float64 { return NewSMATVStandard(period).Calculate(c) }),
+		closeSeries("ema", "Exponential Moving Average", 20, ema,
+			func(period int, c []float64) []float64 { return NewEMATVStandard(period).Calculate(c) }),
+		closeSeries("rma", "Wilder Moving Average", 14, wilder,
+			func(period int, c []float64) []float64 { return NewRMATVStandard(period).Calculate(c) }),
+		{
+			Name:        "hma",
+			Description: "Hull Moving Average",
+			Params:      []ParamSpec{periodParam("period", 20, "Période")},
+			Outputs:     []string{"value"},
+			Warmup: func(params []float64) []int {
+				period := p(params, 0)
+				return []int{period + int(math.Sqrt(float64(period))) - 1}
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				_, _, closes, _ := klineColumns(klines)
+				return [][]float64{NewHMATVStandard(p(params, 0)).Calculate(closes)}
+			},
+		},
+		{
+			Name:        "vwma",
+			Description: "Volume Weighted Moving Average",
+			Params:      []ParamSpec{periodParam("period", 20, "Période")},
+			Outputs:     []string{"value"},
+			Warmup:      func(params []float64) []int { return []int{p(params, 0)} },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				_, _, closes, volumes := klineColumns(klines)
+				return [][]float64{NewVWMATVStandard(p(params, 0)).Calculate(closes, volumes)}
+			},
+		},
+		{
+			Name:        "delta",
+			Description: "Delta agresseur approché (position de la clôture), delta % et déséquilibre pondéré par le volume",
+			Params:      []ParamSpec{periodParam("period", 14, "Fenêtre du déséquilibre")},
+			Outputs:     []string{"value", "pct", "imbalance"},
+			Warmup:      func(params []float64) []int { return []int{1, 1, p(params, 0)} },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				flow := OrderFlowFromKlines(klines)
+				return [][]float64{flow.Delta, flow.DeltaPct, flow.Imbalance(p(params, 0))}
+			},
+		},
+		{
+			Name:        "atr",
+			Description: "Average True Range",
+			Params:      []ParamSpec{periodParam("period", 14, "Période RMA")},
+			Outputs:     []string{"value"},
+			Warmup:      func(params []float64) []int { return []int{p(params, 0)} },
+			Settling:    func(params []float64) int { return convergenceBars(wilder(p(params, 0))) },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, _ := klineColumns(klines)
+				return [][]float64{NewATRTVStandard(p(params, 0)).Calculate(h, l, c)}
+			},
+		},
+		{
+			Name:        "dmi",
+			Description: "Directional Movement Index (+DI, -DI, DX, ADX)",
+			Params: []ParamSpec{
+				periodParam("period", 14, "Période DI"),
+				periodParam("adx", 14, "Lissage ADX"),
+			},
+			Outputs: []string{"plus_di", "minus_di", "adx", "dx"},
+			Warmup: func(params []float64) []int {
+				di, adx := p(params, 0), p(params, 1)
+				return []int{di, di, di + adx - 1, di}
+			},
+			Settling: func(params []float64) int {
+				return convergenceBars(wilder(p(params, 0))) + convergenceBars(wilder(p(params, 1)))
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, _ := klineColumns(klines)
+				dmi := NewDMITVStandardWithPeriods(p(params, 0), p(params, 1))
+				plusDI, minusDI, adx := dmi.Calculate(h, l, c)
+				return [][]float64{plusDI, minusDI, adx, dmi.CalculateDX(plusDI, minusDI)}
+			},
+		},
+		{
+			Name:        "mfi",
+			Description: "Money Flow Index",
+			Params:      []ParamSpec{periodParam("period", 14, "Période")},
+			Outputs:     []string{"value"},
+			Warmup:      func(params []float64) []int { return []int{p(params, 0) + 1} },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, v := klineColumns(klines)
+				return [][]float64{NewMFITVStandard(p(params, 0)).Calculate(h, l, c, v)}
+			},
+		},
+		{
+			Name:        "cci",
+			Description: "Commodity Channel Index",
+			Params:      []ParamSpec{periodParam("period", 20, "Période")},
+			Outputs:     []string{"value"},
+			Warmup:      func(params []float64) []int { return []int{p(params, 0)} },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, _ := klineColumns(klines)
+				return [][]float64{NewCCITVStandard(p(params, 0)).Calculate(h, l, c)}
+			},
+		},
+		{
+			Name:        "chop",
+			Description: "Choppiness Index",
+			Params:      []ParamSpec{periodParam("period", 14, "Période")},
+			Outputs:     []string{"value"},
+			Warmup:      func(params []float64) []int { return []int{p(params, 0)} },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, _ := klineColumns(klines)
+				return [][]float64{NewCHOPTVStandard(p(params, 0)).Calculate(h, l, c)}
+			},
+		},
+		{
+			Name:        "stoch",
+			Description: "Stochastic (%K lissé, %D)",
+			Params: []ParamSpec{
+				periodParam("k", 14, "Période %K"),
+				periodParam("smooth", 3, "Lissage %K"),
+				periodParam("d", 3, "Période %D"),
+			},
+			Outputs: []string{"k", "d"},
+			Warmup: func(params []float64) []int {
+				k := p(params, 0) + p(params, 1) - 1
+				return []int{k, k + p(params, 2) - 1}
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, _ := klineColumns(klines)
+				k, d := NewStochTVStandard(p(params, 0), p(params, 1), p(params, 2)).Calculate(h, l, c)
+				return [][]float64{k, d}
+			},
+		},
+		{
+			Name:        "macd",
+			Description: "MACD (ligne, signal, histogramme)",
+			Params: []ParamSpec{
+				periodParam("fast", 12, "EMA rapide"),
+				periodParam("slow", 26, "EMA lente"),
+				periodParam("signal", 9, "EMA signal"),
+			},
+			Outputs: []string{"macd", "signal", "hist"},
+			Warmup: func(params []float64) []int {
+				slow := p(params, 1)
+				signal := slow + p(params, 2) - 1
+				return []int{slow, signal, signal}
+			},
+			Settling: func(params []float64) int {
+				return convergenceBars(ema(p(params, 1))) + convergenceBars(ema(p(params, 2)))
+			},
+			Validate: func(params []float64) error {
+				if params[0] >= params[1] {
+					return fmt.Errorf("macd: fast period %v must be below slow period %v", params[0], params[1])
+				}
+				return nil
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				_, _, c, _ := klineColumns(klines)
+				macd, signal, hist := NewMACDTVStandard(p(params, 0), p(params, 1), p(params, 2)).Calculate(c)
+				return [][]float64{macd, signal, hist}
+			},
+		},
+		{
+			Name:        "bb",
+			Description: "Bollinger Bands (basis, bandes, %B, bandwidth)",
+			Params: []ParamSpec{
+				periodParam("period", 20, "Période SMA / écart-type"),
+				multParam("mult", 2, "Multiplicateur d'écart-type"),
+			},
+			Outputs: []string{"basis", "upper", "lower", "percent_b", "bandwidth"},
+			Warmup: func(params []float64) []int {
+				period := p(params, 0)
+				return []int{period, period, period, period, period}
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				_, _, c, _ := klineColumns(klines)
+				bb := NewBollingerTVStandard(p(params, 0), params[1])
+				basis, upper, lower := bb.Calculate(c)
+				return [][]float64{basis, upper, lower, bb.CalculatePercentB(c, upper, lower), bb.CalculateBandwidth(basis, upper, lower)}
+			},
+		},
+		{
+			Name:        "kc",
+			Description: "Keltner Channels (EMA ± mult × ATR)",
+			Params: []ParamSpec{
+				periodParam("period", 20, "Période EMA"),
+				multParam("mult", 2, "Multiplicateur ATR"),
+				periodParam("atr", 10, "Période ATR"),
+			},
+			Outputs: []string{"middle", "upper", "lower"},
+			Warmup: func(params []float64) []int {
+				middle := p(params, 0)
+				bands := middle
+				if atr := p(params, 2); atr > bands {
+					bands = atr
+				}
+				return []int{middle, bands, bands}
+			},
+			Settling: func(params []float64) int {
+				settling := convergenceBars(ema(p(params, 0)))
+				if atr := convergenceBars(wilder(p(params, 2))); atr > settling {
+					settling = atr
+				}
+				return settling
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, _ := klineColumns(klines)
+				middle, upper, lower := NewKeltnerTVStandard(p(params, 0), params[1], p(params, 2)).Calculate(h, l, c)
+				return [][]float64{middle, upper, lower}
+			},
+		},
+		{
+			Name:        "donchian",
+			Description: "Donchian Channels (plus haut, plus bas, milieu)",
+			Params:      []ParamSpec{periodParam("period", 20, "Période")},
+			Outputs:     []string{"upper", "lower", "basis"},
+			Warmup: func(params []float64) []int {
+				period := p(params, 0)
+				return []int{period, period, period}
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, _, _ := klineColumns(klines)
+				upper, lower, basis := NewDonchianTVStandard(p(params, 0)).Calculate(h, l)
+				return [][]float64{upper, lower, basis}
+			},
+		},
+		{
+			Name:        "supertrend",
+			Description: "Supertrend (ligne, direction -1 haussière / +1 baissière)",
+			Params: []ParamSpec{
+				periodParam("atr", 10, "Période ATR"),
+				multParam("factor", 3, "Multiplicateur ATR"),
+			},
+			Outputs: []string{"value", "direction"},
+			Warmup: func(params []float64) []int {
+				atr := p(params, 0)
+				return []int{atr, atr}
+			},
+			Settling: func(params []float64) int { return convergenceBars(wilder(p(params, 0))) },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				h, l, c, _ := klineColumns(klines)
+				value, direction := NewSupertrendTVStandard(p(params, 0), params[1]).Calculate(h, l, c)
+				return [][]float64{value, direction}
+			},
+		},
+		{
+			Name:        "kvo",
+			Description: "Klinger Volume Oscillator (dernière bougie = NaN, bougie en cours)",
+			Params: []ParamSpec{
+				periodParam("fast", 34, "EMA rapide"),
+				periodParam("slow", 55, "EMA lente"),
+				periodParam("signal", 13, "EMA signal"),
+			},
+			Outputs: []string{"kvo", "signal", "hist"},
+			Warmup: func(params []float64) []int {
+				slow := p(params, 1)
+				signal := slow + p(params, 2) - 1
+				return []int{slow, signal, signal}
+			},
+			Settling: func(params []float64) int {
+				return convergenceBars(ema(p(params, 1))) + convergenceBars(ema(p(params, 2)))
+			},
+			Validate: func(params []float64) error {
+				if params[0] >= params[1] {
+					return fmt.Errorf("kvo: fast period %v must be below slow period %v", params[0], params[1])
+				}
+				return nil
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				kvo, signal, hist := KVOFromKlines(klines, p(params, 0), p(params, 1), p(params, 2))
+				return [][]float64{kvo, signal, hist}
+			},
+		},
+		{
+			Name:        "vwap",
+			Description: "VWAP de session (reset UTC quotidien décalé) et bandes d'écart-type",
+			Params: []ParamSpec{
+				{Name: "offset", Kind: ParamInt, Default: 0, Min: 0, Max: 1439, Description: "Début de session (minutes après 00:00 UTC)"},
+				multParam("mult", 1, "Multiplicateur d'écart-type des bandes"),
+			},
+			Outputs: []string{"value", "upper", "lower", "stdev"},
+			Warmup:  func(params []float64) []int { return []int{1, 1, 1, 1} },
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				session := DailyUTCSession()
+				session.Offset = time.Duration(p(params, 0)) * time.Minute
+				vwap, stdev := NewVWAPTVStandard(session).CalculateFromKlines(klines)
+				upper, lower := VWAPBands(vwap, stdev, params[1])
+				return [][]float64{vwap, upper, lower, stdev}
+			},
+		},
+		{
+			Name:        "regime",
+			Description: "Régime de marché (1 hausse, -1 baisse, 0 range, 2 chop volatil), percentile ATR et pente VWMA",
+			Params: []ParamSpec{
+				periodParam("period", 14, "Période CHOP / DMI / ATR"),
+				periodParam("vwma", 20, "Période VWMA de pente"),
+				periodParam("lookback", 100, "Fenêtre du percentile ATR"),
+			},
+			Outputs: []string{"value", "atr_pct", "slope"},
+			Warmup: func(params []float64) []int {
+				cfg := regimeRegistryConfig(params)
+				return []int{cfg.Warmup(), cfg.Period + cfg.PercentileLookback - 1, cfg.VWMAPeriod + cfg.SlopeBars}
+			},
+			Settling: func(params []float64) int {
+				// Lissages ADX (DI + ADX) ; l'hystérésis oublie son état initial dans le même délai
+				return 2 * convergenceBars(wilder(p(params, 0)))
+			},
+			Compute: func(klines []Kline, params []float64) [][]float64 {
+				series := NewRegimeClassifier(regimeRegistryConfig(params)).ClassifyFromKlines(klines)
+				return [][]float64{series.Codes(), series.ATRPercentile, series.Slope}
+			},
+		},
+	}
+
+	for _, spec := range specs {
+		if err := r.Register(spec); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
@@ -0,0 +1,110 @@
+package indicators
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestRegistry_WarmupMatchesOutputs vérifie que le warmup déclaré correspond à la première valeur valide
+func TestRegistry_WarmupMatchesOutputs(t *testing.T) {
+	klines := randomWalkKlines(400, 3)
+	registry := DefaultRegistry()
+
+	refs := []string{}
+	for _, name := range registry.Names() {
+		spec, _ := registry.Lookup(name)
+		for _, output := range spec.Outputs {
+			refs = append(refs, name+"()."+output)
+		}
+	}
+	refs = append(refs, "sma(5)", "ema(7)", "hma(9)", "dmi(10,4).adx", "dmi(10,4).dx",
+		"stoch(9,2,5).d", "macd(5,13,4).signal", "mfi(6)")
+
+	for _, text := range refs {
+		ref, err := registry.Parse(text)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", text, err)
+		}
+
+		values := ref.Compute(klines)
+		first := -1
+		for i, v := range values {
+			if !math.IsNaN(v) {
+				first = i
+				break
+			}
+		}
+		if first != ref.Warmup()-1 {
+			t.Errorf("%s: first valid value at bar %d, declared warmup %d", ref, first, ref.Warmup())
+		}
+		if ref.StableWarmup() < ref.Warmup() {
+			t.Errorf("%s: stable warmup %d below warmup %d", ref, ref.StableWarmup(), ref.Warmup())
+		}
+	}
+}
+
+// TestRegistry_Parse vérifie la syntaxe des références et les erreurs de paramètres
+func TestRegistry_Parse(t *testing.T) {
+	ref, err := ParseIndicatorRef("dmi(14,14).adx")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if ref.Output != "adx" || ref.Param("period") != 14 || ref.Param("adx") != 14 || ref.Warmup() != 27 {
+		t.Errorf("Unexpected ref %s warmup %d", ref, ref.Warmup())
+	}
+
+	ref, err = ParseIndicatorRef(" stoch(k=5, d=4) ")
+	if err != nil || ref.String() != "stoch(5,3,4).k" {
+		t.Errorf("Named params: got %v, %v", ref, err)
+	}
+	if ref, err := ParseIndicatorRef("vwma"); err != nil || ref.String() != "vwma(20).value" {
+		t.Errorf("Defaults: got %v, %v", ref, err)
+	}
+
+	invalid := map[string]string{
+		"foo(3)":            "unknown indicator",
+		"dmi(14).pdi":       "unknown output",
+		"ema(0)":            "out of range",
+		"ema(2.5)":          "integer",
+		"ema(3,4)":          "at most",
+		"macd(26,12)":       "below slow",
+		"ema(x)":            "invalid parameter",
+		"stoch(k=3, 4)":     "positional parameter after named",
+		"dmi(14, period=3)": "given twice",
+		"sma(3":             "missing ')'",
+	}
+	for text, want := range invalid {
+		if _, err := ParseIndicatorRef(text); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q): expected error containing %q, got %v", text, want, err)
+		}
+	}
+}
+
+// TestRegistry_MinHistory vérifie l'historique minimal calculé pour plusieurs références
+func TestRegistry_MinHistory(t *testing.T) {
+	adx, _ := ParseIndicatorRef("dmi(14,14).adx")
+	history, err := MinHistory("vwma(6)", "vwma(20)", "dmi(14,14).adx")
+	if err != nil {
+		t.Fatalf("MinHistory failed: %v", err)
+	}
+	if history != adx.StableWarmup() || history <= adx.Warmup() {
+		t.Errorf("Expected ADX stable warmup %d, got %d", adx.StableWarmup(), history)
+	}
+
+	if history, _ := MinHistory("sma(50)", "cci(20)"); history != 50 {
+		t.Errorf("Window indicators need no settling: expected 50, got %d", history)
+	}
+	if _, err := MinHistory("ema(20)", "nope"); err == nil {
+		t.Error("Expected error for unknown reference")
+	}
+
+	registry := NewRegistry()
+	spec, _ := DefaultRegistry().Lookup("sma")
+	if err := registry.Register(spec); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register(spec); err == nil {
+		t.Error("Expected duplicate registration error")
+	}
+}
@@ -0,0 +1,478 @@
+package indicators
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+)
+
+// StreamingIndicator - Indicateur incrémental mis à jour bougie par bougie
+//
+// Chaque Update coûte O(1) vis-à-vis de la longueur d'historique : les moyennes
+// récursives (EMA, RMA, ATR, DMI, MACD) sont O(1), les indicateurs à fenêtre
+// (SMA, VWMA, MFI, CCI, Stochastic) coûtent au plus O(période) afin de reproduire
+// exactement l'ordre des sommes des versions batch TV Standard.
+// Nourries avec la même série, les valeurs sont identiques bit à bit aux versions batch.
+type StreamingIndicator interface {
+	// Update intègre une nouvelle bougie clôturée
+	Update(bar Kline)
+	// Value retourne la valeur principale courante (NaN pendant le warmup)
+	Value() float64
+	// Ready indique si la valeur principale est disponible
+	Ready() bool
+	// Snapshot sérialise l'état interne
+	Snapshot() ([]byte, error)
+	// Restore restaure un état produit par Snapshot d'un indicateur de même type et paramètres
+	Restore(data []byte) error
+}
+
+// streamingSnapshotVersion version du format d'état (incrémenter si les structures d'état changent)
+const streamingSnapshotVersion = 1
+
+// streamingEnvelope enveloppe versionnée d'un état sérialisé
+type streamingEnvelope struct {
+	Kind    string
+	Version int
+	State   []byte
+}
+
+// encodeStreamingState sérialise un état (gob conserve NaN/Inf bit à bit)
+func encodeStreamingState(kind string, state interface{}) ([]byte, error) {
+	var stateBuf bytes.Buffer
+	if err := gob.NewEncoder(&stateBuf).Encode(state); err != nil {
+		return nil, fmt.Errorf("failed to encode %s state: %w", kind, err)
+	}
+
+	var buf bytes.Buffer
+	envelope := streamingEnvelope{Kind: kind, Version: streamingSnapshotVersion, State: stateBuf.Bytes()}
+	if err := gob.NewEncoder(&buf).Encode(envelope); err != nil {
+		return nil, fmt.Errorf("failed to encode %s snapshot: %w", kind, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeStreamingState restaure un état en vérifiant type et version
+func decodeStreamingState(kind string, data []byte, state interface{}) error {
+	var envelope streamingEnvelope
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if envelope.Kind != kind {
+		return fmt.Errorf("snapshot kind mismatch: got %s, want %s", envelope.Kind, kind)
+	}
+	if envelope.Version != streamingSnapshotVersion {
+		return fmt.Errorf("unsupported %s snapshot version: %d", kind, envelope.Version)
+	}
+	if err := gob.NewDecoder(bytes.NewReader(envelope.State)).Decode(state); err != nil {
+		return fmt.Errorf("failed to decode %s state: %w", kind, err)
+	}
+	return nil
+}
+
+// checkPeriod vérifie qu'un état restauré correspond aux paramètres de l'indicateur
+func checkPeriod(kind string, got, want int) error {
+	if got != want {
+		return fmt.Errorf("%s snapshot period mismatch: got %d, want %d", kind, got, want)
+	}
+	return nil
+}
+
+// isBadValue reproduit le test NaN/Inf des implémentations TV Standard
+func isBadValue(x float64) bool {
+	return math.IsNaN(x) || math.IsInf(x, 0)
+}
+
+// ============================================================================
+// SMA
+// ============================================================================
+
+// smaState état de SMATVStandard.Calculate rejoué valeur par valeur
+type smaState struct {
+	Period int
+	Ring   []float64 // Valeurs brutes des Period dernières bougies
+	Pos    int
+	Seen   int
+	Sum    float64
+	Count  int
+	Value  float64
+}
+
+func newSMAState(period int) smaState {
+	size := period
+	if size < 0 {
+		size = 0
+	}
+	return smaState{Period: period, Ring: make([]float64, size), Value: math.NaN()}
+}
+
+// update reproduit la fenêtre glissante (y compris la réinitialisation sur valeur invalide)
+func (s *smaState) update(v float64) float64 {
+	if s.Period <= 0 {
+		s.Value = math.NaN()
+		return s.Value
+	}
+
+	hasOld := s.Seen >= s.Period
+	old := s.Ring[s.Pos]
+	s.Ring[s.Pos] = v
+	s.Pos = (s.Pos + 1) % s.Period
+	s.Seen++
+
+	if isBadValue(v) {
+		s.Sum = 0
+		s.Count = 0
+		s.Value = math.NaN()
+		return s.Value
+	}
+
+	s.Sum += v
+	s.Count++
+	if hasOld && !isBadValue(old) {
+		s.Sum -= old
+		s.Count--
+	}
+
+	if s.Seen >= s.Period && s.Count == s.Period {
+		s.Value = s.Sum / float64(s.Period)
+	} else {
+		s.Value = math.NaN()
+	}
+	return s.Value
+}
+
+// window retourne les Period dernières valeurs, de la plus ancienne à la plus récente
+func (s *smaState) window(i int) float64 {
+	return s.Ring[(s.Pos+i)%s.Period]
+}
+
+// SMAStream - SMA TV Standard incrémental (source: close)
+type SMAStream struct {
+	state smaState
+}
+
+// NewSMAStream crée un SMA incrémental
+func NewSMAStream(period int) *SMAStream {
+	return &SMAStream{state: newSMAState(period)}
+}
+
+// Update intègre la clôture de la bougie
+func (s *SMAStream) Update(bar Kline) { s.UpdateValue(bar.Close) }
+
+// UpdateValue intègre une valeur source quelconque et retourne le SMA
+func (s *SMAStream) UpdateValue(v float64) float64 { return s.state.update(v) }
+
+// Value retourne le SMA courant
+func (s *SMAStream) Value() float64 { return s.state.Value }
+
+// Ready indique si le SMA est disponible
+func (s *SMAStream) Ready() bool { return !math.IsNaN(s.state.Value) }
+
+// Snapshot sérialise l'état
+func (s *SMAStream) Snapshot() ([]byte, error) { return encodeStreamingState("sma", &s.state) }
+
+// Restore restaure l'état
+func (s *SMAStream) Restore(data []byte) error {
+	var state smaState
+	if err := decodeStreamingState("sma", data, &state); err != nil {
+		return err
+	}
+	if err := checkPeriod("sma", state.Period, s.state.Period); err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// ============================================================================
+// EMA / RMA
+// ============================================================================
+
+// recursiveState état commun EMA/RMA : seed SMA paresseux puis formule récursive
+type recursiveState struct {
+	Period int
+	Wilder bool // true = RMA (alpha = 1/period), false = EMA (alpha = 2/(period+1))
+	SMA    smaState
+	Seen   int
+	Seeded bool
+	Value  float64
+}
+
+func newRecursiveState(period int, wilder bool) recursiveState {
+	return recursiveState{Period: period, Wilder: wilder, SMA: newSMAState(period), Value: math.NaN()}
+}
+
+// update reproduit EMATVStandard.Calculate / RMATVStandard.Calculate
+func (s *recursiveState) update(v float64) float64 {
+	if s.Period <= 0 {
+		s.Value = math.NaN()
+		return s.Value
+	}
+
+	// RMA length 1 : recopie de la source (comme TradingView)
+	if s.Wilder && s.Period == 1 {
+		s.Seen++
+		s.Value = v
+		return s.Value
+	}
+
+	sma := s.SMA.update(v)
+	s.Seen++
+	if s.Seen < s.Period {
+		s.Value = math.NaN()
+		return s.Value
+	}
+
+	if !s.Seeded {
+		if !isBadValue(sma) {
+			s.Value = sma
+			s.Seeded = true
+		} else {
+			s.Value = math.NaN()
+		}
+		return s.Value
+	}
+
+	prev := s.Value
+	if isBadValue(prev) || isBadValue(v) {
+		s.Value = math.NaN()
+		s.Seeded = false
+		return s.Value
+	}
+
+	if s.Wilder {
+		s.Value = (prev*float64(s.Period-1) + v) / float64(s.Period)
+	} else {
+		alpha := 2.0 / (float64(s.Period) + 1.0)
+		s.Value = alpha*v + (1.0-alpha)*prev
+	}
+	return s.Value
+}
+
+// EMAStream - EMA TV Standard incrémental (source: close)
+type EMAStream struct {
+	state recursiveState
+}
+
+// NewEMAStream crée une EMA incrémentale
+func NewEMAStream(period int) *EMAStream {
+	return &EMAStream{state: newRecursiveState(period, false)}
+}
+
+// Update intègre la clôture de la bougie
+func (s *EMAStream) Update(bar Kline) { s.UpdateValue(bar.Close) }
+
+// UpdateValue intègre une valeur source quelconque et retourne l'EMA
+func (s *EMAStream) UpdateValue(v float64) float64 { return s.state.update(v) }
+
+// Value retourne l'EMA courante
+func (s *EMAStream) Value() float64 { return s.state.Value }
+
+// Ready indique si l'EMA est disponible
+func (s *EMAStream) Ready() bool { return !math.IsNaN(s.state.Value) }
+
+// Snapshot sérialise l'état
+func (s *EMAStream) Snapshot() ([]byte, error) { return encodeStreamingState("ema", &s.state) }
+
+// Restore restaure l'état
+func (s *EMAStream) Restore(data []byte) error {
+	return restoreRecursive("ema", data, &s.state)
+}
+
+// RMAStream - RMA TV Standard (Wilder) incrémental (source: close)
+type RMAStream struct {
+	state recursiveState
+}
+
+// NewRMAStream crée un RMA incrémental
+func NewRMAStream(period int) *RMAStream {
+	return &RMAStream{state: newRecursiveState(period, true)}
+}
+
+// Update intègre la clôture de la bougie
+func (s *RMAStream) Update(bar Kline) { s.UpdateValue(bar.Close) }
+
+// UpdateValue intègre une valeur source quelconque et retourne le RMA
+func (s *RMAStream) UpdateValue(v float64) float64 { return s.state.update(v) }
+
+// Value retourne le RMA courant
+func (s *RMAStream) Value() float64 { return s.state.Value }
+
+// Ready indique si le RMA est disponible
+func (s *RMAStream) Ready() bool { return !math.IsNaN(s.state.Value) }
+
+// Snapshot sérialise l'état
+func (s *RMAStream) Snapshot() ([]byte, error) { return encodeStreamingState("rma", &s.state) }
+
+// Restore restaure l'état
+func (s *RMAStream) Restore(data []byte) error {
+	return restoreRecursive("rma", data, &s.state)
+}
+
+// restoreRecursive restaure un état EMA/RMA
+func restoreRecursive(kind string, data []byte, target *recursiveState) error {
+	var state recursiveState
+	if err := decodeStreamingState(kind, data, &state); err != nil {
+		return err
+	}
+	if err := checkPeriod(kind, state.Period, target.Period); err != nil {
+		return err
+	}
+	*target = state
+	return nil
+}
+
+// ============================================================================
+// VWMA
+// ============================================================================
+
+// vwmaState état de VWMATVStandard.Calculate
+type vwmaState struct {
+	Period int
+	Close  []float64
+	Volume []float64
+	Pos    int
+	Seen   int
+	Value  float64
+}
+
+// VWMAStream - VWMA TV Standard incrémental
+type VWMAStream struct {
+	state vwmaState
+}
+
+// NewVWMAStream crée un VWMA incrémental
+func NewVWMAStream(period int) *VWMAStream {
+	size := period
+	if size < 0 {
+		size = 0
+	}
+	return &VWMAStream{state: vwmaState{
+		Period: period,
+		Close:  make([]float64, size),
+		Volume: make([]float64, size),
+		Value:  math.NaN(),
+	}}
+}
+
+// Update intègre une bougie ; les sommes sont refaites sur la fenêtre dans l'ordre du batch
+func (s *VWMAStream) Update(bar Kline) {
+	st := &s.state
+	if st.Period <= 0 {
+		st.Value = math.NaN()
+		return
+	}
+
+	st.Close[st.Pos] = bar.Close
+	st.Volume[st.Pos] = bar.Volume
+	st.Pos = (st.Pos + 1) % st.Period
+	st.Seen++
+
+	if st.Seen < st.Period {
+		st.Value = math.NaN()
+		return
+	}
+
+	var sumWeightedPrice, sumVolume float64
+	for i := 0; i < st.Period; i++ {
+		j := (st.Pos + i) % st.Period
+		sumWeightedPrice += st.Close[j] * st.Volume[j]
+		sumVolume += st.Volume[j]
+	}
+
+	if sumVolume != 0 {
+		st.Value = sumWeightedPrice / sumVolume
+	} else {
+		st.Value = math.NaN()
+	}
+}
+
+// Value retourne le VWMA courant
+func (s *VWMAStream) Value() float64 { return s.state.Value }
+
+// Ready indique si le VWMA est disponible
+func (s *VWMAStream) Ready() bool { return !math.IsNaN(s.state.Value) }
+
+// Snapshot sérialise l'état
+func (s *VWMAStream) Snapshot() ([]byte, error) { return encodeStreamingState("vwma", &s.state) }
+
+// Restore restaure l'état
+func (s *VWMAStream) Restore(data []byte) error {
+	var state vwmaState
+	if err := decodeStreamingState("vwma", data, &state); err != nil {
+		return err
+	}
+	if err := checkPeriod("vwma", state.Period, s.state.Period); err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// ============================================================================
+// ATR
+// ============================================================================
+
+// atrState état de ATRTVStandard.Calculate
+type atrState struct {
+	Period    int
+	PrevClose float64
+	Seen      int
+	SeedSum   float64
+	Value     float64
+}
+
+// ATRStream - ATR TV Standard incrémental (RMA du True Range)
+type ATRStream struct {
+	state atrState
+}
+
+// NewATRStream crée un ATR incrémental
+func NewATRStream(period int) *ATRStream {
+	return &ATRStream{state: atrState{Period: period, Value: math.NaN()}}
+}
+
+// Update intègre une bougie
+func (s *ATRStream) Update(bar Kline) {
+	st := &s.state
+	tr := bar.High - bar.Low
+	if st.Seen > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(bar.High-st.PrevClose), math.Abs(bar.Low-st.PrevClose)))
+	}
+	st.PrevClose = bar.Close
+	st.Seen++
+
+	switch {
+	case st.Period <= 0:
+		st.Value = math.NaN()
+	case st.Seen < st.Period:
+		st.SeedSum += tr
+	case st.Seen == st.Period:
+		st.SeedSum += tr
+		st.Value = st.SeedSum / float64(st.Period)
+	default:
+		st.Value = (st.Value*float64(st.Period-1) + tr) / float64(st.Period)
+	}
+}
+
+// Value retourne l'ATR courant
+func (s *ATRStream) Value() float64 { return s.state.Value }
+
+// Ready indique si l'ATR est disponible
+func (s *ATRStream) Ready() bool { return !math.IsNaN(s.state.Value) }
+
+// Snapshot sérialise l'état
+func (s *ATRStream) Snapshot() ([]byte, error) { return encodeStreamingState("atr", &s.state) }
+
+// Restore restaure l'état
+func (s *ATRStream) Restore(data []byte) error {
+	var state atrState
+	if err := decodeStreamingState("atr", data, &state); err != nil {
+		return err
+	}
+	if err := checkPeriod("atr", state.Period, s.state.Period); err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
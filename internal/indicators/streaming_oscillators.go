@@ -0,0 +1,503 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================================================
+// DMI
+// ============================================================================
+
+// dmiState état de DMITVStandard.Calculate
+type dmiState struct {
+	PeriodDI  int
+	PeriodADX int
+	PrevHigh  float64
+	PrevLow   float64
+	PrevClose float64
+	Seen      int
+	TR        recursiveState
+	PlusDM    recursiveState
+	MinusDM   recursiveState
+	ADXState  recursiveState
+	PlusDI    float64
+	MinusDI   float64
+	DX        float64
+}
+
+// DMIStream - DMI TV Standard incrémental (+DI, -DI, ADX)
+type DMIStream struct {
+	state dmiState
+}
+
+// NewDMIStream crée un DMI incrémental (même période pour DI et ADX)
+func NewDMIStream(period int) *DMIStream {
+	return NewDMIStreamWithPeriods(period, period)
+}
+
+// NewDMIStreamWithPeriods crée un DMI incrémental avec périodes distinctes DI/ADX
+func NewDMIStreamWithPeriods(periodDI, periodADX int) *DMIStream {
+	return &DMIStream{state: dmiState{
+		PeriodDI:  periodDI,
+		PeriodADX: periodADX,
+		TR:        newRecursiveState(periodDI, true),
+		PlusDM:    newRecursiveState(periodDI, true),
+		MinusDM:   newRecursiveState(periodDI, true),
+		ADXState:  newRecursiveState(periodADX, true),
+		PlusDI:    math.NaN(),
+		MinusDI:   math.NaN(),
+		DX:        math.NaN(),
+	}}
+}
+
+// Update intègre une bougie
+func (s *DMIStream) Update(bar Kline) {
+	st := &s.state
+
+	tr := bar.High - bar.Low
+	plusDM, minusDM := 0.0, 0.0
+	if st.Seen > 0 {
+		highLow := bar.High - bar.Low
+		highClosePrev := math.Abs(bar.High - st.PrevClose)
+		lowClosePrev := math.Abs(bar.Low - st.PrevClose)
+		tr = math.Max(highLow, math.Max(highClosePrev, lowClosePrev))
+
+		upMove := bar.High - st.PrevHigh
+		downMove := st.PrevLow - bar.Low
+		if upMove > downMove && upMove > 0 {
+			plusDM = upMove
+		}
+		if downMove > upMove && downMove > 0 {
+			minusDM = downMove
+		}
+	}
+	st.PrevHigh, st.PrevLow, st.PrevClose = bar.High, bar.Low, bar.Close
+	st.Seen++
+
+	atr := st.TR.update(tr)
+	smoothedPlusDM := st.PlusDM.update(plusDM)
+	smoothedMinusDM := st.MinusDM.update(minusDM)
+
+	if !math.IsNaN(atr) && atr != 0 {
+		st.PlusDI = 100 * smoothedPlusDM / atr
+		st.MinusDI = 100 * smoothedMinusDM / atr
+	} else {
+		st.PlusDI = math.NaN()
+		st.MinusDI = math.NaN()
+	}
+
+	if !math.IsNaN(st.PlusDI) && !math.IsNaN(st.MinusDI) {
+		sum := st.PlusDI + st.MinusDI
+		if sum != 0 {
+			st.DX = 100 * math.Abs(st.PlusDI-st.MinusDI) / sum
+		} else {
+			st.DX = 0
+		}
+	} else {
+		st.DX = math.NaN()
+	}
+
+	st.ADXState.update(st.DX)
+}
+
+// Value retourne l'ADX courant
+func (s *DMIStream) Value() float64 { return s.state.ADXState.Value }
+
+// PlusDI retourne le +DI courant
+func (s *DMIStream) PlusDI() float64 { return s.state.PlusDI }
+
+// MinusDI retourne le -DI courant
+func (s *DMIStream) MinusDI() float64 { return s.state.MinusDI }
+
+// ADX retourne l'ADX courant
+func (s *DMIStream) ADX() float64 { return s.state.ADXState.Value }
+
+// DX retourne le DX courant
+func (s *DMIStream) DX() float64 { return s.state.DX }
+
+// Ready indique si l'ADX est disponible
+func (s *DMIStream) Ready() bool { return !math.IsNaN(s.state.ADXState.Value) }
+
+// Snapshot sérialise l'état
+func (s *DMIStream) Snapshot() ([]byte, error) { return encodeStreamingState("dmi", &s.state) }
+
+// Restore restaure l'état
+func (s *DMIStream) Restore(data []byte) error {
+	var state dmiState
+	if err := decodeStreamingState("dmi", data, &state); err != nil {
+		return err
+	}
+	if state.PeriodDI != s.state.PeriodDI || state.PeriodADX != s.state.PeriodADX {
+		return fmt.Errorf("dmi snapshot period mismatch: got %d/%d, want %d/%d",
+			state.PeriodDI, state.PeriodADX, s.state.PeriodDI, s.state.PeriodADX)
+	}
+	s.state = state
+	return nil
+}
+
+// ============================================================================
+// MFI
+// ============================================================================
+
+// mfiState état de MFITVStandard.Calculate
+type mfiState struct {
+	Period   int
+	PrevTP   float64
+	Positive []float64
+	Negative []float64
+	Pos      int
+	Seen     int
+	Value    float64
+}
+
+// MFIStream - MFI TV Standard incrémental
+type MFIStream struct {
+	state mfiState
+}
+
+// NewMFIStream crée un MFI incrémental
+func NewMFIStream(period int) *MFIStream {
+	size := period
+	if size < 0 {
+		size = 0
+	}
+	return &MFIStream{state: mfiState{
+		Period:   period,
+		Positive: make([]float64, size),
+		Negative: make([]float64, size),
+		Value:    math.NaN(),
+	}}
+}
+
+// Update intègre une bougie
+func (s *MFIStream) Update(bar Kline) {
+	st := &s.state
+	tp := (bar.High + bar.Low + bar.Close) / 3.0
+	rawMoneyFlow := tp * bar.Volume
+
+	positive, negative := 0.0, 0.0
+	if st.Seen > 0 {
+		if tp > st.PrevTP {
+			positive = rawMoneyFlow
+		} else if tp < st.PrevTP {
+			negative = rawMoneyFlow
+		}
+	}
+	st.PrevTP = tp
+	st.Seen++
+
+	if st.Period <= 0 {
+		st.Value = math.NaN()
+		return
+	}
+
+	st.Positive[st.Pos] = positive
+	st.Negative[st.Pos] = negative
+	st.Pos = (st.Pos + 1) % st.Period
+
+	// Le batch commence à l'index period (une bougie de plus que la fenêtre)
+	if st.Seen <= st.Period {
+		st.Value = math.NaN()
+		return
+	}
+
+	sumPositive, sumNegative := 0.0, 0.0
+	validCount := 0
+	for i := 0; i < st.Period; i++ {
+		j := (st.Pos + i) % st.Period
+		if !math.IsNaN(st.Positive[j]) && !math.IsNaN(st.Negative[j]) {
+			sumPositive += st.Positive[j]
+			sumNegative += st.Negative[j]
+			validCount++
+		}
+	}
+
+	if validCount == st.Period {
+		st.Value = (&MFITVStandard{period: st.Period}).calculateMFIValue(sumPositive, sumNegative)
+	} else {
+		st.Value = math.NaN()
+	}
+}
+
+// Value retourne le MFI courant
+func (s *MFIStream) Value() float64 { return s.state.Value }
+
+// Ready indique si le MFI est disponible
+func (s *MFIStream) Ready() bool { return !math.IsNaN(s.state.Value) }
+
+// Snapshot sérialise l'état
+func (s *MFIStream) Snapshot() ([]byte, error) { return encodeStreamingState("mfi", &s.state) }
+
+// Restore restaure l'état
+func (s *MFIStream) Restore(data []byte) error {
+	var state mfiState
+	if err := decodeStreamingState("mfi", data, &state); err != nil {
+		return err
+	}
+	if err := checkPeriod("mfi", state.Period, s.state.Period); err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// ============================================================================
+// CCI
+// ============================================================================
+
+// cciState état de CCITVStandard.Calculate
+type cciState struct {
+	Period int
+	TP     smaState // Fenêtre des typical prices + SMA
+	Value  float64
+}
+
+// CCIStream - CCI TV Standard incrémental
+type CCIStream struct {
+	state cciState
+}
+
+// NewCCIStream crée un CCI incrémental
+func NewCCIStream(period int) *CCIStream {
+	return &CCIStream{state: cciState{Period: period, TP: newSMAState(period), Value: math.NaN()}}
+}
+
+// Update intègre une bougie
+func (s *CCIStream) Update(bar Kline) {
+	st := &s.state
+	tp := (bar.High + bar.Low + bar.Close) / 3.0
+	sma := st.TP.update(tp)
+
+	if st.Period <= 0 || st.TP.Seen < st.Period {
+		st.Value = math.NaN()
+		return
+	}
+
+	sum := 0.0
+	for i := 0; i < st.Period; i++ {
+		sum += math.Abs(st.TP.window(i) - sma)
+	}
+	meanDev := sum / float64(st.Period)
+
+	if meanDev == 0 {
+		st.Value = 0.0
+	} else {
+		st.Value = (tp - sma) / (0.015 * meanDev)
+	}
+}
+
+// Value retourne le CCI courant
+func (s *CCIStream) Value() float64 { return s.state.Value }
+
+// Ready indique si le CCI est disponible
+func (s *CCIStream) Ready() bool { return !math.IsNaN(s.state.Value) }
+
+// Snapshot sérialise l'état
+func (s *CCIStream) Snapshot() ([]byte, error) { return encodeStreamingState("cci", &s.state) }
+
+// Restore restaure l'état
+func (s *CCIStream) Restore(data []byte) error {
+	var state cciState
+	if err := decodeStreamingState("cci", data, &state); err != nil {
+		return err
+	}
+	if err := checkPeriod("cci", state.Period, s.state.Period); err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// ============================================================================
+// STOCHASTIC
+// ============================================================================
+
+// stochState état de StochTVStandard.Calculate
+type stochState struct {
+	PeriodK int
+	SmoothK int
+	PeriodD int
+	High    []float64
+	Low     []float64
+	Pos     int
+	Seen    int
+	KRaw    float64
+	K       smaState
+	D       smaState
+}
+
+// StochStream - Stochastic TV Standard incrémental (%K lissé, %D)
+type StochStream struct {
+	state stochState
+}
+
+// NewStochStream crée un Stochastic incrémental
+func NewStochStream(periodK, smoothK, periodD int) *StochStream {
+	size := periodK
+	if size < 0 {
+		size = 0
+	}
+	return &StochStream{state: stochState{
+		PeriodK: periodK,
+		SmoothK: smoothK,
+		PeriodD: periodD,
+		High:    make([]float64, size),
+		Low:     make([]float64, size),
+		KRaw:    math.NaN(),
+		K:       newSMAState(smoothK),
+		D:       newSMAState(periodD),
+	}}
+}
+
+// Update intègre une bougie
+func (s *StochStream) Update(bar Kline) {
+	st := &s.state
+	st.Seen++
+	st.KRaw = math.NaN()
+
+	if st.PeriodK > 0 {
+		st.High[st.Pos] = bar.High
+		st.Low[st.Pos] = bar.Low
+		st.Pos = (st.Pos + 1) % st.PeriodK
+
+		if st.Seen >= st.PeriodK {
+			// Même parcours que le batch : initialisation sur la plus ancienne bougie
+			highestHigh := st.High[st.Pos]
+			lowestLow := st.Low[st.Pos]
+			for i := 0; i < st.PeriodK; i++ {
+				j := (st.Pos + i) % st.PeriodK
+				if st.High[j] > highestHigh {
+					highestHigh = st.High[j]
+				}
+				if st.Low[j] < lowestLow {
+					lowestLow = st.Low[j]
+				}
+			}
+
+			if highestHigh == lowestLow {
+				st.KRaw = 50.0
+			} else {
+				st.KRaw = 100.0 * (bar.Close - lowestLow) / (highestHigh - lowestLow)
+			}
+		}
+	}
+
+	st.D.update(st.K.update(st.KRaw))
+}
+
+// Value retourne le %K lissé courant
+func (s *StochStream) Value() float64 { return s.state.K.Value }
+
+// K retourne le %K lissé courant
+func (s *StochStream) K() float64 { return s.state.K.Value }
+
+// D retourne le %D courant
+func (s *StochStream) D() float64 { return s.state.D.Value }
+
+// Ready indique si %K et %D sont disponibles
+func (s *StochStream) Ready() bool {
+	return !math.IsNaN(s.state.K.Value) && !math.IsNaN(s.state.D.Value)
+}
+
+// Snapshot sérialise l'état
+func (s *StochStream) Snapshot() ([]byte, error) { return encodeStreamingState("stoch", &s.state) }
+
+// Restore restaure l'état
+func (s *StochStream) Restore(data []byte) error {
+	var state stochState
+	if err := decodeStreamingState("stoch", data, &state); err != nil {
+		return err
+	}
+	if state.PeriodK != s.state.PeriodK || state.SmoothK != s.state.SmoothK || state.PeriodD != s.state.PeriodD {
+		return fmt.Errorf("stoch snapshot period mismatch: got %d/%d/%d, want %d/%d/%d",
+			state.PeriodK, state.SmoothK, state.PeriodD, s.state.PeriodK, s.state.SmoothK, s.state.PeriodD)
+	}
+	s.state = state
+	return nil
+}
+
+// ============================================================================
+// MACD
+// ============================================================================
+
+// macdState état de MACDTVStandard.Calculate
+type macdState struct {
+	Fast      recursiveState
+	Slow      recursiveState
+	Signal    recursiveState
+	MACD      float64
+	Histogram float64
+}
+
+// MACDStream - MACD TV Standard incrémental (source: close)
+type MACDStream struct {
+	state macdState
+}
+
+// NewMACDStream crée un MACD incrémental
+func NewMACDStream(fast, slow, signal int) *MACDStream {
+	return &MACDStream{state: macdState{
+		Fast:      newRecursiveState(fast, false),
+		Slow:      newRecursiveState(slow, false),
+		Signal:    newRecursiveState(signal, false),
+		MACD:      math.NaN(),
+		Histogram: math.NaN(),
+	}}
+}
+
+// Update intègre la clôture de la bougie
+func (s *MACDStream) Update(bar Kline) { s.UpdateValue(bar.Close) }
+
+// UpdateValue intègre une valeur source quelconque
+func (s *MACDStream) UpdateValue(v float64) {
+	st := &s.state
+	fast := st.Fast.update(v)
+	slow := st.Slow.update(v)
+
+	if !math.IsNaN(fast) && !math.IsNaN(slow) {
+		st.MACD = fast - slow
+	} else {
+		st.MACD = math.NaN()
+	}
+
+	signal := st.Signal.update(st.MACD)
+	if !math.IsNaN(st.MACD) && !math.IsNaN(signal) {
+		st.Histogram = st.MACD - signal
+	} else {
+		st.Histogram = math.NaN()
+	}
+}
+
+// Value retourne la ligne MACD courante
+func (s *MACDStream) Value() float64 { return s.state.MACD }
+
+// MACD retourne la ligne MACD courante
+func (s *MACDStream) MACD() float64 { return s.state.MACD }
+
+// Signal retourne la ligne de signal courante
+func (s *MACDStream) Signal() float64 { return s.state.Signal.Value }
+
+// Histogram retourne l'histogramme courant
+func (s *MACDStream) Histogram() float64 { return s.state.Histogram }
+
+// Ready indique si MACD et signal sont disponibles
+func (s *MACDStream) Ready() bool { return !math.IsNaN(s.state.Histogram) }
+
+// Snapshot sérialise l'état
+func (s *MACDStream) Snapshot() ([]byte, error) { return encodeStreamingState("macd", &s.state) }
+
+// Restore restaure l'état
+func (s *MACDStream) Restore(data []byte) error {
+	var state macdState
+	if err := decodeStreamingState("macd", data, &state); err != nil {
+		return err
+	}
+	if state.Fast.Period != s.state.Fast.Period || state.Slow.Period != s.state.Slow.Period || state.Signal.Period != s.state.Signal.Period {
+		return fmt.Errorf("macd snapshot period mismatch: got %d/%d/%d, want %d/%d/%d",
+			state.Fast.Period, state.Slow.Period, state.Signal.Period,
+			s.state.Fast.Period, s.state.Slow.Period, s.state.Signal.Period)
+	}
+	s.state = state
+	return nil
+}
@@ -0,0 +1,197 @@
+package indicators
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomWalkKlines génère une série déterministe réaliste (prix décimaux, volumes variables)
+func randomWalkKlines(count int, seed int64) []Kline {
+	rng := rand.New(rand.NewSource(seed))
+	klines := make([]Kline, count)
+	price := 150.0
+	for i := range klines {
+		open := price
+		price += rng.NormFloat64() * 0.7
+		high := math.Max(open, price) + rng.Float64()*0.4
+		low := math.Min(open, price) - rng.Float64()*0.4
+		volume := 500 + rng.Float64()*2000
+		if i%37 == 0 {
+			high, low = open, open // Bougie plate (cas division par zéro)
+			price = open
+		}
+		klines[i] = Kline{Timestamp: int64(i) * 60000, Open: open, High: high, Low: low, Close: price, Volume: volume}
+	}
+	return klines
+}
+
+// sameBits compare deux séries bit à bit (NaN == NaN)
+func sameBits(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: length %d, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(got[i]) && math.IsNaN(want[i]) {
+			continue
+		}
+		if math.Float64bits(got[i]) != math.Float64bits(want[i]) {
+			t.Fatalf("%s: bar %d got %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+// streamCase décrit un indicateur incrémental et ses sorties comparées au batch
+type streamCase struct {
+	name    string
+	factory func() StreamingIndicator
+	outputs func(StreamingIndicator) []float64
+	batch   func(high, low, close, volume []float64) [][]float64
+}
+
+func streamCases() []streamCase {
+	value := func(s StreamingIndicator) []float64 { return []float64{s.Value()} }
+	return []streamCase{
+		{"sma", func() StreamingIndicator { return NewSMAStream(20) }, value,
+			func(h, l, c, v []float64) [][]float64 { return [][]float64{NewSMATVStandard(20).Calculate(c)} }},
+		{"ema", func() StreamingIndicator { return NewEMAStream(12) }, value,
+			func(h, l, c, v []float64) [][]float64 { return [][]float64{NewEMATVStandard(12).Calculate(c)} }},
+		{"rma", func() StreamingIndicator { return NewRMAStream(14) }, value,
+			func(h, l, c, v []float64) [][]float64 { return [][]float64{NewRMATVStandard(14).Calculate(c)} }},
+		{"vwma", func() StreamingIndicator { return NewVWMAStream(6) }, value,
+			func(h, l, c, v []float64) [][]float64 { return [][]float64{NewVWMATVStandard(6).Calculate(c, v)} }},
+		{"atr", func() StreamingIndicator { return NewATRStream(14) }, value,
+			func(h, l, c, v []float64) [][]float64 { return [][]float64{NewATRTVStandard(14).Calculate(h, l, c)} }},
+		{"mfi", func() StreamingIndicator { return NewMFIStream(14) }, value,
+			func(h, l, c, v []float64) [][]float64 { return [][]float64{NewMFITVStandard(14).Calculate(h, l, c, v)} }},
+		{"cci", func() StreamingIndicator { return NewCCIStream(20) }, value,
+			func(h, l, c, v []float64) [][]float64 { return [][]float64{NewCCITVStandard(20).Calculate(h, l, c)} }},
+		{"dmi", func() StreamingIndicator { return NewDMIStreamWithPeriods(14, 10) },
+			func(s StreamingIndicator) []float64 {
+				d := s.(*DMIStream)
+				return []float64{d.PlusDI(), d.MinusDI(), d.ADX()}
+			},
+			func(h, l, c, v []float64) [][]float64 {
+				p, m, a := NewDMITVStandardWithPeriods(14, 10).Calculate(h, l, c)
+				return [][]float64{p, m, a}
+			}},
+		{"stoch", func() StreamingIndicator { return NewStochStream(14, 3, 3) },
+			func(s StreamingIndicator) []float64 {
+				st := s.(*StochStream)
+				return []float64{st.K(), st.D()}
+			},
+			func(h, l, c, v []float64) [][]float64 {
+				k, d := NewStochTVStandard(14, 3, 3).Calculate(h, l, c)
+				return [][]float64{k, d}
+			}},
+		{"macd", func() StreamingIndicator { return NewMACDStream(12, 26, 9) },
+			func(s StreamingIndicator) []float64 {
+				m := s.(*MACDStream)
+				return []float64{m.MACD(), m.Signal(), m.Histogram()}
+			},
+			func(h, l, c, v []float64) [][]float64 {
+				m, s, hist := NewMACDTVStandard(12, 26, 9).Calculate(c)
+				return [][]float64{m, s, hist}
+			}},
+	}
+}
+
+// TestStreaming_BitIdenticalToBatch vérifie l'égalité bit à bit avec les versions TV Standard
+func TestStreaming_BitIdenticalToBatch(t *testing.T) {
+	klines := randomWalkKlines(600, 42)
+	high := make([]float64, len(klines))
+	low := make([]float64, len(klines))
+	closes := make([]float64, len(klines))
+	volume := make([]float64, len(klines))
+	for i, k := range klines {
+		high[i], low[i], closes[i], volume[i] = k.High, k.Low, k.Close, k.Volume
+	}
+
+	for _, tc := range streamCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			want := tc.batch(high, low, closes, volume)
+			got := make([][]float64, len(want))
+			for j := range got {
+				got[j] = make([]float64, len(klines))
+			}
+
+			stream := tc.factory()
+			for i, k := range klines {
+				stream.Update(k)
+				for j, v := range tc.outputs(stream) {
+					got[j][i] = v
+				}
+			}
+
+			for j := range want {
+				sameBits(t, tc.name, got[j], want[j])
+			}
+			if !stream.Ready() {
+				t.Errorf("%s: expected Ready after %d bars", tc.name, len(klines))
+			}
+		})
+	}
+}
+
+// TestStreaming_SnapshotRestore vérifie la reprise exacte après Snapshot/Restore
+func TestStreaming_SnapshotRestore(t *testing.T) {
+	klines := randomWalkKlines(300, 7)
+
+	for _, tc := range streamCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			reference := tc.factory()
+			resumed := tc.factory()
+
+			for i, k := range klines {
+				reference.Update(k)
+				if i < 150 {
+					resumed.Update(k)
+					continue
+				}
+				if i == 150 {
+					data, err := resumed.Snapshot()
+					if err != nil {
+						t.Fatalf("Snapshot failed: %v", err)
+					}
+					resumed = tc.factory()
+					if err := resumed.Restore(data); err != nil {
+						t.Fatalf("Restore failed: %v", err)
+					}
+				}
+				resumed.Update(k)
+				sameBits(t, tc.name, tc.outputs(resumed), tc.outputs(reference))
+			}
+		})
+	}
+
+	// Les paramètres et le type doivent correspondre
+	data, _ := NewEMAStream(12).Snapshot()
+	if err := NewEMAStream(26).Restore(data); err == nil {
+		t.Error("Expected period mismatch error")
+	}
+	if err := NewRMAStream(12).Restore(data); err == nil {
+		t.Error("Expected kind mismatch error")
+	}
+}
+
+// TestStreaming_InvalidValues vérifie la réinitialisation sur NaN comme le batch
+func TestStreaming_InvalidValues(t *testing.T) {
+	src := []float64{1, 2, 3, 4, math.NaN(), 5, 6, 7, 8, 9, math.Inf(1), 10, 11, 12, 13}
+
+	ema := NewEMAStream(3)
+	rma := NewRMAStream(3)
+	sma := NewSMAStream(3)
+	gotEMA := make([]float64, len(src))
+	gotRMA := make([]float64, len(src))
+	gotSMA := make([]float64, len(src))
+	for i, v := range src {
+		gotEMA[i] = ema.UpdateValue(v)
+		gotRMA[i] = rma.UpdateValue(v)
+		gotSMA[i] = sma.UpdateValue(v)
+	}
+
+	sameBits(t, "ema", gotEMA, NewEMATVStandard(3).Calculate(src))
+	sameBits(t, "rma", gotRMA, NewRMATVStandard(3).Calculate(src))
+	sameBits(t, "sma", gotSMA, NewSMATVStandard(3).Calculate(src))
+}
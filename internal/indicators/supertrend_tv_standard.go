@@ -0,0 +1,137 @@
+package indicators
+
+import (
+	"math"
+)
+
+// SupertrendTVStandard - Implémentation Supertrend TradingView Standard (ta.supertrend)
+// Formules Pine:
+//
+//	upperBand = hl2 + factor * ta.atr(atrPeriod), lowerBand = hl2 - factor * atr
+//	lowerBand := lowerBand > prevLowerBand or close[1] < prevLowerBand ? lowerBand : prevLowerBand
+//	upperBand := upperBand < prevUpperBand or close[1] > prevUpperBand ? upperBand : prevUpperBand
+//	direction = na(atr[1]) ? 1 : prevSuperTrend == prevUpperBand ? (close > upperBand ? -1 : 1) : (close < lowerBand ? 1 : -1)
+//	superTrend = direction == -1 ? lowerBand : upperBand
+//
+// Convention TV: direction -1 = tendance haussière, +1 = tendance baissière
+// Défauts TV: factor 3, atrPeriod 10
+type SupertrendTVStandard struct {
+	atrPeriod int
+	factor    float64
+}
+
+// NewSupertrendTVStandard crée une nouvelle instance Supertrend TV Standard
+func NewSupertrendTVStandard(atrPeriod int, factor float64) *SupertrendTVStandard {
+	return &SupertrendTVStandard{
+		atrPeriod: atrPeriod,
+		factor:    factor,
+	}
+}
+
+// Calculate calcule la ligne Supertrend et la direction (NaN pendant le warmup ATR)
+func (st *SupertrendTVStandard) Calculate(high, low, close []float64) (supertrend, direction []float64) {
+	n := len(high)
+	if n != len(low) || n != len(close) {
+		return nil, nil
+	}
+
+	atr := NewATRTVStandard(st.atrPeriod).Calculate(high, low, close)
+
+	supertrend = make([]float64, n)
+	direction = make([]float64, n)
+	// nz(band[1]) : les bandes précédentes inconnues valent 0
+	prevUpper, prevLower, prevSuper := 0.0, 0.0, math.NaN()
+
+	for i := 0; i < n; i++ {
+		supertrend[i] = math.NaN()
+		direction[i] = math.NaN()
+		if math.IsNaN(atr[i]) {
+			prevUpper, prevLower = 0, 0
+			continue
+		}
+
+		src := (high[i] + low[i]) / 2
+		upperBand := src + st.factor*atr[i]
+		lowerBand := src - st.factor*atr[i]
+
+		prevClose := 0.0
+		if i > 0 {
+			prevClose = close[i-1]
+		}
+		if !(lowerBand > prevLower || prevClose < prevLower) {
+			lowerBand = prevLower
+		}
+		if !(upperBand < prevUpper || prevClose > prevUpper) {
+			upperBand = prevUpper
+		}
+
+		var dir float64
+		switch {
+		case i == 0 || math.IsNaN(atr[i-1]):
+			dir = 1
+		case prevSuper == prevUpper:
+			dir = 1
+			if close[i] > upperBand {
+				dir = -1
+			}
+		default:
+			dir = -1
+			if close[i] < lowerBand {
+				dir = 1
+			}
+		}
+
+		value := upperBand
+		if dir == -1 {
+			value = lowerBand
+		}
+
+		supertrend[i] = value
+		direction[i] = dir
+		prevUpper, prevLower, prevSuper = upperBand, lowerBand, value
+	}
+
+	return supertrend, direction
+}
+
+// CalculateFromKlines calcule le Supertrend depuis des klines
+func (st *SupertrendTVStandard) CalculateFromKlines(klines []Kline) (supertrend, direction []float64) {
+	high, low, closes, _ := klineColumns(klines)
+	return st.Calculate(high, low, closes)
+}
+
+// GetLastValues retourne la dernière ligne et direction valides
+func (st *SupertrendTVStandard) GetLastValues(supertrend, direction []float64) (float64, float64) {
+	for i := len(supertrend) - 1; i >= 0; i-- {
+		if !math.IsNaN(supertrend[i]) {
+			return supertrend[i], direction[i]
+		}
+	}
+	return math.NaN(), math.NaN()
+}
+
+// GetSignal retourne la tendance pour une direction TV
+func (st *SupertrendTVStandard) GetSignal(direction float64) string {
+	switch direction {
+	case -1:
+		return "HAUSSIER"
+	case 1:
+		return "BAISSIER"
+	}
+	return "INCONNU"
+}
+
+// DetecterRetournement détecte un changement de direction sur la bougie index
+// Retourne "HAUSSIER" (1 -> -1), "BAISSIER" (-1 -> 1) ou ""
+func (st *SupertrendTVStandard) DetecterRetournement(direction []float64, index int) string {
+	if index < 1 || index >= len(direction) || math.IsNaN(direction[index-1]) || math.IsNaN(direction[index]) {
+		return ""
+	}
+	if direction[index-1] == 1 && direction[index] == -1 {
+		return "HAUSSIER"
+	}
+	if direction[index-1] == -1 && direction[index] == 1 {
+		return "BAISSIER"
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+package indicators
+
+import (
+	"math"
+	"sort"
+)
+
+// SwingPoint sommet ou creux de swing confirmé (fractal PivotLeft / PivotRight)
+type SwingPoint struct {
+	Index        int     // Bougie du pivot
+	ConfirmIndex int     // Première bougie où le pivot est connu (Index + PivotRight)
+	Price        float64 // High du sommet ou Low du creux
+	High         bool    // true = sommet, false = creux
+}
+
+// DetectSwings détecte les swings (PivotHigh sur high, PivotLow sur low) triés par ConfirmIndex
+func DetectSwings(high, low []float64, left, right int) []SwingPoint {
+	highs, lows := PivotHigh(high, left, right), PivotLow(low, left, right)
+	var swings []SwingPoint
+	for i := range high {
+		if highs[i] {
+			swings = append(swings, SwingPoint{Index: i, ConfirmIndex: i + right, Price: high[i], High: true})
+		}
+		if i < len(lows) && lows[i] {
+			swings = append(swings, SwingPoint{Index: i, ConfirmIndex: i + right, Price: low[i]})
+		}
+	}
+	return swings
+}
+
+// SRConfig paramètres des zones support / résistance
+type SRConfig struct {
+	PivotLeft    int     `yaml:"pivot_left"`     // Bougies à gauche du swing (défaut 5)
+	PivotRight   int     `yaml:"pivot_right"`    // Bougies à droite = délai de confirmation (défaut 5)
+	ATRPeriod    int     `yaml:"atr_period"`     // ATR de la largeur de zone (défaut 14)
+	ZoneWidthATR float64 `yaml:"zone_width_atr"` // Écart maximal d'un swing au centre de zone (défaut 0.5 ATR)
+	Lookback     int     `yaml:"lookback"`       // Swings confirmés sur N bougies (défaut 300, 0 = défaut)
+	MinTouches   int     `yaml:"min_touches"`    // Touches minimales d'une zone retenue (défaut 1)
+}
+
+// DefaultSRConfig retourne la configuration par défaut
+func DefaultSRConfig() SRConfig {
+	return SRConfig{PivotLeft: 5, PivotRight: 5, ATRPeriod: 14, ZoneWidthATR: 0.5, Lookback: 300, MinTouches: 1}
+}
+
+// withDefaults remplace les valeurs nulles par les défauts
+func (c SRConfig) withDefaults() SRConfig {
+	def := DefaultSRConfig()
+	for _, f := range []struct{ v, d *int }{
+		{&c.PivotLeft, &def.PivotLeft}, {&c.PivotRight, &def.PivotRight}, {&c.ATRPeriod, &def.ATRPeriod},
+		{&c.Lookback, &def.Lookback}, {&c.MinTouches, &def.MinTouches},
+	} {
+		if *f.v <= 0 {
+			*f.v = *f.d
+		}
+	}
+	if c.ZoneWidthATR <= 0 {
+		c.ZoneWidthATR = def.ZoneWidthATR
+	}
+	return c
+}
+
+// SRZone zone de prix regroupant des swings proches
+type SRZone struct {
+	Low       float64 // Plus bas des swings de la zone
+	High      float64 // Plus haut des swings de la zone
+	Price     float64 // Centre (moyenne des swings)
+	Touches   int     // Nombre de swings
+	Highs     int     // Dont sommets (résistance)
+	Lows      int     // Dont creux (support)
+	LastIndex int     // Bougie du swing le plus récent
+}
+
+// SRLevels zones connues à une bougie, triées par prix croissant
+type SRLevels []SRZone
+
+// SRDetector détecteur de swings et de zones support / résistance (sans look-ahead)
+//
+// Les swings sont regroupés dans l'ordre de confirmation : un swing rejoint la zone dont le
+// centre est à moins de ZoneWidthATR × ATR (ATR de sa bougie de confirmation), sinon il
+// ouvre une nouvelle zone. Les zones d'une bougie i n'utilisent que les swings confirmés
+// à i, sur les Lookback dernières bougies.
+type SRDetector struct {
+	config SRConfig
+	swings []SwingPoint
+	atr    []float64
+}
+
+// NewSRDetector crée un détecteur (valeurs nulles remplacées par les défauts)
+func NewSRDetector(config SRConfig) *SRDetector {
+	return &SRDetector{config: config.withDefaults()}
+}
+
+// Config retourne la configuration effective
+func (d *SRDetector) Config() SRConfig {
+	return d.config
+}
+
+// Calculate détecte les swings et prépare l'ATR de regroupement
+func (d *SRDetector) Calculate(high, low, close []float64) []SwingPoint {
+	cfg := d.config
+	d.swings = DetectSwings(high, low, cfg.PivotLeft, cfg.PivotRight)
+	sort.SliceStable(d.swings, func(a, b int) bool { return d.swings[a].ConfirmIndex < d.swings[b].ConfirmIndex })
+	d.atr = NewATRTVStandard(cfg.ATRPeriod).Calculate(high, low, close)
+	return d.swings
+}
+
+// CalculateFromKlines détecte les swings depuis des klines
+func (d *SRDetector) CalculateFromKlines(klines []Kline) []SwingPoint {
+	high, low, closes, _ := klineColumns(klines)
+	return d.Calculate(high, low, closes)
+}
+
+// Swings retourne les swings du dernier calcul
+func (d *SRDetector) Swings() []SwingPoint {
+	return d.swings
+}
+
+// ZonesAt retourne les zones connues à la bougie index
+func (d *SRDetector) ZonesAt(index int) SRLevels {
+	cfg := d.config
+	var zones SRLevels
+	for _, sw := range d.swings {
+		if sw.ConfirmIndex > index {
+			break
+		}
+		if sw.Index < index-cfg.Lookback {
+			continue
+		}
+		width := math.NaN()
+		if sw.ConfirmIndex < len(d.atr) {
+			width = d.atr[sw.ConfirmIndex] * cfg.ZoneWidthATR
+		}
+		best := -1
+		for z := range zones {
+			dist := math.Abs(zones[z].Price - sw.Price)
+			if !math.IsNaN(width) && dist <= width && (best < 0 || dist < math.Abs(zones[best].Price-sw.Price)) {
+				best = z
+			}
+		}
+		if best < 0 {
+			zones = append(zones, SRZone{Low: sw.Price, High: sw.Price})
+			best = len(zones) - 1
+		}
+		zone := &zones[best]
+		zone.Price = (zone.Price*float64(zone.Touches) + sw.Price) / float64(zone.Touches+1)
+		zone.Touches++
+		zone.Low, zone.High = math.Min(zone.Low, sw.Price), math.Max(zone.High, sw.Price)
+		zone.LastIndex = sw.Index
+		if sw.High {
+			zone.Highs++
+		} else {
+			zone.Lows++
+		}
+	}
+
+	kept := zones[:0]
+	for _, zone := range zones {
+		if zone.Touches >= cfg.MinTouches {
+			kept = append(kept, zone)
+		}
+	}
+	sort.Slice(kept, func(a, b int) bool { return kept[a].Price < kept[b].Price })
+	return kept
+}
+
+// NearestSeries support le plus proche sous la clôture et résistance la plus proche au-dessus,
+// pour chaque bougie (NaN si aucune zone)
+func (d *SRDetector) NearestSeries(close []float64) (support, resistance []float64) {
+	support, resistance = make([]float64, len(close)), make([]float64, len(close))
+	for i, c := range close {
+		support[i], resistance[i] = math.NaN(), math.NaN()
+		zones := d.ZonesAt(i)
+		if below, ok := zones.NearestBelow(c); ok {
+			support[i] = below.Price
+		}
+		if above, ok := zones.NearestAbove(c); ok {
+			resistance[i] = above.Price
+		}
+	}
+	return support, resistance
+}
+
+// NearestBelow zone la plus proche dont le centre est strictement sous price
+func (levels SRLevels) NearestBelow(price float64) (SRZone, bool) {
+	for i := len(levels) - 1; i >= 0; i-- {
+		if levels[i].Price < price {
+			return levels[i], true
+		}
+	}
+	return SRZone{}, false
+}
+
+// NearestAbove zone la plus proche dont le centre est strictement au-dessus de price
+func (levels SRLevels) NearestAbove(price float64) (SRZone, bool) {
+	for _, zone := range levels {
+		if zone.Price > price {
+			return zone, true
+		}
+	}
+	return SRZone{}, false
+}
+
+// NearestLevel niveau le plus proche sous (above = false) ou au-dessus (above = true) de price
+// parmi des niveaux quelconques (zones, pivots), NaN ignorés
+func NearestLevel(levels []float64, price float64, above bool) (float64, bool) {
+	best, found := 0.0, false
+	for _, level := range levels {
+		if math.IsNaN(level) || (above && level <= price) || (!above && level >= price) {
+			continue
+		}
+		if !found || math.Abs(level-price) < math.Abs(best-price) {
+			best, found = level, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,60 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// swingKlines oscillation entre ~100 et ~110 (période 20) : sommets et creux répétés
+func swingKlines(n int) (high, low, closes []float64) {
+	for i := 0; i < n; i++ {
+		c := 105 + 5*math.Sin(2*math.Pi*float64(i)/20)
+		high, low, closes = append(high, c+0.5), append(low, c-0.5), append(closes, c)
+	}
+	return high, low, closes
+}
+
+// TestSwings_ConfirmationAndZones vérifie la confirmation des swings et le regroupement en zones
+func TestSwings_ConfirmationAndZones(t *testing.T) {
+	high, low, closes := swingKlines(120)
+	detector := NewSRDetector(SRConfig{PivotLeft: 3, PivotRight: 3})
+	swings := detector.Calculate(high, low, closes)
+	if len(swings) == 0 || swings[0].Index != 5 || !swings[0].High || swings[0].ConfirmIndex != 8 {
+		t.Fatalf("Unexpected first swing %+v", swings)
+	}
+
+	// Sommet en 5 inconnu avant sa confirmation en 8
+	if zones := detector.ZonesAt(7); len(zones) != 0 {
+		t.Errorf("Swing must not be visible before confirmation, got %+v", zones)
+	}
+
+	zones := detector.ZonesAt(119)
+	if len(zones) != 2 {
+		t.Fatalf("Expected a support and a resistance zone, got %+v", zones)
+	}
+	support, resistance := zones[0], zones[1]
+	if !almostEqual(support.Price, 99.5) || support.Lows != support.Touches || support.Touches < 5 {
+		t.Errorf("Unexpected support zone %+v", support)
+	}
+	if !almostEqual(resistance.Price, 110.5) || resistance.Highs != resistance.Touches {
+		t.Errorf("Unexpected resistance zone %+v", resistance)
+	}
+
+	below, okBelow := zones.NearestBelow(105)
+	above, okAbove := zones.NearestAbove(105)
+	if !okBelow || !okAbove || below.Price != support.Price || above.Price != resistance.Price {
+		t.Errorf("Unexpected nearest zones %+v / %+v", below, above)
+	}
+	if _, ok := zones.NearestAbove(111); ok {
+		t.Errorf("No zone expected above 111")
+	}
+
+	sup, res := detector.NearestSeries(closes)
+	if !math.IsNaN(sup[10]) || math.IsNaN(sup[119]) || math.IsNaN(res[119]) {
+		t.Errorf("Unexpected nearest series sup[10]=%v sup[119]=%v res[119]=%v", sup[10], sup[119], res[119])
+	}
+
+	if level, ok := NearestLevel([]float64{90, math.NaN(), 104, 108}, 105, false); !ok || level != 104 {
+		t.Errorf("NearestLevel below: got %v, %v", level, ok)
+	}
+}
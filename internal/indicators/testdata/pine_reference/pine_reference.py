@@ -0,0 +1,359 @@
+#!/usr/bin/env python3
+"""Reference values for the cross-implementation harness (pine_reference_test.go).
+
+These are NOT TradingView exports: the klines are a seeded random walk and the
+expected values come from this independent Python transcription of the Pine v5
+built-ins. The harness checks the Go code against this second implementation
+of the same specification, not against the TradingView engine.
+
+Writes one CSV per indicator using the TradingView "Export chart data" layout:
+time (unix seconds, bar open), open, high, low, close, Volume, then one column
+per plot. Each ta.* built-in is transcribed from the Pine v5 reference
+manual, independently of the Go implementations, and run from bar 0 like
+a Pine script on a chart that starts at the first fixture bar.
+
+Recorded klines with real TradingView exports can be added under the same
+column titles; until then nothing here proves TradingView parity.
+
+Run from this directory:
+
+    python3 pine_reference.py
+"""
+
+import csv
+import math
+import random
+
+NA = float("nan")
+PERIOD_S = 300  # 5m
+START_S = 1709582400  # 2024-03-04 20:00:00 UTC (crosses two daily VWAP sessions)
+BARS = 600
+
+
+def na(x):
+    return x is None or (isinstance(x, float) and math.isnan(x))
+
+
+# --- Klines -----------------------------------------------------------------
+
+def make_klines():
+    """Deterministic SOLUSDT-like 5m random walk with volatility regimes."""
+    rng = random.Random(20240304)
+    price = 142.0
+    bars = []
+    for i in range(BARS):
+        vol = 0.0025 if (i // 120) % 2 == 0 else 0.006
+        drift = 0.0004 if (i // 200) % 3 == 0 else (-0.0003 if (i // 200) % 3 == 1 else 0.0)
+        o = price
+        c = o * (1 + drift + rng.gauss(0, vol))
+        h = max(o, c) * (1 + abs(rng.gauss(0, vol / 2)))
+        l = min(o, c) * (1 - abs(rng.gauss(0, vol / 2)))
+        v = round(rng.uniform(800, 6000) * (1 + 3 * abs(c - o) / o / vol / 10), 2)
+        o, h, l, c = (round(x, 3) for x in (o, h, l, c))
+        # Rounding must keep the candle coherent
+        h = max(h, o, c)
+        l = min(l, o, c)
+        bars.append({"time": START_S + i * PERIOD_S, "open": o, "high": h, "low": l, "close": c, "volume": v})
+        price = c
+    return bars
+
+
+# --- Pine built-ins ----------------------------------------------------------
+
+def change(src):
+    return [NA] + [src[i] - src[i - 1] for i in range(1, len(src))]
+
+
+def sma(src, length):
+    out = [NA] * len(src)
+    for i in range(length - 1, len(src)):
+        window = src[i - length + 1:i + 1]
+        if any(na(x) for x in window):
+            continue
+        out[i] = sum(window) / length
+    return out
+
+
+def wma(src, length):
+    out = [NA] * len(src)
+    norm = length * (length + 1) / 2
+    for i in range(length - 1, len(src)):
+        window = src[i - length + 1:i + 1]
+        if any(na(x) for x in window):
+            continue
+        out[i] = sum(x * (k + 1) for k, x in enumerate(window)) / norm
+    return out
+
+
+def _recursive(src, length, alpha):
+    """sum := na(sum[1]) ? ta.sma(src, length) : alpha * src + (1 - alpha) * nz(sum[1])"""
+    seed = sma(src, length)
+    out = [NA] * len(src)
+    for i in range(len(src)):
+        prev = out[i - 1] if i > 0 else NA
+        if na(prev):
+            out[i] = seed[i]
+        elif not na(src[i]):
+            out[i] = alpha * src[i] + (1 - alpha) * prev
+    return out
+
+
+def ema(src, length):
+    return _recursive(src, length, 2 / (length + 1))
+
+
+def rma(src, length):
+    return _recursive(src, length, 1 / length)
+
+
+def tr(high, low, close, handle_na=True):
+    out = []
+    for i in range(len(high)):
+        if i == 0:
+            out.append(high[i] - low[i] if handle_na else NA)
+        else:
+            out.append(max(high[i] - low[i], abs(high[i] - close[i - 1]), abs(low[i] - close[i - 1])))
+    return out
+
+
+def atr(high, low, close, length):
+    return rma(tr(high, low, close, True), length)
+
+
+def highest(src, length):
+    return [NA if i < length - 1 else max(src[i - length + 1:i + 1]) for i in range(len(src))]
+
+
+def lowest(src, length):
+    return [NA if i < length - 1 else min(src[i - length + 1:i + 1]) for i in range(len(src))]
+
+
+def msum(src, length):
+    out = [NA] * len(src)
+    for i in range(length - 1, len(src)):
+        window = src[i - length + 1:i + 1]
+        if not any(na(x) for x in window):
+            out[i] = sum(window)
+    return out
+
+
+def stdev(src, length):
+    """ta.stdev (biased / population)"""
+    mean = sma(src, length)
+    out = [NA] * len(src)
+    for i in range(length - 1, len(src)):
+        if na(mean[i]):
+            continue
+        out[i] = math.sqrt(sum((x - mean[i]) ** 2 for x in src[i - length + 1:i + 1]) / length)
+    return out
+
+
+def dev(src, length):
+    """ta.dev (mean absolute deviation)"""
+    mean = sma(src, length)
+    out = [NA] * len(src)
+    for i in range(length - 1, len(src)):
+        if na(mean[i]):
+            continue
+        out[i] = sum(abs(x - mean[i]) for x in src[i - length + 1:i + 1]) / length
+    return out
+
+
+# --- Indicators (TradingView built-in scripts) -----------------------------
+
+def dmi(high, low, close, di_len, adx_len):
+    up = change(high)
+    down = [NA if na(x) else -x for x in change(low)]
+    plus_dm = [NA if na(u) else (u if u > d and u > 0 else 0.0) for u, d in zip(up, down)]
+    minus_dm = [NA if na(d) else (d if d > u and d > 0 else 0.0) for u, d in zip(up, down)]
+    trur = rma(tr(high, low, close, False), di_len)
+    sp, sm = rma(plus_dm, di_len), rma(minus_dm, di_len)
+    plus = [NA if na(t) or t == 0 else 100 * p / t for p, t in zip(sp, trur)]
+    minus = [NA if na(t) or t == 0 else 100 * m / t for m, t in zip(sm, trur)]
+    dx = []
+    for p, m in zip(plus, minus):
+        if na(p) or na(m):
+            dx.append(NA)
+        else:
+            s = p + m
+            dx.append(abs(p - m) / (1 if s == 0 else s))
+    adx = [NA if na(x) else 100 * x for x in rma(dx, adx_len)]
+    return plus, minus, adx
+
+
+def mfi(high, low, close, volume, length):
+    src = [(h + l + c) / 3 for h, l, c in zip(high, low, close)]
+    ch = change(src)
+    # na(change) <= 0 is false in Pine: the first bar counts as an up bar
+    upper = msum([v * (0 if (not na(d) and d <= 0) else s) for s, d, v in zip(src, ch, volume)], length)
+    lower = msum([v * (0 if (na(d) or d >= 0) else s) for s, d, v in zip(src, ch, volume)], length)
+    out = []
+    for u, lo in zip(upper, lower):
+        if na(u) or na(lo):
+            out.append(NA)
+        elif lo == 0:
+            out.append(100.0)
+        else:
+            out.append(100 - 100 / (1 + u / lo))
+    return out
+
+
+def cci(high, low, close, length):
+    src = [(h + l + c) / 3 for h, l, c in zip(high, low, close)]
+    ma, md = sma(src, length), dev(src, length)
+    return [NA if na(m) or na(d) else (0.0 if d == 0 else (s - m) / (0.015 * d)) for s, m, d in zip(src, ma, md)]
+
+
+def chop(high, low, close, length):
+    s = msum(atr(high, low, close, 1), length)
+    hh, ll = highest(high, length), lowest(low, length)
+    return [NA if na(a) else 100 * math.log10(a / (h - l)) / math.log10(length) for a, h, l in zip(s, hh, ll)]
+
+
+def stoch(high, low, close, k_len, smooth, d_len):
+    hh, ll = highest(high, k_len), lowest(low, k_len)
+    raw = [NA if na(h) else (50.0 if h == l else 100 * (c - l) / (h - l)) for c, h, l in zip(close, hh, ll)]
+    k = sma(raw, smooth)
+    return k, sma(k, d_len)
+
+
+def macd(src, fast, slow, signal):
+    f, s = ema(src, fast), ema(src, slow)
+    line = [NA if na(a) or na(b) else a - b for a, b in zip(f, s)]
+    sig = ema(line, signal)
+    return line, sig, [NA if na(a) or na(b) else a - b for a, b in zip(line, sig)]
+
+
+def hma(src, length):
+    half, full = wma(src, length // 2), wma(src, length)
+    diff = [NA if na(a) or na(b) else 2 * a - b for a, b in zip(half, full)]
+    return wma(diff, int(math.floor(math.sqrt(length))))
+
+
+def vwma(src, volume, length):
+    num = sma([s * v for s, v in zip(src, volume)], length)
+    den = sma(volume, length)
+    return [NA if na(a) or na(b) or b == 0 else a / b for a, b in zip(num, den)]
+
+
+def bollinger(src, length, mult):
+    basis, sd = sma(src, length), stdev(src, length)
+    upper = [NA if na(b) else b + mult * d for b, d in zip(basis, sd)]
+    lower = [NA if na(b) else b - mult * d for b, d in zip(basis, sd)]
+    return basis, upper, lower
+
+
+def keltner(high, low, close, length, mult, atr_len):
+    ma = ema(close, length)
+    rng = atr(high, low, close, atr_len)
+    upper = [NA if na(m) or na(r) else m + mult * r for m, r in zip(ma, rng)]
+    lower = [NA if na(m) or na(r) else m - mult * r for m, r in zip(ma, rng)]
+    return ma, upper, lower
+
+
+def donchian(high, low, length):
+    up, lo = highest(high, length), lowest(low, length)
+    return up, lo, [NA if na(u) else (u + l) / 2 for u, l in zip(up, lo)]
+
+
+def supertrend(high, low, close, factor, atr_len):
+    a = atr(high, low, close, atr_len)
+    st, direction = [NA] * len(close), [NA] * len(close)
+    prev_lower = prev_upper = NA
+    for i in range(len(close)):
+        src = (high[i] + low[i]) / 2
+        upper = src + factor * a[i] if not na(a[i]) else NA
+        lower = src - factor * a[i] if not na(a[i]) else NA
+        prev_close = close[i - 1] if i > 0 else NA
+        pl = 0.0 if na(prev_lower) else prev_lower
+        pu = 0.0 if na(prev_upper) else prev_upper
+        if not na(lower):
+            lower = lower if (lower > pl or (not na(prev_close) and prev_close < pl)) else pl
+        if not na(upper):
+            upper = upper if (upper < pu or (not na(prev_close) and prev_close > pu)) else pu
+        prev_st = st[i - 1] if i > 0 else NA
+        if i == 0 or na(a[i - 1]):
+            d = 1.0
+        elif prev_st == prev_upper:
+            d = -1.0 if close[i] > upper else 1.0
+        else:
+            d = 1.0 if close[i] < lower else -1.0
+        if not na(a[i]):
+            st[i] = lower if d == -1 else upper
+            direction[i] = d
+        prev_lower, prev_upper = lower, upper
+    return st, direction
+
+
+def vwap(times, high, low, close, volume, mult):
+    out, upper, lower = [], [], []
+    session = None
+    s_sv = s_v = s_ssv = 0.0
+    for t, h, l, c, v in zip(times, high, low, close, volume):
+        day = t // 86400
+        if day != session:
+            session = day
+            s_sv = s_v = s_ssv = 0.0
+        src = (h + l + c) / 3
+        s_sv += src * v
+        s_v += v
+        s_ssv += v * src * src
+        value = s_sv / s_v
+        sd = math.sqrt(max(s_ssv / s_v - value * value, 0.0))
+        out.append(value)
+        upper.append(value + mult * sd)
+        lower.append(value - mult * sd)
+    return out, upper, lower
+
+
+# --- Export ------------------------------------------------------------------
+
+def write(name, bars, plots):
+    with open(name, "w", newline="") as f:
+        w = csv.writer(f, lineterminator="\n")
+        w.writerow(["time", "open", "high", "low", "close", "Volume"] + [title for title, _ in plots])
+        for i, b in enumerate(bars):
+            row = [b["time"], b["open"], b["high"], b["low"], b["close"], b["volume"]]
+            for _, series in plots:
+                v = series[i]
+                row.append("NaN" if na(v) else repr(float(v)))
+            w.writerow(row)
+
+
+def main():
+    bars = make_klines()
+    t = [b["time"] for b in bars]
+    h = [b["high"] for b in bars]
+    lo = [b["low"] for b in bars]
+    c = [b["close"] for b in bars]
+    v = [b["volume"] for b in bars]
+
+    write("sma_20.csv", bars, [("MA", sma(c, 20))])
+    write("ema_20.csv", bars, [("EMA", ema(c, 20))])
+    write("rma_14.csv", bars, [("RMA", rma(c, 14))])
+    write("hma_20.csv", bars, [("HMA", hma(c, 20))])
+    write("vwma_20.csv", bars, [("VWMA", vwma(c, v, 20))])
+    write("atr_14.csv", bars, [("ATR", atr(h, lo, c, 14))])
+    plus, minus, adx = dmi(h, lo, c, 14, 14)
+    write("dmi_14_14.csv", bars, [("+DI", plus), ("-DI", minus), ("ADX", adx)])
+    write("mfi_14.csv", bars, [("MF", mfi(h, lo, c, v, 14))])
+    write("cci_20.csv", bars, [("CCI", cci(h, lo, c, 20))])
+    write("chop_14.csv", bars, [("CHOP", chop(h, lo, c, 14))])
+    k, d = stoch(h, lo, c, 14, 3, 3)
+    write("stoch_14_3_3.csv", bars, [("%K", k), ("%D", d)])
+    line, sig, hist = macd(c, 12, 26, 9)
+    write("macd_12_26_9.csv", bars, [("MACD", line), ("Signal", sig), ("Histogram", hist)])
+    basis, upper, lower = bollinger(c, 20, 2)
+    write("bb_20_2.csv", bars, [("Basis", basis), ("Upper", upper), ("Lower", lower)])
+    basis, upper, lower = keltner(h, lo, c, 20, 2, 10)
+    write("kc_20_2_10.csv", bars, [("Basis", basis), ("Upper", upper), ("Lower", lower)])
+    upper, lower, basis = donchian(h, lo, 20)
+    write("donchian_20.csv", bars, [("Upper", upper), ("Lower", lower), ("Basis", basis)])
+    st, direction = supertrend(h, lo, c, 3, 10)
+    write("supertrend_10_3.csv", bars, [("Supertrend", st), ("Direction", direction)])
+    value, upper, lower = vwap(t, h, lo, c, v, 1)
+    write("vwap_session_1.csv", bars, [("VWAP", value), ("Upper Band #1", upper), ("Lower Band #1", lower)])
+
+
+if __name__ == "__main__":
+    main()
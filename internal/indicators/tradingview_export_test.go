@@ -0,0 +1,69 @@
+package indicators
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Parité TradingView sur exports réels
+//
+// testdata/tradingview reçoit des fichiers "Export chart data" de TradingView (bougies
+// réelles + plots de l'indicateur built-in), nommés comme la fixture Pine de référence du
+// même cas (ex: ema_20.csv). Contrairement à testdata/pine_reference, les valeurs
+// attendues sortent du moteur TradingView : c'est la seule preuve de parité. Le pas des
+// bougies n'est pas imposé (règle 4️⃣ propre aux fixtures 5m générées).
+
+const tradingViewDir = "testdata/tradingview"
+
+// tradingViewFamilies familles d'indicateurs à couvrir chacune par au moins un export réel
+var tradingViewFamilies = map[string][]string{
+	"moyennes mobiles": {"sma", "ema", "hma", "vwma"},
+	"lissages RMA":     {"rma", "atr", "dmi", "supertrend"},
+	"bandes":           {"bollinger", "keltner", "donchian"},
+	"VWAP":             {"vwap"},
+}
+
+// tradingViewRules règles de précision applicables à un export réel (sans la règle 4️⃣)
+func tradingViewRules() []precisionRule {
+	var rules []precisionRule
+	for _, rule := range precisionRules {
+		if rule.id != "4-timestamps" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// TestTradingViewExport compare chaque implémentation aux exports TradingView présents
+func TestTradingViewExport(t *testing.T) {
+	found := make(map[string]bool)
+	for _, tc := range parityCases {
+		tc := tc
+		path := filepath.Join(tradingViewDir, tc.fixture)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		found[tc.source] = true
+		t.Run(tc.source, func(t *testing.T) {
+			checkParityCase(t, tc, path, tradingViewRules())
+		})
+	}
+
+	var missing []string
+	for family, sources := range tradingViewFamilies {
+		covered := false
+		for _, source := range sources {
+			covered = covered || found[source]
+		}
+		if !covered {
+			missing = append(missing, family)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		t.Skipf("no real TradingView export in %s for: %s", tradingViewDir, strings.Join(missing, ", "))
+	}
+}
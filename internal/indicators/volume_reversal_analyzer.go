@@ -58,15 +58,7 @@ func (vra *VolumeReversalAnalyzer) AnalyzeVolumeReversal(
 	currentKline := klines[currentIndex]
 	
 	// Déterminer la couleur de la bougie actuelle
-	var currentColor string
-	var isGreen bool
-	if currentKline.Close > currentKline.Open {
-		currentColor = "verte"
-		isGreen = true
-	} else if currentKline.Close < currentKline.Open {
-		currentColor = "rouge"
-		isGreen = false
-	} else {
+	if currentKline.Close == currentKline.Open {
 		// Bougie doji (close = open)
 		return AnalyzeVolumeReversalResult{
 			IsReversalWithHighVolume: false,
@@ -76,12 +68,81 @@ func (vra *VolumeReversalAnalyzer) AnalyzeVolumeReversal(
 		}
 	}
 	
+	isGreenAt := func(i int) (bool, bool) {
+		return klines[i].Close > klines[i].Open, klines[i].Close != klines[i].Open
+	}
+	volumeAt := func(i int) float64 {
+		return klines[i].Volume
+	}
+	return vra.searchReversal(currentIndex, lookback, volumeThreshold, isGreenAt, volumeAt)
+}
+
+// AnalyzeFlowReversal variante de AnalyzeVolumeReversal sur le flux agresseur réel :
+// le sens de chaque bougie est celui de son delta ("verte" = acheteurs dominants) et le
+// volume comparé est le volume agresseur du côté dominant (BuyVolume ou SellVolume).
+// Une bougie verte absorbée par les vendeurs n'est donc plus comptée comme haussière.
+func (vra *VolumeReversalAnalyzer) AnalyzeFlowReversal(
+	flow *OrderFlow,
+	currentIndex int,
+	lookback int,
+	volumeThreshold float64,
+) AnalyzeVolumeReversalResult {
+	
+	if lookback <= 0 {
+		lookback = vra.defaultLookback
+	}
+	if volumeThreshold <= 0 {
+		volumeThreshold = vra.defaultVolumeThreshold
+	}
+	if flow == nil || currentIndex < 0 || currentIndex >= len(flow.Delta) {
+		return AnalyzeVolumeReversalResult{
+			IsReversalWithHighVolume: false,
+			Message:                  "Indice invalide",
+		}
+	}
+	
+	if flow.Delta[currentIndex] == 0 {
+		return AnalyzeVolumeReversalResult{
+			IsReversalWithHighVolume: false,
+			CurrentCandleColor:       "doji",
+			Message:                  "Delta nul (flux équilibré)",
+		}
+	}
+	
+	isGreenAt := func(i int) (bool, bool) {
+		return flow.Delta[i] > 0, flow.Delta[i] != 0
+	}
+	volumeAt := func(i int) float64 {
+		if flow.Delta[i] > 0 {
+			return flow.BuyVolume[i]
+		}
+		return flow.SellVolume[i]
+	}
+	return vra.searchReversal(currentIndex, lookback, volumeThreshold, isGreenAt, volumeAt)
+}
+
+// searchReversal cherche les bougies inverses avec expansion progressive du lookback
+// isGreenAt retourne (verte, directionnelle) ; volumeAt le volume comparé.
+func (vra *VolumeReversalAnalyzer) searchReversal(
+	currentIndex int,
+	lookback int,
+	volumeThreshold float64,
+	isGreenAt func(int) (bool, bool),
+	volumeAt func(int) float64,
+) AnalyzeVolumeReversalResult {
+	
+	isGreen, _ := isGreenAt(currentIndex)
+	currentColor := "verte"
+	if !isGreen {
+		currentColor = "rouge"
+	}
+	
 	// Chercher les bougies inverses avec expansion progressive
 	searchLookback := lookback
 	maxSearchLookback := lookback * 8 // Limite raisonnable
 	
 	for searchLookback <= maxSearchLookback {
-		result := vra.analyzeWithLookback(klines, currentIndex, searchLookback, volumeThreshold, isGreen)
+		result := vra.analyzeWithLookback(currentIndex, searchLookback, volumeThreshold, isGreen, isGreenAt, volumeAt)
 		if result.AverageInverseVolume > 0 {
 			// On a trouvé des bougies inverses
 			result.LookbackUsed = searchLookback
@@ -95,7 +156,7 @@ func (vra *VolumeReversalAnalyzer) AnalyzeVolumeReversal(
 	return AnalyzeVolumeReversalResult{
 		IsReversalWithHighVolume: false,
 		CurrentCandleColor:       currentColor,
-		CurrentVolume:            currentKline.Volume,
+		CurrentVolume:            volumeAt(currentIndex),
 		LookbackUsed:             maxSearchLookback,
 		Message:                  fmt.Sprintf("Aucune bougie %s trouvée sur les %d dernières bougies", 
 			vra.getInverseColor(currentColor), maxSearchLookback),
@@ -104,14 +165,15 @@ func (vra *VolumeReversalAnalyzer) AnalyzeVolumeReversal(
 
 // analyzeWithLookback analyse avec un lookback spécifique
 func (vra *VolumeReversalAnalyzer) analyzeWithLookback(
-	klines []Kline,
 	currentIndex int,
 	lookback int,
 	volumeThreshold float64,
 	isGreen bool,
+	isGreenAt func(int) (bool, bool),
+	volumeAt func(int) float64,
 ) AnalyzeVolumeReversalResult {
 	
-	currentKline := klines[currentIndex]
+	currentVolume := volumeAt(currentIndex)
 	currentColor := "verte"
 	if !isGreen {
 		currentColor = "rouge"
@@ -125,18 +187,12 @@ func (vra *VolumeReversalAnalyzer) analyzeWithLookback(
 	}
 	
 	for i := startIndex; i < currentIndex; i++ {
-		kline := klines[i]
-		
 		// Vérifier si c'est une bougie inverse
-		isInverse := false
-		if isGreen && kline.Close < kline.Open {
-			isInverse = true // Bougie rouge inverse
-		} else if !isGreen && kline.Close > kline.Open {
-			isInverse = true // Bougie verte inverse
-		}
+		green, directional := isGreenAt(i)
+		isInverse := directional && green != isGreen
 		
-		if isInverse && !math.IsNaN(kline.Volume) && kline.Volume > 0 {
-			inverseVolumes = append(inverseVolumes, kline.Volume)
+		if volume := volumeAt(i); isInverse && !math.IsNaN(volume) && volume > 0 {
+			inverseVolumes = append(inverseVolumes, volume)
 		}
 	}
 	
@@ -145,7 +201,7 @@ func (vra *VolumeReversalAnalyzer) analyzeWithLookback(
 		return AnalyzeVolumeReversalResult{
 			IsReversalWithHighVolume: false,
 			CurrentCandleColor:       currentColor,
-			CurrentVolume:            currentKline.Volume,
+			CurrentVolume:            currentVolume,
 			AverageInverseVolume:     0,
 		}
 	}
@@ -158,7 +214,7 @@ func (vra *VolumeReversalAnalyzer) analyzeWithLookback(
 	averageInverseVolume := sumInverseVolume / float64(len(inverseVolumes))
 	
 	// Calculer le ratio
-	volumeRatio := currentKline.Volume / averageInverseVolume
+	volumeRatio := currentVolume / averageInverseVolume
 	
 	// Vérifier la condition
 	isReversalWithHighVolume := volumeRatio >= volumeThreshold
@@ -166,7 +222,7 @@ func (vra *VolumeReversalAnalyzer) analyzeWithLookback(
 	// Créer le message
 	message := fmt.Sprintf("Bougie %s - Volume: %.2f, Moyenne %s (n=%d): %.2f, Ratio: %.2fx",
 		currentColor,
-		currentKline.Volume,
+		currentVolume,
 		vra.getInverseColor(currentColor),
 		len(inverseVolumes),
 		averageInverseVolume,
@@ -182,7 +238,7 @@ func (vra *VolumeReversalAnalyzer) analyzeWithLookback(
 	return AnalyzeVolumeReversalResult{
 		IsReversalWithHighVolume: isReversalWithHighVolume,
 		CurrentCandleColor:       currentColor,
-		CurrentVolume:            currentKline.Volume,
+		CurrentVolume:            currentVolume,
 		AverageInverseVolume:     averageInverseVolume,
 		VolumeRatio:              volumeRatio,
 		Message:                  message,
@@ -0,0 +1,243 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// VWAPSession - Découpage des sessions VWAP
+// Les sessions suivent shared.TimeframeBucketStart décalé de Offset : Period 24h => reset
+// quotidien à 00:00 UTC + Offset (TV "Session"), Period 7*24h => lundi 00:00 UTC + Offset.
+type VWAPSession struct {
+	Period time.Duration // Durée d'une session (24h, 7*24h, ...)
+	Offset time.Duration // Décalage du début de session depuis 00:00 UTC
+}
+
+// DailyUTCSession session quotidienne réinitialisée à 00:00 UTC
+func DailyUTCSession() VWAPSession {
+	return VWAPSession{Period: 24 * time.Hour}
+}
+
+// Start retourne le début (ms) de la session contenant timestamp (ms)
+func (s VWAPSession) Start(timestamp int64) int64 {
+	period := s.Period.Milliseconds()
+	if period <= 0 {
+		period = (24 * time.Hour).Milliseconds()
+	}
+	offset := s.Offset.Milliseconds()
+	return shared.TimeframeBucketStart(timestamp-offset, period) + offset
+}
+
+// vwapSums cumuls TradingView (ta.vwap avec stdev)
+type vwapSums struct {
+	sumSrcVol    float64
+	sumVol       float64
+	sumSrcSrcVol float64
+}
+
+func (s *vwapSums) add(src, volume float64) {
+	if math.IsNaN(src) || math.IsNaN(volume) {
+		return
+	}
+	s.sumSrcVol += src * volume
+	s.sumVol += volume
+	s.sumSrcSrcVol += volume * src * src
+}
+
+// values retourne vwap et écart-type pondéré (variance négative arrondie à 0 comme TV)
+func (s *vwapSums) values() (float64, float64) {
+	if s.sumVol == 0 {
+		return math.NaN(), math.NaN()
+	}
+	vwap := s.sumSrcVol / s.sumVol
+	variance := s.sumSrcSrcVol/s.sumVol - vwap*vwap
+	if variance < 0 {
+		variance = 0
+	}
+	return vwap, math.Sqrt(variance)
+}
+
+// VWAPTVStandard - Implémentation VWAP de session TradingView Standard
+// Formules Pine (ta.vwap(hlc3, anchor, stdev_mult)):
+//
+//	vwap = cum(src * vol) / cum(vol) depuis le début de session
+//	stdev = sqrt(max(cum(vol * src²) / cum(vol) - vwap², 0))
+//	bandes = vwap ± mult * stdev
+type VWAPTVStandard struct {
+	session VWAPSession
+}
+
+// NewVWAPTVStandard crée une instance VWAP de session
+func NewVWAPTVStandard(session VWAPSession) *VWAPTVStandard {
+	return &VWAPTVStandard{
+		session: session,
+	}
+}
+
+// Calculate calcule VWAP et écart-type depuis les bougies (source hlc3)
+// timestamps = ouverture des bougies en ms ; la session est celle de l'ouverture.
+func (v *VWAPTVStandard) Calculate(timestamps []int64, high, low, close, volume []float64) (vwap, stdev []float64) {
+	n := len(timestamps)
+	if n != len(high) || n != len(low) || n != len(close) || n != len(volume) {
+		return nil, nil
+	}
+
+	vwap = make([]float64, n)
+	stdev = make([]float64, n)
+	var sums vwapSums
+	session := int64(math.MinInt64)
+
+	for i := 0; i < n; i++ {
+		if start := v.session.Start(timestamps[i]); start != session {
+			session = start
+			sums = vwapSums{}
+		}
+		sums.add((high[i]+low[i]+close[i])/3, volume[i])
+		vwap[i], stdev[i] = sums.values()
+	}
+
+	return vwap, stdev
+}
+
+// CalculateFromKlines calcule le VWAP de session depuis des klines
+func (v *VWAPTVStandard) CalculateFromKlines(klines []Kline) (vwap, stdev []float64) {
+	high, low, closes, volume := klineColumns(klines)
+	return v.Calculate(klineTimestamps(klines), high, low, closes, volume)
+}
+
+// CalculateFromTrades calcule le VWAP exact depuis les trades (prix × quantité)
+// barTimestamps = ouverture des bougies en ms (croissant), trades triés par temps.
+// La valeur de la bougie i inclut tous les trades jusqu'à l'ouverture de la bougie i+1
+// (tous les trades restants pour la dernière bougie).
+func (v *VWAPTVStandard) CalculateFromTrades(barTimestamps []int64, trades []shared.TradeData) (vwap, stdev []float64) {
+	n := len(barTimestamps)
+	vwap = make([]float64, n)
+	stdev = make([]float64, n)
+	var sums vwapSums
+	session := int64(math.MinInt64)
+	t := 0
+
+	// Trades antérieurs à la première bougie ignorés
+	for t < len(trades) && n > 0 && trades[t].Time < barTimestamps[0] {
+		t++
+	}
+
+	for i := 0; i < n; i++ {
+		if start := v.session.Start(barTimestamps[i]); start != session {
+			session = start
+			sums = vwapSums{}
+		}
+		for t < len(trades) && (i == n-1 || trades[t].Time < barTimestamps[i+1]) {
+			sums.add(trades[t].Price, trades[t].Quantity)
+			t++
+		}
+		vwap[i], stdev[i] = sums.values()
+	}
+
+	return vwap, stdev
+}
+
+// AnchoredVWAPTVStandard - VWAP ancré (TV "Anchored VWAP")
+// Cumul depuis la bougie contenant l'ancre, jamais réinitialisé. NaN avant l'ancre.
+type AnchoredVWAPTVStandard struct {
+	anchor int64 // ms
+}
+
+// NewAnchoredVWAPTVStandard crée un VWAP ancré sur un timestamp (ms)
+func NewAnchoredVWAPTVStandard(anchor int64) *AnchoredVWAPTVStandard {
+	return &AnchoredVWAPTVStandard{
+		anchor: anchor,
+	}
+}
+
+// AnchorIndex retourne l'index de la bougie contenant l'ancre (dernière ouverture <= ancre), -1 si avant la série
+func (a *AnchoredVWAPTVStandard) AnchorIndex(timestamps []int64) int {
+	idx := -1
+	for i, ts := range timestamps {
+		if ts > a.anchor {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
+
+// Calculate calcule VWAP ancré et écart-type depuis les bougies (source hlc3)
+func (a *AnchoredVWAPTVStandard) Calculate(timestamps []int64, high, low, close, volume []float64) (vwap, stdev []float64) {
+	n := len(timestamps)
+	if n != len(high) || n != len(low) || n != len(close) || n != len(volume) {
+		return nil, nil
+	}
+
+	vwap = make([]float64, n)
+	stdev = make([]float64, n)
+	start := a.AnchorIndex(timestamps)
+	var sums vwapSums
+
+	for i := 0; i < n; i++ {
+		if start < 0 || i < start {
+			vwap[i], stdev[i] = math.NaN(), math.NaN()
+			continue
+		}
+		sums.add((high[i]+low[i]+close[i])/3, volume[i])
+		vwap[i], stdev[i] = sums.values()
+	}
+
+	return vwap, stdev
+}
+
+// CalculateFromKlines calcule le VWAP ancré depuis des klines
+func (a *AnchoredVWAPTVStandard) CalculateFromKlines(klines []Kline) (vwap, stdev []float64) {
+	high, low, closes, volume := klineColumns(klines)
+	return a.Calculate(klineTimestamps(klines), high, low, closes, volume)
+}
+
+// CalculateFromTrades calcule le VWAP ancré exact : seuls les trades >= ancre sont cumulés
+func (a *AnchoredVWAPTVStandard) CalculateFromTrades(barTimestamps []int64, trades []shared.TradeData) (vwap, stdev []float64) {
+	n := len(barTimestamps)
+	vwap = make([]float64, n)
+	stdev = make([]float64, n)
+	start := a.AnchorIndex(barTimestamps)
+	var sums vwapSums
+	t := 0
+
+	for t < len(trades) && trades[t].Time < a.anchor {
+		t++
+	}
+
+	for i := 0; i < n; i++ {
+		if start < 0 || i < start {
+			vwap[i], stdev[i] = math.NaN(), math.NaN()
+			continue
+		}
+		for t < len(trades) && (i == n-1 || trades[t].Time < barTimestamps[i+1]) {
+			sums.add(trades[t].Price, trades[t].Quantity)
+			t++
+		}
+		vwap[i], stdev[i] = sums.values()
+	}
+
+	return vwap, stdev
+}
+
+// VWAPBands calcule les bandes vwap ± mult * stdev
+func VWAPBands(vwap, stdev []float64, mult float64) (upper, lower []float64) {
+	upper = make([]float64, len(vwap))
+	lower = make([]float64, len(vwap))
+	for i := range vwap {
+		upper[i] = vwap[i] + mult*stdev[i]
+		lower[i] = vwap[i] - mult*stdev[i]
+	}
+	return upper, lower
+}
+
+// klineTimestamps extrait les timestamps d'ouverture
+func klineTimestamps(klines []Kline) []int64 {
+	out := make([]int64, len(klines))
+	for i, k := range klines {
+		out[i] = k.Timestamp
+	}
+	return out
+}
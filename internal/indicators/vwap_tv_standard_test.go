@@ -0,0 +1,81 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// vwapKlines 4 bougies 12h : deux sessions quotidiennes UTC
+func vwapKlines() []Kline {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC).UnixMilli()
+	step := (12 * time.Hour).Milliseconds()
+	return []Kline{
+		{Timestamp: day, High: 11, Low: 9, Close: 10, Volume: 1},           // hlc3 10
+		{Timestamp: day + step, High: 21, Low: 19, Close: 20, Volume: 3},   // hlc3 20
+		{Timestamp: day + 2*step, High: 31, Low: 29, Close: 30, Volume: 2}, // nouvelle session
+		{Timestamp: day + 3*step, High: 41, Low: 39, Close: 40, Volume: 2},
+	}
+}
+
+// TestVWAP_SessionReset vérifie cumul, écart-type pondéré et reset à 00:00 UTC
+func TestVWAP_SessionReset(t *testing.T) {
+	vwap, stdev := NewVWAPTVStandard(DailyUTCSession()).CalculateFromKlines(vwapKlines())
+
+	// Session 1 : (10*1 + 20*3) / 4 = 17.5 ; variance = (100 + 1200)/4 - 17.5² = 18.75
+	if !almostEqual(vwap[0], 10) || !almostEqual(vwap[1], 17.5) || !almostEqual(stdev[1], math.Sqrt(18.75)) {
+		t.Errorf("Unexpected session 1: vwap %v stdev %v", vwap[:2], stdev[:2])
+	}
+	// Session 2 repart de zéro
+	if !almostEqual(vwap[2], 30) || stdev[2] != 0 || !almostEqual(vwap[3], 35) || !almostEqual(stdev[3], 5) {
+		t.Errorf("Unexpected session 2: vwap %v stdev %v", vwap[2:], stdev[2:])
+	}
+
+	upper, lower := VWAPBands(vwap, stdev, 2)
+	if !almostEqual(upper[3], 45) || !almostEqual(lower[3], 25) {
+		t.Errorf("Unexpected bands %v / %v", upper[3], lower[3])
+	}
+
+	// Session décalée de 12h : les bougies 1 et 2 forment une session
+	shifted, _ := NewVWAPTVStandard(VWAPSession{Period: 24 * time.Hour, Offset: 12 * time.Hour}).CalculateFromKlines(vwapKlines())
+	if !almostEqual(shifted[1], 20) || !almostEqual(shifted[2], 24) {
+		t.Errorf("Unexpected shifted session %v", shifted)
+	}
+}
+
+// TestVWAP_Anchored vérifie l'ancrage (NaN avant, pas de reset de session)
+func TestVWAP_Anchored(t *testing.T) {
+	klines := vwapKlines()
+	// Ancre au milieu de la bougie 1 : la bougie contenant l'ancre est incluse
+	anchor := klines[1].Timestamp + 1000
+	vwap, _ := NewAnchoredVWAPTVStandard(anchor).CalculateFromKlines(klines)
+
+	if !math.IsNaN(vwap[0]) || !almostEqual(vwap[1], 20) || !almostEqual(vwap[2], 24) || !almostEqual(vwap[3], 200.0/7) {
+		t.Errorf("Unexpected anchored VWAP %v", vwap)
+	}
+}
+
+// TestVWAP_FromTrades vérifie le VWAP exact depuis les trades
+func TestVWAP_FromTrades(t *testing.T) {
+	klines := vwapKlines()
+	timestamps := klineTimestamps(klines)
+	trades := []shared.TradeData{
+		{Time: timestamps[0] - 1, Price: 1000, Quantity: 5}, // Avant la série : ignoré
+		{Time: timestamps[0], Price: 10, Quantity: 1},
+		{Time: timestamps[0] + 10, Price: 12, Quantity: 1},
+		{Time: timestamps[1] + 5, Price: 20, Quantity: 2},
+		{Time: timestamps[2], Price: 30, Quantity: 1},
+	}
+
+	vwap, _ := NewVWAPTVStandard(DailyUTCSession()).CalculateFromTrades(timestamps, trades)
+	if !almostEqual(vwap[0], 11) || !almostEqual(vwap[1], 62.0/4) || !almostEqual(vwap[2], 30) || !almostEqual(vwap[3], 30) {
+		t.Errorf("Unexpected trade VWAP %v", vwap)
+	}
+
+	anchored, _ := NewAnchoredVWAPTVStandard(timestamps[0]+5).CalculateFromTrades(timestamps, trades)
+	if !almostEqual(anchored[0], 12) || !almostEqual(anchored[1], 52.0/3) {
+		t.Errorf("Unexpected anchored trade VWAP %v", anchored)
+	}
+}
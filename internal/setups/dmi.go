@@ -0,0 +1,29 @@
+package setups
+
+import (
+	"agent-economique/internal/indicators"
+	"agent-economique/internal/signals"
+)
+
+// DMISeries holds the DMI values used by the DMI setups, indexed like the klines
+type DMISeries struct {
+	DIp, DIm, DX, ADX []float64
+}
+
+// ComputeDMI computes +DI, -DI, DX and ADX (TradingView standard) on klines
+func ComputeDMI(klines []signals.Kline, diPeriod, adxPeriod int) DMISeries {
+	high := make([]float64, len(klines))
+	low := make([]float64, len(klines))
+	closes := make([]float64, len(klines))
+	for i, k := range klines {
+		high[i], low[i], closes[i] = k.High, k.Low, k.Close
+	}
+	dmi := indicators.NewDMITVStandardWithPeriods(diPeriod, adxPeriod)
+	dip, dim, adx := dmi.Calculate(high, low, closes)
+	return DMISeries{DIp: dip, DIm: dim, DX: dmi.CalculateDX(dip, dim), ADX: adx}
+}
+
+// DMIWarmup returns the bars needed before the first valid ADX (plus the previous bar)
+func DMIWarmup(diPeriod, adxPeriod int) int {
+	return diPeriod + adxPeriod + 1
+}
@@ -2,6 +2,8 @@ package dmiopen
 
 import (
 	"math"
+
+	"agent-economique/internal/setups"
 )
 
 // Side represents orientation for the setup
@@ -94,112 +96,103 @@ type Event struct {
 	Diag      Diagnostics
 }
 
-type pending struct {
-	side       Side
-	crossIndex int
-	recheckAt  int
-	lag        int
-}
-
 // DmiOpen is the stateful detector
 type DmiOpen struct {
-	cfg      Config
-	pendings []pending
+	cfg     Config
+	current Inputs
+	machine *setups.Machine
 }
 
 func New(cfg Config) *DmiOpen {
-	return &DmiOpen{cfg: cfg, pendings: make([]pending, 0, 8)}
+	d := &DmiOpen{cfg: cfg}
+	d.machine = setups.NewMachine(rules{cfg: cfg, at: func(int) Inputs { return d.current }}, machineConfig(cfg))
+	return d
 }
 
-func (d *DmiOpen) Reset() { d.pendings = d.pendings[:0] }
+func (d *DmiOpen) Reset() { d.machine.Reset() }
+
+// Diagnostics returns the decisions of the underlying setup machine
+func (d *DmiOpen) Diagnostics() []setups.Decision { return d.machine.Diagnostics() }
 
 func (d *DmiOpen) Step(in Inputs) (Event, bool) {
-	var out Event
-	cfg := d.cfg
-
-	// 1) Process rechecks scheduled for this bar
-	if len(d.pendings) > 0 {
-		validatedCross := -1
-		cancelCrossIndex := -1
-		newPendings := d.pendings[:0]
-		for _, p := range d.pendings {
-			if p.recheckAt == in.Index {
-				// During recheck, optionally enforce context-side and DX>ADX
-				contextOK := true
-				if cfg.RecheckRequireContextSide {
-					if p.side == SideLong {
-						contextOK = in.DIp > in.DIm
-					} else {
-						contextOK = in.DIm > in.DIp
-					}
-				}
-				dxAboveOK := true
-				if cfg.RecheckRequireDXAboveADX {
-					dxAboveOK = in.DX > in.ADX
-				}
-				if (!contextOK || !dxAboveOK) && cfg.RecheckCancelSiblingsOnFlip {
-					// Cancel all future rechecks for this cross; consume current
-					cancelCrossIndex = p.crossIndex
-					continue
-				}
-				// Evaluate gating only (no new cross/pente checks)
-				diSup, diInf := diSupInf(p.side, in.DIp, in.DIm)
-				pass, diag := passesGating(cfg, p.side, in.DX, in.ADX, diSup, diInf, in.DIp, in.DIm)
-				if pass && !out.Triggered {
-					out = Event{Triggered: true, Side: p.side, Lag: p.lag, Index: in.Index, CrossAt: p.crossIndex, Diag: diag}
-					validatedCross = p.crossIndex
-					continue // consume this pending
-				}
-				// consume even if not validated; do not re-add
-				continue
-			}
-			// If we validated a cross this bar, purge its siblings
-			if (validatedCross != -1 && p.crossIndex == validatedCross) || (cancelCrossIndex != -1 && p.crossIndex == cancelCrossIndex) {
-				continue
-			}
-			newPendings = append(newPendings, p)
-		}
-		d.pendings = newPendings
+	d.current = in
+	evt, ok := d.machine.Step(in.Index)
+	if !ok {
+		return Event{}, false
 	}
+	diag, _ := evt.Check.Detail.(Diagnostics)
+	return Event{Triggered: true, Side: Side(evt.Side), Lag: evt.Lag, Index: evt.Index, CrossAt: evt.TriggerIndex, Diag: diag}, true
+}
 
-	// 2) Detect edge-trigger cross at this bar
-	if in.Index > 0 {
-		cross := in.DXPrev <= in.ADXPrev+cfg.Eps && in.DX > in.ADX
-		dxUp := !cfg.RequireDXUp || (in.DX > in.DXPrev)
-		adxUp := !cfg.RequireADXUp || (in.ADX > in.ADXPrev)
-		ctxLong := in.DIp > in.DIm
-		ctxShort := in.DIm > in.DIp
-
-		if cross && dxUp && adxUp && (ctxLong || ctxShort) {
-			var side Side
-			if ctxLong {
-				side = SideLong
-			} else {
-				side = SideShort
-			}
-			diSup, diInf := diSupInf(side, in.DIp, in.DIm)
-			pass, diag := passesGating(cfg, side, in.DX, in.ADX, diSup, diInf, in.DIp, in.DIm)
-			if pass && !out.Triggered {
-				out = Event{Triggered: true, Side: side, Lag: 0, Index: in.Index, CrossAt: in.Index, Diag: diag}
-			} else {
-				// schedule rechecks according to config
-				lags := []struct {
-					use bool
-					lag int
-				}{
-					{cfg.UseRecheck1, 1}, {cfg.UseRecheck2, 2}, {cfg.UseRecheck3, 3},
-					{cfg.UseRecheck4, 4}, {cfg.UseRecheck5, 5}, {cfg.UseRecheck6, 6},
-				}
-				for _, lg := range lags {
-					if lg.use {
-						d.pendings = append(d.pendings, pending{side: side, crossIndex: in.Index, recheckAt: in.Index + lg.lag, lag: lg.lag})
-					}
-				}
-			}
+// machineConfig maps the recheck flags to the machine plan
+func machineConfig(cfg Config) setups.Config {
+	var lags []int
+	for lag, use := range []bool{cfg.UseRecheck1, cfg.UseRecheck2, cfg.UseRecheck3, cfg.UseRecheck4, cfg.UseRecheck5, cfg.UseRecheck6} {
+		if use {
+			lags = append(lags, lag+1)
 		}
 	}
+	return setups.Config{RecheckLags: lags, CancelSiblingsOnFlip: cfg.RecheckCancelSiblingsOnFlip}
+}
+
+// rules implements setups.Rules; at returns the indicator values of bar i
+type rules struct {
+	cfg Config
+	at  func(i int) Inputs
+}
 
-	return out, out.Triggered
+// Trigger detects the DX/ADX edge cross; the DI context gives the side
+func (r rules) Trigger(i int) (setups.Side, bool) {
+	in := r.at(i)
+	if in.Index <= 0 {
+		return "", false
+	}
+	cross := in.DXPrev <= in.ADXPrev+r.cfg.Eps && in.DX > in.ADX
+	dxUp := !r.cfg.RequireDXUp || (in.DX > in.DXPrev)
+	adxUp := !r.cfg.RequireADXUp || (in.ADX > in.ADXPrev)
+	if !cross || !dxUp || !adxUp {
+		return "", false
+	}
+	switch {
+	case in.DIp > in.DIm:
+		return setups.SideLong, true
+	case in.DIm > in.DIp:
+		return setups.SideShort, true
+	}
+	return "", false
+}
+
+// Gate evaluates the gating rules (same rules at trigger and on rechecks)
+func (r rules) Gate(i int, side setups.Side, lag int) setups.Check {
+	in := r.at(i)
+	s := Side(side)
+	diSup, diInf := diSupInf(s, in.DIp, in.DIm)
+	pass, diag := passesGating(r.cfg, s, in.DX, in.ADX, diSup, diInf, in.DIp, in.DIm)
+	check := setups.Check{Pass: pass, Detail: diag, Values: map[string]float64{
+		"dip": in.DIp, "dim": in.DIm, "dx": in.DX, "adx": in.ADX, "gap_dx_adx": diag.GapDXADX,
+	}}
+	if !pass {
+		check.Reason = "gating"
+	}
+	return check
+}
+
+// Invariant enforces the recheck integrity requirements. A flip only breaks the
+// candidate when RecheckCancelSiblingsOnFlip is set, otherwise gating decides.
+func (r rules) Invariant(i int, side setups.Side) setups.Check {
+	if !r.cfg.RecheckCancelSiblingsOnFlip {
+		return setups.Check{Pass: true}
+	}
+	in := r.at(i)
+	if r.cfg.RecheckRequireContextSide {
+		if (side == setups.SideLong && !(in.DIp > in.DIm)) || (side == setups.SideShort && !(in.DIm > in.DIp)) {
+			return setups.Check{Reason: "context_flip", Values: map[string]float64{"dip": in.DIp, "dim": in.DIm}}
+		}
+	}
+	if r.cfg.RecheckRequireDXAboveADX && !(in.DX > in.ADX) {
+		return setups.Check{Reason: "dx_below_adx", Values: map[string]float64{"dx": in.DX, "adx": in.ADX}}
+	}
+	return setups.Check{Pass: true}
 }
 
 func diSupInf(side Side, DIp, DIm float64) (diSup, diInf float64) {
@@ -2,6 +2,8 @@ package dmiresp
 
 import (
 	"math"
+
+	"agent-economique/internal/setups"
 )
 
 type Side string
@@ -69,82 +71,93 @@ type Event struct {
 	Diag      Diagnostics
 }
 
-type pending struct {
-	side       Side
-	crossIndex int
-	recheckAt  int // only i+1 supported
-	lag        int // 1
-}
-
 type DmiResp struct {
-	cfg      Config
-	pendings []pending
+	cfg     Config
+	current Inputs
+	machine *setups.Machine
 }
 
 func New(cfg Config) *DmiResp {
-	return &DmiResp{cfg: cfg, pendings: make([]pending, 0, 4)}
+	d := &DmiResp{cfg: cfg}
+	d.machine = setups.NewMachine(rules{cfg: cfg, at: func(int) Inputs { return d.current }}, machineConfig(cfg))
+	return d
 }
 
-func (d *DmiResp) Reset() { d.pendings = d.pendings[:0] }
+func (d *DmiResp) Reset() { d.machine.Reset() }
+
+// Diagnostics returns the decisions of the underlying setup machine
+func (d *DmiResp) Diagnostics() []setups.Decision { return d.machine.Diagnostics() }
 
 func (d *DmiResp) Step(in Inputs) (Event, bool) {
-	var out Event
-	cfg := d.cfg
-
-	// 1) Confirmation pendings at this bar (only i+1 supported)
-	if len(d.pendings) > 0 {
-		validatedCross := -1
-		newPendings := d.pendings[:0]
-		for _, p := range d.pendings {
-			if p.recheckAt == in.Index {
-				// Confirmation rule: DX > DI_inf and DX - DI_inf <= RespGap, plus numeric gating
-				diSup, diInf := diSupInf(p.side, in.DIp, in.DIm)
-				_ = diSup // not used directly in confirm
-				if confirmOK(cfg, in.DX, in.ADX, diInf) {
-					if !out.Triggered {
-						out = Event{Triggered: true, Side: p.side, Lag: p.lag, Index: in.Index, CrossAt: p.crossIndex, Diag: diagFor(cfg, p.side, in)}
-						validatedCross = p.crossIndex
-					}
-					continue // consume
-				}
-				// consume even if not validated; do not re-add
-				continue
-			}
-			if validatedCross != -1 && p.crossIndex == validatedCross {
-				continue
-			}
-			newPendings = append(newPendings, p)
-		}
-		d.pendings = newPendings
+	d.current = in
+	evt, ok := d.machine.Step(in.Index)
+	if !ok {
+		return Event{}, false
+	}
+	diag, _ := evt.Check.Detail.(Diagnostics)
+	return Event{Triggered: true, Side: Side(evt.Side), Lag: evt.Lag, Index: evt.Index, CrossAt: evt.TriggerIndex, Diag: diag}, true
+}
+
+// machineConfig maps the confirmation flag to the machine plan (only i+1 supported)
+func machineConfig(cfg Config) setups.Config {
+	if cfg.UseConfirm1 {
+		return setups.Config{RecheckLags: []int{1}}
+	}
+	return setups.Config{}
+}
+
+// rules implements setups.Rules; at returns the indicator values of bar i
+type rules struct {
+	cfg Config
+	at  func(i int) Inputs
+}
+
+// Trigger detects the respiration cross (DX on ADX at i-1, below at i)
+func (r rules) Trigger(i int) (setups.Side, bool) {
+	in := r.at(i)
+	if in.Index <= 0 {
+		return "", false
+	}
+	dxDown := !r.cfg.RequireDXDown || (in.DX < in.DXPrev)
+	adxDown := !r.cfg.RequireADXDown || (in.ADX < in.ADXPrev)
+	prevOn := math.Abs(in.DXPrev-in.ADXPrev) <= r.cfg.Eps
+	currBelow := in.DX < in.ADX
+	if !dxDown || !adxDown || !prevOn || !currBelow {
+		return "", false
 	}
+	switch {
+	case in.DIp > in.DIm:
+		return setups.SideLong, true
+	case in.DIm > in.DIp:
+		return setups.SideShort, true
+	}
+	return "", false
+}
 
-	// 2) Detect respiration cross at this bar
-	if in.Index > 0 {
-		ctxLong := in.DIp > in.DIm
-		ctxShort := in.DIm > in.DIp
-		dxDown := !cfg.RequireDXDown || (in.DX < in.DXPrev)
-		adxDown := !cfg.RequireADXDown || (in.ADX < in.ADXPrev)
-		prevOn := math.Abs(in.DXPrev-in.ADXPrev) <= cfg.Eps
-		currBelow := in.DX < in.ADX
-
-		if (ctxLong || ctxShort) && dxDown && adxDown && prevOn && currBelow {
-			var side Side
-			if ctxLong { side = SideLong } else { side = SideShort }
-			_, diInf := diSupInf(side, in.DIp, in.DIm)
-
-			// Immediate validation cases A/B
-			caseA := in.DX <= diInf
-			caseB := in.DX > diInf && (in.DX-diInf) <= cfg.RespGap
-			if (caseA || caseB) && numericOK(cfg, in.DX, in.ADX) && !out.Triggered {
-				out = Event{Triggered: true, Side: side, Lag: 0, Index: in.Index, CrossAt: in.Index, Diag: diagFor(cfg, side, in)}
-			} else if cfg.UseConfirm1 {
-				// Schedule confirm at i+1
-				d.pendings = append(d.pendings, pending{side: side, crossIndex: in.Index, recheckAt: in.Index + 1, lag: 1})
-			}
-		}
+// Gate validates cases A/B at the cross, the confirmation rule at i+1
+func (r rules) Gate(i int, side setups.Side, lag int) setups.Check {
+	in := r.at(i)
+	_, diInf := diSupInf(Side(side), in.DIp, in.DIm)
+	var pass bool
+	if lag == 0 {
+		caseA := in.DX <= diInf
+		caseB := in.DX > diInf && (in.DX-diInf) <= r.cfg.RespGap
+		pass = (caseA || caseB) && numericOK(r.cfg, in.DX, in.ADX)
+	} else {
+		pass = confirmOK(r.cfg, in.DX, in.ADX, diInf)
 	}
+	check := setups.Check{Pass: pass, Detail: diagFor(r.cfg, Side(side), in), Values: map[string]float64{
+		"dip": in.DIp, "dim": in.DIm, "dx": in.DX, "adx": in.ADX, "gap_dx_diinf": in.DX - diInf,
+	}}
+	if !pass {
+		check.Reason = "respiration"
+	}
+	return check
+}
 
-	return out, out.Triggered
+// Invariant has no recheck requirement for the respiration setup
+func (r rules) Invariant(i int, side setups.Side) setups.Check {
+	return setups.Check{Pass: true}
 }
 
 func diSupInf(side Side, DIp, DIm float64) (diSup, diInf float64) {
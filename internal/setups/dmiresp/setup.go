@@ -0,0 +1,54 @@
+package dmiresp
+
+import (
+	"fmt"
+
+	"agent-economique/internal/setups"
+	"agent-economique/internal/signals"
+)
+
+// Setup runs DmiResp on klines through setups.Generator
+type Setup struct {
+	cfg       Config
+	diPeriod  int
+	adxPeriod int
+}
+
+// NewSetup creates the kline setup (DMI TradingView standard with distinct periods)
+func NewSetup(cfg Config, diPeriod, adxPeriod int) (*Setup, error) {
+	if diPeriod <= 0 || adxPeriod <= 0 {
+		return nil, fmt.Errorf("dmiresp: invalid DMI periods %d/%d", diPeriod, adxPeriod)
+	}
+	return &Setup{cfg: cfg, diPeriod: diPeriod, adxPeriod: adxPeriod}, nil
+}
+
+// NewGenerator wraps the setup into a signals.Generator
+func NewGenerator(cfg Config, diPeriod, adxPeriod int, gen setups.GeneratorConfig) (*setups.Generator, error) {
+	setup, err := NewSetup(cfg, diPeriod, adxPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return setups.NewGenerator(setup, gen), nil
+}
+
+func (s *Setup) Name() string { return "dmi_resp" }
+
+func (s *Setup) MachineConfig() setups.Config { return machineConfig(s.cfg) }
+
+func (s *Setup) MinHistorySize() int { return setups.DMIWarmup(s.diPeriod, s.adxPeriod) }
+
+// Prepare computes the DMI and returns rules indexed like klines
+func (s *Setup) Prepare(klines []signals.Kline) (setups.Rules, error) {
+	dmi := setups.ComputeDMI(klines, s.diPeriod, s.adxPeriod)
+	at := func(i int) Inputs {
+		if i <= 0 || i >= len(klines) {
+			return Inputs{Index: 0}
+		}
+		return Inputs{
+			Index:   i,
+			DIpPrev: dmi.DIp[i-1], DImPrev: dmi.DIm[i-1], DXPrev: dmi.DX[i-1], ADXPrev: dmi.ADX[i-1],
+			DIp: dmi.DIp[i], DIm: dmi.DIm[i], DX: dmi.DX[i], ADX: dmi.ADX[i],
+		}
+	}
+	return rules{cfg: s.cfg, at: at}, nil
+}
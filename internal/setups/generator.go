@@ -0,0 +1,270 @@
+package setups
+
+import (
+	"fmt"
+	"time"
+
+	"agent-economique/internal/signals"
+)
+
+// Setup is a setup that can be driven by the Generator adapter
+type Setup interface {
+	// Name of the setup (generator name)
+	Name() string
+	// Prepare computes the indicators on klines and returns rules indexed like klines
+	Prepare(klines []signals.Kline) (Rules, error)
+	// MachineConfig returns the recheck plan
+	MachineConfig() Config
+	// MinHistorySize returns the bars needed before the first trigger
+	MinHistorySize() int
+}
+
+// GeneratorConfig position handling of the adapter
+type GeneratorConfig struct {
+	ReverseOnOpposite bool // An opposite event closes the position and opens the other side
+	ExitOnOpposite    bool // An opposite event closes the position (implied by ReverseOnOpposite)
+	MaxHoldBars       int  // Closes the position after N bars (0 = never)
+}
+
+// Generator adapts a Setup to signals.Generator
+//
+// Each closed bar is stepped once through the machine (the last kline is the forming
+// bar and is skipped). Bars keep an absolute index across calls so that rechecks
+// survive a sliding kline window. A validated event opens a position when flat.
+type Generator struct {
+	setup  Setup
+	config GeneratorConfig
+
+	machine       *Machine
+	rules         Rules
+	offset        int // Absolute index of klines[0]
+	processed     int // Absolute index of the next bar to step
+	lastProcessed time.Time
+	barTime       time.Time         // OpenTime of the bar being stepped
+	triggerTimes  map[int]time.Time // OpenTime of triggers that may still be rechecked
+
+	position   signals.SignalType
+	entryPrice float64
+	entryTime  time.Time
+	entryBar   int
+	metrics    signals.GeneratorMetrics
+}
+
+// NewGenerator creates the adapter
+func NewGenerator(setup Setup, config GeneratorConfig) *Generator {
+	g := &Generator{setup: setup, config: config}
+	g.machine = NewMachine(indexedRules{g}, setup.MachineConfig())
+	return g
+}
+
+// Name returns the setup name
+func (g *Generator) Name() string {
+	return g.setup.Name()
+}
+
+// MinHistorySize returns the history required by the setup and its rechecks
+func (g *Generator) MinHistorySize() int {
+	maxLag := 0
+	for _, lag := range g.setup.MachineConfig().RecheckLags {
+		if lag > maxLag {
+			maxLag = lag
+		}
+	}
+	return g.setup.MinHistorySize() + maxLag + 1
+}
+
+// Machine returns the underlying state machine (diagnostics)
+func (g *Generator) Machine() *Machine {
+	return g.machine
+}
+
+// Initialize resets the state
+func (g *Generator) Initialize(config signals.GeneratorConfig) error {
+	g.machine.Reset()
+	g.rules = nil
+	g.offset, g.processed = 0, 0
+	g.lastProcessed = time.Time{}
+	g.triggerTimes = nil
+	g.position = ""
+	g.metrics = signals.GeneratorMetrics{}
+	return nil
+}
+
+// CalculateIndicators prepares the setup rules for the klines window
+func (g *Generator) CalculateIndicators(klines []signals.Kline) error {
+	if len(klines) == 0 {
+		return fmt.Errorf("aucune kline")
+	}
+	rules, err := g.setup.Prepare(klines)
+	if err != nil {
+		return err
+	}
+	g.rules = rules
+
+	// Realign the absolute index on the first kline not yet processed
+	if g.lastProcessed.IsZero() {
+		g.offset = g.processed
+		return nil
+	}
+	for j := len(klines) - 1; j >= 0; j-- {
+		if !klines[j].OpenTime.After(g.lastProcessed) {
+			g.offset = g.processed - 1 - j
+			return nil
+		}
+	}
+	g.offset = g.processed
+	return nil
+}
+
+// DetectSignals steps the closed bars not yet processed
+func (g *Generator) DetectSignals(klines []signals.Kline) ([]signals.Signal, error) {
+	var out []signals.Signal
+	lastClosed := len(klines) - 2
+	if lastClosed < 0 {
+		return out, nil
+	}
+	if g.rules == nil {
+		if err := g.CalculateIndicators(klines); err != nil {
+			return nil, err
+		}
+	}
+
+	for j := 0; j <= lastClosed; j++ {
+		if !klines[j].OpenTime.After(g.lastProcessed) {
+			continue
+		}
+		abs := g.offset + j
+		g.processed = abs + 1
+		g.lastProcessed = klines[j].OpenTime
+		out = append(out, g.processBar(klines, j, abs)...)
+	}
+
+	for _, sig := range out {
+		g.record(sig)
+	}
+	return out, nil
+}
+
+// processBar steps the machine and manages the position on bar j
+func (g *Generator) processBar(klines []signals.Kline, j, abs int) []signals.Signal {
+	var out []signals.Signal
+	if g.position != "" && g.config.MaxHoldBars > 0 && abs-g.entryBar >= g.config.MaxHoldBars {
+		out = append(out, g.exit(klines[j], "max_hold"))
+	}
+
+	// The trigger bar of a recheck may have left a sliding window: keep its time
+	// for as long as a recheck can still reference it
+	g.barTime = klines[j].OpenTime
+	maxLag := g.MinHistorySize() - g.setup.MinHistorySize() - 1
+	for index := range g.triggerTimes {
+		if index < abs-maxLag {
+			delete(g.triggerTimes, index)
+		}
+	}
+
+	event, ok := g.machine.Step(abs)
+	if !ok {
+		return out
+	}
+	side := signals.SignalTypeLong
+	if event.Side == SideShort {
+		side = signals.SignalTypeShort
+	}
+	switch g.position {
+	case side:
+		return out
+	case "":
+	default:
+		if !g.config.ReverseOnOpposite && !g.config.ExitOnOpposite {
+			return out
+		}
+		out = append(out, g.exit(klines[j], "opposite"))
+		if !g.config.ReverseOnOpposite {
+			return out
+		}
+	}
+
+	k := klines[j]
+	g.position, g.entryPrice, g.entryTime, g.entryBar = side, k.Close, k.OpenTime, abs
+	metadata := map[string]interface{}{
+		"generator":    g.setup.Name(),
+		"lag":          event.Lag,
+		"trigger_time": g.triggerTimes[event.TriggerIndex],
+	}
+	for name, value := range event.Check.Values {
+		metadata[name] = value
+	}
+	out = append(out, signals.Signal{
+		Timestamp:  k.OpenTime,
+		Action:     signals.SignalActionEntry,
+		Type:       side,
+		Price:      k.Close,
+		Confidence: 1,
+		Metadata:   metadata,
+	})
+	return out
+}
+
+// exit closes the current position on kline k
+func (g *Generator) exit(k signals.Kline, reason string) signals.Signal {
+	entryPrice, entryTime := g.entryPrice, g.entryTime
+	sig := signals.Signal{
+		Timestamp:  k.OpenTime,
+		Action:     signals.SignalActionExit,
+		Type:       g.position,
+		Price:      k.Close,
+		Confidence: 1,
+		Metadata:   map[string]interface{}{"generator": g.setup.Name(), "exit_reason": reason},
+		EntryPrice: &entryPrice,
+		EntryTime:  &entryTime,
+	}
+	g.position = ""
+	return sig
+}
+
+// record updates the metrics
+func (g *Generator) record(sig signals.Signal) {
+	m := &g.metrics
+	m.TotalSignals++
+	if sig.Action == signals.SignalActionEntry {
+		m.EntrySignals++
+		if sig.Type == signals.SignalTypeLong {
+			m.LongSignals++
+		} else {
+			m.ShortSignals++
+		}
+	} else {
+		m.ExitSignals++
+	}
+	m.AvgConfidence += (sig.Confidence - m.AvgConfidence) / float64(m.TotalSignals)
+	m.LastSignalTime = sig.Timestamp
+}
+
+// GetMetrics returns the generator metrics
+func (g *Generator) GetMetrics() signals.GeneratorMetrics {
+	return g.metrics
+}
+
+// indexedRules translates absolute machine indices to the current klines window
+type indexedRules struct {
+	g *Generator
+}
+
+func (r indexedRules) Trigger(i int) (Side, bool) {
+	side, ok := r.g.rules.Trigger(i - r.g.offset)
+	if ok {
+		if r.g.triggerTimes == nil {
+			r.g.triggerTimes = make(map[int]time.Time)
+		}
+		r.g.triggerTimes[i] = r.g.barTime
+	}
+	return side, ok
+}
+
+func (r indexedRules) Gate(i int, side Side, lag int) Check {
+	return r.g.rules.Gate(i-r.g.offset, side, lag)
+}
+
+func (r indexedRules) Invariant(i int, side Side) Check {
+	return r.g.rules.Invariant(i-r.g.offset, side)
+}
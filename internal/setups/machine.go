@@ -0,0 +1,201 @@
+// Package setups provides a reusable state machine for trigger / recheck setups.
+//
+// A setup is described by Rules: a trigger event on bar i opens a candidate for a
+// side; the candidate is validated immediately when its gate passes, otherwise it
+// is rechecked on bars i+lag for each configured lag. A recheck first evaluates the
+// invariants (a failed invariant drops the candidate, and all its sibling rechecks
+// when CancelSiblingsOnFlip is set), then the gate. A validated candidate purges its
+// siblings. At most one event is emitted per bar. Every decision is recorded.
+package setups
+
+// Side represents orientation for the setup
+type Side string
+
+const (
+	SideLong  Side = "LONG"
+	SideShort Side = "SHORT"
+)
+
+// Check is the outcome of a gate or invariant evaluation
+type Check struct {
+	Pass   bool
+	Reason string             // Short explanation when the check fails
+	Values map[string]float64 // Values used by the check (for logs)
+	Detail interface{}        // Setup-specific diagnostics
+}
+
+// Rules defines a setup. Bar indices are the ones passed to Machine.Step.
+type Rules interface {
+	// Trigger detects the event that opens a candidate on bar i
+	Trigger(i int) (Side, bool)
+	// Gate evaluates the validation rules of a candidate on bar i (lag 0 = trigger bar)
+	Gate(i int, side Side, lag int) Check
+	// Invariant checks that a pending candidate still holds on its recheck bar i
+	Invariant(i int, side Side) Check
+}
+
+// Config controls the recheck plan
+type Config struct {
+	RecheckLags          []int // Bars after the trigger at which failed candidates are rechecked
+	CancelSiblingsOnFlip bool  // A failed invariant also cancels the remaining rechecks of the trigger
+	MaxDiagnostics       int   // Decisions kept in memory (0 = 1000, < 0 = none)
+}
+
+// DecisionKind labels a recorded decision
+type DecisionKind string
+
+const (
+	DecisionTrigger    DecisionKind = "TRIGGER"    // Candidate opened
+	DecisionValidated  DecisionKind = "VALIDATED"  // Gate passed, event emitted
+	DecisionSuppressed DecisionKind = "SUPPRESSED" // Gate passed but an event was already emitted on the bar
+	DecisionRejected   DecisionKind = "REJECTED"   // Gate failed
+	DecisionScheduled  DecisionKind = "SCHEDULED"  // Recheck planned
+	DecisionBroken     DecisionKind = "BROKEN"     // Invariant failed on recheck
+	DecisionCancelled  DecisionKind = "CANCELLED"  // Sibling recheck purged
+)
+
+// Decision is one structured diagnostic entry
+type Decision struct {
+	Kind         DecisionKind
+	Index        int // Bar of the decision
+	TriggerIndex int
+	Side         Side
+	Lag          int
+	Check        Check
+}
+
+// Event is emitted when a candidate is validated
+type Event struct {
+	Side         Side
+	Lag          int // 0 for immediate, > 0 for rechecks
+	Index        int // Validation bar
+	TriggerIndex int
+	Check        Check
+}
+
+type pending struct {
+	side         Side
+	triggerIndex int
+	recheckAt    int
+	lag          int
+}
+
+// Machine is the stateful setup runner
+type Machine struct {
+	rules       Rules
+	config      Config
+	pendings    []pending
+	diagnostics []Decision
+}
+
+// NewMachine creates a machine for the given rules
+func NewMachine(rules Rules, config Config) *Machine {
+	if config.MaxDiagnostics == 0 {
+		config.MaxDiagnostics = 1000
+	}
+	return &Machine{rules: rules, config: config, pendings: make([]pending, 0, 8)}
+}
+
+// Reset drops pending candidates and diagnostics
+func (m *Machine) Reset() {
+	m.pendings = m.pendings[:0]
+	m.diagnostics = nil
+}
+
+// Pending returns the number of scheduled rechecks
+func (m *Machine) Pending() int {
+	return len(m.pendings)
+}
+
+// Diagnostics returns the decisions recorded since the last Reset (oldest first)
+func (m *Machine) Diagnostics() []Decision {
+	return m.diagnostics
+}
+
+// Step processes bar i: rechecks due on i first, then a new trigger on i
+func (m *Machine) Step(i int) (Event, bool) {
+	var out Event
+	triggered := false
+
+	// 1) Rechecks scheduled for this bar
+	if len(m.pendings) > 0 {
+		purged := make(map[int]bool)
+		kept := m.pendings[:0]
+		for _, p := range m.pendings {
+			if purged[p.triggerIndex] {
+				m.record(Decision{Kind: DecisionCancelled, Index: i, TriggerIndex: p.triggerIndex, Side: p.side, Lag: p.lag})
+				continue
+			}
+			if p.recheckAt != i {
+				kept = append(kept, p)
+				continue
+			}
+			if inv := m.rules.Invariant(i, p.side); !inv.Pass {
+				m.record(Decision{Kind: DecisionBroken, Index: i, TriggerIndex: p.triggerIndex, Side: p.side, Lag: p.lag, Check: inv})
+				if m.config.CancelSiblingsOnFlip {
+					purged[p.triggerIndex] = true
+				}
+				continue
+			}
+			check := m.rules.Gate(i, p.side, p.lag)
+			switch {
+			case check.Pass && !triggered:
+				out = Event{Side: p.side, Lag: p.lag, Index: i, TriggerIndex: p.triggerIndex, Check: check}
+				triggered = true
+				purged[p.triggerIndex] = true
+				m.record(Decision{Kind: DecisionValidated, Index: i, TriggerIndex: p.triggerIndex, Side: p.side, Lag: p.lag, Check: check})
+			case check.Pass:
+				m.record(Decision{Kind: DecisionSuppressed, Index: i, TriggerIndex: p.triggerIndex, Side: p.side, Lag: p.lag, Check: check})
+			default:
+				m.record(Decision{Kind: DecisionRejected, Index: i, TriggerIndex: p.triggerIndex, Side: p.side, Lag: p.lag, Check: check})
+			}
+		}
+		// Siblings listed before the purging candidate were already kept
+		pendings := kept[:0]
+		for _, p := range kept {
+			if purged[p.triggerIndex] {
+				m.record(Decision{Kind: DecisionCancelled, Index: i, TriggerIndex: p.triggerIndex, Side: p.side, Lag: p.lag})
+				continue
+			}
+			pendings = append(pendings, p)
+		}
+		m.pendings = pendings
+	}
+
+	// 2) New trigger on this bar
+	side, ok := m.rules.Trigger(i)
+	if !ok {
+		return out, triggered
+	}
+	m.record(Decision{Kind: DecisionTrigger, Index: i, TriggerIndex: i, Side: side})
+	check := m.rules.Gate(i, side, 0)
+	if check.Pass && !triggered {
+		out = Event{Side: side, Lag: 0, Index: i, TriggerIndex: i, Check: check}
+		m.record(Decision{Kind: DecisionValidated, Index: i, TriggerIndex: i, Side: side, Check: check})
+		return out, true
+	}
+	kind := DecisionRejected
+	if check.Pass {
+		kind = DecisionSuppressed
+	}
+	m.record(Decision{Kind: kind, Index: i, TriggerIndex: i, Side: side, Check: check})
+	for _, lag := range m.config.RecheckLags {
+		if lag <= 0 {
+			continue
+		}
+		m.pendings = append(m.pendings, pending{side: side, triggerIndex: i, recheckAt: i + lag, lag: lag})
+		m.record(Decision{Kind: DecisionScheduled, Index: i, TriggerIndex: i, Side: side, Lag: lag})
+	}
+	return out, triggered
+}
+
+// record appends a decision, keeping at most MaxDiagnostics entries
+func (m *Machine) record(d Decision) {
+	if m.config.MaxDiagnostics < 0 {
+		return
+	}
+	m.diagnostics = append(m.diagnostics, d)
+	if len(m.diagnostics) > m.config.MaxDiagnostics {
+		m.diagnostics = m.diagnostics[len(m.diagnostics)-m.config.MaxDiagnostics:]
+	}
+}
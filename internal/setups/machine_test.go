@@ -0,0 +1,104 @@
+package setups
+
+import "testing"
+
+// scriptRules triggers on the listed bars; gates and invariants pass on the listed bars
+type scriptRules struct {
+	triggers map[int]Side
+	gates    map[int]bool
+	broken   map[int]bool
+}
+
+func (r scriptRules) Trigger(i int) (Side, bool) {
+	side, ok := r.triggers[i]
+	return side, ok
+}
+
+func (r scriptRules) Gate(i int, side Side, lag int) Check {
+	return Check{Pass: r.gates[i]}
+}
+
+func (r scriptRules) Invariant(i int, side Side) Check {
+	return Check{Pass: !r.broken[i], Reason: "flip"}
+}
+
+func run(m *Machine, bars int) map[int]Event {
+	out := make(map[int]Event)
+	for i := 0; i < bars; i++ {
+		if evt, ok := m.Step(i); ok {
+			out[i] = evt
+		}
+	}
+	return out
+}
+
+func countKind(decisions []Decision, kind DecisionKind) int {
+	n := 0
+	for _, d := range decisions {
+		if d.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// TestMachine_ImmediateAndRecheck checks immediate validation, recheck lags and sibling purge
+func TestMachine_ImmediateAndRecheck(t *testing.T) {
+	rules := scriptRules{
+		triggers: map[int]Side{2: SideLong, 10: SideShort},
+		gates:    map[int]bool{2: true, 12: true, 13: true},
+	}
+	m := NewMachine(rules, Config{RecheckLags: []int{1, 2, 3}})
+	events := run(m, 20)
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", events)
+	}
+	if evt := events[2]; evt.Side != SideLong || evt.Lag != 0 || evt.TriggerIndex != 2 {
+		t.Errorf("Unexpected immediate event %+v", evt)
+	}
+	if evt := events[12]; evt.Side != SideShort || evt.Lag != 2 || evt.TriggerIndex != 10 {
+		t.Errorf("Unexpected recheck event %+v", evt)
+	}
+	// Lag 3 (bar 13) purged by the validation at lag 2
+	if _, ok := events[13]; ok || m.Pending() != 0 {
+		t.Errorf("Sibling recheck must be purged (pending %d)", m.Pending())
+	}
+	diag := m.Diagnostics()
+	if countKind(diag, DecisionScheduled) != 3 || countKind(diag, DecisionRejected) != 2 || countKind(diag, DecisionCancelled) != 1 {
+		t.Errorf("Unexpected diagnostics %+v", diag)
+	}
+}
+
+// TestMachine_CancelSiblingsOnFlip checks that a broken invariant cancels the remaining rechecks
+func TestMachine_CancelSiblingsOnFlip(t *testing.T) {
+	rules := scriptRules{
+		triggers: map[int]Side{5: SideLong},
+		gates:    map[int]bool{7: true},
+		broken:   map[int]bool{6: true},
+	}
+	if events := run(NewMachine(rules, Config{RecheckLags: []int{1, 2}}), 10); len(events) != 1 || events[7].Lag != 2 {
+		t.Errorf("Without cancel, lag 2 must validate: %+v", events)
+	}
+
+	m := NewMachine(rules, Config{RecheckLags: []int{1, 2}, CancelSiblingsOnFlip: true})
+	if events := run(m, 10); len(events) != 0 {
+		t.Errorf("With cancel, no event expected: %+v", events)
+	}
+	diag := m.Diagnostics()
+	if countKind(diag, DecisionBroken) != 1 || countKind(diag, DecisionCancelled) != 1 {
+		t.Errorf("Unexpected diagnostics %+v", diag)
+	}
+
+	// Diagnostics bounded or disabled
+	m = NewMachine(rules, Config{RecheckLags: []int{1, 2}, MaxDiagnostics: 2})
+	run(m, 10)
+	if len(m.Diagnostics()) != 2 {
+		t.Errorf("Expected 2 diagnostics kept, got %d", len(m.Diagnostics()))
+	}
+	m = NewMachine(rules, Config{RecheckLags: []int{1}, MaxDiagnostics: -1})
+	run(m, 10)
+	if len(m.Diagnostics()) != 0 {
+		t.Errorf("Diagnostics must be disabled")
+	}
+}
@@ -61,6 +61,7 @@ type BinanceDataConfig struct {
 	Downloader  DownloadConfigYAML  `yaml:"downloader"`
 	Streaming   StreamingConfigYAML `yaml:"streaming"`
 	Validation  ValidationConfigYAML `yaml:"validation"`
+	Store       StoreConfigYAML     `yaml:"store"`
 }
 
 // BingXDataConfig holds BingX-specific configuration
@@ -102,6 +103,14 @@ type StreamingConfigYAML struct {
 	EnableMetrics bool `yaml:"enable_metrics"`
 }
 
+// StoreConfigYAML holds configuration for the compiled binary store
+type StoreConfigYAML struct {
+	Enabled       bool   `yaml:"enabled"`        // Lire les trades/klines depuis le store binaire
+	RootPath      string `yaml:"root_path"`      // Dossier du store (default: <cache_root>/store)
+	UseMmap       bool   `yaml:"use_mmap"`       // Lecture via mmap
+	MaxConcurrent int    `yaml:"max_concurrent"` // Workers de compilation ZIP -> binaire
+}
+
 // ValidationConfigYAML matches the YAML structure for validation config
 type ValidationConfigYAML struct {
 	MaxPriceDeviation    float64 `yaml:"max_price_deviation"`
@@ -0,0 +1,50 @@
+// Package shared provides futures derivatives data (funding, open interest, long/short ratio)
+package shared
+
+import "sort"
+
+// DerivativesPoint is one timestamped derivatives observation
+type DerivativesPoint struct {
+	Time  int64   `json:"time"` // Unix ms at which the value became known
+	Value float64 `json:"value"`
+}
+
+// DerivativesSeries groups the futures market series of one symbol.
+//
+// FundingRates holds settled funding (Time = funding time). PredictedFunding holds
+// observations of the next funding estimate (Time = observation time); live runners
+// append one point per poll. OpenInterest is expressed in base asset and
+// LongShortRatio is the global long/short account ratio.
+type DerivativesSeries struct {
+	Symbol           string             `json:"symbol"`
+	FundingRates     []DerivativesPoint `json:"funding_rates,omitempty"`
+	PredictedFunding []DerivativesPoint `json:"predicted_funding,omitempty"`
+	OpenInterest     []DerivativesPoint `json:"open_interest,omitempty"`
+	LongShortRatio   []DerivativesPoint `json:"long_short_ratio,omitempty"`
+}
+
+// SortDerivativesPoints sorts points by time in place
+func SortDerivativesPoints(points []DerivativesPoint) {
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time < points[j].Time
+	})
+}
+
+// AlignDerivativesPoints returns, for each cutoff, the value of the last point with
+// Time <= cutoff. ok[i] is false when no point is known yet. Points and cutoffs must be sorted.
+func AlignDerivativesPoints(points []DerivativesPoint, cutoffs []int64) (values []float64, ok []bool) {
+	values = make([]float64, len(cutoffs))
+	ok = make([]bool, len(cutoffs))
+
+	j := -1
+	for i, cutoff := range cutoffs {
+		for j+1 < len(points) && points[j+1].Time <= cutoff {
+			j++
+		}
+		if j >= 0 {
+			values[i] = points[j].Value
+			ok[i] = true
+		}
+	}
+	return values, ok
+}
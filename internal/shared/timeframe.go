@@ -0,0 +1,149 @@
+// Package shared provides timeframe parsing and bucket alignment helpers
+package shared
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	msPerSecond = int64(1000)
+	msPerMinute = 60 * msPerSecond
+	msPerHour   = 60 * msPerMinute
+	msPerDay    = 24 * msPerHour
+	msPerWeek   = 7 * msPerDay
+
+	// weekOffsetMs shifts epoch alignment so weeks start on Monday 00:00 UTC (epoch is a Thursday)
+	weekOffsetMs = 4 * msPerDay
+)
+
+// nativeTimeframes lists the intervals published directly by exchanges (Binance Vision/REST)
+var nativeTimeframes = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w"}
+
+// ParseTimeframe converts a timeframe string ("15s", "7m", "3h", "1d", "1w") to milliseconds
+func ParseTimeframe(timeframe string) (int64, error) {
+	tf := strings.TrimSpace(timeframe)
+	if len(tf) < 2 {
+		return 0, fmt.Errorf("unsupported timeframe: %s", timeframe)
+	}
+
+	unit := tf[len(tf)-1]
+	value, err := strconv.ParseInt(tf[:len(tf)-1], 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("unsupported timeframe: %s", timeframe)
+	}
+
+	switch unit {
+	case 's':
+		return value * msPerSecond, nil
+	case 'm':
+		return value * msPerMinute, nil
+	case 'h':
+		return value * msPerHour, nil
+	case 'd':
+		return value * msPerDay, nil
+	case 'w':
+		return value * msPerWeek, nil
+	default:
+		return 0, fmt.Errorf("unsupported timeframe unit in %s", timeframe)
+	}
+}
+
+// IsNativeTimeframe reports whether the timeframe is published by exchanges
+func IsNativeTimeframe(timeframe string) bool {
+	for _, tf := range nativeTimeframes {
+		if tf == timeframe {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceTimeframe returns the largest native timeframe a custom timeframe can be aggregated from.
+// An empty result means the timeframe is sub-minute and must be built from trades.
+func SourceTimeframe(timeframe string) (string, error) {
+	if IsNativeTimeframe(timeframe) {
+		return timeframe, nil
+	}
+
+	intervalMs, err := ParseTimeframe(timeframe)
+	if err != nil {
+		return "", err
+	}
+
+	best := ""
+	var bestMs int64
+	for _, tf := range nativeTimeframes {
+		nativeMs, _ := ParseTimeframe(tf)
+		if nativeMs > intervalMs || nativeMs <= bestMs {
+			continue
+		}
+		// The native bars must tile both the target bucket and the UTC day reset
+		if intervalMs%nativeMs == 0 && (nativeMs >= msPerDay || msPerDay%nativeMs == 0) {
+			best, bestMs = tf, nativeMs
+		}
+	}
+
+	return best, nil
+}
+
+// TimeframeBucketStart returns the open time of the bucket containing timestamp.
+//
+// Intervals that divide a day align on the epoch (and therefore on UTC midnight).
+// Intraday intervals that do not divide a day (7m, 5h...) restart at each UTC
+// midnight, TradingView style, so the last bucket of a day is shorter.
+// Weekly intervals start on Monday 00:00 UTC.
+func TimeframeBucketStart(timestamp, intervalMs int64) int64 {
+	switch {
+	case intervalMs <= 0:
+		return timestamp
+	case intervalMs%msPerWeek == 0:
+		return floorDiv(timestamp-weekOffsetMs, intervalMs)*intervalMs + weekOffsetMs
+	case intervalMs >= msPerDay || msPerDay%intervalMs == 0:
+		return floorDiv(timestamp, intervalMs) * intervalMs
+	default:
+		dayStart := floorDiv(timestamp, msPerDay) * msPerDay
+		return dayStart + ((timestamp-dayStart)/intervalMs)*intervalMs
+	}
+}
+
+// TimeframeBucketEnd returns the close time (inclusive, Binance style) of the bucket starting at bucketStart
+func TimeframeBucketEnd(bucketStart, intervalMs int64) int64 {
+	end := bucketStart + intervalMs
+	if intervalMs < msPerDay && msPerDay%intervalMs != 0 {
+		nextDay := (floorDiv(bucketStart, msPerDay) + 1) * msPerDay
+		if end > nextDay {
+			end = nextDay
+		}
+	}
+	return end - 1
+}
+
+// NextTimeframeBoundary returns the open time of the bucket following the one containing timestamp
+func NextTimeframeBoundary(timestamp int64, timeframe string) (int64, error) {
+	intervalMs, err := ParseTimeframe(timeframe)
+	if err != nil {
+		return 0, err
+	}
+	return TimeframeBucketEnd(TimeframeBucketStart(timestamp, intervalMs), intervalMs) + 1, nil
+}
+
+// IsTimeframeBoundary reports whether timestamp (ms, truncated to the second) opens a bucket
+func IsTimeframeBoundary(timestamp int64, timeframe string) bool {
+	intervalMs, err := ParseTimeframe(timeframe)
+	if err != nil {
+		return false
+	}
+	seconds := floorDiv(timestamp, msPerSecond) * msPerSecond
+	return TimeframeBucketStart(seconds, intervalMs) == seconds
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
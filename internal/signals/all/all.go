@@ -0,0 +1,15 @@
+// Package all enregistre tous les générateurs de internal/signals dans le registre
+// (import pour effet de bord : _ "agent-economique/internal/signals/all")
+package all
+
+import (
+	_ "agent-economique/internal/signals/ban_fin_momentium"
+	_ "agent-economique/internal/signals/declarative"
+	_ "agent-economique/internal/signals/direction"
+	_ "agent-economique/internal/signals/direction_dmi"
+	_ "agent-economique/internal/signals/scalping_momentium"
+	_ "agent-economique/internal/signals/smart_eco"
+	_ "agent-economique/internal/signals/smart_eco_anchored"
+	_ "agent-economique/internal/signals/trend"
+	_ "agent-economique/internal/signals/vwma_cross_dmi_simple"
+)
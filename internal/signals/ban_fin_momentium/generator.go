@@ -9,81 +9,81 @@ import (
 )
 
 type Config struct {
-	ATRPeriod         int
-	BodyATRMultiplier float64
+	ATRPeriod         int     `yaml:"atr_period"`
+	BodyATRMultiplier float64 `yaml:"body_atr_multiplier"`
 
 	// Volume gating
-	VolumeSMAPeriod int
-	VolumeCoeff     float64 // volume_normalized > VolumeCoeff * SMA(volume)
+	VolumeSMAPeriod int     `yaml:"volume_sma_period"`
+	VolumeCoeff     float64 `yaml:"volume_coeff"` // volume_normalized > VolumeCoeff * SMA(volume)
 
 	// VWMA fast/slow (trend gate)
-	VWMAFastPeriod      int
-	VWMASlowPeriod      int
-	EnableVWMATrendGate bool // if true, require fast>slow for LONG and fast<slow for SHORT
-	EnableVWMACross     bool
+	VWMAFastPeriod      int  `yaml:"vwma_fast_period"`
+	VWMASlowPeriod      int  `yaml:"vwma_slow_period"`
+	EnableVWMATrendGate bool `yaml:"enable_vwma_trend_gate"` // if true, require fast>slow for LONG and fast<slow for SHORT
+	EnableVWMACross     bool `yaml:"enable_vwma_cross"`
 
 	// Aggregation
-	Aggregate3    bool // if true, use 3-candle aggregation for body/volume/color
-	EnableBarGate bool
+	Aggregate3    bool `yaml:"aggregate_3"` // if true, use 3-candle aggregation for body/volume/color
+	EnableBarGate bool `yaml:"enable_bar_gate"`
 
 	// Setup toggles (enable/disable independently)
-	EnableOpenLong   bool
-	EnableOpenShort  bool
-	EnableCloseLong  bool
-	EnableCloseShort bool
+	EnableOpenLong   bool `yaml:"enable_open_long"`
+	EnableOpenShort  bool `yaml:"enable_open_short"`
+	EnableCloseLong  bool `yaml:"enable_close_long"`
+	EnableCloseShort bool `yaml:"enable_close_short"`
 
 	// Stochastic
-	StochKPeriod int
-	StochKSmooth int
-	StochDPeriod int
+	StochKPeriod int `yaml:"stoch_k_period"`
+	StochKSmooth int `yaml:"stoch_k_smooth"`
+	StochDPeriod int `yaml:"stoch_d_period"`
 	// Filter toggles
-	EnableStochCross bool // optional cross K vs D in direction
+	EnableStochCross bool `yaml:"enable_stoch_cross"` // optional cross K vs D in direction
 	// Per-direction Stoch K extremes (range-style)
-	StochKOversoldLong    float64
-	StochKOverboughtLong  float64
-	StochKOversoldShort   float64
-	StochKOverboughtShort float64
+	StochKOversoldLong    float64 `yaml:"stoch_k_oversold_long"`
+	StochKOverboughtLong  float64 `yaml:"stoch_k_overbought_long"`
+	StochKOversoldShort   float64 `yaml:"stoch_k_oversold_short"`
+	StochKOverboughtShort float64 `yaml:"stoch_k_overbought_short"`
 	// Independent toggles per Stoch extreme
-	EnableStochKOversoldLongGate    bool
-	EnableStochKOverboughtLongGate  bool
-	EnableStochKOversoldShortGate   bool
-	EnableStochKOverboughtShortGate bool
+	EnableStochKOversoldLongGate    bool `yaml:"enable_stoch_k_oversold_long_gate"`
+	EnableStochKOverboughtLongGate  bool `yaml:"enable_stoch_k_overbought_long_gate"`
+	EnableStochKOversoldShortGate   bool `yaml:"enable_stoch_k_oversold_short_gate"`
+	EnableStochKOverboughtShortGate bool `yaml:"enable_stoch_k_overbought_short_gate"`
 
 	// MFI (per-direction extremes, both directions)
-	MFIPeriod          int
-	MFIOverboughtLong  float64 // used for OPEN LONG gating ("non en sur-achat")
-	MFIOversoldShort   float64 // used for OPEN SHORT gating ("non en sur-vente")
-	MFIOverboughtShort float64 // used for OPEN SHORT gating upper bound
-	MFIOversoldLong    float64 // used for OPEN LONG gating lower bound
+	MFIPeriod          int     `yaml:"mfi_period"`
+	MFIOverboughtLong  float64 `yaml:"mfi_overbought_long"`  // used for OPEN LONG gating ("non en sur-achat")
+	MFIOversoldShort   float64 `yaml:"mfi_oversold_short"`   // used for OPEN SHORT gating ("non en sur-vente")
+	MFIOverboughtShort float64 `yaml:"mfi_overbought_short"` // used for OPEN SHORT gating upper bound
+	MFIOversoldLong    float64 `yaml:"mfi_oversold_long"`    // used for OPEN LONG gating lower bound
 
 	// CCI (per-direction extremes, both directions)
-	CCIPeriod          int
-	CCIOverboughtLong  float64 // used for OPEN LONG gating ("non en sur-achat")
-	CCIOversoldShort   float64 // used for OPEN SHORT gating ("non en sur-vente")
-	CCIOverboughtShort float64 // used for OPEN SHORT gating upper bound
-	CCIOversoldLong    float64 // used for OPEN LONG gating lower bound
+	CCIPeriod          int     `yaml:"cci_period"`
+	CCIOverboughtLong  float64 `yaml:"cci_overbought_long"`  // used for OPEN LONG gating ("non en sur-achat")
+	CCIOversoldShort   float64 `yaml:"cci_oversold_short"`   // used for OPEN SHORT gating ("non en sur-vente")
+	CCIOverboughtShort float64 `yaml:"cci_overbought_short"` // used for OPEN SHORT gating upper bound
+	CCIOversoldLong    float64 `yaml:"cci_oversold_long"`    // used for OPEN LONG gating lower bound
 
 	// MACD (line, signal, histogram)
-	MACDFastPeriod      int
-	MACDSlowPeriod      int
-	MACDSignalPeriod    int
-	EnableMACDCrossGate bool
-	EnableMACDSignGate  bool
-	EnableMACDHistGate  bool
+	MACDFastPeriod      int  `yaml:"macd_fast_period"`
+	MACDSlowPeriod      int  `yaml:"macd_slow_period"`
+	MACDSignalPeriod    int  `yaml:"macd_signal_period"`
+	EnableMACDCrossGate bool `yaml:"enable_macd_cross_gate"`
+	EnableMACDSignGate  bool `yaml:"enable_macd_sign_gate"`
+	EnableMACDHistGate  bool `yaml:"enable_macd_hist_gate"`
 
 	// Filter toggles
-	EnableMFIGate bool
-	EnableCCIGate bool
+	EnableMFIGate bool `yaml:"enable_mfi_gate"`
+	EnableCCIGate bool `yaml:"enable_cci_gate"`
 	// Independent toggles per MFI extreme
-	EnableMFIOversoldLongGate    bool
-	EnableMFIOverboughtLongGate  bool
-	EnableMFIOversoldShortGate   bool
-	EnableMFIOverboughtShortGate bool
+	EnableMFIOversoldLongGate    bool `yaml:"enable_mfi_oversold_long_gate"`
+	EnableMFIOverboughtLongGate  bool `yaml:"enable_mfi_overbought_long_gate"`
+	EnableMFIOversoldShortGate   bool `yaml:"enable_mfi_oversold_short_gate"`
+	EnableMFIOverboughtShortGate bool `yaml:"enable_mfi_overbought_short_gate"`
 	// Independent toggles per CCI extreme
-	EnableCCIOversoldLongGate    bool
-	EnableCCIOverboughtLongGate  bool
-	EnableCCIOversoldShortGate   bool
-	EnableCCIOverboughtShortGate bool
+	EnableCCIOversoldLongGate    bool `yaml:"enable_cci_oversold_long_gate"`
+	EnableCCIOverboughtLongGate  bool `yaml:"enable_cci_overbought_long_gate"`
+	EnableCCIOversoldShortGate   bool `yaml:"enable_cci_oversold_short_gate"`
+	EnableCCIOverboughtShortGate bool `yaml:"enable_cci_overbought_short_gate"`
 }
 
 // EvaluateLast evaluates only the last closed candle and returns at most one signal
@@ -391,6 +391,34 @@ func (g *Generator) Initialize(config signals.GeneratorConfig) error {
 	return nil
 }
 
+// MinHistorySize minimal history from the indicator registry (+2 bars for 3-candle aggregation)
+func (g *Generator) MinHistorySize() int {
+	refs := []string{
+		fmt.Sprintf("atr(%d)", g.cfg.ATRPeriod),
+		fmt.Sprintf("stoch(%d,%d,%d).d", g.cfg.StochKPeriod, g.cfg.StochKSmooth, g.cfg.StochDPeriod),
+		fmt.Sprintf("mfi(%d)", g.cfg.MFIPeriod),
+		fmt.Sprintf("cci(%d)", g.cfg.CCIPeriod),
+		fmt.Sprintf("sma(%d)", g.cfg.VolumeSMAPeriod),
+	}
+	if g.cfg.VWMAFastPeriod > 0 {
+		refs = append(refs, fmt.Sprintf("vwma(%d)", g.cfg.VWMAFastPeriod))
+	}
+	if g.cfg.VWMASlowPeriod > 0 {
+		refs = append(refs, fmt.Sprintf("vwma(%d)", g.cfg.VWMASlowPeriod))
+	}
+	if g.cfg.MACDFastPeriod > 0 && g.cfg.MACDSlowPeriod > 0 && g.cfg.MACDSignalPeriod > 0 {
+		refs = append(refs, fmt.Sprintf("macd(%d,%d,%d).signal", g.cfg.MACDFastPeriod, g.cfg.MACDSlowPeriod, g.cfg.MACDSignalPeriod))
+	}
+	size, err := signals.IndicatorHistory(refs...)
+	if err != nil {
+		return 0
+	}
+	if g.cfg.Aggregate3 {
+		size += 2
+	}
+	return size
+}
+
 func (g *Generator) CalculateIndicators(klines []signals.Kline) error {
 	if len(klines) == 0 {
 		return fmt.Errorf("no klines")
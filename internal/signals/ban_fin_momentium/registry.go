@@ -0,0 +1,92 @@
+package ban_fin_momentium
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "ban_fin_momentium",
+		Description:   "Setups open/close sur bougie impulsive (corps/ATR, volume), gates Stoch/MFI/CCI/MACD/VWMA",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return NewGenerator(*config.(*Config)), nil
+		},
+	})
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/ban_fin_momentium, gates optionnels désactivés)
+func DefaultConfig() Config {
+	return Config{
+		ATRPeriod:             10,
+		BodyATRMultiplier:     0.8,
+		VolumeSMAPeriod:       10,
+		VolumeCoeff:           0.8,
+		VWMAFastPeriod:        4,
+		VWMASlowPeriod:        12,
+		EnableOpenLong:        true,
+		EnableOpenShort:       true,
+		EnableCloseLong:       true,
+		EnableCloseShort:      true,
+		StochKPeriod:          14,
+		StochKSmooth:          2,
+		StochDPeriod:          3,
+		EnableStochCross:      true,
+		StochKOversoldLong:    40,
+		StochKOverboughtLong:  70,
+		StochKOversoldShort:   30,
+		StochKOverboughtShort: 60,
+		MFIPeriod:             30,
+		MFIOverboughtLong:     80,
+		MFIOversoldShort:      20,
+		MFIOverboughtShort:    80,
+		MFIOversoldLong:       20,
+		CCIPeriod:             20,
+		CCIOverboughtLong:     100,
+		CCIOversoldShort:      -100,
+		CCIOverboughtShort:    100,
+		CCIOversoldLong:       -100,
+		MACDFastPeriod:        12,
+		MACDSlowPeriod:        26,
+		MACDSignalPeriod:      9,
+	}
+}
+
+// validateConfig reprend les contrôles d'Initialize en nommant le champ YAML
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("atr_period", c.ATRPeriod),
+		signals.CheckRange("body_atr_multiplier", c.BodyATRMultiplier, 1e-9, 100),
+		signals.CheckPeriod("volume_sma_period", c.VolumeSMAPeriod),
+		signals.CheckPeriod("stoch_k_period", c.StochKPeriod),
+		signals.CheckPeriod("stoch_k_smooth", c.StochKSmooth),
+		signals.CheckPeriod("stoch_d_period", c.StochDPeriod),
+		signals.CheckPeriod("mfi_period", c.MFIPeriod),
+		signals.CheckPeriod("cci_period", c.CCIPeriod),
+	)
+	if err != nil {
+		return err
+	}
+	if c.EnableVWMATrendGate || c.EnableVWMACross {
+		if err := signals.FirstError(
+			signals.CheckPeriod("vwma_fast_period", c.VWMAFastPeriod),
+			signals.CheckPeriod("vwma_slow_period", c.VWMASlowPeriod),
+		); err != nil {
+			return err
+		}
+	}
+	if c.EnableMACDCrossGate || c.EnableMACDSignGate || c.EnableMACDHistGate {
+		if err := signals.FirstError(
+			signals.CheckPeriod("macd_fast_period", c.MACDFastPeriod),
+			signals.CheckPeriod("macd_slow_period", c.MACDSlowPeriod),
+			signals.CheckPeriod("macd_signal_period", c.MACDSignalPeriod),
+		); err != nil {
+			return err
+		}
+	}
+	if !c.EnableOpenLong && !c.EnableOpenShort {
+		return signals.FieldErrorf("enable_open_long", "at least one of enable_open_long / enable_open_short must be true")
+	}
+	return nil
+}
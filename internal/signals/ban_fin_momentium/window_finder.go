@@ -23,25 +23,17 @@ type WindowConfig struct {
 	OpenMode   WindowOpenMode
 }
 
-type windowState struct {
-	active   bool
-	dir      signals.SignalType
-	startIdx int
-	deadline int
-
-	lastStochCross int // +1 up, -1 down, 0 none
-	lastVWMACross  int
-	lastMACDCross  int
-}
-
-// WindowFinder applique la logique de fen
-// tre optionnelle par-dessus un Generator BAN_FIN_MOMENTIUM.
-// Il ne modifie pas le Generator lui-mame et n'est utilise que si EnableWindowMode est active cf4te appelant.
+// WindowFinder applique la logique de fenêtre optionnelle par-dessus un Generator BAN_FIN_MOMENTIUM.
+// Il ne modifie pas le Generator lui-même et n'est utilisé que si EnableWindowMode est activé côté appelant.
+// Les fenêtres sont tenues par un signals.WindowGenerator sans position (la position est gérée par
+// l'appelant) dont les sources lisent les indicateurs du Generator :
+//   - croisements Stoch / VWMA / MACD actifs : conditions "latest" (dernier croisement de la fenêtre)
+//   - barre d'impulsion ("bar") et filtres tendance / CCI / MFI / Stoch K / MACD : conditions "bar"
 type WindowFinder struct {
 	gen    *Generator
 	cfg    WindowConfig
-	longW  windowState
-	shortW windowState
+	window *signals.WindowGenerator // nil : aucun gate ou aucune source d'ouverture, jamais de signal
+	klines []signals.Kline          // Flux passé au dernier Step (lu par la source "bar")
 }
 
 func NewWindowFinder(gen *Generator, cfg WindowConfig) *WindowFinder {
@@ -54,410 +46,232 @@ func NewWindowFinder(gen *Generator, cfg WindowConfig) *WindowFinder {
 		mode != WindowOpenModeStochCross && mode != WindowOpenModeVWMACross && mode != WindowOpenModeMACDCross {
 		mode = WindowOpenModeAuto
 	}
-	return &WindowFinder{
+	f := &WindowFinder{
 		gen: gen,
 		cfg: WindowConfig{WindowBars: bars, OpenMode: mode},
 	}
+
+	var sides []signals.SignalType
+	if gen.cfg.EnableOpenLong {
+		sides = append(sides, signals.SignalTypeLong)
+	}
+	if gen.cfg.EnableOpenShort {
+		sides = append(sides, signals.SignalTypeShort)
+	}
+	// Si aucun gate n'est actif (bar, Stoch, MFI, CCI, VWMA, MACD), ne pas produire de signaux en mode fenêtre.
+	if len(sides) == 0 || !gen.hasWindowGate() {
+		return f
+	}
+	window, err := signals.NewWindowGenerator(signals.WindowConfig{
+		Name:           "ban_fin_momentium_window",
+		Conditions:     f.conditions(),
+		OpenMode:       signals.WindowOpenMode(mode),
+		Sides:          sides,
+		WindowBars:     bars,
+		MaxDiagnostics: -1,
+		NoPosition:     true,
+	})
+	if err != nil {
+		// Mode d'ouverture sans source active (ex: stoch_cross sans croisement Stoch) : jamais ouverte
+		return f
+	}
+	f.window = window
+	return f
 }
 
-// Step evalue la bougie idx (derniare bougie ferme) dans une logique de fenetre.
-// klines est le flux complet de bougies (fermees et en cours), idx est l'index de la bougie fermee ne analyser.
+// conditions construit les conditions de la fenêtre à partir des gates actifs
+func (f *WindowFinder) conditions() []signals.WindowCondition {
+	g := f.gen
+	var conds []signals.WindowCondition
+	cross := func(name signals.WindowOpenMode, at func(i int) (up, down bool)) {
+		conds = append(conds, signals.WindowCondition{
+			Source: &gateSource{name: string(name), cross: true, at: func(i int, side signals.SignalType) bool {
+				up, down := at(i)
+				return (side == signals.SignalTypeLong && up) || (side == signals.SignalTypeShort && down)
+			}},
+			Mode: signals.WindowModeLatest,
+		})
+	}
+	if g.cfg.EnableStochCross {
+		cross(signals.WindowOpenStochCross, g.stochCrossAt)
+	}
+	if g.cfg.EnableVWMACross {
+		cross(signals.WindowOpenVWMACross, g.vwmaCrossAt)
+	}
+	if g.cfg.EnableMACDCrossGate {
+		cross(signals.WindowOpenMACDCross, g.macdCrossAt)
+	}
+	// La source "bar" existe toujours : elle ouvre les fenêtres des modes bar / auto sans croisement
+	conds = append(conds,
+		signals.WindowCondition{
+			Source: &gateSource{name: string(signals.WindowOpenBar), at: func(i int, side signals.SignalType) bool {
+				return g.barGateAt(f.klines, i, side)
+			}},
+			Mode: signals.WindowModeBar,
+		},
+		signals.WindowCondition{
+			Source: &gateSource{name: "filters", at: g.filterGatesAt},
+			Mode:   signals.WindowModeBar,
+		},
+	)
+	return conds
+}
+
+// Step evalue la bougie idx (dernière bougie fermée) dans une logique de fenêtre.
+// klines est le flux complet de bougies (fermées et en cours), idx est l'index de la bougie fermée à analyser.
 // Retourne au plus un signal directionnel ENTRY.
 func (f *WindowFinder) Step(klines []signals.Kline, idx int) (*signals.Signal, error) {
 	g := f.gen
 	if idx <= 0 || idx >= len(klines) {
 		return nil, fmt.Errorf("invalid idx %d for window step", idx)
 	}
-
-	// Validation des indicateurs comme dans EvaluateLast
-	if idx >= len(g.atr) || math.IsNaN(g.atr[idx]) {
+	if f.window == nil || !g.windowReady(idx) {
 		return nil, nil
 	}
-	if idx >= len(g.volSMA) || math.IsNaN(g.volSMA[idx]) {
+
+	f.klines = klines
+	sigs := f.window.Step(klines, idx)
+	if len(sigs) == 0 {
 		return nil, nil
 	}
-	stochExtGatesEnabled := g.cfg.EnableStochKOversoldLongGate || g.cfg.EnableStochKOverboughtLongGate ||
+	body, isGreen, isRed, volNorm := g.bodyVolumeColorAt(klines, idx)
+	b2atr := body / g.atr[idx]
+	v2sma := volNorm / g.volSMA[idx]
+	return g.build(signals.SignalActionEntry, sigs[0].Type, klines, idx, b2atr, v2sma, isGreen, isRed), nil
+}
+
+// gateSource condition de fenêtre évaluée sur les indicateurs du Generator
+type gateSource struct {
+	name  string
+	cross bool
+	at    func(i int, side signals.SignalType) bool
+}
+
+func (s *gateSource) Name() string { return s.name }
+
+// Calculate ne fait rien : les indicateurs viennent de Generator.CalculateIndicators
+func (s *gateSource) Calculate([]signals.Kline) error { return nil }
+
+func (s *gateSource) At(i int, side signals.SignalType) bool { return s.at(i, side) }
+
+func (s *gateSource) MinHistorySize() int { return 0 }
+
+func (s *gateSource) CrossEvents() bool { return s.cross }
+
+// hasWindowGate indique si au moins un gate est actif (bar, Stoch, MFI, CCI, VWMA, MACD)
+func (g *Generator) hasWindowGate() bool {
+	return g.cfg.EnableBarGate || g.cfg.EnableStochCross || g.stochExtGatesEnabled() || g.cfg.EnableMFIGate ||
+		g.cfg.EnableCCIGate || g.cfg.EnableVWMATrendGate || g.cfg.EnableVWMACross ||
+		g.cfg.EnableMACDCrossGate || g.cfg.EnableMACDSignGate || g.cfg.EnableMACDHistGate
+}
+
+func (g *Generator) stochExtGatesEnabled() bool {
+	return g.cfg.EnableStochKOversoldLongGate || g.cfg.EnableStochKOverboughtLongGate ||
 		g.cfg.EnableStochKOversoldShortGate || g.cfg.EnableStochKOverboughtShortGate
-	needStoch := g.cfg.EnableStochCross || stochExtGatesEnabled
-	needMFI := g.cfg.EnableMFIGate
-	needCCI := g.cfg.EnableCCIGate
-	needVWMA := g.cfg.EnableVWMATrendGate || g.cfg.EnableVWMACross
-	needMACD := g.cfg.EnableMACDCrossGate || g.cfg.EnableMACDSignGate || g.cfg.EnableMACDHistGate
-	// Si aucun gate n'est actif (bar, Stoch, MFI, CCI, VWMA, MACD), ne pas produire de signaux en mode fenêtre.
-	hasAnyGate := g.cfg.EnableBarGate || g.cfg.EnableStochCross || stochExtGatesEnabled || needMFI || needCCI || needVWMA || needMACD
-	if !hasAnyGate {
-		return nil, nil
+}
+
+// windowReady valide les indicateurs de la bougie idx comme dans EvaluateLast (sinon la bougie est ignorée)
+func (g *Generator) windowReady(idx int) bool {
+	valid := func(arr []float64) bool { return idx < len(arr) && !math.IsNaN(arr[idx]) }
+	if !valid(g.atr) || !valid(g.volSMA) {
+		return false
 	}
-	if needStoch {
-		if idx >= len(g.stochK) || math.IsNaN(g.stochK[idx]) {
-			return nil, nil
-		}
-		if g.cfg.EnableStochCross {
-			if idx >= len(g.stochD) || math.IsNaN(g.stochD[idx]) {
-				return nil, nil
-			}
-		}
+	if (g.cfg.EnableStochCross || g.stochExtGatesEnabled()) && !valid(g.stochK) {
+		return false
 	}
-	if needMFI {
-		if idx >= len(g.mfi) || math.IsNaN(g.mfi[idx]) {
-			return nil, nil
-		}
+	if g.cfg.EnableStochCross && !valid(g.stochD) {
+		return false
 	}
-	if needCCI {
-		if idx >= len(g.cci) || math.IsNaN(g.cci[idx]) {
-			return nil, nil
-		}
+	if g.cfg.EnableMFIGate && !valid(g.mfi) {
+		return false
 	}
-	if needVWMA {
-		if idx >= len(g.vwmaFast) || math.IsNaN(g.vwmaFast[idx]) {
-			return nil, nil
-		}
-		if idx >= len(g.vwmaSlow) || math.IsNaN(g.vwmaSlow[idx]) {
-			return nil, nil
-		}
+	if g.cfg.EnableCCIGate && !valid(g.cci) {
+		return false
 	}
-	if needMACD {
-		if idx >= len(g.macdLine) || math.IsNaN(g.macdLine[idx]) {
-			return nil, nil
-		}
-		if idx >= len(g.macdSignal) || math.IsNaN(g.macdSignal[idx]) {
-			return nil, nil
-		}
-		if idx >= len(g.macdHist) || math.IsNaN(g.macdHist[idx]) {
-			return nil, nil
-		}
+	if (g.cfg.EnableVWMATrendGate || g.cfg.EnableVWMACross) && (!valid(g.vwmaFast) || !valid(g.vwmaSlow)) {
+		return false
 	}
-
-	body, isGreen, isRed, volNorm := g.bodyVolumeColorAt(klines, idx)
-	b2atr := body / g.atr[idx]
-	v2sma := volNorm / g.volSMA[idx]
-
-	// Croisements locaux sur cette bougie
-	stochUp, stochDown := false, false
-	if g.cfg.EnableStochCross {
-		up, down := g.stochCrossAt(idx)
-		stochUp, stochDown = up, down
+	needMACD := g.cfg.EnableMACDCrossGate || g.cfg.EnableMACDSignGate || g.cfg.EnableMACDHistGate
+	if needMACD && (!valid(g.macdLine) || !valid(g.macdSignal) || !valid(g.macdHist)) {
+		return false
 	}
-	vwUp, vwDown := false, false
-	if g.cfg.EnableVWMACross {
-		up, down := g.vwmaCrossAt(idx)
-		vwUp, vwDown = up, down
+	return true
+}
+
+// barGateAt analyse de barre (couleur, corps/ATR, volume/SMA) ; vraie si le gate est inactif
+func (g *Generator) barGateAt(klines []signals.Kline, i int, side signals.SignalType) bool {
+	if !g.cfg.EnableBarGate {
+		return true
 	}
-	macdUp, macdDown := false, false
-	if g.cfg.EnableMACDCrossGate {
-		up, down := g.macdCrossAt(idx)
-		macdUp, macdDown = up, down
+	body, isGreen, isRed, volNorm := g.bodyVolumeColorAt(klines, i)
+	if (side == signals.SignalTypeLong && !isGreen) || (side == signals.SignalTypeShort && !isRed) {
+		return false
 	}
+	return body/g.atr[i] >= g.cfg.BodyATRMultiplier && volNorm/g.volSMA[i] >= g.cfg.VolumeCoeff
+}
 
-	// Trend via VWMA (CCI_trend est ge9re par la de9mo comme avant)
-	trendOKLong := true
-	trendOKShort := true
-	if g.cfg.EnableVWMATrendGate {
-		trendOKLong = g.vwmaFast[idx] > g.vwmaSlow[idx]
-		trendOKShort = g.vwmaFast[idx] < g.vwmaSlow[idx]
-	}
+// filterGatesAt tendance VWMA, CCI, MFI, Stoch K extrêmes, signe et histogramme MACD sur la bougie i
+func (g *Generator) filterGatesAt(i int, side signals.SignalType) bool {
+	long := side == signals.SignalTypeLong
 
-	// Analyse de barre
-	barOKLong, barOKShort := true, true
-	if g.cfg.EnableBarGate {
-		barOKLong = isGreen && b2atr >= g.cfg.BodyATRMultiplier && v2sma >= g.cfg.VolumeCoeff
-		barOKShort = isRed && b2atr >= g.cfg.BodyATRMultiplier && v2sma >= g.cfg.VolumeCoeff
+	// Trend via VWMA (CCI_trend est géré par la démo comme avant)
+	if g.cfg.EnableVWMATrendGate {
+		if long && !(g.vwmaFast[i] > g.vwmaSlow[i]) || !long && !(g.vwmaFast[i] < g.vwmaSlow[i]) {
+			return false
+		}
 	}
 
 	// CCI court
-	cciOKLong, cciOKShort := true, true
 	if g.cfg.EnableCCIGate {
-		cciVal := g.cci[idx]
-		if g.cfg.EnableCCIOversoldLongGate {
-			cciOKLong = cciOKLong && cciVal >= g.cfg.CCIOversoldLong
+		v := g.cci[i]
+		if long && (g.cfg.EnableCCIOversoldLongGate && v < g.cfg.CCIOversoldLong ||
+			g.cfg.EnableCCIOverboughtLongGate && v > g.cfg.CCIOverboughtLong) {
+			return false
 		}
-		if g.cfg.EnableCCIOverboughtLongGate {
-			cciOKLong = cciOKLong && cciVal <= g.cfg.CCIOverboughtLong
-		}
-		if g.cfg.EnableCCIOversoldShortGate {
-			cciOKShort = cciOKShort && cciVal >= g.cfg.CCIOversoldShort
-		}
-		if g.cfg.EnableCCIOverboughtShortGate {
-			cciOKShort = cciOKShort && cciVal <= g.cfg.CCIOverboughtShort
+		if !long && (g.cfg.EnableCCIOversoldShortGate && v < g.cfg.CCIOversoldShort ||
+			g.cfg.EnableCCIOverboughtShortGate && v > g.cfg.CCIOverboughtShort) {
+			return false
 		}
 	}
 
 	// MFI
-	mfiOKLong, mfiOKShort := true, true
 	if g.cfg.EnableMFIGate {
-		mfiVal := g.mfi[idx]
-		if g.cfg.EnableMFIOversoldLongGate {
-			mfiOKLong = mfiOKLong && mfiVal >= g.cfg.MFIOversoldLong
-		}
-		if g.cfg.EnableMFIOverboughtLongGate {
-			mfiOKLong = mfiOKLong && mfiVal <= g.cfg.MFIOverboughtLong
+		v := g.mfi[i]
+		if long && (g.cfg.EnableMFIOversoldLongGate && v < g.cfg.MFIOversoldLong ||
+			g.cfg.EnableMFIOverboughtLongGate && v > g.cfg.MFIOverboughtLong) {
+			return false
 		}
-		if g.cfg.EnableMFIOversoldShortGate {
-			mfiOKShort = mfiOKShort && mfiVal >= g.cfg.MFIOversoldShort
-		}
-		if g.cfg.EnableMFIOverboughtShortGate {
-			mfiOKShort = mfiOKShort && mfiVal <= g.cfg.MFIOverboughtShort
+		if !long && (g.cfg.EnableMFIOversoldShortGate && v < g.cfg.MFIOversoldShort ||
+			g.cfg.EnableMFIOverboughtShortGate && v > g.cfg.MFIOverboughtShort) {
+			return false
 		}
 	}
 
-	// Stoch K extr0ames
-	stochKOKLong, stochKOKShort := true, true
-	if g.cfg.EnableStochKOversoldLongGate || g.cfg.EnableStochKOverboughtLongGate ||
-		g.cfg.EnableStochKOversoldShortGate || g.cfg.EnableStochKOverboughtShortGate {
-		kVal := g.stochK[idx]
-		if g.cfg.EnableStochKOversoldLongGate {
-			stochKOKLong = stochKOKLong && kVal <= g.cfg.StochKOversoldLong
-		}
-		if g.cfg.EnableStochKOverboughtLongGate {
-			stochKOKLong = stochKOKLong && kVal <= g.cfg.StochKOverboughtLong
+	// Stoch K extrêmes
+	if g.stochExtGatesEnabled() {
+		k := g.stochK[i]
+		if long && (g.cfg.EnableStochKOversoldLongGate && k > g.cfg.StochKOversoldLong ||
+			g.cfg.EnableStochKOverboughtLongGate && k > g.cfg.StochKOverboughtLong) {
+			return false
 		}
-		if g.cfg.EnableStochKOversoldShortGate {
-			stochKOKShort = stochKOKShort && kVal >= g.cfg.StochKOversoldShort
-		}
-		if g.cfg.EnableStochKOverboughtShortGate {
-			stochKOKShort = stochKOKShort && kVal >= g.cfg.StochKOverboughtShort
+		if !long && (g.cfg.EnableStochKOversoldShortGate && k < g.cfg.StochKOversoldShort ||
+			g.cfg.EnableStochKOverboughtShortGate && k < g.cfg.StochKOverboughtShort) {
+			return false
 		}
 	}
 
-	// MACD sign/histo
-	macdSignOKLong, macdSignOKShort := true, true
+	// MACD signe / histogramme
 	if g.cfg.EnableMACDSignGate {
-		m := g.macdLine[idx]
-		s := g.macdSignal[idx]
-		macdSignOKLong = m < 0 && s < 0
-		macdSignOKShort = m > 0 && s > 0
-	}
-	macdHistOKLong, macdHistOKShort := true, true
-	if g.cfg.EnableMACDHistGate {
-		h := g.macdHist[idx]
-		macdHistOKLong = h > 0
-		macdHistOKShort = h < 0
-	}
-
-	// Mise e jour / ouverture des fenetres
-	crossGateEnabled := g.cfg.EnableStochCross || g.cfg.EnableVWMACross || g.cfg.EnableMACDCrossGate
-
-	// Helpers croisement par direction
-	longCrossEvent := (g.cfg.EnableStochCross && stochUp) || (g.cfg.EnableVWMACross && vwUp) || (g.cfg.EnableMACDCrossGate && macdUp)
-	shortCrossEvent := (g.cfg.EnableStochCross && stochDown) || (g.cfg.EnableVWMACross && vwDown) || (g.cfg.EnableMACDCrossGate && macdDown)
-
-	// Ouverture fenetre LONG
-	if g.cfg.EnableOpenLong && !f.longW.active {
-		shouldOpen := false
-		switch f.cfg.OpenMode {
-		case WindowOpenModeAuto:
-			if crossGateEnabled {
-				shouldOpen = longCrossEvent
-			} else {
-				shouldOpen = barOKLong
-			}
-		case WindowOpenModeCross:
-			if crossGateEnabled {
-				shouldOpen = longCrossEvent
-			}
-		case WindowOpenModeBar:
-			shouldOpen = barOKLong
-		case WindowOpenModeStochCross:
-			if g.cfg.EnableStochCross {
-				shouldOpen = stochUp
-			}
-		case WindowOpenModeVWMACross:
-			if g.cfg.EnableVWMACross {
-				shouldOpen = vwUp
-			}
-		case WindowOpenModeMACDCross:
-			if g.cfg.EnableMACDCrossGate {
-				shouldOpen = macdUp
-			}
-		}
-		if shouldOpen {
-			f.longW.active = true
-			f.longW.dir = signals.SignalTypeLong
-			f.longW.startIdx = idx
-			f.longW.deadline = idx + f.cfg.WindowBars - 1
-			// Initialiser les derniers crosses
-			if g.cfg.EnableStochCross {
-				if stochUp {
-					f.longW.lastStochCross = 1
-				} else if stochDown {
-					f.longW.lastStochCross = -1
-				}
-			}
-			if g.cfg.EnableVWMACross {
-				if vwUp {
-					f.longW.lastVWMACross = 1
-				} else if vwDown {
-					f.longW.lastVWMACross = -1
-				}
-			}
-			if g.cfg.EnableMACDCrossGate {
-				if macdUp {
-					f.longW.lastMACDCross = 1
-				} else if macdDown {
-					f.longW.lastMACDCross = -1
-				}
-			}
+		m, s := g.macdLine[i], g.macdSignal[i]
+		if long && !(m < 0 && s < 0) || !long && !(m > 0 && s > 0) {
+			return false
 		}
 	}
-
-	// Ouverture fenetre SHORT
-	if g.cfg.EnableOpenShort && !f.shortW.active {
-		shouldOpen := false
-		switch f.cfg.OpenMode {
-		case WindowOpenModeAuto:
-			if crossGateEnabled {
-				shouldOpen = shortCrossEvent
-			} else {
-				shouldOpen = barOKShort
-			}
-		case WindowOpenModeCross:
-			if crossGateEnabled {
-				shouldOpen = shortCrossEvent
-			}
-		case WindowOpenModeBar:
-			shouldOpen = barOKShort
-		case WindowOpenModeStochCross:
-			if g.cfg.EnableStochCross {
-				shouldOpen = stochDown
-			}
-		case WindowOpenModeVWMACross:
-			if g.cfg.EnableVWMACross {
-				shouldOpen = vwDown
-			}
-		case WindowOpenModeMACDCross:
-			if g.cfg.EnableMACDCrossGate {
-				shouldOpen = macdDown
-			}
-		}
-		if shouldOpen {
-			f.shortW.active = true
-			f.shortW.dir = signals.SignalTypeShort
-			f.shortW.startIdx = idx
-			f.shortW.deadline = idx + f.cfg.WindowBars - 1
-			if g.cfg.EnableStochCross {
-				if stochUp {
-					f.shortW.lastStochCross = 1
-				} else if stochDown {
-					f.shortW.lastStochCross = -1
-				}
-			}
-			if g.cfg.EnableVWMACross {
-				if vwUp {
-					f.shortW.lastVWMACross = 1
-				} else if vwDown {
-					f.shortW.lastVWMACross = -1
-				}
-			}
-			if g.cfg.EnableMACDCrossGate {
-				if macdUp {
-					f.shortW.lastMACDCross = 1
-				} else if macdDown {
-					f.shortW.lastMACDCross = -1
-				}
-			}
-		}
-	}
-
-	// Expiration des fenetres
-	if f.longW.active && idx > f.longW.deadline {
-		f.longW = windowState{}
-	}
-	if f.shortW.active && idx > f.shortW.deadline {
-		f.shortW = windowState{}
-	}
-
-	// Mise e jour des derniers crosses dans les fenetres actives
-	if f.longW.active {
-		if g.cfg.EnableStochCross {
-			if stochUp {
-				f.longW.lastStochCross = 1
-			} else if stochDown {
-				f.longW.lastStochCross = -1
-			}
-		}
-		if g.cfg.EnableVWMACross {
-			if vwUp {
-				f.longW.lastVWMACross = 1
-			} else if vwDown {
-				f.longW.lastVWMACross = -1
-			}
-		}
-		if g.cfg.EnableMACDCrossGate {
-			if macdUp {
-				f.longW.lastMACDCross = 1
-			} else if macdDown {
-				f.longW.lastMACDCross = -1
-			}
-		}
-	}
-	if f.shortW.active {
-		if g.cfg.EnableStochCross {
-			if stochUp {
-				f.shortW.lastStochCross = 1
-			} else if stochDown {
-				f.shortW.lastStochCross = -1
-			}
-		}
-		if g.cfg.EnableVWMACross {
-			if vwUp {
-				f.shortW.lastVWMACross = 1
-			} else if vwDown {
-				f.shortW.lastVWMACross = -1
-			}
-		}
-		if g.cfg.EnableMACDCrossGate {
-			if macdUp {
-				f.shortW.lastMACDCross = 1
-			} else if macdDown {
-				f.shortW.lastMACDCross = -1
-			}
-		}
-	}
-
-	// Validation des fenetres
-	// On respecte la priorite LONG puis SHORT comme dans EvaluateLast (OPEN LONG avant OPEN SHORT).
-
-	// LONG
-	if f.longW.active && g.cfg.EnableOpenLong {
-		crossOK := true
-		if g.cfg.EnableStochCross {
-			crossOK = crossOK && f.longW.lastStochCross == 1
-		}
-		if g.cfg.EnableVWMACross {
-			crossOK = crossOK && f.longW.lastVWMACross == 1
-		}
-		if g.cfg.EnableMACDCrossGate {
-			crossOK = crossOK && f.longW.lastMACDCross == 1
-		}
-		if crossOK && trendOKLong && stochKOKLong && barOKLong && cciOKLong && mfiOKLong && macdSignOKLong && macdHistOKLong {
-			// Construire signal LONG
-			sig := g.build(signals.SignalActionEntry, signals.SignalTypeLong, klines, idx, b2atr, v2sma, isGreen, isRed)
-			// Une fois un signal valide, on reinitialise les fenetres
-			f.longW = windowState{}
-			f.shortW = windowState{}
-			return sig, nil
-		}
-	}
-
-	// SHORT
-	if f.shortW.active && g.cfg.EnableOpenShort {
-		crossOK := true
-		if g.cfg.EnableStochCross {
-			crossOK = crossOK && f.shortW.lastStochCross == -1
-		}
-		if g.cfg.EnableVWMACross {
-			crossOK = crossOK && f.shortW.lastVWMACross == -1
-		}
-		if g.cfg.EnableMACDCrossGate {
-			crossOK = crossOK && f.shortW.lastMACDCross == -1
-		}
-		if crossOK && trendOKShort && stochKOKShort && barOKShort && cciOKShort && mfiOKShort && macdSignOKShort && macdHistOKShort {
-			sig := g.build(signals.SignalActionEntry, signals.SignalTypeShort, klines, idx, b2atr, v2sma, isGreen, isRed)
-			f.longW = windowState{}
-			f.shortW = windowState{}
-			return sig, nil
+	if g.cfg.EnableMACDHistGate {
+		h := g.macdHist[i]
+		if long && !(h > 0) || !long && !(h < 0) {
+			return false
 		}
 	}
-
-	return nil, nil
+	return true
 }
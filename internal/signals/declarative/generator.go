@@ -0,0 +1,363 @@
+package declarative
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"agent-economique/internal/indicators/expr"
+	"agent-economique/internal/signals"
+)
+
+// defaultConfidence confiance si aucune formule n'est définie ou si elle est inconnue
+const defaultConfidence = 0.5
+
+// metadataField champ de métadonnée évalué à l'émission
+type metadataField struct {
+	name string
+	expr *expr.Expr
+}
+
+// Generator générateur compilé depuis une stratégie déclarative
+//
+// Position unique : une entrée n'est émise qu'à plat (ou en retournement si
+// reverse_on_opposite), une sortie seulement si la position correspondante est ouverte.
+// Les bougies sont traitées une seule fois (suivi par OpenTime), la dernière kline
+// (bougie en cours) est ignorée comme dans les autres générateurs.
+type Generator struct {
+	spec   StrategySpec
+	config signals.GeneratorConfig
+
+	entryLong, entryShort *compiledRule
+	exitLong, exitShort   *compiledRule
+	confidence            *expr.Expr
+	metadata              []metadataField
+	reverseOnOpposite     bool
+	warmup                int
+
+	// État
+	ctx           *expr.Context
+	lastProcessed time.Time
+	consumed      map[string]time.Time // Dernière ancre consommée par règle
+	position      signals.SignalType   // "" = à plat
+	entryPrice    float64
+	entryTime     time.Time
+	metrics       signals.GeneratorMetrics
+}
+
+// Name retourne le nom de la stratégie
+func (g *Generator) Name() string {
+	return g.spec.Name
+}
+
+// Spec retourne la spécification d'origine
+func (g *Generator) Spec() StrategySpec {
+	return g.spec
+}
+
+// Position retourne la position courante ("" si à plat)
+func (g *Generator) Position() signals.SignalType {
+	return g.position
+}
+
+// MinHistorySize historique minimal déduit des expressions (warmup + lookbacks + fenêtres)
+func (g *Generator) MinHistorySize() int {
+	history := 0
+	for _, rule := range g.rules() {
+		if h := rule.history() + rule.window; h > history {
+			history = h
+		}
+	}
+	for _, e := range g.numericExprs() {
+		if h := e.MinHistory(); h > history {
+			history = h
+		}
+	}
+	return history
+}
+
+// rules retourne les règles définies
+func (g *Generator) rules() []*compiledRule {
+	var out []*compiledRule
+	for _, rule := range []*compiledRule{g.entryLong, g.entryShort, g.exitLong, g.exitShort} {
+		if rule != nil {
+			out = append(out, rule)
+		}
+	}
+	return out
+}
+
+// numericExprs expressions évaluées à l'émission (confiance, métadonnées)
+func (g *Generator) numericExprs() []*expr.Expr {
+	var out []*expr.Expr
+	if g.confidence != nil {
+		out = append(out, g.confidence)
+	}
+	for _, field := range g.metadata {
+		out = append(out, field.expr)
+	}
+	return out
+}
+
+// Initialize initialise le générateur et réinitialise l'état
+func (g *Generator) Initialize(config signals.GeneratorConfig) error {
+	if config.HistorySize > 0 && config.HistorySize < g.warmup {
+		return fmt.Errorf("history size %d below strategy %s minimum %d", config.HistorySize, g.spec.Name, g.warmup)
+	}
+	g.config = config
+	g.ctx = nil
+	g.lastProcessed = time.Time{}
+	g.consumed = make(map[string]time.Time)
+	g.position = ""
+	g.metrics = signals.GeneratorMetrics{}
+	return nil
+}
+
+// CalculateIndicators prépare le contexte d'évaluation (indicateurs calculés à la demande)
+func (g *Generator) CalculateIndicators(klines []signals.Kline) error {
+	if len(klines) == 0 {
+		return fmt.Errorf("aucune kline")
+	}
+	g.ctx = expr.NewContext(signals.ToIndicatorKlines(klines))
+	return nil
+}
+
+// DetectSignals évalue les règles sur les bougies clôturées non encore traitées
+func (g *Generator) DetectSignals(klines []signals.Kline) ([]signals.Signal, error) {
+	var out []signals.Signal
+	lastClosedIdx := len(klines) - 2
+	if lastClosedIdx < 0 {
+		return out, nil
+	}
+	if g.consumed == nil {
+		g.consumed = make(map[string]time.Time)
+	}
+	if g.ctx == nil || g.ctx.Len() != len(klines) {
+		if err := g.CalculateIndicators(klines); err != nil {
+			return nil, err
+		}
+	}
+
+	start := lastClosedIdx + 1
+	for start > 0 && klines[start-1].OpenTime.After(g.lastProcessed) {
+		start--
+	}
+	if start < g.warmup-1 {
+		start = g.warmup - 1
+	}
+
+	for j := start; j <= lastClosedIdx; j++ {
+		out = append(out, g.processBar(klines, j)...)
+	}
+	if lastClosedIdx >= 0 && klines[lastClosedIdx].OpenTime.After(g.lastProcessed) {
+		g.lastProcessed = klines[lastClosedIdx].OpenTime
+	}
+
+	for _, sig := range out {
+		g.record(sig)
+	}
+	return out, nil
+}
+
+// processBar évalue sorties puis entrées sur la bougie j
+func (g *Generator) processBar(klines []signals.Kline, j int) []signals.Signal {
+	var out []signals.Signal
+
+	if g.position != "" {
+		exit := g.exitLong
+		if g.position == signals.SignalTypeShort {
+			exit = g.exitShort
+		}
+		if exit != nil {
+			if anchor, ok := g.fires(exit, klines, j, g.entryTime); ok {
+				out = append(out, g.exitSignal(klines, j, exit, anchor, "rule"))
+			}
+		}
+	}
+
+	longAnchor, longOK := g.firesEntry(g.entryLong, klines, j)
+	shortAnchor, shortOK := g.firesEntry(g.entryShort, klines, j)
+	if longOK && shortOK {
+		// Signaux contradictoires sur la même bougie : aucune entrée
+		g.consume(g.entryLong, klines[longAnchor].OpenTime)
+		g.consume(g.entryShort, klines[shortAnchor].OpenTime)
+		return out
+	}
+
+	rule, anchor, side := g.entryLong, longAnchor, signals.SignalTypeLong
+	if shortOK {
+		rule, anchor, side = g.entryShort, shortAnchor, signals.SignalTypeShort
+	} else if !longOK {
+		return out
+	}
+	g.consume(rule, klines[anchor].OpenTime)
+
+	switch g.position {
+	case side:
+		return out
+	case "":
+	default:
+		if !g.reverseOnOpposite {
+			return out
+		}
+		out = append(out, g.exitSignal(klines, j, rule, anchor, "reverse"))
+	}
+
+	k := klines[j]
+	g.position, g.entryPrice, g.entryTime = side, k.Close, k.OpenTime
+	out = append(out, signals.Signal{
+		Timestamp:  k.OpenTime,
+		Action:     signals.SignalActionEntry,
+		Type:       side,
+		Price:      k.Close,
+		Confidence: g.confidenceAt(rule, j),
+		Metadata:   g.metadataAt(klines, rule, anchor, j),
+	})
+	return out
+}
+
+// firesEntry évalue une règle d'entrée optionnelle
+func (g *Generator) firesEntry(rule *compiledRule, klines []signals.Kline, j int) (int, bool) {
+	if rule == nil {
+		return 0, false
+	}
+	return g.fires(rule, klines, j, time.Time{})
+}
+
+// fires cherche la plus ancienne ancre valide pour émettre à la bougie j
+//
+// Ancre t dans [j-window, j], postérieure à after et à la dernière ancre consommée ;
+// chaque confirmation vraie au moins une fois dans [t-pre_window, j] ; chaque
+// condition require vraie en j. N'utilise que des bougies <= j (pas de look-ahead).
+func (g *Generator) fires(rule *compiledRule, klines []signals.Kline, j int, after time.Time) (int, bool) {
+	for _, req := range rule.require {
+		if !req.True(g.ctx, j) {
+			return 0, false
+		}
+	}
+
+	consumed := g.consumed[rule.name]
+	for t := maxInt(0, j-rule.window); t <= j; t++ {
+		anchorTime := klines[t].OpenTime
+		if !anchorTime.After(consumed) || !anchorTime.After(after) || !rule.trigger.True(g.ctx, t) {
+			continue
+		}
+		if g.confirmed(rule, maxInt(0, t-rule.preWindow), j) {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// confirmed vérifie que chaque confirmation est vraie au moins une fois dans [from, to]
+func (g *Generator) confirmed(rule *compiledRule, from, to int) bool {
+	for _, c := range rule.confirm {
+		seen := false
+		for i := from; i <= to && !seen; i++ {
+			seen = c.True(g.ctx, i)
+		}
+		if !seen {
+			return false
+		}
+	}
+	return true
+}
+
+// consume marque une ancre comme utilisée par la règle
+func (g *Generator) consume(rule *compiledRule, anchorTime time.Time) {
+	if anchorTime.After(g.consumed[rule.name]) {
+		g.consumed[rule.name] = anchorTime
+	}
+}
+
+// exitSignal clôture la position courante
+func (g *Generator) exitSignal(klines []signals.Kline, j int, rule *compiledRule, anchor int, reason string) signals.Signal {
+	if reason == "rule" {
+		g.consume(rule, klines[anchor].OpenTime)
+	}
+	k := klines[j]
+	entryPrice, entryTime := g.entryPrice, g.entryTime
+	metadata := g.metadataAt(klines, rule, anchor, j)
+	metadata["exit_reason"] = reason
+
+	sig := signals.Signal{
+		Timestamp:  k.OpenTime,
+		Action:     signals.SignalActionExit,
+		Type:       g.position,
+		Price:      k.Close,
+		Confidence: g.confidenceAt(rule, j),
+		Metadata:   metadata,
+		EntryPrice: &entryPrice,
+		EntryTime:  &entryTime,
+	}
+	g.position = ""
+	return sig
+}
+
+// confidenceAt évalue la formule de confiance (règle puis globale), bornée à [0,1]
+func (g *Generator) confidenceAt(rule *compiledRule, j int) float64 {
+	formula := g.confidence
+	if rule.confidence != nil {
+		formula = rule.confidence
+	}
+	if formula == nil {
+		return defaultConfidence
+	}
+	value := formula.Value(g.ctx, j)
+	if math.IsNaN(value) {
+		return defaultConfidence
+	}
+	return math.Max(0, math.Min(1, value))
+}
+
+// metadataAt construit les métadonnées du signal (valeurs inconnues = nil)
+func (g *Generator) metadataAt(klines []signals.Kline, rule *compiledRule, anchor, j int) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"generator":        g.spec.Name,
+		"rule":             rule.name,
+		"anchor_time":      klines[anchor].OpenTime,
+		"bars_from_anchor": j - anchor,
+	}
+	for _, field := range g.metadata {
+		value := field.expr.Value(g.ctx, j)
+		switch {
+		case math.IsNaN(value):
+			metadata[field.name] = nil
+		case field.expr.IsCondition():
+			metadata[field.name] = value == 1
+		default:
+			metadata[field.name] = value
+		}
+	}
+	return metadata
+}
+
+// record met à jour les métriques
+func (g *Generator) record(sig signals.Signal) {
+	m := &g.metrics
+	m.TotalSignals++
+	if sig.Action == signals.SignalActionEntry {
+		m.EntrySignals++
+		if sig.Type == signals.SignalTypeLong {
+			m.LongSignals++
+		} else {
+			m.ShortSignals++
+		}
+	} else {
+		m.ExitSignals++
+	}
+	m.AvgConfidence += (sig.Confidence - m.AvgConfidence) / float64(m.TotalSignals)
+	m.LastSignalTime = sig.Timestamp
+}
+
+// GetMetrics retourne les métriques
+func (g *Generator) GetMetrics() signals.GeneratorMetrics {
+	return g.metrics
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,29 @@
+package declarative
+
+import (
+	"strings"
+
+	"agent-economique/internal/signals"
+)
+
+// Config paramètres du générateur déclaratif dans le registre : fichier de stratégie YAML
+type Config struct {
+	File string `yaml:"file"`
+}
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "declarative",
+		Description:   "Stratégie YAML déclarative (règles en expressions d'indicateurs)",
+		DefaultConfig: func() interface{} { return &Config{} },
+		Validate: func(config interface{}) error {
+			if strings.TrimSpace(config.(*Config).File) == "" {
+				return signals.FieldErrorf("file", "required (path to the strategy YAML)")
+			}
+			return nil
+		},
+		New: func(config interface{}) (signals.Generator, error) {
+			return LoadFile(config.(*Config).File)
+		},
+	})
+}
@@ -0,0 +1,254 @@
+// Package declarative compile un fichier de stratégie YAML (règles en expressions
+// internal/indicators/expr) en un signals.Generator
+package declarative
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"agent-economique/internal/indicators/expr"
+)
+
+// Expression source d'une expression avec sa ligne YAML (pour les erreurs)
+type Expression struct {
+	Source string
+	Line   int
+}
+
+// UnmarshalYAML lit une expression scalaire en conservant sa ligne
+func (e *Expression) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expression must be a string", value.Line)
+	}
+	e.Source = value.Value
+	e.Line = value.Line
+	return nil
+}
+
+// RuleSpec règle d'entrée ou de sortie
+//
+// Le trigger ancre une fenêtre [ancre - PreWindow, ancre + Window] ; chaque condition
+// Confirm doit être vraie au moins une fois dans la fenêtre (jusqu'à la bougie courante),
+// chaque condition Require doit être vraie sur la bougie d'émission. Le signal est émis
+// à la première bougie de [ancre, ancre + Window] où tout est satisfait.
+type RuleSpec struct {
+	Trigger    Expression   `yaml:"trigger"`
+	Confirm    []Expression `yaml:"confirm"`
+	Require    []Expression `yaml:"require"`
+	Window     int          `yaml:"window"`
+	PreWindow  int          `yaml:"pre_window"`
+	Confidence *Expression  `yaml:"confidence"` // Remplace la formule globale
+}
+
+// StrategySpec fichier de stratégie déclarative
+type StrategySpec struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	EntryLong  *RuleSpec `yaml:"entry_long"`
+	EntryShort *RuleSpec `yaml:"entry_short"`
+	ExitLong   *RuleSpec `yaml:"exit_long"`
+	ExitShort  *RuleSpec `yaml:"exit_short"`
+
+	// Confidence formule numérique évaluée à l'émission (bornée à [0,1], défaut 0.5)
+	Confidence *Expression `yaml:"confidence"`
+	// Metadata champs enregistrés dans Signal.Metadata (nom -> expression)
+	Metadata map[string]Expression `yaml:"metadata"`
+
+	// ReverseOnOpposite ferme et retourne la position sur une entrée opposée (défaut true)
+	ReverseOnOpposite *bool `yaml:"reverse_on_opposite"`
+	// Warmup bougies ignorées au démarrage (défaut : historique minimal des expressions)
+	Warmup int `yaml:"warmup"`
+}
+
+// LoadFile lit et compile un fichier de stratégie
+func LoadFile(path string) (*Generator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read strategy file %s: %w", path, err)
+	}
+	g, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return g, nil
+}
+
+// Parse décode et compile une stratégie YAML
+func Parse(data []byte) (*Generator, error) {
+	var spec StrategySpec
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to parse strategy YAML: %w", err)
+	}
+	return Compile(spec)
+}
+
+// compiledRule règle compilée
+type compiledRule struct {
+	name       string
+	trigger    *expr.Expr
+	confirm    []*expr.Expr
+	require    []*expr.Expr
+	window     int
+	preWindow  int
+	confidence *expr.Expr
+}
+
+// history bougies nécessaires à la règle
+func (r *compiledRule) history() int {
+	history := r.trigger.MinHistory()
+	for _, list := range [][]*expr.Expr{r.confirm, r.require} {
+		for _, e := range list {
+			if h := e.MinHistory(); h > history {
+				history = h
+			}
+		}
+	}
+	if r.confidence != nil && r.confidence.MinHistory() > history {
+		history = r.confidence.MinHistory()
+	}
+	return history
+}
+
+// Compile valide une spécification et construit le générateur
+func Compile(spec StrategySpec) (*Generator, error) {
+	if strings.TrimSpace(spec.Name) == "" {
+		return nil, fmt.Errorf("name: required")
+	}
+	if spec.EntryLong == nil && spec.EntryShort == nil {
+		return nil, fmt.Errorf("entry_long/entry_short: at least one entry rule is required")
+	}
+	if spec.Warmup < 0 {
+		return nil, fmt.Errorf("warmup: must be >= 0")
+	}
+
+	g := &Generator{
+		spec:              spec,
+		reverseOnOpposite: spec.ReverseOnOpposite == nil || *spec.ReverseOnOpposite,
+	}
+
+	var err error
+	rules := []struct {
+		name string
+		spec *RuleSpec
+		dst  **compiledRule
+	}{
+		{"entry_long", spec.EntryLong, &g.entryLong},
+		{"entry_short", spec.EntryShort, &g.entryShort},
+		{"exit_long", spec.ExitLong, &g.exitLong},
+		{"exit_short", spec.ExitShort, &g.exitShort},
+	}
+	for _, r := range rules {
+		if r.spec == nil {
+			continue
+		}
+		if *r.dst, err = compileRule(r.name, r.spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.Confidence != nil {
+		if g.confidence, err = compileNumber("confidence", *spec.Confidence); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(spec.Metadata))
+	for name := range spec.Metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e, err := compileExpression("metadata."+name, spec.Metadata[name])
+		if err != nil {
+			return nil, err
+		}
+		g.metadata = append(g.metadata, metadataField{name: name, expr: e})
+	}
+
+	g.warmup = spec.Warmup
+	if g.warmup == 0 {
+		g.warmup = g.MinHistorySize()
+	}
+	return g, nil
+}
+
+// compileRule compile une règle et ses conditions
+func compileRule(name string, spec *RuleSpec) (*compiledRule, error) {
+	if spec.Trigger.Source == "" {
+		return nil, fmt.Errorf("%s.trigger: required", name)
+	}
+	if spec.Window < 0 || spec.PreWindow < 0 {
+		return nil, fmt.Errorf("%s: window and pre_window must be >= 0", name)
+	}
+
+	rule := &compiledRule{name: name, window: spec.Window, preWindow: spec.PreWindow}
+	var err error
+	if rule.trigger, err = compileCondition(name+".trigger", spec.Trigger); err != nil {
+		return nil, err
+	}
+	for i, e := range spec.Confirm {
+		c, err := compileCondition(fmt.Sprintf("%s.confirm[%d]", name, i), e)
+		if err != nil {
+			return nil, err
+		}
+		rule.confirm = append(rule.confirm, c)
+	}
+	for i, e := range spec.Require {
+		c, err := compileCondition(fmt.Sprintf("%s.require[%d]", name, i), e)
+		if err != nil {
+			return nil, err
+		}
+		rule.require = append(rule.require, c)
+	}
+	if spec.Confidence != nil {
+		if rule.confidence, err = compileNumber(name+".confidence", *spec.Confidence); err != nil {
+			return nil, err
+		}
+	}
+	return rule, nil
+}
+
+// fieldError préfixe une erreur d'expression avec la ligne YAML et le champ
+func fieldError(field string, e Expression, err error) error {
+	if e.Line > 0 {
+		return fmt.Errorf("line %d: %s: %w", e.Line, field, err)
+	}
+	return fmt.Errorf("%s: %w", field, err)
+}
+
+// compileExpression compile une expression quelconque
+func compileExpression(field string, e Expression) (*expr.Expr, error) {
+	compiled, err := expr.Compile(e.Source)
+	if err != nil {
+		return nil, fieldError(field, e, err)
+	}
+	return compiled, nil
+}
+
+// compileCondition compile une expression booléenne
+func compileCondition(field string, e Expression) (*expr.Expr, error) {
+	compiled, err := expr.CompileCondition(e.Source)
+	if err != nil {
+		return nil, fieldError(field, e, err)
+	}
+	return compiled, nil
+}
+
+// compileNumber compile une expression numérique
+func compileNumber(field string, e Expression) (*expr.Expr, error) {
+	compiled, err := compileExpression(field, e)
+	if err != nil {
+		return nil, err
+	}
+	if compiled.IsCondition() {
+		return nil, fieldError(field, e, fmt.Errorf("expression must be a number, got condition"))
+	}
+	return compiled, nil
+}
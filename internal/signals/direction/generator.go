@@ -39,13 +39,13 @@ type Interval struct {
 
 // Config configuration spécifique Direction
 type Config struct {
-	VWMAPeriod          int
-	SlopePeriod         int
-	KConfirmation       int
-	UseDynamicThreshold bool
-	ATRPeriod           int
-	ATRCoefficient      float64
-	FixedThreshold      float64
+	VWMAPeriod          int     `yaml:"vwma_period"`
+	SlopePeriod         int     `yaml:"slope_period"`
+	KConfirmation       int     `yaml:"k_confirmation"`
+	UseDynamicThreshold bool    `yaml:"use_dynamic_threshold"`
+	ATRPeriod           int     `yaml:"atr_period"`
+	ATRCoefficient      float64 `yaml:"atr_coefficient"`
+	FixedThreshold      float64 `yaml:"fixed_threshold"`
 }
 
 // NewDirectionGenerator crée un nouveau générateur Direction
@@ -73,6 +73,24 @@ func (g *DirectionGenerator) Initialize(config signals.GeneratorConfig) error {
 	return nil
 }
 
+// MinHistorySize historique minimal : VWMA (et ATR) stabilisés, puis pente et confirmations
+// avant la première bougie fermée analysée (+ bougie en cours)
+func (g *DirectionGenerator) MinHistorySize() int {
+	refs := []string{fmt.Sprintf("vwma(%d)", g.vwmaPeriod)}
+	if g.useDynamicThreshold {
+		refs = append(refs, fmt.Sprintf("atr(%d)", g.atrPeriod))
+	}
+	size, err := signals.IndicatorHistory(refs...)
+	if err != nil {
+		return 0
+	}
+	size += g.slopePeriod + g.kConfirmation
+	if warmup := g.vwmaPeriod + g.slopePeriod + g.kConfirmation + 2; warmup > size {
+		size = warmup
+	}
+	return size
+}
+
 // CalculateIndicators calcule VWMA6 et ATR
 func (g *DirectionGenerator) CalculateIndicators(klines []signals.Kline) error {
 	if len(klines) < g.vwmaPeriod {
@@ -0,0 +1,49 @@
+package direction
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "direction",
+		Description:   "Pente VWMA confirmée sur K bougies, seuil fixe ou dynamique (ATR)",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return NewDirectionGenerator(*config.(*Config)), nil
+		},
+	})
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/direction_generator_demo)
+func DefaultConfig() Config {
+	return Config{
+		VWMAPeriod:          3,
+		SlopePeriod:         2,
+		KConfirmation:       1,
+		UseDynamicThreshold: true,
+		ATRPeriod:           8,
+		ATRCoefficient:      0.1,
+		FixedThreshold:      0.1,
+	}
+}
+
+// validateConfig vérifie périodes et seuils (erreurs nommant le champ YAML)
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("vwma_period", c.VWMAPeriod),
+		signals.CheckPeriod("slope_period", c.SlopePeriod),
+		signals.CheckPeriod("k_confirmation", c.KConfirmation),
+	)
+	if err != nil {
+		return err
+	}
+	if c.UseDynamicThreshold {
+		return signals.FirstError(
+			signals.CheckPeriod("atr_period", c.ATRPeriod),
+			signals.CheckRange("atr_coefficient", c.ATRCoefficient, 0, 100),
+		)
+	}
+	return signals.CheckRange("fixed_threshold", c.FixedThreshold, 0, 100)
+}
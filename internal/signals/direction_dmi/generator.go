@@ -53,6 +53,8 @@ type DirectionDMIGenerator struct {
 
 	// État interne - Positions
 	lastProcessedIdx int
+	lastProcessed    signals.ResumePoint  // Dernière bougie traitée (snapshot)
+	resume           *signals.ResumePoint // Reprise en attente après Restore
 	openPositions    map[int]*OpenPosition
 	nextPositionID   int
 	metrics          signals.GeneratorMetrics
@@ -74,32 +76,32 @@ type OpenPosition struct {
 // Config configuration spécifique Direction+DMI
 type Config struct {
 	// Paramètres VWMA (Direction)
-	VWMAPeriod          int
-	SlopePeriod         int
-	KConfirmation       int
-	UseDynamicThreshold bool
-	ATRPeriod           int
-	ATRCoefficient      float64
-	FixedThreshold      float64
+	VWMAPeriod          int     `yaml:"vwma_period"`
+	SlopePeriod         int     `yaml:"slope_period"`
+	KConfirmation       int     `yaml:"k_confirmation"`
+	UseDynamicThreshold bool    `yaml:"use_dynamic_threshold"`
+	ATRPeriod           int     `yaml:"atr_period"`
+	ATRCoefficient      float64 `yaml:"atr_coefficient"`
+	FixedThreshold      float64 `yaml:"fixed_threshold"`
 
 	// Paramètres DMI
-	DMIPeriod            int
-	DMISmooth            int
-	GammaGapDI           float64
-	GammaGapDX           float64
-	WindowGammaValidate  int
-	WindowMatching       int
+	DMIPeriod           int     `yaml:"dmi_period"`
+	DMISmooth           int     `yaml:"dmi_smooth"`
+	GammaGapDI          float64 `yaml:"gamma_gap_di"`
+	GammaGapDX          float64 `yaml:"gamma_gap_dx"`
+	WindowGammaValidate int     `yaml:"window_gamma_validate"`
+	WindowMatching      int     `yaml:"window_matching"`
 
 	// Flags validation optionnelle (nouveaux)
-	RequireDICrossover   bool    // Exiger croisement DI obligatoire
-	RequireDXValidation  bool    // Exiger validation DX/ADX
-	RequireGapValidation bool    // Exiger validation gaps
+	RequireDICrossover   bool `yaml:"require_di_crossover"`   // Exiger croisement DI obligatoire
+	RequireDXValidation  bool `yaml:"require_dx_validation"`  // Exiger validation DX/ADX
+	RequireGapValidation bool `yaml:"require_gap_validation"` // Exiger validation gaps
 
 	// Flags d'activation
-	EnableEntryTrend        bool
-	EnableEntryCounterTrend bool
-	EnableExitTrend         bool
-	EnableExitCounterTrend  bool
+	EnableEntryTrend        bool `yaml:"enable_entry_trend"`
+	EnableEntryCounterTrend bool `yaml:"enable_entry_counter_trend"`
+	EnableExitTrend         bool `yaml:"enable_exit_trend"`
+	EnableExitCounterTrend  bool `yaml:"enable_exit_counter_trend"`
 }
 
 // SignalVWMA représente un signal VWMA détecté dans une bougie
@@ -257,6 +259,23 @@ func (g *DirectionDMIGenerator) Initialize() error {
 	return nil
 }
 
+// MinHistorySize historique minimal : VWMA, ATR et ADX stabilisés, plus pente, confirmations
+// et fenêtres de matching / validation des gaps remontant dans le passé
+func (g *DirectionDMIGenerator) MinHistorySize() int {
+	refs := []string{
+		fmt.Sprintf("vwma(%d)", g.vwmaPeriod),
+		fmt.Sprintf("dmi(%d,%d).adx", g.dmiPeriod, g.dmiPeriod),
+	}
+	if g.useDynamicThreshold {
+		refs = append(refs, fmt.Sprintf("atr(%d)", g.atrPeriod))
+	}
+	size, err := signals.IndicatorHistory(refs...)
+	if err != nil {
+		return 0
+	}
+	return size + g.slopePeriod + g.kConfirmation + g.windowMatching + g.windowGammaValidate
+}
+
 // CalculateIndicators calcule VWMA, pente, ATR, DMI, DX, ADX
 func (g *DirectionDMIGenerator) CalculateIndicators(klines []signals.Kline) error {
 	if len(klines) < g.vwmaPeriod {
@@ -322,6 +341,7 @@ func abs(x float64) float64 {
 // DetectSignals détecte les signaux selon spécification avec fenêtre de matching
 func (g *DirectionDMIGenerator) DetectSignals(klines []signals.Kline) ([]signals.Signal, error) {
 	var newSignals []signals.Signal
+	g.applyResume(klines)
 
 	// Déterminer la plage à traiter
 	startIdx := g.lastProcessedIdx + 1
@@ -373,6 +393,7 @@ func (g *DirectionDMIGenerator) DetectSignals(klines []signals.Kline) ([]signals
 	// Mettre à jour métriques
 	g.metrics.TotalSignals += len(newSignals)
 	g.lastProcessedIdx = lastClosedIdx
+	g.lastProcessed = signals.NewResumePoint(klines, lastClosedIdx)
 
 	return newSignals, nil
 }
@@ -0,0 +1,74 @@
+package direction_dmi
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "direction_dmi",
+		Description:   "Pente VWMA + croisement DI + DX/ADX dans une fenêtre de matching, entrées/sorties tendance et contre-tendance",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return registryGenerator{NewDirectionDMIGenerator(signals.GeneratorConfig{}, *config.(*Config))}, nil
+		},
+	})
+}
+
+// registryGenerator adapte DirectionDMIGenerator (Initialize sans argument) à signals.Generator
+type registryGenerator struct {
+	*DirectionDMIGenerator
+}
+
+// Initialize enregistre la config commune puis valide les paramètres
+func (g registryGenerator) Initialize(config signals.GeneratorConfig) error {
+	g.config = config
+	return g.DirectionDMIGenerator.Initialize()
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/direction_dmi_generator_demo)
+func DefaultConfig() Config {
+	return Config{
+		VWMAPeriod:              20,
+		SlopePeriod:             6,
+		KConfirmation:           2,
+		UseDynamicThreshold:     true,
+		ATRPeriod:               8,
+		ATRCoefficient:          0.25,
+		FixedThreshold:          0.1,
+		DMIPeriod:               14,
+		DMISmooth:               14,
+		GammaGapDI:              2.0,
+		GammaGapDX:              2.0,
+		WindowGammaValidate:     5,
+		WindowMatching:          5,
+		EnableEntryTrend:        true,
+		EnableEntryCounterTrend: true,
+		EnableExitTrend:         true,
+		EnableExitCounterTrend:  true,
+	}
+}
+
+// validateConfig reprend les contrôles d'Initialize en nommant le champ YAML
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("vwma_period", c.VWMAPeriod),
+		signals.CheckPeriod("slope_period", c.SlopePeriod),
+		signals.CheckPeriod("k_confirmation", c.KConfirmation),
+		signals.CheckPeriod("atr_period", c.ATRPeriod),
+		signals.CheckPeriod("dmi_period", c.DMIPeriod),
+		signals.CheckPeriod("dmi_smooth", c.DMISmooth),
+		signals.CheckPeriod("window_matching", c.WindowMatching),
+	)
+	if err != nil {
+		return err
+	}
+	if c.WindowGammaValidate < 0 {
+		return signals.FieldErrorf("window_gamma_validate", "must be >= 0, got %d", c.WindowGammaValidate)
+	}
+	if !c.EnableExitTrend && !c.EnableExitCounterTrend {
+		return signals.FieldErrorf("enable_exit_trend", "at least one of enable_exit_trend / enable_exit_counter_trend must be true")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package direction_dmi
+
+import (
+	"sort"
+
+	"agent-economique/internal/signals"
+)
+
+// snapshotVersion version du format d'état
+const snapshotVersion = 1
+
+// snapshotState état persisté : positions ouvertes et dernière bougie traitée
+type snapshotState struct {
+	Resume         signals.ResumePoint      `json:"resume"`
+	Positions      []OpenPosition           `json:"positions"`
+	NextPositionID int                      `json:"next_position_id"`
+	Metrics        signals.GeneratorMetrics `json:"metrics"`
+}
+
+// Snapshot encode l'état du générateur (JSON versionné)
+func (g *DirectionDMIGenerator) Snapshot() ([]byte, error) {
+	state := snapshotState{Resume: g.lastProcessed, NextPositionID: g.nextPositionID, Metrics: g.metrics}
+	if g.resume != nil {
+		state.Resume = *g.resume
+	}
+	for _, pos := range g.openPositions {
+		state.Positions = append(state.Positions, *pos)
+	}
+	sort.Slice(state.Positions, func(a, b int) bool { return state.Positions[a].ID < state.Positions[b].ID })
+	return signals.EncodeSnapshot("direction_dmi", snapshotVersion, state)
+}
+
+// Restore recharge l'état ; les index sont recalés sur la prochaine fenêtre de klines
+func (g *DirectionDMIGenerator) Restore(data []byte) error {
+	var state snapshotState
+	if _, err := signals.DecodeSnapshot(data, "direction_dmi", snapshotVersion, &state); err != nil {
+		return err
+	}
+	g.openPositions = make(map[int]*OpenPosition, len(state.Positions))
+	for i := range state.Positions {
+		pos := state.Positions[i]
+		g.openPositions[pos.ID] = &pos
+	}
+	g.nextPositionID = state.NextPositionID
+	g.metrics = state.Metrics
+	g.lastProcessed = state.Resume
+	g.resume = &state.Resume
+	return nil
+}
+
+// applyResume recale lastProcessedIdx et les index d'entrée sur la fenêtre courante après un Restore
+func (g *DirectionDMIGenerator) applyResume(klines []signals.Kline) {
+	if g.resume == nil {
+		return
+	}
+	shift := g.resume.Shift(klines)
+	g.lastProcessedIdx = g.resume.Index + shift
+	for _, pos := range g.openPositions {
+		pos.EntryIndex += shift
+	}
+	g.resume = nil
+}
@@ -0,0 +1,115 @@
+package signals
+
+import (
+	"fmt"
+
+	"agent-economique/internal/indicators"
+)
+
+// Modes du filtre de divergence
+const (
+	DivergenceModeRequire = "require" // ENTRY seulement après une divergence dans le sens de l'entrée
+	DivergenceModeBlock   = "block"   // ENTRY bloquée après une divergence de sens opposé
+)
+
+// DivergenceFilterConfig configuration du filtre de divergence prix / oscillateur
+type DivergenceFilterConfig struct {
+	Oscillator   string `yaml:"oscillator"`     // Référence du registre, ex: "mfi(14)", "cci(20)", "stoch().k", "macd().hist", "kvo().kvo"
+	PivotLeft    int    `yaml:"pivot_left"`     // Défaut 5
+	PivotRight   int    `yaml:"pivot_right"`    // Défaut 5 (délai de confirmation)
+	RangeMin     int    `yaml:"range_min"`      // Défaut 5
+	RangeMax     int    `yaml:"range_max"`      // Défaut 60
+	PivotOnPrice bool   `yaml:"pivot_on_price"` // Pivots sur le prix au lieu de l'oscillateur
+	Hidden       bool   `yaml:"hidden"`         // Prend aussi en compte les divergences cachées
+	MaxAge       int    `yaml:"max_age"`        // Bougies max entre confirmation et entrée (défaut 10)
+	Mode         string `yaml:"mode"`           // "require" (défaut) ou "block"
+}
+
+// DivergenceFilter décore un générateur et filtre les entrées selon les divergences
+// confirmées à la bougie du signal (pivot + PivotRight bougies clôturées, pas de look-ahead).
+// La sortie correspondant à une entrée bloquée est également retirée.
+type DivergenceFilter struct {
+	entryFilter
+	config   DivergenceFilterConfig
+	ref      *indicators.IndicatorRef
+	detector *indicators.DivergenceDetector
+
+	divergences []indicators.Divergence
+}
+
+// NewDivergenceFilter crée un filtre de divergence autour d'un générateur
+func NewDivergenceFilter(inner Generator, config DivergenceFilterConfig) (*DivergenceFilter, error) {
+	if config.Oscillator == "" {
+		return nil, fmt.Errorf("oscillator: required")
+	}
+	ref, err := indicators.ParseIndicatorRef(config.Oscillator)
+	if err != nil {
+		return nil, fmt.Errorf("oscillator: %w", err)
+	}
+	if config.Mode == "" {
+		config.Mode = DivergenceModeRequire
+	}
+	if config.Mode != DivergenceModeRequire && config.Mode != DivergenceModeBlock {
+		return nil, fmt.Errorf("mode: unknown mode %q (require, block)", config.Mode)
+	}
+	if config.MaxAge < 0 {
+		return nil, fmt.Errorf("max_age: must be >= 0")
+	}
+	if config.MaxAge == 0 {
+		config.MaxAge = 10
+	}
+
+	detector := indicators.NewDivergenceDetector(indicators.DivergenceConfig{
+		PivotLeft:    config.PivotLeft,
+		PivotRight:   config.PivotRight,
+		RangeMin:     config.RangeMin,
+		RangeMax:     config.RangeMax,
+		PivotOnPrice: config.PivotOnPrice,
+		Hidden:       config.Hidden,
+	})
+	f := &DivergenceFilter{config: config, ref: ref, detector: detector}
+	f.entryFilter = newEntryFilter(inner, "divergence_filter", f.allows)
+	f.entryFilter.history = f.history
+	return f, nil
+}
+
+// history historique de l'oscillateur stabilisé (+ pivots)
+func (f *DivergenceFilter) history() int {
+	cfg := f.detector.Config()
+	return f.ref.StableWarmup() + cfg.RangeMax + cfg.PivotLeft + cfg.PivotRight
+}
+
+// CalculateIndicators délègue puis calcule oscillateur et divergences
+func (f *DivergenceFilter) CalculateIndicators(klines []Kline) error {
+	if err := f.inner.CalculateIndicators(klines); err != nil {
+		return err
+	}
+	ik := ToIndicatorKlines(klines)
+	f.divergences = f.detector.DetectFromKlines(ik, f.ref.Compute(ik))
+	return nil
+}
+
+// Divergences retourne les divergences détectées au dernier calcul
+func (f *DivergenceFilter) Divergences() []indicators.Divergence {
+	return f.divergences
+}
+
+// allows applique le mode du filtre à l'entrée de la bougie i et l'étiquette avec la
+// divergence exigée
+func (f *DivergenceFilter) allows(sig *Signal, i int) bool {
+	if i < 0 {
+		return f.config.Mode == DivergenceModeBlock
+	}
+	bullish := sig.Type == SignalTypeLong
+	if f.config.Mode == DivergenceModeBlock {
+		_, opposite := indicators.LatestDivergenceAt(f.divergences, i, f.config.MaxAge, !bullish)
+		return !opposite
+	}
+	div, ok := indicators.LatestDivergenceAt(f.divergences, i, f.config.MaxAge, bullish)
+	if !ok {
+		return false
+	}
+	setMetadata(sig, "divergence", string(div.Kind))
+	setMetadata(sig, "divergence_bars", i-div.ConfirmIndex)
+	return true
+}
@@ -0,0 +1,441 @@
+package signals
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EnsemblePolicy règle de vote des entrées
+type EnsemblePolicy string
+
+const (
+	EnsembleUnanimous EnsemblePolicy = "unanimous" // Tous les générateurs dans le même sens
+	EnsembleKOfN      EnsemblePolicy = "k_of_n"    // Au moins K générateurs dans le même sens
+	EnsembleWeighted  EnsemblePolicy = "weighted"  // Vote pondéré par poids × confiance
+	EnsemblePrimary   EnsemblePolicy = "primary"   // Générateur principal (premier) + K confirmateurs
+)
+
+// EnsembleExitPolicy règle de sortie
+type EnsembleExitPolicy string
+
+const (
+	EnsembleExitFirst    EnsembleExitPolicy = "first"    // Première sortie d'un générateur
+	EnsembleExitMajority EnsembleExitPolicy = "majority" // Sorties de plus de la moitié des générateurs
+)
+
+// EnsembleConfig configuration du méta-générateur
+type EnsembleConfig struct {
+	Name       string
+	Policy     EnsemblePolicy     // Défaut unanimous
+	ExitPolicy EnsembleExitPolicy // Défaut first
+
+	K         int       // k_of_n : votes requis ; primary : confirmateurs requis (défaut 1)
+	Weights   []float64 // weighted : poids par générateur (défaut 1)
+	Threshold float64   // weighted : score minimal Σ(poids × confiance) / Σ poids (défaut 0.5)
+	Window    int       // Ancienneté maximale d'un vote en bougies (0 = même bougie)
+
+	ReverseOnOpposite bool // Une entrée opposée clôture et retourne la position
+}
+
+// EnsembleVote signal d'un générateur enfant retenu dans une décision
+type EnsembleVote struct {
+	Generator string
+	Signal    Signal
+}
+
+// ensembleChild état de vote d'un générateur enfant
+type ensembleChild struct {
+	gen      Generator
+	weight   float64
+	entry    *Signal // Dernière entrée non consommée
+	exit     *Signal // Sortie depuis l'entrée de l'ensemble
+	position SignalType
+}
+
+// Ensemble méta-générateur combinant N générateurs
+//
+// Les signaux des enfants sont traités par bougie, dans l'ordre chronologique. Une entrée
+// d'enfant est un vote valable Window bougies ; une entrée de l'ensemble consomme les votes
+// qui l'ont produite. Position unique ; les sorties suivent ExitPolicy (sorties d'enfants
+// du sens de la position). Les signaux enfants retenus sont dans Metadata["children"].
+type Ensemble struct {
+	config   EnsembleConfig
+	children []*ensembleChild
+
+	position   SignalType
+	entryPrice float64
+	entryTime  time.Time
+	metrics    GeneratorMetrics
+}
+
+// NewEnsemble valide la configuration et crée le méta-générateur
+func NewEnsemble(children []Generator, config EnsembleConfig) (*Ensemble, error) {
+	if len(children) == 0 {
+		return nil, fmt.Errorf("generators: at least one generator required")
+	}
+	if config.Name == "" {
+		config.Name = "ensemble"
+	}
+	if config.Policy == "" {
+		config.Policy = EnsembleUnanimous
+	}
+	if config.ExitPolicy == "" {
+		config.ExitPolicy = EnsembleExitFirst
+	}
+	switch config.Policy {
+	case EnsembleUnanimous, EnsembleWeighted:
+	case EnsembleKOfN:
+		if config.K <= 0 || config.K > len(children) {
+			return nil, fmt.Errorf("k: must be within [1, %d] for k_of_n, got %d", len(children), config.K)
+		}
+	case EnsemblePrimary:
+		if config.K == 0 {
+			config.K = 1
+		}
+		if config.K < 0 || config.K > len(children)-1 {
+			return nil, fmt.Errorf("k: must be within [1, %d] confirmers for primary, got %d", len(children)-1, config.K)
+		}
+	default:
+		return nil, fmt.Errorf("policy: unknown policy %q (unanimous, k_of_n, weighted, primary)", config.Policy)
+	}
+	switch config.ExitPolicy {
+	case EnsembleExitFirst, EnsembleExitMajority:
+	default:
+		return nil, fmt.Errorf("exit_policy: unknown policy %q (first, majority)", config.ExitPolicy)
+	}
+	if len(config.Weights) != 0 && len(config.Weights) != len(children) {
+		return nil, fmt.Errorf("weights: expected %d weights, got %d", len(children), len(config.Weights))
+	}
+	if config.Threshold < 0 || config.Threshold > 1 {
+		return nil, fmt.Errorf("threshold: must be within [0, 1], got %v", config.Threshold)
+	}
+	if config.Threshold == 0 {
+		config.Threshold = 0.5
+	}
+	if config.Window < 0 {
+		return nil, fmt.Errorf("window: must be >= 0, got %d", config.Window)
+	}
+
+	e := &Ensemble{config: config}
+	for i, gen := range children {
+		weight := 1.0
+		if len(config.Weights) > 0 {
+			weight = config.Weights[i]
+			if weight < 0 {
+				return nil, fmt.Errorf("weights[%d]: must be >= 0, got %v", i, weight)
+			}
+		}
+		e.children = append(e.children, &ensembleChild{gen: gen, weight: weight})
+	}
+	return e, nil
+}
+
+// Name retourne le nom de l'ensemble
+func (e *Ensemble) Name() string {
+	return e.config.Name
+}
+
+// Config retourne la configuration normalisée
+func (e *Ensemble) Config() EnsembleConfig {
+	return e.config
+}
+
+// Position retourne la position courante ("" si à plat)
+func (e *Ensemble) Position() SignalType {
+	return e.position
+}
+
+// MinHistorySize historique maximal des générateurs enfants
+func (e *Ensemble) MinHistorySize() int {
+	size := 0
+	for _, c := range e.children {
+		if h := RequiredHistorySize(c.gen, 0); h > size {
+			size = h
+		}
+	}
+	return size
+}
+
+// SetMarketContext transmet le contexte dérivés aux générateurs enfants
+func (e *Ensemble) SetMarketContext(ctx *MarketContext) {
+	for _, c := range e.children {
+		if consumer, ok := c.gen.(MarketContextConsumer); ok {
+			consumer.SetMarketContext(ctx)
+		}
+	}
+}
+
+// SetExplainSink transmet le sink explain aux générateurs enfants
+func (e *Ensemble) SetExplainSink(sink ExplainSink) {
+	for _, c := range e.children {
+		EnableExplain(c.gen, sink)
+	}
+}
+
+// Explains indique si au moins un générateur enfant produit des enregistrements explain
+func (e *Ensemble) Explains() bool {
+	for _, c := range e.children {
+		if SupportsExplain(c.gen) {
+			return true
+		}
+	}
+	return false
+}
+
+// Initialize initialise les générateurs enfants et réinitialise l'état
+func (e *Ensemble) Initialize(config GeneratorConfig) error {
+	for _, c := range e.children {
+		if err := c.gen.Initialize(config); err != nil {
+			return fmt.Errorf("%s: %w", c.gen.Name(), err)
+		}
+		c.entry, c.exit, c.position = nil, nil, ""
+	}
+	e.position = ""
+	e.metrics = GeneratorMetrics{}
+	return nil
+}
+
+// CalculateIndicators délègue aux générateurs enfants
+func (e *Ensemble) CalculateIndicators(klines []Kline) error {
+	for _, c := range e.children {
+		if err := c.gen.CalculateIndicators(klines); err != nil {
+			return fmt.Errorf("%s: %w", c.gen.Name(), err)
+		}
+	}
+	return nil
+}
+
+// DetectSignals collecte les signaux enfants puis applique les règles bougie par bougie
+func (e *Ensemble) DetectSignals(klines []Kline) ([]Signal, error) {
+	type childSignal struct {
+		child  int
+		signal Signal
+	}
+	var collected []childSignal
+	for i, c := range e.children {
+		out, err := c.gen.DetectSignals(klines)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.gen.Name(), err)
+		}
+		for _, sig := range out {
+			collected = append(collected, childSignal{child: i, signal: sig})
+		}
+	}
+	sort.SliceStable(collected, func(a, b int) bool {
+		return collected[a].signal.Timestamp.Before(collected[b].signal.Timestamp)
+	})
+
+	index := make(map[int64]int, len(klines))
+	for i, k := range klines {
+		index[k.OpenTime.UnixMilli()] = i
+	}
+
+	var out []Signal
+	for start := 0; start < len(collected); {
+		at := collected[start].signal.Timestamp
+		end := start
+		for end < len(collected) && collected[end].signal.Timestamp.Equal(at) {
+			cs := collected[end]
+			e.children[cs.child].observe(cs.signal)
+			end++
+		}
+		bar, ok := index[at.UnixMilli()]
+		if !ok {
+			bar = len(klines) - 1
+		}
+		out = append(out, e.processBar(klines, bar, at, index)...)
+		start = end
+	}
+
+	for _, sig := range out {
+		e.record(sig)
+	}
+	return out, nil
+}
+
+// observe met à jour l'état de vote d'un enfant
+func (c *ensembleChild) observe(sig Signal) {
+	s := sig
+	if sig.Action == SignalActionEntry {
+		c.entry = &s
+		c.position = sig.Type
+		return
+	}
+	if sig.Type == c.position {
+		c.position = ""
+	}
+	c.exit = &s
+}
+
+// processBar évalue sorties puis entrées à l'instant at (bougie bar)
+func (e *Ensemble) processBar(klines []Kline, bar int, at time.Time, index map[int64]int) []Signal {
+	var out []Signal
+	price := closeAt(klines, bar)
+
+	if e.position != "" {
+		if votes, ok := e.exitVotes(); ok {
+			out = append(out, e.exitSignal(at, price, string(e.config.ExitPolicy), votes))
+		}
+	}
+
+	var side SignalType
+	var votes []EnsembleVote
+	var confidence float64
+	for _, candidate := range []SignalType{SignalTypeLong, SignalTypeShort} {
+		v, conf, ok := e.entryVotes(candidate, bar, index)
+		if !ok {
+			continue
+		}
+		if side != "" {
+			// Votes contradictoires sur la même bougie : aucune entrée
+			return out
+		}
+		side, votes, confidence = candidate, v, conf
+	}
+	if side == "" || side == e.position {
+		return out
+	}
+	if e.position != "" {
+		if !e.config.ReverseOnOpposite {
+			return out
+		}
+		out = append(out, e.exitSignal(at, price, "reverse", nil))
+	}
+
+	for _, c := range e.children {
+		if c.entry != nil && c.entry.Type == side {
+			c.entry = nil
+		}
+		c.exit = nil
+	}
+	e.position, e.entryPrice, e.entryTime = side, price, at
+	return append(out, Signal{
+		Timestamp:  at,
+		Action:     SignalActionEntry,
+		Type:       side,
+		Price:      price,
+		Confidence: confidence,
+		Metadata: map[string]interface{}{
+			"generator": e.config.Name,
+			"policy":    string(e.config.Policy),
+			"votes":     len(votes),
+			"children":  votes,
+		},
+	})
+}
+
+// entryVotes évalue la règle d'entrée pour side ; retourne les votes retenus et la confiance
+func (e *Ensemble) entryVotes(side SignalType, bar int, index map[int64]int) ([]EnsembleVote, float64, bool) {
+	var votes []EnsembleVote
+	var confSum, score, opposing, totalWeight float64
+	primary := false
+	for i, c := range e.children {
+		totalWeight += c.weight
+		if c.entry == nil {
+			continue
+		}
+		entryBar, ok := index[c.entry.Timestamp.UnixMilli()]
+		if !ok || bar-entryBar > e.config.Window {
+			continue
+		}
+		if c.entry.Type != side {
+			opposing += c.weight * c.entry.Confidence
+			continue
+		}
+		votes = append(votes, EnsembleVote{Generator: c.gen.Name(), Signal: *c.entry})
+		confSum += c.entry.Confidence
+		score += c.weight * c.entry.Confidence
+		if i == 0 {
+			primary = true
+		}
+	}
+	if len(votes) == 0 {
+		return nil, 0, false
+	}
+	confidence := confSum / float64(len(votes))
+
+	switch e.config.Policy {
+	case EnsembleUnanimous:
+		return votes, confidence, len(votes) == len(e.children)
+	case EnsembleKOfN:
+		return votes, confidence, len(votes) >= e.config.K
+	case EnsemblePrimary:
+		return votes, confidence, primary && len(votes)-1 >= e.config.K
+	default:
+		if totalWeight == 0 {
+			return nil, 0, false
+		}
+		normalized := score / totalWeight
+		return votes, normalized, normalized >= e.config.Threshold && score > opposing
+	}
+}
+
+// exitVotes évalue la règle de sortie pour la position courante
+func (e *Ensemble) exitVotes() ([]EnsembleVote, bool) {
+	var votes []EnsembleVote
+	for _, c := range e.children {
+		if c.exit != nil && c.exit.Type == e.position && !c.exit.Timestamp.Before(e.entryTime) {
+			votes = append(votes, EnsembleVote{Generator: c.gen.Name(), Signal: *c.exit})
+		}
+	}
+	if e.config.ExitPolicy == EnsembleExitMajority {
+		return votes, 2*len(votes) > len(e.children)
+	}
+	return votes, len(votes) > 0
+}
+
+// exitSignal clôture la position courante
+func (e *Ensemble) exitSignal(at time.Time, price float64, reason string, votes []EnsembleVote) Signal {
+	entryPrice, entryTime := e.entryPrice, e.entryTime
+	sig := Signal{
+		Timestamp:  at,
+		Action:     SignalActionExit,
+		Type:       e.position,
+		Price:      price,
+		Confidence: 1,
+		Metadata: map[string]interface{}{
+			"generator":   e.config.Name,
+			"exit_reason": reason,
+			"children":    votes,
+		},
+		EntryPrice: &entryPrice,
+		EntryTime:  &entryTime,
+	}
+	for _, c := range e.children {
+		c.exit = nil
+	}
+	e.position = ""
+	return sig
+}
+
+// closeAt prix de clôture de la bougie i (0 si hors fenêtre)
+func closeAt(klines []Kline, i int) float64 {
+	if i < 0 || i >= len(klines) {
+		return 0
+	}
+	return klines[i].Close
+}
+
+// record met à jour les métriques
+func (e *Ensemble) record(sig Signal) {
+	m := &e.metrics
+	m.TotalSignals++
+	if sig.Action == SignalActionEntry {
+		m.EntrySignals++
+		if sig.Type == SignalTypeLong {
+			m.LongSignals++
+		} else {
+			m.ShortSignals++
+		}
+	} else {
+		m.ExitSignals++
+	}
+	m.AvgConfidence += (sig.Confidence - m.AvgConfidence) / float64(m.TotalSignals)
+	m.LastSignalTime = sig.Timestamp
+}
+
+// GetMetrics retourne les métriques
+func (e *Ensemble) GetMetrics() GeneratorMetrics {
+	return e.metrics
+}
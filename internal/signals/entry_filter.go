@@ -0,0 +1,137 @@
+package signals
+
+import "time"
+
+// entryFilter base des décorateurs qui filtrent les entrées d'un générateur (funding,
+// divergence, régime, figures). Chaque filtre fournit sa règle allows ; la base délègue le
+// reste au générateur décoré, compte les entrées bloquées et retire la sortie
+// correspondant à une entrée bloquée.
+type entryFilter struct {
+	inner  Generator
+	suffix string // Suffixe du nom ("+suffix")
+
+	// allows indique si l'entrée de la bougie i (-1 hors fenêtre) est conservée ; la règle
+	// peut étiqueter le signal
+	allows func(sig *Signal, i int) bool
+	// tagExit étiquette une sortie conservée (optionnel)
+	tagExit func(sig *Signal, i int)
+	// history historique propre au filtre (optionnel)
+	history func() int
+
+	ctx       *MarketContext
+	index     map[int64]int  // OpenTime ms -> bougie, de la fenêtre du dernier DetectSignals
+	blockedAt map[int64]bool // Entrées bloquées (OpenTime ms)
+	blocked   int
+}
+
+// newEntryFilter crée la base d'un filtre d'entrées
+func newEntryFilter(inner Generator, suffix string, allows func(sig *Signal, i int) bool) entryFilter {
+	return entryFilter{inner: inner, suffix: suffix, allows: allows, blockedAt: make(map[int64]bool)}
+}
+
+// Name retourne le nom du générateur décoré suffixé
+func (f *entryFilter) Name() string {
+	return f.inner.Name() + "+" + f.suffix
+}
+
+// Initialize initialise le générateur décoré et oublie les entrées bloquées
+func (f *entryFilter) Initialize(config GeneratorConfig) error {
+	f.blockedAt = make(map[int64]bool)
+	f.blocked = 0
+	return f.inner.Initialize(config)
+}
+
+// MinHistorySize historique du générateur décoré et du filtre
+func (f *entryFilter) MinHistorySize() int {
+	size := 0
+	if f.history != nil {
+		size = f.history()
+	}
+	if req, ok := f.inner.(HistoryRequirement); ok && req.MinHistorySize() > size {
+		size = req.MinHistorySize()
+	}
+	return size
+}
+
+// SetMarketContext reçoit le contexte dérivés et le transmet au générateur décoré
+func (f *entryFilter) SetMarketContext(ctx *MarketContext) {
+	f.ctx = ctx
+	if consumer, ok := f.inner.(MarketContextConsumer); ok {
+		consumer.SetMarketContext(ctx)
+	}
+}
+
+// SetExplainSink transmet le sink explain au générateur décoré
+func (f *entryFilter) SetExplainSink(sink ExplainSink) {
+	EnableExplain(f.inner, sink)
+}
+
+// Explains indique si le générateur décoré produit des enregistrements explain
+func (f *entryFilter) Explains() bool { return SupportsExplain(f.inner) }
+
+// CalculateIndicators délègue au générateur décoré
+func (f *entryFilter) CalculateIndicators(klines []Kline) error {
+	return f.inner.CalculateIndicators(klines)
+}
+
+// DetectSignals délègue puis applique la règle du filtre aux entrées
+func (f *entryFilter) DetectSignals(klines []Kline) ([]Signal, error) {
+	sigs, err := f.inner.DetectSignals(klines)
+	if err != nil {
+		return nil, err
+	}
+
+	f.index = make(map[int64]int, len(klines))
+	for i, k := range klines {
+		f.index[k.OpenTime.UnixMilli()] = i
+	}
+
+	filtered := make([]Signal, 0, len(sigs))
+	for _, sig := range sigs {
+		switch sig.Action {
+		case SignalActionEntry:
+			if !f.allows(&sig, f.barIndex(sig.Timestamp)) {
+				f.blocked++
+				f.blockedAt[sig.Timestamp.UnixMilli()] = true
+				continue
+			}
+		case SignalActionExit:
+			if sig.EntryTime != nil && f.blockedAt[sig.EntryTime.UnixMilli()] {
+				delete(f.blockedAt, sig.EntryTime.UnixMilli())
+				continue
+			}
+			if f.tagExit != nil {
+				f.tagExit(&sig, f.barIndex(sig.Timestamp))
+			}
+		}
+		filtered = append(filtered, sig)
+	}
+
+	return filtered, nil
+}
+
+// barIndex bougie de la fenêtre courante ouverte à t (-1 si absente)
+func (f *entryFilter) barIndex(t time.Time) int {
+	if i, found := f.index[t.UnixMilli()]; found {
+		return i
+	}
+	return -1
+}
+
+// GetMetrics retourne les métriques du générateur décoré
+func (f *entryFilter) GetMetrics() GeneratorMetrics {
+	return f.inner.GetMetrics()
+}
+
+// BlockedEntries retourne le nombre d'entrées bloquées par le filtre
+func (f *entryFilter) BlockedEntries() int {
+	return f.blocked
+}
+
+// setMetadata ajoute une métadonnée au signal
+func setMetadata(sig *Signal, key string, value interface{}) {
+	if sig.Metadata == nil {
+		sig.Metadata = make(map[string]interface{})
+	}
+	sig.Metadata[key] = value
+}
@@ -73,7 +73,7 @@ func NewScalpingLiveBybitApp(config *shared.Config, mode string, generatorType s
 		genConfig := signals.GeneratorConfig{
 			Symbol:      config.BinanceData.Symbols[0],
 			Timeframe:   config.Strategy.ScalpingConfig.Timeframe,
-			HistorySize: 300,
+			HistorySize: signals.RequiredHistorySize(generator, 300),
 		}
 		if err := generator.Initialize(genConfig); err != nil {
 			log.Printf("⚠️  Erreur initialisation générateur: %v", err)
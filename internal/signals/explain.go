@@ -0,0 +1,133 @@
+package signals
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// RuleCheck résultat d'une règle d'entrée sur une bougie (valeur, seuil, verdict)
+type RuleCheck struct {
+	Rule      string   `json:"rule"`
+	Value     *float64 `json:"value,omitempty"`     // nil si NaN / indisponible
+	Op        string   `json:"op,omitempty"`        // Comparaison appliquée (">=", "<", "cross_up"...)
+	Threshold *float64 `json:"threshold,omitempty"` // nil si la règle n'a pas de seuil
+	Pass      bool     `json:"pass"`
+	Detail    string   `json:"detail,omitempty"`
+}
+
+// NewRuleCheck crée une règle valeur/seuil (NaN et ±Inf deviennent nil pour rester encodables en JSON)
+func NewRuleCheck(rule string, value float64, op string, threshold float64, pass bool) RuleCheck {
+	return RuleCheck{Rule: rule, Value: explainValue(value), Op: op, Threshold: explainValue(threshold), Pass: pass}
+}
+
+// BarExplanation enregistrement explain d'une bougie évaluée pour un sens
+type BarExplanation struct {
+	Time      time.Time    `json:"time"`
+	Generator string       `json:"generator"`
+	Side      SignalType   `json:"side"`
+	Price     float64      `json:"price"`
+	Rules     []RuleCheck  `json:"rules"`
+	Failed    []string     `json:"failed,omitempty"` // Règles en échec, dans l'ordre d'évaluation
+	Fired     bool         `json:"fired"`
+	Action    SignalAction `json:"action,omitempty"` // Renseigné si un signal a été émis
+}
+
+// NewBarExplanation assemble l'enregistrement : Fired si toutes les règles passent
+func NewBarExplanation(generator string, k Kline, side SignalType, rules []RuleCheck) BarExplanation {
+	exp := BarExplanation{Time: k.OpenTime, Generator: generator, Side: side, Price: k.Close, Rules: rules, Fired: true}
+	for _, r := range rules {
+		if !r.Pass {
+			exp.Fired = false
+			exp.Failed = append(exp.Failed, r.Rule)
+		}
+	}
+	return exp
+}
+
+// ExplainSink destination des enregistrements explain
+type ExplainSink interface {
+	Record(exp BarExplanation) error
+}
+
+// ExplainSinkFunc adapte une fonction en ExplainSink (ex: filtrage avant écriture)
+type ExplainSinkFunc func(exp BarExplanation) error
+
+// Record appelle la fonction
+func (f ExplainSinkFunc) Record(exp BarExplanation) error { return f(exp) }
+
+// Explainer interface optionnelle des générateurs capables d'expliquer chaque bougie évaluée.
+// Un sink nil désactive le mode explain (aucun surcoût).
+type Explainer interface {
+	SetExplainSink(sink ExplainSink)
+}
+
+// ExplainForwarder décorateur qui transmet le sink : Explains indique si un générateur encapsulé
+// produit réellement des enregistrements
+type ExplainForwarder interface {
+	Explains() bool
+}
+
+// SupportsExplain indique si le générateur (ou un générateur décoré) produit des enregistrements explain.
+// Seul smart_eco implémente Explainer ; les décorateurs ne font que transmettre.
+func SupportsExplain(g Generator) bool {
+	if forwarder, ok := g.(ExplainForwarder); ok {
+		return forwarder.Explains()
+	}
+	_, ok := g.(Explainer)
+	return ok
+}
+
+// EnableExplain branche le sink si le générateur le supporte.
+// Retourne false si aucun enregistrement ne sera produit (ex: décorateur autour de direction_dmi).
+func EnableExplain(g Generator, sink ExplainSink) bool {
+	explainer, ok := g.(Explainer)
+	if ok {
+		explainer.SetExplainSink(sink)
+	}
+	return ok && SupportsExplain(g)
+}
+
+// ExplainBuffer sink mémoire
+type ExplainBuffer struct {
+	records []BarExplanation
+}
+
+// Record ajoute l'enregistrement
+func (b *ExplainBuffer) Record(exp BarExplanation) error {
+	b.records = append(b.records, exp)
+	return nil
+}
+
+// Records retourne les enregistrements accumulés
+func (b *ExplainBuffer) Records() []BarExplanation { return b.records }
+
+// Reset vide le buffer
+func (b *ExplainBuffer) Reset() { b.records = nil }
+
+// JSONLExplainWriter sink JSONL : un objet BarExplanation par ligne
+type JSONLExplainWriter struct {
+	enc *json.Encoder
+}
+
+// NewJSONLExplainWriter écrit sur w (fichier, stdout...)
+func NewJSONLExplainWriter(w io.Writer) *JSONLExplainWriter {
+	return &JSONLExplainWriter{enc: json.NewEncoder(w)}
+}
+
+// Record encode l'enregistrement sur une ligne
+func (w *JSONLExplainWriter) Record(exp BarExplanation) error {
+	if err := w.enc.Encode(exp); err != nil {
+		return fmt.Errorf("explain: %w", err)
+	}
+	return nil
+}
+
+func explainValue(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
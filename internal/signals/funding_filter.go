@@ -0,0 +1,64 @@
+package signals
+
+import "fmt"
+
+// FundingFilterConfig seuils de funding au-delà desquels les entrées sont bloquées
+type FundingFilterConfig struct {
+	MaxLongFunding   float64 `yaml:"max_long_funding"`   // Bloque ENTRY LONG si funding >= seuil (ex: 0.0005), 0 = désactivé
+	MinShortFunding  float64 `yaml:"min_short_funding"`  // Bloque ENTRY SHORT si funding <= seuil (ex: -0.0005), 0 = désactivé
+	BlockWhenUnknown bool    `yaml:"block_when_unknown"` // Bloque les entrées si le funding est inconnu
+}
+
+// FundingFilter décore un générateur et supprime les entrées quand le funding est extrême.
+// La sortie correspondant à une entrée bloquée est également retirée.
+type FundingFilter struct {
+	entryFilter
+	config FundingFilterConfig
+}
+
+// NewFundingFilter crée un filtre funding autour d'un générateur
+func NewFundingFilter(inner Generator, config FundingFilterConfig) *FundingFilter {
+	f := &FundingFilter{config: config}
+	f.entryFilter = newEntryFilter(inner, "funding_filter", f.allows)
+	return f
+}
+
+// Initialize valide les seuils et initialise le générateur décoré
+func (f *FundingFilter) Initialize(config GeneratorConfig) error {
+	if f.config.MaxLongFunding < 0 {
+		return fmt.Errorf("max_long_funding must be >= 0")
+	}
+	if f.config.MinShortFunding > 0 {
+		return fmt.Errorf("min_short_funding must be <= 0")
+	}
+	return f.entryFilter.Initialize(config)
+}
+
+// allows bloque les entrées à funding extrême et étiquette les autres avec le funding connu
+func (f *FundingFilter) allows(sig *Signal, i int) bool {
+	funding, known := 0.0, false
+	if i >= 0 {
+		funding, known = f.ctx.Funding(i)
+	}
+	if f.blocks(sig.Type, funding, known) {
+		return false
+	}
+	if known {
+		setMetadata(sig, "funding_rate", funding)
+	}
+	return true
+}
+
+// blocks indique si une entrée doit être bloquée
+func (f *FundingFilter) blocks(direction SignalType, funding float64, known bool) bool {
+	if !known {
+		return f.config.BlockWhenUnknown
+	}
+	switch direction {
+	case SignalTypeLong:
+		return f.config.MaxLongFunding > 0 && funding >= f.config.MaxLongFunding
+	case SignalTypeShort:
+		return f.config.MinShortFunding < 0 && funding <= f.config.MinShortFunding
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package signals
+
+import (
+	"agent-economique/internal/indicators"
+)
+
+// HistoryRequirement interface optionnelle des générateurs déclarant leur historique minimal
+type HistoryRequirement interface {
+	// MinHistorySize nombre de klines nécessaires pour des indicateurs valides et stabilisés
+	MinHistorySize() int
+}
+
+// IndicatorHistory historique minimal pour des références d'indicateurs (ex: "dmi(14,14).adx")
+func IndicatorHistory(refs ...string) (int, error) {
+	return indicators.MinHistory(refs...)
+}
+
+// RequiredHistorySize retourne l'historique à charger pour un générateur
+//
+// Si le générateur implémente HistoryRequirement, sa valeur remplace fallback
+// (la valeur codée en dur historique, ex: 300) ; sinon fallback est conservé.
+func RequiredHistorySize(g Generator, fallback int) int {
+	if req, ok := g.(HistoryRequirement); ok {
+		if size := req.MinHistorySize(); size > 0 {
+			return size
+		}
+	}
+	return fallback
+}
+
+// ToIndicatorKlines convertit les klines unifiées vers le format du package indicators
+func ToIndicatorKlines(klines []Kline) []indicators.Kline {
+	out := make([]indicators.Kline, len(klines))
+	for i, k := range klines {
+		out[i] = indicators.Kline{
+			Timestamp: k.OpenTime.UnixMilli(),
+			Open:      k.Open,
+			High:      k.High,
+			Low:       k.Low,
+			Close:     k.Close,
+			Volume:    k.Volume,
+		}
+	}
+	return out
+}
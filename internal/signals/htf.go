@@ -0,0 +1,251 @@
+package signals
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"agent-economique/internal/indicators"
+	"agent-economique/internal/shared"
+)
+
+// HTFProjector calcule une référence d'indicateur sur un timeframe supérieur (HTF) et
+// projette sur chaque bougie du timeframe de base la dernière valeur HTF clôturée.
+//
+// Une bougie HTF n'est utilisée qu'une fois toutes ses bougies de base clôturées
+// (CloseTime HTF <= clôture de la bougie de base) : la bougie HTF en formation n'est
+// jamais visible. La première bougie HTF, incomplète si la série commence en cours de
+// période, est ignorée.
+type HTFProjector struct {
+	timeframe  string
+	intervalMs int64
+	ref        *indicators.IndicatorRef
+	aggregator shared.TimeframeAggregator
+
+	// Mode streaming
+	maxHistory    int
+	closed        []indicators.Kline
+	forming       *indicators.Kline
+	formingEnd    int64
+	formingPartly bool
+	started       bool
+	value         float64
+}
+
+// NewHTFProjector crée un projecteur (ex: "1h", "dmi(14,14).adx")
+//
+// L'agrégateur est fourni par l'appelant (ex: binance.NewTimeframeAggregator) : le package
+// signals ne dépend d'aucune source de données.
+func NewHTFProjector(aggregator shared.TimeframeAggregator, timeframe, ref string) (*HTFProjector, error) {
+	if aggregator == nil {
+		return nil, fmt.Errorf("aggregator: required")
+	}
+	intervalMs, err := shared.ParseTimeframe(timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("timeframe: %w", err)
+	}
+	parsed, err := indicators.ParseIndicatorRef(ref)
+	if err != nil {
+		return nil, fmt.Errorf("indicator: %w", err)
+	}
+
+	maxHistory := 4 * parsed.StableWarmup()
+	if maxHistory < 200 {
+		maxHistory = 200
+	}
+	return &HTFProjector{
+		timeframe:  timeframe,
+		intervalMs: intervalMs,
+		ref:        parsed,
+		aggregator: aggregator,
+		maxHistory: maxHistory,
+		value:      math.NaN(),
+	}, nil
+}
+
+// Timeframe retourne le timeframe supérieur
+func (p *HTFProjector) Timeframe() string {
+	return p.timeframe
+}
+
+// Ref retourne la référence d'indicateur (forme canonique)
+func (p *HTFProjector) Ref() string {
+	return p.ref.String()
+}
+
+// MinHistorySize bougies de base nécessaires pour une valeur HTF stabilisée
+// (bougie HTF initiale incomplète et bougie en formation comprises)
+func (p *HTFProjector) MinHistorySize(barDuration time.Duration) int {
+	ratio := 1
+	if barMs := barDuration.Milliseconds(); barMs > 0 {
+		ratio = int((p.intervalMs + barMs - 1) / barMs)
+	}
+	return (p.ref.StableWarmup() + 2) * ratio
+}
+
+// checkBarDuration vérifie que le timeframe de base est inférieur au HTF
+func (p *HTFProjector) checkBarDuration(barDuration time.Duration) error {
+	if barDuration <= 0 || barDuration.Milliseconds() >= p.intervalMs {
+		return fmt.Errorf("bar duration %s must be lower than timeframe %s", barDuration, p.timeframe)
+	}
+	return nil
+}
+
+// Project mode batch : série HTF projetée, alignée sur klines (NaN tant qu'aucune bougie
+// HTF complète n'est clôturée ou pendant le warmup de l'indicateur)
+func (p *HTFProjector) Project(klines []Kline, barDuration time.Duration) ([]float64, error) {
+	if err := p.checkBarDuration(barDuration); err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(klines))
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	if len(klines) == 0 {
+		return out, nil
+	}
+
+	barMs := barDuration.Milliseconds()
+	htf, err := aggregateHigherTimeframe(p.aggregator, p.timeframe, klines, barMs)
+	if err != nil {
+		return nil, err
+	}
+
+	values := p.ref.Compute(toHTFKlines(htf))
+	if len(values) != len(htf) {
+		return out, nil
+	}
+
+	j := 0
+	for i, k := range klines {
+		cutoff := k.OpenTime.UnixMilli() + barMs - 1
+		for j < len(htf) && htf[j].CloseTime <= cutoff {
+			j++
+		}
+		if j > 0 {
+			out[i] = values[j-1]
+		}
+	}
+	return out, nil
+}
+
+// aggregateHigherTimeframe agrège les klines de base (durée barMs) sur le timeframe
+// supérieur ; la première bougie HTF, incomplète si la série commence en cours de
+// période, est retirée. La dernière peut être en formation (CloseTime à comparer).
+func aggregateHigherTimeframe(aggregator shared.TimeframeAggregator, timeframe string, klines []Kline, barMs int64) ([]shared.KlineData, error) {
+	source := make([]shared.KlineData, len(klines))
+	for i, k := range klines {
+		open := k.OpenTime.UnixMilli()
+		source[i] = shared.KlineData{
+			OpenTime:  open,
+			Open:      k.Open,
+			High:      k.High,
+			Low:       k.Low,
+			Close:     k.Close,
+			Volume:    k.Volume,
+			CloseTime: open + barMs - 1,
+		}
+	}
+	htf, err := aggregator.AggregateKlinesToTimeframe(source, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate %s: %w", timeframe, err)
+	}
+	if len(htf) > 0 && len(klines) > 0 && htf[0].OpenTime < klines[0].OpenTime.UnixMilli() {
+		htf = htf[1:]
+	}
+	return htf, nil
+}
+
+// toHTFKlines convertit les bougies agrégées pour le registre
+func toHTFKlines(klines []shared.KlineData) []indicators.Kline {
+	out := make([]indicators.Kline, len(klines))
+	for i, k := range klines {
+		out[i] = indicators.Kline{
+			Timestamp: k.OpenTime,
+			Open:      k.Open,
+			High:      k.High,
+			Low:       k.Low,
+			Close:     k.Close,
+			Volume:    k.Volume,
+		}
+	}
+	return out
+}
+
+// Update mode streaming : ajoute une bougie de base clôturée et retourne la valeur projetée.
+// L'historique HTF est borné (4 × warmup stabilisé, min 200 bougies HTF).
+func (p *HTFProjector) Update(bar Kline, barDuration time.Duration) (float64, error) {
+	if err := p.checkBarDuration(barDuration); err != nil {
+		return math.NaN(), err
+	}
+	open := bar.OpenTime.UnixMilli()
+	start := shared.TimeframeBucketStart(open, p.intervalMs)
+
+	// Nouvelle période (ou trou de données) : la bougie HTF précédente est close
+	if p.forming != nil && p.forming.Timestamp != start {
+		p.closeForming()
+	}
+	if p.forming == nil {
+		p.forming = &indicators.Kline{Timestamp: start, Open: bar.Open, High: bar.High, Low: bar.Low, Close: bar.Close, Volume: bar.Volume}
+		p.formingEnd = shared.TimeframeBucketEnd(start, p.intervalMs)
+		p.formingPartly = !p.started && open != start
+		p.started = true
+	} else {
+		p.forming.High = math.Max(p.forming.High, bar.High)
+		p.forming.Low = math.Min(p.forming.Low, bar.Low)
+		p.forming.Close = bar.Close
+		p.forming.Volume += bar.Volume
+	}
+
+	if open+barDuration.Milliseconds()-1 >= p.formingEnd {
+		p.closeForming()
+	}
+	return p.value, nil
+}
+
+// closeForming clôture la bougie HTF en formation et recalcule la valeur projetée
+func (p *HTFProjector) closeForming() {
+	forming, partly := p.forming, p.formingPartly
+	p.forming = nil
+	p.formingPartly = false
+	if partly {
+		return
+	}
+
+	p.closed = append(p.closed, *forming)
+	if len(p.closed) > p.maxHistory {
+		p.closed = append(p.closed[:0], p.closed[len(p.closed)-p.maxHistory:]...)
+	}
+	values := p.ref.Compute(p.closed)
+	if len(values) == len(p.closed) {
+		p.value = values[len(values)-1]
+	}
+}
+
+// Value retourne la dernière valeur HTF clôturée en mode streaming (NaN si inconnue)
+func (p *HTFProjector) Value() float64 {
+	return p.value
+}
+
+// Reset réinitialise l'état streaming
+func (p *HTFProjector) Reset() {
+	p.closed = nil
+	p.forming = nil
+	p.formingPartly = false
+	p.started = false
+	p.value = math.NaN()
+}
+
+// ProjectHigherTimeframe projette une référence d'indicateur calculée sur un timeframe
+// supérieur sur les klines du générateur (ex: ADX "1h" sur des bougies "5m")
+func ProjectHigherTimeframe(aggregator shared.TimeframeAggregator, klines []Kline, baseTimeframe, timeframe, ref string) ([]float64, error) {
+	baseMs, err := shared.ParseTimeframe(baseTimeframe)
+	if err != nil {
+		return nil, fmt.Errorf("base timeframe: %w", err)
+	}
+	projector, err := NewHTFProjector(aggregator, timeframe, ref)
+	if err != nil {
+		return nil, err
+	}
+	return projector.Project(klines, time.Duration(baseMs)*time.Millisecond)
+}
@@ -0,0 +1,497 @@
+package signals
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// HTFStateSource état de tendance HTF projeté sur les bougies de base
+type HTFStateSource interface {
+	// Name description de la source (métadonnées)
+	Name() string
+	// States état par bougie de base : LONG, SHORT ou "" (neutre / inconnu). Seules les
+	// bougies HTF clôturées à la clôture de la bougie de base sont utilisées.
+	States(klines []Kline, barDuration time.Duration) ([]SignalType, error)
+	// MinHistorySize bougies de base nécessaires pour un état valide
+	MinHistorySize(barDuration time.Duration) int
+}
+
+// HTFIndicatorState état HTF depuis une référence d'indicateur : LONG si valeur > Upper,
+// SHORT si valeur < Lower (ex: "macd(12,26,9).hist" sur "15m", seuils 0)
+type HTFIndicatorState struct {
+	projector    *HTFProjector
+	lower, upper float64
+}
+
+// NewHTFIndicatorState crée une source d'état depuis une référence d'indicateur HTF
+func NewHTFIndicatorState(aggregator shared.TimeframeAggregator, timeframe, ref string, lower, upper float64) (*HTFIndicatorState, error) {
+	if lower > upper {
+		return nil, fmt.Errorf("lower: must be <= upper (%v > %v)", lower, upper)
+	}
+	projector, err := NewHTFProjector(aggregator, timeframe, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &HTFIndicatorState{projector: projector, lower: lower, upper: upper}, nil
+}
+
+// Name retourne "timeframe:ref"
+func (s *HTFIndicatorState) Name() string {
+	return s.projector.Timeframe() + ":" + s.projector.Ref()
+}
+
+// MinHistorySize historique du projecteur
+func (s *HTFIndicatorState) MinHistorySize(barDuration time.Duration) int {
+	return s.projector.MinHistorySize(barDuration)
+}
+
+// States projette l'indicateur HTF et applique les seuils
+func (s *HTFIndicatorState) States(klines []Kline, barDuration time.Duration) ([]SignalType, error) {
+	values, err := s.projector.Project(klines, barDuration)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SignalType, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+		case v > s.upper:
+			out[i] = SignalTypeLong
+		case v < s.lower:
+			out[i] = SignalTypeShort
+		}
+	}
+	return out, nil
+}
+
+// htfTransition changement d'état HTF connu à partir de at (clôture HTF, ms)
+type htfTransition struct {
+	at    int64
+	state SignalType
+}
+
+// HTFGeneratorState état HTF depuis un générateur exécuté sur les bougies agrégées : la
+// position du générateur (entrée LONG/SHORT, neutre après une sortie)
+type HTFGeneratorState struct {
+	gen        Generator
+	timeframe  string
+	aggregator shared.TimeframeAggregator
+	config     GeneratorConfig
+
+	transitions []htfTransition
+	state       SignalType
+	lastSignal  time.Time // Dernier signal HTF pris en compte
+}
+
+// NewHTFGeneratorState crée une source d'état depuis un générateur HTF
+func NewHTFGeneratorState(aggregator shared.TimeframeAggregator, gen Generator, timeframe string) (*HTFGeneratorState, error) {
+	if aggregator == nil {
+		return nil, fmt.Errorf("aggregator: required")
+	}
+	if _, err := shared.ParseTimeframe(timeframe); err != nil {
+		return nil, fmt.Errorf("timeframe: %w", err)
+	}
+	return &HTFGeneratorState{gen: gen, timeframe: timeframe, aggregator: aggregator, config: GeneratorConfig{Timeframe: timeframe}}, nil
+}
+
+// Name retourne "timeframe:générateur"
+func (s *HTFGeneratorState) Name() string {
+	return s.timeframe + ":" + s.gen.Name()
+}
+
+// MinHistorySize historique du générateur en bougies de base (+ bougie HTF incomplète et en formation)
+func (s *HTFGeneratorState) MinHistorySize(barDuration time.Duration) int {
+	intervalMs, _ := shared.ParseTimeframe(s.timeframe)
+	ratio := 1
+	if barMs := barDuration.Milliseconds(); barMs > 0 {
+		ratio = int((intervalMs + barMs - 1) / barMs)
+	}
+	return (RequiredHistorySize(s.gen, 0) + 2) * ratio
+}
+
+// Initialize initialise le générateur HTF et oublie les transitions
+func (s *HTFGeneratorState) Initialize(config GeneratorConfig) error {
+	s.transitions, s.state, s.lastSignal = nil, "", time.Time{}
+	config.Timeframe = s.timeframe
+	s.config = config
+	return s.gen.Initialize(config)
+}
+
+// States fait tourner le générateur sur les bougies HTF et projette sa position
+//
+// Le générateur ignore la dernière bougie HTF (en formation, fictive quand la fenêtre se
+// termine sur une clôture HTF) ; un signal daté de l'ouverture d'une bougie HTF n'est
+// visible qu'à sa clôture. Les bougies HTF sont
+// reconstruites à chaque appel depuis la fenêtre (qui glisse en live) : le générateur est
+// réinitialisé et rejoué sur toute la fenêtre, seuls les signaux postérieurs au dernier
+// signal déjà pris en compte font évoluer la position.
+func (s *HTFGeneratorState) States(klines []Kline, barDuration time.Duration) ([]SignalType, error) {
+	barMs := barDuration.Milliseconds()
+	htf, err := aggregateHigherTimeframe(s.aggregator, s.timeframe, klines, barMs)
+	if err != nil {
+		return nil, err
+	}
+	if len(htf) > 0 {
+		bars := make([]Kline, len(htf))
+		closeAt := make(map[int64]int64, len(htf))
+		for i, k := range htf {
+			bars[i] = Kline{OpenTime: time.UnixMilli(k.OpenTime).UTC(), Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume}
+			closeAt[k.OpenTime] = k.CloseTime + 1
+		}
+		// Dernière bougie HTF clôturée avec la fenêtre : bougie en formation fictive pour
+		// que le générateur la traite dès maintenant (comme sur l'historique complet)
+		last := htf[len(htf)-1]
+		if last.CloseTime+1 <= klines[len(klines)-1].OpenTime.UnixMilli()+barMs {
+			bars = append(bars, Kline{OpenTime: time.UnixMilli(last.CloseTime + 1).UTC(), Open: last.Close, High: last.Close, Low: last.Close, Close: last.Close})
+		}
+		if err := s.gen.Initialize(s.config); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.gen.Name(), err)
+		}
+		if err := s.gen.CalculateIndicators(bars); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.gen.Name(), err)
+		}
+		out, err := s.gen.DetectSignals(bars)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.gen.Name(), err)
+		}
+		sort.SliceStable(out, func(a, b int) bool { return out[a].Timestamp.Before(out[b].Timestamp) })
+		seen := s.lastSignal
+		for _, sig := range out {
+			at, ok := closeAt[sig.Timestamp.UnixMilli()]
+			if !ok || !sig.Timestamp.After(seen) {
+				continue // Hors bougie HTF clôturée, ou déjà pris en compte lors d'un appel précédent
+			}
+			s.lastSignal = sig.Timestamp
+			state := s.state
+			if sig.Action == SignalActionEntry {
+				state = sig.Type
+			} else if sig.Type == s.state {
+				state = ""
+			}
+			s.state = state
+			s.transitions = append(s.transitions, htfTransition{at: at, state: state})
+		}
+	}
+
+	// Transitions antérieures à la fenêtre : seule la dernière reste utile
+	if len(klines) > 0 {
+		first := klines[0].OpenTime.UnixMilli() + barMs
+		drop := 0
+		for drop+1 < len(s.transitions) && s.transitions[drop+1].at <= first {
+			drop++
+		}
+		s.transitions = s.transitions[drop:]
+	}
+
+	states := make([]SignalType, len(klines))
+	j, state := 0, SignalType("")
+	for i, k := range klines {
+		cutoff := k.OpenTime.UnixMilli() + barMs
+		for j < len(s.transitions) && s.transitions[j].at <= cutoff {
+			state = s.transitions[j].state
+			j++
+		}
+		states[i] = state
+	}
+	return states, nil
+}
+
+// HTFGateMode action sur une entrée contraire à l'état HTF
+type HTFGateMode string
+
+const (
+	HTFGateBlock HTFGateMode = "block" // Entrée supprimée
+	HTFGateFlip  HTFGateMode = "flip"  // Entrée retournée dans le sens HTF
+)
+
+// HTFGateConfig configuration du filtre de tendance HTF
+type HTFGateConfig struct {
+	Mode         HTFGateMode   // block (défaut) ou flip
+	AllowNeutral bool          // Entrées autorisées quand l'état HTF est neutre ou inconnu
+	ForceExit    bool          // Sortie forcée quand l'état HTF se retourne contre la position
+	BarDuration  time.Duration // Durée des bougies de base (défaut : Timeframe de Initialize)
+}
+
+// htfPosition position ouverte suivie pour la sortie forcée
+type htfPosition struct {
+	side       SignalType
+	entryTime  time.Time
+	entryPrice float64
+}
+
+// HTFGate décore un générateur de timeframe inférieur : ses entrées sont filtrées (ou
+// retournées) par un état de tendance calculé sur un timeframe supérieur. La sortie d'une
+// entrée bloquée est retirée, celle d'une entrée retournée est retournée ; avec ForceExit,
+// une position est clôturée dès que l'état HTF passe dans le sens opposé (exit_reason
+// "htf_reversal") et la sortie ultérieure du générateur décoré est retirée. Seules les
+// bougies HTF clôturées sont utilisées (pas de look-ahead, backtest comme live).
+type HTFGate struct {
+	inner  Generator
+	source HTFStateSource
+	config HTFGateConfig
+
+	barDuration   time.Duration
+	states        []SignalType
+	lastProcessed time.Time
+	blockedAt     map[int64]bool // Entrées bloquées (OpenTime ms)
+	flippedAt     map[int64]bool // Entrées retournées
+	forcedAt      map[int64]bool // Positions clôturées par retournement HTF
+	positions     map[int64]*htfPosition
+	blocked       int
+	flipped       int
+	forced        int
+}
+
+// NewHTFGate crée un filtre de tendance HTF autour d'un générateur
+func NewHTFGate(inner Generator, source HTFStateSource, config HTFGateConfig) (*HTFGate, error) {
+	if source == nil {
+		return nil, fmt.Errorf("source: missing HTF state source")
+	}
+	switch config.Mode {
+	case "":
+		config.Mode = HTFGateBlock
+	case HTFGateBlock, HTFGateFlip:
+	default:
+		return nil, fmt.Errorf("mode: unknown mode %q (block, flip)", config.Mode)
+	}
+	if config.BarDuration < 0 {
+		return nil, fmt.Errorf("bar_duration: must be >= 0, got %s", config.BarDuration)
+	}
+	g := &HTFGate{inner: inner, source: source, config: config, barDuration: config.BarDuration}
+	g.reset()
+	return g, nil
+}
+
+// reset remet l'état à zéro
+func (g *HTFGate) reset() {
+	g.states = nil
+	g.lastProcessed = time.Time{}
+	g.blockedAt = make(map[int64]bool)
+	g.flippedAt = make(map[int64]bool)
+	g.forcedAt = make(map[int64]bool)
+	g.positions = make(map[int64]*htfPosition)
+	g.blocked, g.flipped, g.forced = 0, 0, 0
+}
+
+// Name retourne le nom du générateur décoré suffixé
+func (g *HTFGate) Name() string {
+	return g.inner.Name() + "+htf"
+}
+
+// BlockedEntries nombre d'entrées supprimées
+func (g *HTFGate) BlockedEntries() int {
+	return g.blocked
+}
+
+// FlippedEntries nombre d'entrées retournées
+func (g *HTFGate) FlippedEntries() int {
+	return g.flipped
+}
+
+// ForcedExits nombre de sorties forcées par retournement HTF
+func (g *HTFGate) ForcedExits() int {
+	return g.forced
+}
+
+// MinHistorySize historique du générateur décoré et de la source HTF
+func (g *HTFGate) MinHistorySize() int {
+	size := g.source.MinHistorySize(g.barDuration)
+	if req, ok := g.inner.(HistoryRequirement); ok && req.MinHistorySize() > size {
+		size = req.MinHistorySize()
+	}
+	return size
+}
+
+// SetMarketContext transmet le contexte dérivés au générateur décoré
+func (g *HTFGate) SetMarketContext(ctx *MarketContext) {
+	if consumer, ok := g.inner.(MarketContextConsumer); ok {
+		consumer.SetMarketContext(ctx)
+	}
+}
+
+// SetExplainSink transmet le sink explain au générateur décoré
+func (g *HTFGate) SetExplainSink(sink ExplainSink) {
+	EnableExplain(g.inner, sink)
+}
+
+// Explains indique si le générateur décoré produit des enregistrements explain
+func (g *HTFGate) Explains() bool { return SupportsExplain(g.inner) }
+
+// Initialize initialise le générateur décoré, la source HTF et réinitialise l'état
+func (g *HTFGate) Initialize(config GeneratorConfig) error {
+	if g.config.BarDuration == 0 {
+		if config.Timeframe == "" {
+			return fmt.Errorf("timeframe: required to derive the bar duration")
+		}
+		barMs, err := shared.ParseTimeframe(config.Timeframe)
+		if err != nil {
+			return fmt.Errorf("timeframe: %w", err)
+		}
+		g.barDuration = time.Duration(barMs) * time.Millisecond
+	}
+	if init, ok := g.source.(interface{ Initialize(GeneratorConfig) error }); ok {
+		if err := init.Initialize(config); err != nil {
+			return err
+		}
+	}
+	g.reset()
+	return g.inner.Initialize(config)
+}
+
+// CalculateIndicators délègue puis calcule les états HTF
+func (g *HTFGate) CalculateIndicators(klines []Kline) error {
+	if err := g.inner.CalculateIndicators(klines); err != nil {
+		return err
+	}
+	if g.barDuration <= 0 {
+		return fmt.Errorf("bar duration unknown: call Initialize with a timeframe or set BarDuration")
+	}
+	states, err := g.source.States(klines, g.barDuration)
+	if err != nil {
+		return err
+	}
+	g.states = states
+	return nil
+}
+
+// DetectSignals filtre les signaux du générateur décoré et ajoute les sorties forcées
+func (g *HTFGate) DetectSignals(klines []Kline) ([]Signal, error) {
+	inner, err := g.inner.DetectSignals(klines)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(inner, func(a, b int) bool { return inner[a].Timestamp.Before(inner[b].Timestamp) })
+
+	index := make(map[int64]int, len(klines))
+	for i, k := range klines {
+		index[k.OpenTime.UnixMilli()] = i
+	}
+
+	var out []Signal
+	next := 0
+	for j := 0; j <= len(klines)-2; j++ {
+		if !klines[j].OpenTime.After(g.lastProcessed) {
+			continue
+		}
+		if g.config.ForceExit {
+			out = append(out, g.forceExits(klines[j], g.stateAt(j))...)
+		}
+		for next < len(inner) && !inner[next].Timestamp.After(klines[j].OpenTime) {
+			if sig, ok := g.apply(inner[next], index); ok {
+				out = append(out, sig)
+			}
+			next++
+		}
+		g.lastProcessed = klines[j].OpenTime
+	}
+	// Signaux hors des bougies clôturées (ex: datés de la bougie en cours)
+	for ; next < len(inner); next++ {
+		if sig, ok := g.apply(inner[next], index); ok {
+			out = append(out, sig)
+		}
+	}
+	return out, nil
+}
+
+// stateAt état HTF de la bougie i ("" si inconnu)
+func (g *HTFGate) stateAt(i int) SignalType {
+	if i < 0 || i >= len(g.states) {
+		return ""
+	}
+	return g.states[i]
+}
+
+// forceExits clôture les positions contraires à l'état HTF sur la bougie k
+func (g *HTFGate) forceExits(k Kline, state SignalType) []Signal {
+	if state == "" {
+		return nil
+	}
+	var keys []int64
+	for ms, pos := range g.positions {
+		if pos.side != state {
+			keys = append(keys, ms)
+		}
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a] < keys[b] })
+
+	var out []Signal
+	for _, ms := range keys {
+		pos := g.positions[ms]
+		entryPrice, entryTime := pos.entryPrice, pos.entryTime
+		out = append(out, Signal{
+			Timestamp:  k.OpenTime,
+			Action:     SignalActionExit,
+			Type:       pos.side,
+			Price:      k.Close,
+			Confidence: 1,
+			Metadata:   map[string]interface{}{"generator": g.Name(), "exit_reason": "htf_reversal", "htf_state": string(state)},
+			EntryPrice: &entryPrice,
+			EntryTime:  &entryTime,
+		})
+		delete(g.positions, ms)
+		g.forcedAt[ms] = true
+		g.forced++
+	}
+	return out
+}
+
+// apply filtre, retourne ou conserve un signal du générateur décoré
+func (g *HTFGate) apply(sig Signal, index map[int64]int) (Signal, bool) {
+	ms := sig.Timestamp.UnixMilli()
+	if sig.Action == SignalActionExit {
+		if sig.EntryTime == nil {
+			return sig, true
+		}
+		entryMs := sig.EntryTime.UnixMilli()
+		delete(g.positions, entryMs)
+		switch {
+		case g.blockedAt[entryMs], g.forcedAt[entryMs]:
+			return sig, false
+		case g.flippedAt[entryMs]:
+			sig.Type = opposite(sig.Type)
+			sig.Metadata = withMetadata(sig.Metadata, "htf_flipped", true)
+		}
+		return sig, true
+	}
+
+	i, ok := index[ms]
+	state := SignalType("")
+	if ok {
+		state = g.stateAt(i)
+	}
+	switch {
+	case state == sig.Type, state == "" && g.config.AllowNeutral:
+	case state != "" && g.config.Mode == HTFGateFlip:
+		sig.Metadata = withMetadata(sig.Metadata, "htf_original_type", string(sig.Type))
+		sig.Metadata["htf_flipped"] = true
+		sig.Type = state
+		g.flippedAt[ms] = true
+		g.flipped++
+	default:
+		g.blockedAt[ms] = true
+		g.blocked++
+		return sig, false
+	}
+	sig.Metadata = withMetadata(sig.Metadata, "htf_state", string(state))
+	g.positions[ms] = &htfPosition{side: sig.Type, entryTime: sig.Timestamp, entryPrice: sig.Price}
+	return sig, true
+}
+
+// withMetadata copie les métadonnées et ajoute une clé
+func withMetadata(metadata map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// GetMetrics délègue au générateur décoré
+func (g *HTFGate) GetMetrics() GeneratorMetrics {
+	return g.inner.GetMetrics()
+}
@@ -0,0 +1,143 @@
+package signals
+
+import (
+	"math"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// MarketContext séries dérivés (funding, open interest, long/short) alignées sur les klines.
+//
+// Chaque valeur i est la dernière observation connue à la clôture de la bougie i
+// (Time <= OpenTime + durée bougie - 1ms) : pas de look-ahead. NaN = donnée inconnue.
+type MarketContext struct {
+	FundingRate      []float64 // Dernier funding réglé
+	PredictedFunding []float64 // Dernière estimation du prochain funding
+	OpenInterest     []float64 // Open interest (base asset)
+	LongShortRatio   []float64 // Ratio comptes long/short
+}
+
+// MarketContextConsumer interface optionnelle des générateurs qui consomment le contexte dérivés.
+// Le contexte est fourni avant CalculateIndicators et aligné sur les mêmes klines.
+type MarketContextConsumer interface {
+	SetMarketContext(ctx *MarketContext)
+}
+
+// NewMarketContext aligne une série dérivés sur les klines (barDuration = durée d'une bougie)
+func NewMarketContext(klines []Kline, barDuration time.Duration, series *shared.DerivativesSeries) *MarketContext {
+	ctx := &MarketContext{}
+	if series == nil {
+		return ctx
+	}
+
+	cutoffs := make([]int64, len(klines))
+	for i, k := range klines {
+		cutoffs[i] = k.OpenTime.Add(barDuration).UnixMilli() - 1
+	}
+
+	ctx.FundingRate = alignPoints(series.FundingRates, cutoffs)
+	ctx.PredictedFunding = alignPoints(series.PredictedFunding, cutoffs)
+	ctx.OpenInterest = alignPoints(series.OpenInterest, cutoffs)
+	ctx.LongShortRatio = alignPoints(series.LongShortRatio, cutoffs)
+
+	// Une estimation antérieure au dernier règlement est périmée
+	if len(ctx.PredictedFunding) > 0 && len(ctx.FundingRate) > 0 {
+		predictedAt := alignTimes(series.PredictedFunding, cutoffs)
+		settledAt := alignTimes(series.FundingRates, cutoffs)
+		for i := range ctx.PredictedFunding {
+			if predictedAt[i] <= settledAt[i] {
+				ctx.PredictedFunding[i] = math.NaN()
+			}
+		}
+	}
+	return ctx
+}
+
+// Funding retourne le funding le plus récent connu sur la bougie i :
+// l'estimation si disponible, sinon le dernier funding réglé
+func (c *MarketContext) Funding(i int) (float64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	if v, ok := valueAt(c.PredictedFunding, i); ok {
+		return v, true
+	}
+	return valueAt(c.FundingRate, i)
+}
+
+// OpenInterestChange retourne la variation relative de l'open interest sur lookback bougies
+func (c *MarketContext) OpenInterestChange(i, lookback int) (float64, bool) {
+	if c == nil || lookback <= 0 {
+		return 0, false
+	}
+	current, ok := valueAt(c.OpenInterest, i)
+	if !ok {
+		return 0, false
+	}
+	previous, ok := valueAt(c.OpenInterest, i-lookback)
+	if !ok || previous == 0 {
+		return 0, false
+	}
+	return (current - previous) / previous, true
+}
+
+// LongShort retourne le ratio long/short connu sur la bougie i
+func (c *MarketContext) LongShort(i int) (float64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	return valueAt(c.LongShortRatio, i)
+}
+
+// CalculateIndicatorsWithContext transmet le contexte au générateur s'il le supporte puis calcule les indicateurs
+func CalculateIndicatorsWithContext(g Generator, klines []Kline, ctx *MarketContext) error {
+	if consumer, ok := g.(MarketContextConsumer); ok {
+		consumer.SetMarketContext(ctx)
+	}
+	return g.CalculateIndicators(klines)
+}
+
+// alignPoints trie une copie des points et les aligne sur les cutoffs (NaN si inconnu)
+func alignPoints(points []shared.DerivativesPoint, cutoffs []int64) []float64 {
+	if len(points) == 0 {
+		return nil
+	}
+	sorted := append([]shared.DerivativesPoint(nil), points...)
+	shared.SortDerivativesPoints(sorted)
+
+	values, ok := shared.AlignDerivativesPoints(sorted, cutoffs)
+	for i := range values {
+		if !ok[i] {
+			values[i] = math.NaN()
+		}
+	}
+	return values
+}
+
+// alignTimes retourne l'horodatage de la dernière observation connue à chaque cutoff (-1 si aucune)
+func alignTimes(points []shared.DerivativesPoint, cutoffs []int64) []int64 {
+	stamps := make([]shared.DerivativesPoint, len(points))
+	for i, p := range points {
+		stamps[i] = shared.DerivativesPoint{Time: p.Time, Value: float64(p.Time)}
+	}
+	shared.SortDerivativesPoints(stamps)
+
+	values, ok := shared.AlignDerivativesPoints(stamps, cutoffs)
+	times := make([]int64, len(cutoffs))
+	for i := range values {
+		times[i] = -1
+		if ok[i] {
+			times[i] = int64(values[i])
+		}
+	}
+	return times
+}
+
+// valueAt retourne series[i] si connu
+func valueAt(series []float64, i int) (float64, bool) {
+	if i < 0 || i >= len(series) || math.IsNaN(series[i]) {
+		return 0, false
+	}
+	return series[i], true
+}
@@ -0,0 +1,150 @@
+package signals
+
+import (
+	"fmt"
+	"strings"
+
+	"agent-economique/internal/baranalysis"
+	"agent-economique/internal/indicators"
+)
+
+// PatternFilterConfig configuration du filtre de figures chandeliers
+type PatternFilterConfig struct {
+	Patterns    baranalysis.PatternConfig `yaml:"patterns"`     // Seuils ATR (valeurs nulles = défauts)
+	ATRPeriod   int                       `yaml:"atr_period"`   // ATR de normalisation (défaut 14)
+	Require     []string                  `yaml:"require"`      // Figures exigées (défaut : toutes les figures directionnelles)
+	Block       []string                  `yaml:"block"`        // Figures contraires bloquant l'entrée
+	MaxAge      int                       `yaml:"max_age"`      // Ancienneté maximale en bougies (0 = bougie du signal)
+	MinStrength float64                   `yaml:"min_strength"` // Force minimale (0-1)
+	TagOnly     bool                      `yaml:"tag_only"`     // Étiquette les signaux sans filtrer
+}
+
+// PatternFilter décore un générateur : une entrée exige une figure récente (Require) dans
+// son sens ou neutre, et est bloquée par une figure récente contraire (Block). Les figures
+// neutres (inside bar, doji) ne comptent pour Require que si elles y sont listées. Les
+// signaux conservés reçoivent les métadonnées "pattern" et "pattern_strength" de la
+// figure retenue. La sortie correspondant à une entrée bloquée est également retirée.
+type PatternFilter struct {
+	entryFilter
+	config  PatternFilterConfig
+	require map[baranalysis.PatternKind]bool
+	block   map[baranalysis.PatternKind]bool
+
+	events []baranalysis.PatternEvent
+}
+
+// NewPatternFilter crée un filtre de figures autour d'un générateur
+func NewPatternFilter(inner Generator, config PatternFilterConfig) (*PatternFilter, error) {
+	if config.ATRPeriod <= 0 {
+		config.ATRPeriod = 14
+	}
+	if config.MaxAge < 0 {
+		return nil, fmt.Errorf("max_age: must be >= 0, got %d", config.MaxAge)
+	}
+	if config.MinStrength < 0 || config.MinStrength > 1 {
+		return nil, fmt.Errorf("min_strength: must be within [0, 1], got %v", config.MinStrength)
+	}
+	require, err := parsePatternKinds("require", config.Require)
+	if err != nil {
+		return nil, err
+	}
+	if len(require) == 0 {
+		for _, kind := range baranalysis.PatternKinds {
+			if !kind.IsNeutral() {
+				require[kind] = true
+			}
+		}
+	}
+	block, err := parsePatternKinds("block", config.Block)
+	if err != nil {
+		return nil, err
+	}
+	f := &PatternFilter{config: config, require: require, block: block}
+	f.entryFilter = newEntryFilter(inner, "pattern_filter", f.allows)
+	f.entryFilter.history = f.history
+	return f, nil
+}
+
+// parsePatternKinds valide une liste de libellés de figures
+func parsePatternKinds(field string, labels []string) (map[baranalysis.PatternKind]bool, error) {
+	out := make(map[baranalysis.PatternKind]bool)
+	for _, label := range labels {
+		kind, ok := baranalysis.ParsePatternKind(label)
+		if !ok {
+			names := make([]string, len(baranalysis.PatternKinds))
+			for i, k := range baranalysis.PatternKinds {
+				names[i] = string(k)
+			}
+			return nil, fmt.Errorf("%s: unknown pattern %q (%s)", field, label, strings.Join(names, ", "))
+		}
+		out[kind] = true
+	}
+	return out, nil
+}
+
+// history historique de l'ATR stabilisé (+ 3 bougies de figure)
+func (f *PatternFilter) history() int {
+	size, err := IndicatorHistory(fmt.Sprintf("atr(%d)", f.config.ATRPeriod))
+	if err != nil {
+		size = f.config.ATRPeriod
+	}
+	return size + 3 + f.config.MaxAge
+}
+
+// CalculateIndicators délègue puis détecte les figures
+func (f *PatternFilter) CalculateIndicators(klines []Kline) error {
+	if err := f.inner.CalculateIndicators(klines); err != nil {
+		return err
+	}
+	high, low, closes := make([]float64, len(klines)), make([]float64, len(klines)), make([]float64, len(klines))
+	for i, k := range klines {
+		high[i], low[i], closes[i] = k.High, k.Low, k.Close
+	}
+	atr := indicators.NewATRTVStandard(f.config.ATRPeriod).Calculate(high, low, closes)
+	bars := make([]baranalysis.BarAnalysis, len(klines))
+	for i, k := range klines {
+		bars[i] = baranalysis.AnalyzeBar(k.Open, k.High, k.Low, k.Close, atr[i], k.OpenTime)
+	}
+	f.events = baranalysis.DetectPatterns(bars, f.config.Patterns)
+	return nil
+}
+
+// Patterns retourne les figures du dernier calcul
+func (f *PatternFilter) Patterns() []baranalysis.PatternEvent {
+	return f.events
+}
+
+// allows exige une figure récente dans le sens de l'entrée (ou neutre listée), refuse une
+// figure contraire bloquante et étiquette l'entrée avec la figure retenue
+func (f *PatternFilter) allows(sig *Signal, i int) bool {
+	side := 1
+	if sig.Type == SignalTypeShort {
+		side = -1
+	}
+	var confirm, against baranalysis.PatternEvent
+	confirmed, opposed := false, false
+	if i >= 0 {
+		confirm, confirmed = baranalysis.LatestPattern(f.events, i, f.config.MaxAge, f.require, func(ev baranalysis.PatternEvent) bool {
+			return ev.Strength >= f.config.MinStrength && (ev.Bias == side || ev.Bias == 0)
+		})
+		if len(f.block) > 0 {
+			against, opposed = baranalysis.LatestPattern(f.events, i, f.config.MaxAge, f.block, func(ev baranalysis.PatternEvent) bool {
+				return ev.Strength >= f.config.MinStrength && ev.Bias == -side
+			})
+		}
+	}
+	if !f.config.TagOnly && (!confirmed || opposed) {
+		return false
+	}
+
+	tag := &confirm
+	if !confirmed {
+		if !opposed {
+			return true
+		}
+		tag = &against
+	}
+	setMetadata(sig, "pattern", string(tag.Kind))
+	setMetadata(sig, "pattern_strength", tag.Strength)
+	return true
+}
@@ -0,0 +1,137 @@
+package signals
+
+import (
+	"fmt"
+	"strings"
+
+	"agent-economique/internal/indicators"
+)
+
+// RegimeFilterConfig configuration du filtre de régime de marché
+type RegimeFilterConfig struct {
+	Regime     indicators.RegimeConfig `yaml:"regime"`      // Classificateur (valeurs nulles = défauts)
+	AllowLong  []string                `yaml:"allow_long"`  // Régimes autorisant un LONG (défaut TENDANCE_HAUSSE)
+	AllowShort []string                `yaml:"allow_short"` // Régimes autorisant un SHORT (défaut TENDANCE_BAISSE)
+	TagOnly    bool                    `yaml:"tag_only"`    // Étiquette les signaux sans filtrer
+}
+
+// RegimeFilter décore un générateur : étiquette chaque signal avec le régime de sa bougie
+// (métadonnée "regime", et "entry_regime" pour une sortie) et bloque les entrées hors des
+// régimes autorisés. La sortie correspondant à une entrée bloquée est également retirée.
+type RegimeFilter struct {
+	entryFilter
+	config     RegimeFilterConfig
+	classifier *indicators.RegimeClassifier
+	allowLong  map[indicators.Regime]bool
+	allowShort map[indicators.Regime]bool
+
+	regimes *indicators.RegimeSeries
+}
+
+// NewRegimeFilter crée un filtre de régime autour d'un générateur
+func NewRegimeFilter(inner Generator, config RegimeFilterConfig) (*RegimeFilter, error) {
+	allowLong, err := parseRegimes("allow_long", config.AllowLong, indicators.RegimeTrendUp)
+	if err != nil {
+		return nil, err
+	}
+	allowShort, err := parseRegimes("allow_short", config.AllowShort, indicators.RegimeTrendDown)
+	if err != nil {
+		return nil, err
+	}
+	f := &RegimeFilter{
+		config:     config,
+		classifier: indicators.NewRegimeClassifier(config.Regime),
+		allowLong:  allowLong,
+		allowShort: allowShort,
+	}
+	f.entryFilter = newEntryFilter(inner, "regime_filter", f.allows)
+	f.entryFilter.history = f.history
+	f.tagExit = f.tagExitRegimes
+	return f, nil
+}
+
+// parseRegimes valide une liste de libellés de régime
+func parseRegimes(field string, labels []string, def indicators.Regime) (map[indicators.Regime]bool, error) {
+	out := make(map[indicators.Regime]bool)
+	if len(labels) == 0 {
+		out[def] = true
+		return out, nil
+	}
+	for _, label := range labels {
+		regime, ok := indicators.ParseRegime(strings.ToUpper(strings.TrimSpace(label)))
+		if !ok {
+			names := make([]string, len(indicators.Regimes))
+			for i, r := range indicators.Regimes {
+				names[i] = string(r)
+			}
+			return nil, fmt.Errorf("%s: unknown regime %q (%s)", field, label, strings.Join(names, ", "))
+		}
+		out[regime] = true
+	}
+	return out, nil
+}
+
+// history historique du classificateur stabilisé
+func (f *RegimeFilter) history() int {
+	cfg := f.classifier.Config()
+	size, err := IndicatorHistory(fmt.Sprintf("regime(%d,%d,%d)", cfg.Period, cfg.VWMAPeriod, cfg.PercentileLookback))
+	if err != nil {
+		size = cfg.Warmup()
+	}
+	return size
+}
+
+// CalculateIndicators délègue puis classe les régimes
+func (f *RegimeFilter) CalculateIndicators(klines []Kline) error {
+	if err := f.inner.CalculateIndicators(klines); err != nil {
+		return err
+	}
+	f.regimes = f.classifier.ClassifyFromKlines(ToIndicatorKlines(klines))
+	return nil
+}
+
+// Regimes retourne la série de régimes du dernier calcul
+func (f *RegimeFilter) Regimes() *indicators.RegimeSeries {
+	return f.regimes
+}
+
+// regimeAt régime de la bougie i (INCONNU hors fenêtre)
+func (f *RegimeFilter) regimeAt(i int) indicators.Regime {
+	if i < 0 {
+		return indicators.RegimeUnknown
+	}
+	return f.regimes.At(i)
+}
+
+// allows étiquette l'entrée avec le régime de sa bougie et indique s'il l'autorise
+func (f *RegimeFilter) allows(sig *Signal, i int) bool {
+	regime := f.regimeAt(i)
+	allowed := f.allowShort[regime]
+	if sig.Type == SignalTypeLong {
+		allowed = f.allowLong[regime]
+	}
+	if !allowed && !f.config.TagOnly {
+		return false
+	}
+	setMetadata(sig, "regime", string(regime))
+	return true
+}
+
+// tagExitRegimes étiquette une sortie avec le régime de sa bougie et celui de son entrée
+func (f *RegimeFilter) tagExitRegimes(sig *Signal, i int) {
+	setMetadata(sig, "regime", string(f.regimeAt(i)))
+	if sig.EntryTime != nil {
+		setMetadata(sig, "entry_regime", string(f.regimeAt(f.barIndex(*sig.EntryTime))))
+	}
+}
+
+// SignalRegime retourne le régime d'une position pour le regroupement des performances :
+// "entry_regime" pour une sortie, sinon "regime" (INCONNU si absent)
+func SignalRegime(sig Signal) string {
+	for _, key := range []string{"entry_regime", "regime"} {
+		if label, ok := sig.Metadata[key].(string); ok && label != "" {
+			return label
+		}
+	}
+	return string(indicators.RegimeUnknown)
+}
@@ -0,0 +1,286 @@
+package signals
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldError erreur de configuration pointant le champ YAML fautif
+// (ex: "generator.params.atr_period (line 7): must be > 0, got 0")
+type FieldError struct {
+	Field   string
+	Line    int // 0 si inconnue
+	Message string
+}
+
+// Error formate "champ (line N): message"
+func (e *FieldError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", e.Field, e.Line, e.Message)
+	}
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrorf crée une erreur sur un champ (nom YAML)
+func FieldErrorf(field, format string, args ...interface{}) *FieldError {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// CheckPeriod vérifie une période >= 1
+func CheckPeriod(field string, value int) error {
+	if value < 1 {
+		return FieldErrorf(field, "must be > 0, got %d", value)
+	}
+	return nil
+}
+
+// CheckRange vérifie min <= value <= max
+func CheckRange(field string, value, min, max float64) error {
+	if value < min || value > max {
+		return FieldErrorf(field, "must be in [%v, %v], got %v", min, max, value)
+	}
+	return nil
+}
+
+// FirstError retourne la première erreur non nil (validations enchaînées dans l'ordre des champs)
+func FirstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GeneratorFactory générateur constructible par nom depuis une config YAML
+//
+// DefaultConfig retourne un pointeur vers une struct de config (tags yaml) remplie avec
+// les valeurs par défaut ; les params YAML ne surchargent que les champs présents.
+// Validate (optionnel) retourne des FieldError nommant le champ YAML.
+type GeneratorFactory struct {
+	Name          string
+	Description   string
+	DefaultConfig func() interface{}
+	Validate      func(config interface{}) error
+	New           func(config interface{}) (Generator, error)
+}
+
+var generatorRegistry = struct {
+	mutex     sync.RWMutex
+	factories map[string]*GeneratorFactory
+}{factories: make(map[string]*GeneratorFactory)}
+
+// RegisterGenerator enregistre une factory (appelé depuis init() des packages générateurs ;
+// panique si le nom est vide ou déjà pris)
+func RegisterGenerator(factory GeneratorFactory) {
+	if factory.Name == "" || factory.DefaultConfig == nil || factory.New == nil {
+		panic(fmt.Sprintf("generator factory %q: name, default config and constructor are required", factory.Name))
+	}
+	if t := reflect.TypeOf(factory.DefaultConfig()); t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("generator factory %q: default config must be a pointer to struct", factory.Name))
+	}
+
+	generatorRegistry.mutex.Lock()
+	defer generatorRegistry.mutex.Unlock()
+	name := strings.ToLower(factory.Name)
+	if _, exists := generatorRegistry.factories[name]; exists {
+		panic(fmt.Sprintf("generator %s already registered", name))
+	}
+	generatorRegistry.factories[name] = &factory
+}
+
+// LookupGenerator retourne la factory d'un générateur
+func LookupGenerator(name string) (*GeneratorFactory, bool) {
+	generatorRegistry.mutex.RLock()
+	defer generatorRegistry.mutex.RUnlock()
+	factory, ok := generatorRegistry.factories[strings.ToLower(name)]
+	return factory, ok
+}
+
+// GeneratorNames retourne les noms enregistrés triés
+func GeneratorNames() []string {
+	generatorRegistry.mutex.RLock()
+	defer generatorRegistry.mutex.RUnlock()
+	names := make([]string, 0, len(generatorRegistry.factories))
+	for name := range generatorRegistry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParamNames noms YAML des paramètres acceptés, dans l'ordre de la struct de config
+func (f *GeneratorFactory) ParamNames() []string {
+	t := reflect.TypeOf(f.DefaultConfig()).Elem()
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := yamlFieldName(t.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// DecodeConfig applique params (mapping YAML, nil = défauts) sur la config par défaut puis valide.
+// Les erreurs sont des FieldError relatives à params (ex: "atr_period").
+func (f *GeneratorFactory) DecodeConfig(params *yaml.Node) (interface{}, error) {
+	config := f.DefaultConfig()
+	lines := make(map[string]int)
+
+	node := params
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node != nil && node.Kind != 0 && node.Tag != "!!null" {
+		if node.Kind != yaml.MappingNode {
+			return nil, &FieldError{Field: "params", Line: node.Line, Message: "must be a mapping of parameters"}
+		}
+		v := reflect.ValueOf(config).Elem()
+		fields := make(map[string]int)
+		for i := 0; i < v.NumField(); i++ {
+			if name := yamlFieldName(v.Type().Field(i)); name != "" {
+				fields[name] = i
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			index, ok := fields[key.Value]
+			if !ok {
+				return nil, &FieldError{Field: key.Value, Line: key.Line, Message: "unknown parameter"}
+			}
+			if _, dup := lines[key.Value]; dup {
+				return nil, &FieldError{Field: key.Value, Line: key.Line, Message: "duplicate parameter"}
+			}
+			lines[key.Value] = key.Line
+			if err := value.Decode(v.Field(index).Addr().Interface()); err != nil {
+				return nil, &FieldError{Field: key.Value, Line: value.Line, Message: yamlDecodeMessage(err)}
+			}
+		}
+	}
+
+	if f.Validate != nil {
+		if err := f.Validate(config); err != nil {
+			var fieldErr *FieldError
+			if errors.As(err, &fieldErr) && fieldErr.Line == 0 {
+				fieldErr.Line = lines[fieldErr.Field]
+			}
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// yamlFieldName nom YAML d'un champ exporté ("" si ignoré)
+func yamlFieldName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// yamlDecodeMessage retire le préfixe "yaml: unmarshal errors: line N:" (la ligne est portée par FieldError)
+func yamlDecodeMessage(err error) string {
+	var typeErr *yaml.TypeError
+	msg := err.Error()
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+	if strings.HasPrefix(msg, "line ") {
+		if colon := strings.Index(msg, ": "); colon >= 0 {
+			msg = msg[colon+2:]
+		}
+	}
+	return msg
+}
+
+// GeneratorSpec section YAML `generator: {name: ..., params: {...}}`
+type GeneratorSpec struct {
+	Name   string    `yaml:"name"`
+	Params yaml.Node `yaml:"params"`
+}
+
+// NewGeneratorFromSpec construit et initialise le générateur décrit par spec.
+// Les erreurs de config pointent le champ : "generator.name", "generator.params.<champ>".
+func NewGeneratorFromSpec(spec GeneratorSpec, base GeneratorConfig) (Generator, error) {
+	factory, config, err := DecodeGeneratorSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return factory.NewGenerator(config, base)
+}
+
+// DecodeGeneratorSpec résout la factory et décode/valide les params une seule fois ;
+// la config retournée peut servir à construire plusieurs générateurs (NewGenerator)
+func DecodeGeneratorSpec(spec GeneratorSpec) (*GeneratorFactory, interface{}, error) {
+	if strings.TrimSpace(spec.Name) == "" {
+		return nil, nil, FieldErrorf("generator.name", "required (available: %s)", strings.Join(GeneratorNames(), ", "))
+	}
+	factory, ok := LookupGenerator(spec.Name)
+	if !ok {
+		return nil, nil, FieldErrorf("generator.name", "unknown generator %q (available: %s)", spec.Name, strings.Join(GeneratorNames(), ", "))
+	}
+	config, err := factory.DecodeConfig(&spec.Params)
+	if err != nil {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			if fieldErr.Field == "params" {
+				fieldErr.Field = "generator.params"
+			} else {
+				fieldErr.Field = "generator.params." + fieldErr.Field
+			}
+			return nil, nil, fieldErr
+		}
+		return nil, nil, fmt.Errorf("generator.params: %w", err)
+	}
+	return factory, config, nil
+}
+
+// NewGenerator construit et initialise un générateur depuis une config décodée (DecodeConfig)
+func (f *GeneratorFactory) NewGenerator(config interface{}, base GeneratorConfig) (Generator, error) {
+	g, err := f.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("generator %s: %w", f.Name, err)
+	}
+	if err := g.Initialize(base); err != nil {
+		return nil, fmt.Errorf("generator %s: %w", f.Name, err)
+	}
+	return g, nil
+}
+
+// ParseGeneratorSpec lit la section `generator:` d'un document YAML (nil si absente)
+func ParseGeneratorSpec(data []byte) (*GeneratorSpec, error) {
+	var doc struct {
+		Generator *GeneratorSpec `yaml:"generator"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("generator: %w", err)
+	}
+	return doc.Generator, nil
+}
+
+// LoadGeneratorSpec lit la section `generator:` d'un fichier YAML (nil si absente)
+func LoadGeneratorSpec(path string) (*GeneratorSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	spec, err := ParseGeneratorSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
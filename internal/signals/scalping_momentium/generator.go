@@ -48,26 +48,26 @@ type Generator struct {
 }
 
 type Config struct {
-	ATRPeriod      int
-	BodyPctMin     float64
-	BodyATRMin     float64
-	StochKPeriod   int
-	StochKSmooth   int
-	StochDPeriod   int
-	StochKLongMax  float64
-	StochKShortMin float64
-	EnableStochCross bool
+	ATRPeriod        int     `yaml:"atr_period"`
+	BodyPctMin       float64 `yaml:"body_pct_min"`
+	BodyATRMin       float64 `yaml:"body_atr_min"`
+	StochKPeriod     int     `yaml:"stoch_k_period"`
+	StochKSmooth     int     `yaml:"stoch_k_smooth"`
+	StochDPeriod     int     `yaml:"stoch_d_period"`
+	StochKLongMax    float64 `yaml:"stoch_k_long_max"`
+	StochKShortMin   float64 `yaml:"stoch_k_short_min"`
+	EnableStochCross bool    `yaml:"enable_stoch_cross"`
 	// Optional filters
-	EnableDMICross   bool
-	DMIPeriod        int
-	EnableMFIFilter  bool
-	MFIPeriod        int
-	MFIOversold      float64
-	MFIOverbought    float64
-	EnableCCIFilter  bool
-	CCIPeriod        int
-	CCIOversold      float64
-	CCIOverbought    float64
+	EnableDMICross  bool    `yaml:"enable_dmi_cross"`
+	DMIPeriod       int     `yaml:"dmi_period"`
+	EnableMFIFilter bool    `yaml:"enable_mfi_filter"`
+	MFIPeriod       int     `yaml:"mfi_period"`
+	MFIOversold     float64 `yaml:"mfi_oversold"`
+	MFIOverbought   float64 `yaml:"mfi_overbought"`
+	EnableCCIFilter bool    `yaml:"enable_cci_filter"`
+	CCIPeriod       int     `yaml:"cci_period"`
+	CCIOversold     float64 `yaml:"cci_oversold"`
+	CCIOverbought   float64 `yaml:"cci_overbought"`
 }
 
 func NewGenerator(cfg Config) *Generator {
@@ -106,6 +106,28 @@ func (g *Generator) Initialize(config signals.GeneratorConfig) error {
 	return nil
 }
 
+// MinHistorySize historique minimal déduit du registre d'indicateurs (ATR, Stoch et filtres actifs)
+func (g *Generator) MinHistorySize() int {
+	refs := []string{
+		fmt.Sprintf("atr(%d)", g.atrPeriod),
+		fmt.Sprintf("stoch(%d,%d,%d).d", g.stochKPeriod, g.stochKSmooth, g.stochDPeriod),
+	}
+	if g.enableDMICross {
+		refs = append(refs, fmt.Sprintf("dmi(%d,%d).plus_di", g.dmiPeriod, g.dmiPeriod))
+	}
+	if g.enableMFIFilter {
+		refs = append(refs, fmt.Sprintf("mfi(%d)", g.mfiPeriod))
+	}
+	if g.enableCCIFilter {
+		refs = append(refs, fmt.Sprintf("cci(%d)", g.cciPeriod))
+	}
+	size, err := signals.IndicatorHistory(refs...)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
 func (g *Generator) CalculateIndicators(klines []signals.Kline) error {
 	if len(klines) == 0 { return fmt.Errorf("aucune kline") }
 	closes := make([]float64, len(klines))
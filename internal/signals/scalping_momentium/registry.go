@@ -0,0 +1,71 @@
+package scalping_momentium
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "scalping_momentium",
+		Description:   "Bougie impulsive (corps/ATR) + Stoch, filtres optionnels DMI/MFI/CCI",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return NewGenerator(*config.(*Config)), nil
+		},
+	})
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/scalping_momentium_demo)
+func DefaultConfig() Config {
+	return Config{
+		ATRPeriod:      3,
+		BodyPctMin:     0.60,
+		BodyATRMin:     0.60,
+		StochKPeriod:   14,
+		StochKSmooth:   3,
+		StochDPeriod:   3,
+		StochKLongMax:  50,
+		StochKShortMin: 50,
+		DMIPeriod:      14,
+		MFIPeriod:      14,
+		MFIOversold:    20,
+		MFIOverbought:  80,
+		CCIPeriod:      20,
+		CCIOversold:    -100,
+		CCIOverbought:  100,
+	}
+}
+
+// validateConfig vérifie périodes et seuils (erreurs nommant le champ YAML)
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("atr_period", c.ATRPeriod),
+		signals.CheckRange("body_pct_min", c.BodyPctMin, 0, 1),
+		signals.CheckRange("body_atr_min", c.BodyATRMin, 0, 100),
+		signals.CheckPeriod("stoch_k_period", c.StochKPeriod),
+		signals.CheckPeriod("stoch_k_smooth", c.StochKSmooth),
+		signals.CheckPeriod("stoch_d_period", c.StochDPeriod),
+		signals.CheckRange("stoch_k_long_max", c.StochKLongMax, 0, 100),
+		signals.CheckRange("stoch_k_short_min", c.StochKShortMin, 0, 100),
+	)
+	if err != nil {
+		return err
+	}
+	if c.EnableDMICross {
+		if err := signals.CheckPeriod("dmi_period", c.DMIPeriod); err != nil {
+			return err
+		}
+	}
+	if c.EnableMFIFilter {
+		if err := signals.CheckPeriod("mfi_period", c.MFIPeriod); err != nil {
+			return err
+		}
+	}
+	if c.EnableCCIFilter {
+		if err := signals.CheckPeriod("cci_period", c.CCIPeriod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -64,42 +64,47 @@ type Generator struct {
 	enableStochExtremes bool
 
 	lastProcessedIdx int
+	lastProcessed    signals.ResumePoint  // Dernière bougie traitée (snapshot)
+	resume           *signals.ResumePoint // Reprise en attente après Restore
 	metrics          signals.GeneratorMetrics
+
+	// Mode explain (nil = désactivé)
+	explain signals.ExplainSink
 }
 
 type Config struct {
-	ATRPeriod      int
-	BodyPctMin     float64
-	BodyATRMin     float64
-	StochKPeriod   int
-	StochKSmooth   int
-	StochDPeriod   int
-	StochKLongMax  float64
-	StochKShortMin float64
-	EnableStochCross bool
+	ATRPeriod        int     `yaml:"atr_period"`
+	BodyPctMin       float64 `yaml:"body_pct_min"`
+	BodyATRMin       float64 `yaml:"body_atr_min"`
+	StochKPeriod     int     `yaml:"stoch_k_period"`
+	StochKSmooth     int     `yaml:"stoch_k_smooth"`
+	StochDPeriod     int     `yaml:"stoch_d_period"`
+	StochKLongMax    float64 `yaml:"stoch_k_long_max"`
+	StochKShortMin   float64 `yaml:"stoch_k_short_min"`
+	EnableStochCross bool    `yaml:"enable_stoch_cross"`
 	// VWMA cross filter
-	EnableVwmaCross  bool
-	VwmaFast         int
-	VwmaSlow         int
+	EnableVwmaCross bool `yaml:"enable_vwma_cross"`
+	VwmaFast        int  `yaml:"vwma_fast"`
+	VwmaSlow        int  `yaml:"vwma_slow"`
 	// Optional stoch extremes
-	EnableStochExtremes bool
+	EnableStochExtremes bool `yaml:"enable_stoch_extremes"`
 	// Optional filters
-	EnableDMICross   bool
-	DMIPeriod        int
-	EnableMFIFilter  bool
-	MFIPeriod        int
-	MFIOversold      float64
-	MFIOverbought    float64
-	EnableCCIFilter  bool
-	CCIPeriod        int
-	CCIOversold      float64
-	CCIOverbought    float64
+	EnableDMICross  bool    `yaml:"enable_dmi_cross"`
+	DMIPeriod       int     `yaml:"dmi_period"`
+	EnableMFIFilter bool    `yaml:"enable_mfi_filter"`
+	MFIPeriod       int     `yaml:"mfi_period"`
+	MFIOversold     float64 `yaml:"mfi_oversold"`
+	MFIOverbought   float64 `yaml:"mfi_overbought"`
+	EnableCCIFilter bool    `yaml:"enable_cci_filter"`
+	CCIPeriod       int     `yaml:"cci_period"`
+	CCIOversold     float64 `yaml:"cci_oversold"`
+	CCIOverbought   float64 `yaml:"cci_overbought"`
 	// MACD filters
-	EnableMacdHistogramFilter bool
-	EnableMacdSigneFilter     bool
-	MacdFast                  int
-	MacdSlow                  int
-	MacdSignalPeriod          int
+	EnableMacdHistogramFilter bool `yaml:"enable_macd_histogram_filter"`
+	EnableMacdSigneFilter     bool `yaml:"enable_macd_signe_filter"`
+	MacdFast                  int  `yaml:"macd_fast"`
+	MacdSlow                  int  `yaml:"macd_slow"`
+	MacdSignalPeriod          int  `yaml:"macd_signal_period"`
 }
 
 func NewGenerator(cfg Config) *Generator {
@@ -147,6 +152,34 @@ func (g *Generator) Initialize(config signals.GeneratorConfig) error {
 	return nil
 }
 
+// MinHistorySize historique minimal déduit du registre d'indicateurs (ATR, Stoch et filtres actifs)
+func (g *Generator) MinHistorySize() int {
+	refs := []string{
+		fmt.Sprintf("atr(%d)", g.atrPeriod),
+		fmt.Sprintf("stoch(%d,%d,%d).d", g.stochKPeriod, g.stochKSmooth, g.stochDPeriod),
+	}
+	if g.enableVwmaCross && g.vwmaFast > 0 && g.vwmaSlow > 0 {
+		refs = append(refs, fmt.Sprintf("vwma(%d)", g.vwmaFast), fmt.Sprintf("vwma(%d)", g.vwmaSlow))
+	}
+	if g.enableDMICross {
+		refs = append(refs, fmt.Sprintf("dmi(%d,%d).plus_di", g.dmiPeriod, g.dmiPeriod))
+	}
+	if g.enableMFIFilter {
+		refs = append(refs, fmt.Sprintf("mfi(%d)", g.mfiPeriod))
+	}
+	if g.enableCCIFilter {
+		refs = append(refs, fmt.Sprintf("cci(%d)", g.cciPeriod))
+	}
+	if (g.enableMacdHistogramFilter || g.enableMacdSigneFilter) && g.macdFast > 0 && g.macdSlow > 0 && g.macdSignalPeriod > 0 {
+		refs = append(refs, fmt.Sprintf("macd(%d,%d,%d).signal", g.macdFast, g.macdSlow, g.macdSignalPeriod))
+	}
+	size, err := signals.IndicatorHistory(refs...)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
 func (g *Generator) CalculateIndicators(klines []signals.Kline) error {
 	if len(klines) == 0 { return fmt.Errorf("aucune kline") }
 	closes := make([]float64, len(klines))
@@ -203,6 +236,7 @@ func (g *Generator) DetectSignals(klines []signals.Kline) ([]signals.Signal, err
 	var out []signals.Signal
 	if len(klines) < 3 { return out, nil }
 	lastClosedIdx := len(klines) - 2
+	g.applyResume(klines)
 	startIdx := g.lastProcessedIdx + 1
 	warmup := max3(g.atrPeriod, g.stochKPeriod+g.stochKSmooth+g.stochDPeriod, 2)
 	if g.vwmaSlow > warmup { warmup = g.vwmaSlow }
@@ -217,125 +251,33 @@ func (g *Generator) DetectSignals(klines []signals.Kline) ([]signals.Signal, err
 	if startIdx > lastClosedIdx { return out, nil }
 
 	for i := startIdx; i <= lastClosedIdx; i++ {
-		// Indicateurs valides
-		if i >= len(g.atrValues) || math.IsNaN(g.atrValues[i]) { continue }
-		if i >= len(g.stochK) || math.IsNaN(g.stochK[i]) { continue }
-		if g.enableStochCross {
-			if i-1 < 0 || i >= len(g.stochD) || math.IsNaN(g.stochD[i]) || math.IsNaN(g.stochD[i-1]) { continue }
-		}
-		if g.enableDMICross {
-			if i-1 < 0 || i >= len(g.diPlus) || i >= len(g.diMinus) || math.IsNaN(g.diPlus[i]) || math.IsNaN(g.diMinus[i]) || math.IsNaN(g.diPlus[i-1]) || math.IsNaN(g.diMinus[i-1]) { continue }
-		}
-		if g.enableMacdSigneFilter {
-			if i >= len(g.macdLine) || i >= len(g.macdSignal) || math.IsNaN(g.macdLine[i]) || math.IsNaN(g.macdSignal[i]) { continue }
-		}
-		if g.enableMacdHistogramFilter {
-			if i >= len(g.macdHist) || math.IsNaN(g.macdHist[i]) { continue }
-		}
-
 		k := klines[i]
-		rangeHL := k.High - k.Low
-		if rangeHL <= 0 { continue }
-		body := math.Abs(k.Close - k.Open)
-		bodyPct := body / rangeHL
-		if bodyPct < g.bodyPctMin { continue }
-		if body < g.bodyATRMin * g.atrValues[i] { continue }
 
-		// Direction
-		var sigType signals.SignalType
-		if k.Close > k.Open {
-			sigType = signals.SignalTypeLong
-		} else if k.Close < k.Open {
-			sigType = signals.SignalTypeShort
-		} else {
+		// Sens candidat : couleur de bougie ; en mode explain les deux sens sont évalués
+		sides := []signals.SignalType{candleSide(k)}
+		if g.explain != nil {
+			sides = []signals.SignalType{signals.SignalTypeLong, signals.SignalTypeShort}
+		} else if sides[0] == "" {
 			continue
 		}
-
-		// Filtre Stoch valeur seuils (optionnel)
-		if g.enableStochExtremes {
-			if sigType == signals.SignalTypeLong {
-				if !(g.stochK[i] < g.stochKLongMax) { continue }
-			} else {
-				if !(g.stochK[i] > g.stochKShortMin) { continue }
-			}
-		}
-
-		// Filtre croisement Stoch K/D bar-à-barre (optionnel)
-		if g.enableStochCross {
-			prevK := g.stochK[i-1]
-			prevD := g.stochD[i-1]
-			curK := g.stochK[i]
-			curD := g.stochD[i]
-			if sigType == signals.SignalTypeLong {
-				// K traverse au-dessus de D (prev K<D et cur K>D)
-				if !(prevK < prevD && curK > curD) { continue }
-			} else {
-				// SHORT: K traverse sous D (prev K>D et cur K<D)
-				if !(prevK > prevD && curK < curD) { continue }
-			}
-		}
-
-		// Filtre croisement DMI (+DI / -DI) (optionnel)
-		if g.enableDMICross {
-			prevPlus := g.diPlus[i-1]; prevMinus := g.diMinus[i-1]
-			curPlus := g.diPlus[i];   curMinus := g.diMinus[i]
-			if sigType == signals.SignalTypeLong {
-				if !(prevPlus < prevMinus && curPlus > curMinus) { continue }
-			} else {
-				if !(prevMinus < prevPlus && curMinus > curPlus) { continue }
-			}
-		}
-
-		// Filtre MACD par signe (optionnel):
-        // LONG si MACD < 0 ET Signal < 0 ; SHORT si MACD > 0 ET Signal > 0
-        if g.enableMacdSigneFilter {
-            if sigType == signals.SignalTypeLong {
-                if !(g.macdLine[i] < 0 && g.macdSignal[i] < 0) { continue }
-            } else {
-                if !(g.macdLine[i] > 0 && g.macdSignal[i] > 0) { continue }
-            }
-        }
-
-		// Filtre MACD histogram (optionnel)
-		if g.enableMacdHistogramFilter {
-			if sigType == signals.SignalTypeLong {
-				if !(g.macdHist[i] > 0) { continue }
-			} else {
-				if !(g.macdHist[i] < 0) { continue }
-			}
-		}
-
-		// Filtre croisement VWMA (optionnel)
-		if g.enableVwmaCross {
-			cross, direction := indicators.DetecterCroisement(g.vwmaFastValues, g.vwmaSlowValues, i)
-			if !cross {
-				continue
-			}
-			if sigType == signals.SignalTypeLong && direction != "HAUSSIER" { continue }
-			if sigType == signals.SignalTypeShort && direction != "BAISSIER" { continue }
-		}
-
-		// Filtre MFI (optionnel): éviter LONG en surachat, éviter SHORT en survente
-		if g.enableMFIFilter {
-			if i >= len(g.mfiValues) || math.IsNaN(g.mfiValues[i]) { continue }
-			mv := g.mfiValues[i]
-			if sigType == signals.SignalTypeLong {
-				if mv >= g.mfiOverbought { continue }
-			} else {
-				if mv <= g.mfiOversold { continue }
+		var sigType signals.SignalType
+		explanations := make([]signals.BarExplanation, 0, len(sides))
+		for _, side := range sides {
+			exp := signals.NewBarExplanation("smart_eco", k, side, g.evaluate(klines, i, side))
+			if exp.Fired {
+				sigType = side
 			}
+			explanations = append(explanations, exp)
 		}
-
-		// Filtre CCI (optionnel): éviter LONG en surachat, éviter SHORT en survente
-		if g.enableCCIFilter {
-			if i >= len(g.cciValues) || math.IsNaN(g.cciValues[i]) { continue }
-			cv := g.cciValues[i]
-			if sigType == signals.SignalTypeLong {
-				if cv >= g.cciOverbought { continue }
-			} else {
-				if cv <= g.cciOversold { continue }
+		if sigType == "" {
+			if err := g.recordExplanations(explanations, ""); err != nil {
+				return nil, err
 			}
+			continue
 		}
+		rangeHL := k.High - k.Low
+		body := math.Abs(k.Close - k.Open)
+		bodyPct := body / rangeHL
 
 		// Label ENTRY/EXIT via références n-1/n-2
 		ref1 := refForIndex(klines[i-1], sigType)
@@ -346,6 +288,9 @@ func (g *Generator) DetectSignals(klines []signals.Kline) ([]signals.Signal, err
 		} else {
 			if k.Close <= minf(ref1, ref2) { action = signals.SignalActionEntry } else { action = signals.SignalActionExit }
 		}
+		if err := g.recordExplanations(explanations, action); err != nil {
+			return nil, err
+		}
 
 		conf := confidence(bodyPct, body/g.atrValues[i])
 		// Prepare optional indicator values for metadata
@@ -414,11 +359,136 @@ func (g *Generator) DetectSignals(klines []signals.Kline) ([]signals.Signal, err
 	}
 
 	g.lastProcessedIdx = lastClosedIdx
+	g.lastProcessed = signals.NewResumePoint(klines, lastClosedIdx)
 	return out, nil
 }
 
 func (g *Generator) GetMetrics() signals.GeneratorMetrics { return g.metrics }
 
+// SetExplainSink active le mode explain : chaque bougie évaluée produit un enregistrement par sens
+func (g *Generator) SetExplainSink(sink signals.ExplainSink) { g.explain = sink }
+
+// evaluate évalue toutes les règles d'entrée de la bougie i pour un sens (sans court-circuit).
+// Le signal est émis si toutes les règles passent.
+func (g *Generator) evaluate(klines []signals.Kline, i int, side signals.SignalType) []signals.RuleCheck {
+	k := klines[i]
+	long := side == signals.SignalTypeLong
+	nan := math.NaN()
+	atr, stochK := at(g.atrValues, i), at(g.stochK, i)
+	rangeHL := k.High - k.Low
+	body := math.Abs(k.Close - k.Open)
+	bodyPct := nan
+	if rangeHL > 0 { bodyPct = body / rangeHL }
+
+	rules := make([]signals.RuleCheck, 0, 12)
+	warm := signals.NewRuleCheck("warmup", nan, "", nan, !math.IsNaN(atr) && !math.IsNaN(stochK))
+	if !warm.Pass { warm.Detail = fmt.Sprintf("atr=%v stoch_k=%v", atr, stochK) }
+	rules = append(rules, warm)
+	if long {
+		rules = append(rules, signals.NewRuleCheck("direction", k.Close-k.Open, ">", 0, k.Close > k.Open))
+	} else {
+		rules = append(rules, signals.NewRuleCheck("direction", k.Close-k.Open, "<", 0, k.Close < k.Open))
+	}
+	rules = append(rules,
+		signals.NewRuleCheck("body_pct", bodyPct, ">=", g.bodyPctMin, rangeHL > 0 && !(bodyPct < g.bodyPctMin)),
+		signals.NewRuleCheck("body_atr", body/atr, ">=", g.bodyATRMin, !math.IsNaN(atr) && !(body < g.bodyATRMin*atr)),
+	)
+
+	if g.enableStochExtremes {
+		if long {
+			rules = append(rules, signals.NewRuleCheck("stoch_extreme", stochK, "<", g.stochKLongMax, stochK < g.stochKLongMax))
+		} else {
+			rules = append(rules, signals.NewRuleCheck("stoch_extreme", stochK, ">", g.stochKShortMin, stochK > g.stochKShortMin))
+		}
+	}
+	if g.enableStochCross {
+		rules = append(rules, crossRule("stoch_cross", long, at(g.stochK, i-1), at(g.stochD, i-1), stochK, at(g.stochD, i)))
+	}
+	if g.enableDMICross {
+		rules = append(rules, crossRule("dmi_cross", long, at(g.diPlus, i-1), at(g.diMinus, i-1), at(g.diPlus, i), at(g.diMinus, i)))
+	}
+	if g.enableMacdSigneFilter {
+		// LONG si MACD < 0 ET Signal < 0 ; SHORT si MACD > 0 ET Signal > 0
+		macd, sig := at(g.macdLine, i), at(g.macdSignal, i)
+		var r signals.RuleCheck
+		if long {
+			r = signals.NewRuleCheck("macd_sign", macd, "<", 0, macd < 0 && sig < 0)
+		} else {
+			r = signals.NewRuleCheck("macd_sign", macd, ">", 0, macd > 0 && sig > 0)
+		}
+		r.Detail = fmt.Sprintf("signal=%.6f", sig)
+		rules = append(rules, r)
+	}
+	if g.enableMacdHistogramFilter {
+		hist := at(g.macdHist, i)
+		if long {
+			rules = append(rules, signals.NewRuleCheck("macd_hist", hist, ">", 0, hist > 0))
+		} else {
+			rules = append(rules, signals.NewRuleCheck("macd_hist", hist, "<", 0, hist < 0))
+		}
+	}
+	if g.enableVwmaCross {
+		r := signals.NewRuleCheck("vwma_cross", at(g.vwmaFastValues, i)-at(g.vwmaSlowValues, i), "cross_down", 0, false)
+		want := "BAISSIER"
+		if long { r.Op, want = "cross_up", "HAUSSIER" }
+		if i < len(g.vwmaFastValues) && i < len(g.vwmaSlowValues) {
+			cross, direction := indicators.DetecterCroisement(g.vwmaFastValues, g.vwmaSlowValues, i)
+			r.Pass = cross && direction == want
+			r.Detail = direction
+		}
+		rules = append(rules, r)
+	}
+	// MFI / CCI : éviter LONG en surachat, éviter SHORT en survente
+	if g.enableMFIFilter {
+		mv := at(g.mfiValues, i)
+		if long {
+			rules = append(rules, signals.NewRuleCheck("mfi", mv, "<", g.mfiOverbought, mv < g.mfiOverbought))
+		} else {
+			rules = append(rules, signals.NewRuleCheck("mfi", mv, ">", g.mfiOversold, mv > g.mfiOversold))
+		}
+	}
+	if g.enableCCIFilter {
+		cv := at(g.cciValues, i)
+		if long {
+			rules = append(rules, signals.NewRuleCheck("cci", cv, "<", g.cciOverbought, cv < g.cciOverbought))
+		} else {
+			rules = append(rules, signals.NewRuleCheck("cci", cv, ">", g.cciOversold, cv > g.cciOversold))
+		}
+	}
+	return rules
+}
+
+// recordExplanations transmet les enregistrements au sink (action renseignée sur le sens émis)
+func (g *Generator) recordExplanations(explanations []signals.BarExplanation, action signals.SignalAction) error {
+	if g.explain == nil { return nil }
+	for _, exp := range explanations {
+		if exp.Fired { exp.Action = action }
+		if err := g.explain.Record(exp); err != nil { return err }
+	}
+	return nil
+}
+
+// crossRule croisement bar-à-barre de a au-dessus (LONG) ou au-dessous (SHORT) de b
+func crossRule(name string, long bool, prevA, prevB, curA, curB float64) signals.RuleCheck {
+	r := signals.NewRuleCheck(name, curA-curB, "cross_down", 0, prevA > prevB && curA < curB)
+	if long {
+		r = signals.NewRuleCheck(name, curA-curB, "cross_up", 0, prevA < prevB && curA > curB)
+	}
+	r.Detail = fmt.Sprintf("prev=%.4f/%.4f cur=%.4f/%.4f", prevA, prevB, curA, curB)
+	return r
+}
+
+func candleSide(k signals.Kline) signals.SignalType {
+	if k.Close > k.Open { return signals.SignalTypeLong }
+	if k.Close < k.Open { return signals.SignalTypeShort }
+	return ""
+}
+
+func at(values []float64, i int) float64 {
+	if i < 0 || i >= len(values) { return math.NaN() }
+	return values[i]
+}
+
 func refForIndex(k signals.Kline, sigType signals.SignalType) float64 {
 	if sigType == signals.SignalTypeLong {
 		// LONG: si bougie rouge -> utiliser Open, si verte -> utiliser Close
@@ -0,0 +1,102 @@
+package smart_eco
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "smart_eco",
+		Description:   "Bougie impulsive (corps/ATR) + Stoch, filtres optionnels VWMA/DMI/MFI/CCI/MACD",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return NewGenerator(*config.(*Config)), nil
+		},
+	})
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/smart_eco)
+func DefaultConfig() Config {
+	return Config{
+		ATRPeriod:        3,
+		BodyPctMin:       0.60,
+		BodyATRMin:       0.60,
+		StochKPeriod:     14,
+		StochKSmooth:     3,
+		StochDPeriod:     3,
+		StochKLongMax:    40,
+		StochKShortMin:   60,
+		VwmaFast:         6,
+		VwmaSlow:         36,
+		DMIPeriod:        14,
+		MFIPeriod:        14,
+		MFIOversold:      20,
+		MFIOverbought:    80,
+		CCIPeriod:        20,
+		CCIOversold:      -100,
+		CCIOverbought:    100,
+		MacdFast:         12,
+		MacdSlow:         26,
+		MacdSignalPeriod: 9,
+	}
+}
+
+// validateConfig vérifie périodes et seuils (erreurs nommant le champ YAML)
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("atr_period", c.ATRPeriod),
+		signals.CheckRange("body_pct_min", c.BodyPctMin, 0, 1),
+		signals.CheckRange("body_atr_min", c.BodyATRMin, 0, 100),
+		signals.CheckPeriod("stoch_k_period", c.StochKPeriod),
+		signals.CheckPeriod("stoch_k_smooth", c.StochKSmooth),
+		signals.CheckPeriod("stoch_d_period", c.StochDPeriod),
+		signals.CheckRange("stoch_k_long_max", c.StochKLongMax, 0, 100),
+		signals.CheckRange("stoch_k_short_min", c.StochKShortMin, 0, 100),
+	)
+	if err != nil {
+		return err
+	}
+	if c.EnableVwmaCross {
+		if err := signals.FirstError(signals.CheckPeriod("vwma_fast", c.VwmaFast), signals.CheckPeriod("vwma_slow", c.VwmaSlow)); err != nil {
+			return err
+		}
+		if c.VwmaFast >= c.VwmaSlow {
+			return signals.FieldErrorf("vwma_slow", "must be > vwma_fast (%d), got %d", c.VwmaFast, c.VwmaSlow)
+		}
+	}
+	if c.EnableDMICross {
+		if err := signals.CheckPeriod("dmi_period", c.DMIPeriod); err != nil {
+			return err
+		}
+	}
+	if c.EnableMFIFilter {
+		if err := signals.CheckPeriod("mfi_period", c.MFIPeriod); err != nil {
+			return err
+		}
+		if c.MFIOversold >= c.MFIOverbought {
+			return signals.FieldErrorf("mfi_overbought", "must be > mfi_oversold (%v), got %v", c.MFIOversold, c.MFIOverbought)
+		}
+	}
+	if c.EnableCCIFilter {
+		if err := signals.CheckPeriod("cci_period", c.CCIPeriod); err != nil {
+			return err
+		}
+		if c.CCIOversold >= c.CCIOverbought {
+			return signals.FieldErrorf("cci_overbought", "must be > cci_oversold (%v), got %v", c.CCIOversold, c.CCIOverbought)
+		}
+	}
+	if c.EnableMacdHistogramFilter || c.EnableMacdSigneFilter {
+		if err := signals.FirstError(
+			signals.CheckPeriod("macd_fast", c.MacdFast),
+			signals.CheckPeriod("macd_slow", c.MacdSlow),
+			signals.CheckPeriod("macd_signal_period", c.MacdSignalPeriod),
+		); err != nil {
+			return err
+		}
+		if c.MacdFast >= c.MacdSlow {
+			return signals.FieldErrorf("macd_slow", "must be > macd_fast (%d), got %d", c.MacdFast, c.MacdSlow)
+		}
+	}
+	return nil
+}
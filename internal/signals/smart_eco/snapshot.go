@@ -0,0 +1,44 @@
+package smart_eco
+
+import (
+	"agent-economique/internal/signals"
+)
+
+// snapshotVersion version du format d'état
+const snapshotVersion = 1
+
+// snapshotState état persisté : dernière bougie traitée (smart_eco ne suit pas de position)
+type snapshotState struct {
+	Resume  signals.ResumePoint      `json:"resume"`
+	Metrics signals.GeneratorMetrics `json:"metrics"`
+}
+
+// Snapshot encode l'état du générateur (JSON versionné)
+func (g *Generator) Snapshot() ([]byte, error) {
+	state := snapshotState{Resume: g.lastProcessed, Metrics: g.metrics}
+	if g.resume != nil {
+		state.Resume = *g.resume
+	}
+	return signals.EncodeSnapshot("smart_eco", snapshotVersion, state)
+}
+
+// Restore recharge l'état ; la reprise est recalée sur la prochaine fenêtre de klines
+func (g *Generator) Restore(data []byte) error {
+	var state snapshotState
+	if _, err := signals.DecodeSnapshot(data, "smart_eco", snapshotVersion, &state); err != nil {
+		return err
+	}
+	g.metrics = state.Metrics
+	g.lastProcessed = state.Resume
+	g.resume = &state.Resume
+	return nil
+}
+
+// applyResume recale lastProcessedIdx sur la fenêtre courante après un Restore
+func (g *Generator) applyResume(klines []signals.Kline) {
+	if g.resume == nil {
+		return
+	}
+	g.lastProcessedIdx = g.resume.IndexIn(klines)
+	g.resume = nil
+}
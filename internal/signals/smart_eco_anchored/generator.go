@@ -71,6 +71,7 @@ type Generator struct {
     // runtime
     config           signals.GeneratorConfig
     lastProcessedIdx int
+    metrics          signals.GeneratorMetrics
 
     // series
     atrValues []float64
@@ -92,47 +93,47 @@ type Generator struct {
 }
 
 type Config struct {
-    ATRPeriod      int
-    BodyPctMin     float64
-    BodyATRMin     float64
-
-    StochKPeriod   int
-    StochKSmooth   int
-    StochDPeriod   int
-    StochKLongMax  float64
-    StochKShortMin float64
-    EnableStochExtremes bool
-    EnableStochCross    bool
-    StochUseRelative    bool
-
-    EnableVwmaCross bool
-    VwmaFast        int
-    VwmaSlow        int
-    VwmaUseRelative bool
-
-    EnableDMICross bool
-    DMIPeriod      int
-    DmiUseRelative bool
-
-    EnableMFIFilter bool
-    MFIPeriod       int
-    MFIOversold     float64
-    MFIOverbought   float64
-
-    EnableCCIFilter bool
-    CCIPeriod       int
-    CCIOversold     float64
-    CCIOverbought   float64
-
-    EnableMacdHistogramFilter bool
-    EnableMacdSigneFilter     bool
-    MacdFast                  int
-    MacdSlow                  int
-    MacdSignalPeriod          int
+    ATRPeriod  int     `yaml:"atr_period"`
+    BodyPctMin float64 `yaml:"body_pct_min"`
+    BodyATRMin float64 `yaml:"body_atr_min"`
+
+    StochKPeriod        int     `yaml:"stoch_k_period"`
+    StochKSmooth        int     `yaml:"stoch_k_smooth"`
+    StochDPeriod        int     `yaml:"stoch_d_period"`
+    StochKLongMax       float64 `yaml:"stoch_k_long_max"`
+    StochKShortMin      float64 `yaml:"stoch_k_short_min"`
+    EnableStochExtremes bool    `yaml:"enable_stoch_extremes"`
+    EnableStochCross    bool    `yaml:"enable_stoch_cross"`
+    StochUseRelative    bool    `yaml:"stoch_use_relative"`
+
+    EnableVwmaCross bool `yaml:"enable_vwma_cross"`
+    VwmaFast        int  `yaml:"vwma_fast"`
+    VwmaSlow        int  `yaml:"vwma_slow"`
+    VwmaUseRelative bool `yaml:"vwma_use_relative"`
+
+    EnableDMICross bool `yaml:"enable_dmi_cross"`
+    DMIPeriod      int  `yaml:"dmi_period"`
+    DmiUseRelative bool `yaml:"dmi_use_relative"`
+
+    EnableMFIFilter bool    `yaml:"enable_mfi_filter"`
+    MFIPeriod       int     `yaml:"mfi_period"`
+    MFIOversold     float64 `yaml:"mfi_oversold"`
+    MFIOverbought   float64 `yaml:"mfi_overbought"`
+
+    EnableCCIFilter bool    `yaml:"enable_cci_filter"`
+    CCIPeriod       int     `yaml:"cci_period"`
+    CCIOversold     float64 `yaml:"cci_oversold"`
+    CCIOverbought   float64 `yaml:"cci_overbought"`
+
+    EnableMacdHistogramFilter bool `yaml:"enable_macd_histogram_filter"`
+    EnableMacdSigneFilter     bool `yaml:"enable_macd_signe_filter"`
+    MacdFast                  int  `yaml:"macd_fast"`
+    MacdSlow                  int  `yaml:"macd_slow"`
+    MacdSignalPeriod          int  `yaml:"macd_signal_period"`
 
     // Anchored behavior
-    WindowSize       int // default 20
-    AnchorByCrossOnly bool
+    WindowSize        int  `yaml:"window_size"` // default 20
+    AnchorByCrossOnly bool `yaml:"anchor_by_cross_only"`
 }
 
 func NewGenerator(cfg Config) *Generator {
@@ -193,6 +194,35 @@ func (g *Generator) Initialize(config signals.GeneratorConfig) error {
     return nil
 }
 
+// MinHistorySize historique minimal déduit du registre d'indicateurs, plus la demi-fenêtre
+// précédant l'ancre
+func (g *Generator) MinHistorySize() int {
+    refs := []string{
+        fmt.Sprintf("atr(%d)", g.atrPeriod),
+        fmt.Sprintf("stoch(%d,%d,%d).d", g.stochKPeriod, g.stochKSmooth, g.stochDPeriod),
+    }
+    if (g.enableVwmaCross || g.vwmaUseRelative) && g.vwmaFast > 0 && g.vwmaSlow > 0 {
+        refs = append(refs, fmt.Sprintf("vwma(%d)", g.vwmaFast), fmt.Sprintf("vwma(%d)", g.vwmaSlow))
+    }
+    if g.enableDMICross || g.dmiUseRelative {
+        refs = append(refs, fmt.Sprintf("dmi(%d,%d).plus_di", g.dmiPeriod, g.dmiPeriod))
+    }
+    if g.enableMFIFilter {
+        refs = append(refs, fmt.Sprintf("mfi(%d)", g.mfiPeriod))
+    }
+    if g.enableCCIFilter {
+        refs = append(refs, fmt.Sprintf("cci(%d)", g.cciPeriod))
+    }
+    if g.enableMacdHistogramFilter || g.enableMacdSigneFilter {
+        refs = append(refs, fmt.Sprintf("macd(%d,%d,%d).signal", g.macdFast, g.macdSlow, g.macdSignalPeriod))
+    }
+    size, err := signals.IndicatorHistory(refs...)
+    if err != nil {
+        return 0
+    }
+    return size + g.windowSize/2
+}
+
 func (g *Generator) CalculateIndicators(klines []signals.Kline) error {
     if len(klines) == 0 { return fmt.Errorf("aucune kline") }
     closes := make([]float64, len(klines))
@@ -365,9 +395,18 @@ func (g *Generator) DetectSignals(klines []signals.Kline) ([]signals.Signal, err
         break
     }
 
+    for _, sig := range out {
+        g.metrics.TotalSignals++
+        if sig.Action == signals.SignalActionEntry { g.metrics.EntrySignals++ } else { g.metrics.ExitSignals++ }
+        if sig.Type == signals.SignalTypeLong { g.metrics.LongSignals++ } else { g.metrics.ShortSignals++ }
+        g.metrics.AvgConfidence += (sig.Confidence - g.metrics.AvgConfidence) / float64(g.metrics.TotalSignals)
+        g.metrics.LastSignalTime = sig.Timestamp
+    }
     return out, nil
 }
 
+func (g *Generator) GetMetrics() signals.GeneratorMetrics { return g.metrics }
+
 func (g *Generator) findAnchor(warmup, last int) (idx int, side signals.SignalType, ok bool) {
     // Check if any cross-based anchor is allowed
     crossesSelected := g.enableStochCross || g.enableDMICross || g.enableVwmaCross
@@ -0,0 +1,86 @@
+package smart_eco_anchored
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "smart_eco_anchored",
+		Description:   "smart_eco avec ancrage des confirmations dans une fenêtre après la bougie impulsive",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return NewGenerator(*config.(*Config)), nil
+		},
+	})
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/smart_eco_anchored)
+func DefaultConfig() Config {
+	return Config{
+		ATRPeriod:           3,
+		BodyPctMin:          0.60,
+		BodyATRMin:          0.60,
+		StochKPeriod:        14,
+		StochKSmooth:        3,
+		StochDPeriod:        3,
+		StochKLongMax:       40,
+		StochKShortMin:      60,
+		EnableStochExtremes: true,
+		VwmaFast:            6,
+		VwmaSlow:            36,
+		DMIPeriod:           14,
+		MFIPeriod:           14,
+		MFIOversold:         20,
+		MFIOverbought:       80,
+		CCIPeriod:           20,
+		CCIOversold:         -100,
+		CCIOverbought:       100,
+		MacdFast:            12,
+		MacdSlow:            26,
+		MacdSignalPeriod:    9,
+		WindowSize:          20,
+		AnchorByCrossOnly:   true,
+	}
+}
+
+// validateConfig vérifie périodes et seuils (erreurs nommant le champ YAML)
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("atr_period", c.ATRPeriod),
+		signals.CheckRange("body_pct_min", c.BodyPctMin, 0, 1),
+		signals.CheckRange("body_atr_min", c.BodyATRMin, 0, 100),
+		signals.CheckPeriod("stoch_k_period", c.StochKPeriod),
+		signals.CheckPeriod("stoch_k_smooth", c.StochKSmooth),
+		signals.CheckPeriod("stoch_d_period", c.StochDPeriod),
+		signals.CheckRange("stoch_k_long_max", c.StochKLongMax, 0, 100),
+		signals.CheckRange("stoch_k_short_min", c.StochKShortMin, 0, 100),
+		signals.CheckPeriod("window_size", c.WindowSize),
+	)
+	if err != nil {
+		return err
+	}
+	if c.EnableVwmaCross && c.VwmaFast >= c.VwmaSlow {
+		return signals.FieldErrorf("vwma_slow", "must be > vwma_fast (%d), got %d", c.VwmaFast, c.VwmaSlow)
+	}
+	if c.EnableDMICross {
+		if err := signals.CheckPeriod("dmi_period", c.DMIPeriod); err != nil {
+			return err
+		}
+	}
+	if c.EnableMFIFilter {
+		if err := signals.CheckPeriod("mfi_period", c.MFIPeriod); err != nil {
+			return err
+		}
+	}
+	if c.EnableCCIFilter {
+		if err := signals.CheckPeriod("cci_period", c.CCIPeriod); err != nil {
+			return err
+		}
+	}
+	if (c.EnableMacdHistogramFilter || c.EnableMacdSigneFilter) && c.MacdFast >= c.MacdSlow {
+		return signals.FieldErrorf("macd_slow", "must be > macd_fast (%d), got %d", c.MacdFast, c.MacdSlow)
+	}
+	return nil
+}
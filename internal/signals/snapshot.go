@@ -0,0 +1,141 @@
+package signals
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Snapshotter interface optionnelle des générateurs à état (positions ouvertes, trailings,
+// dernière bougie traitée). Snapshot encode l'état en JSON versionné ; Restore le recharge
+// sur un générateur neuf configuré à l'identique, avant le prochain DetectSignals.
+type Snapshotter interface {
+	Snapshot() ([]byte, error)
+	Restore(data []byte) error
+}
+
+// GeneratorSnapshot enveloppe commune : nom du générateur, version du format, état propre
+type GeneratorSnapshot struct {
+	Generator string          `json:"generator"`
+	Version   int             `json:"version"`
+	SavedAt   time.Time       `json:"saved_at"`
+	State     json.RawMessage `json:"state"`
+}
+
+// EncodeSnapshot encode l'état d'un générateur dans l'enveloppe versionnée
+func EncodeSnapshot(generator string, version int, state interface{}) ([]byte, error) {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", generator, err)
+	}
+	return json.Marshal(GeneratorSnapshot{Generator: generator, Version: version, SavedAt: time.Now().UTC(), State: raw})
+}
+
+// DecodeSnapshot vérifie l'enveloppe puis décode l'état ; retourne la version lue
+// (versions 1..maxVersion acceptées, la migration éventuelle revient au générateur)
+func DecodeSnapshot(data []byte, generator string, maxVersion int, state interface{}) (int, error) {
+	var snap GeneratorSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return 0, fmt.Errorf("snapshot: %w", err)
+	}
+	if snap.Generator != generator {
+		return 0, fmt.Errorf("generator: snapshot of %q, expected %q", snap.Generator, generator)
+	}
+	if snap.Version < 1 || snap.Version > maxVersion {
+		return 0, fmt.Errorf("version: unsupported snapshot version %d (1..%d)", snap.Version, maxVersion)
+	}
+	if err := json.Unmarshal(snap.State, state); err != nil {
+		return 0, fmt.Errorf("state: %w", err)
+	}
+	return snap.Version, nil
+}
+
+// ResumePoint dernière bougie traitée : index dans la fenêtre de l'époque et OpenTime.
+// Les index des générateurs sont relatifs à la fenêtre de klines ; après un Restore, la
+// fenêtre a glissé et l'OpenTime permet de retrouver l'index correspondant.
+type ResumePoint struct {
+	Index int       `json:"index"`
+	Time  time.Time `json:"time"`
+}
+
+// NewResumePoint point de reprise après traitement de klines[index] (-1 = rien de traité)
+func NewResumePoint(klines []Kline, index int) ResumePoint {
+	if index < 0 || index >= len(klines) {
+		return ResumePoint{Index: -1}
+	}
+	return ResumePoint{Index: index, Time: klines[index].OpenTime}
+}
+
+// IndexIn index de la bougie de reprise dans klines : dernière bougie d'OpenTime <= Time (-1 sinon)
+func (p ResumePoint) IndexIn(klines []Kline) int {
+	if p.Time.IsZero() {
+		return -1
+	}
+	idx := -1
+	for i, k := range klines {
+		if k.OpenTime.After(p.Time) {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
+
+// Shift décalage à appliquer aux index sauvegardés pour la fenêtre klines
+func (p ResumePoint) Shift(klines []Kline) int {
+	return p.IndexIn(klines) - p.Index
+}
+
+// SaveSnapshot écrit l'état du générateur dans path (écriture atomique) ;
+// false si le générateur n'est pas Snapshotter
+func SaveSnapshot(g Generator, path string) (bool, error) {
+	s, ok := g.(Snapshotter)
+	if !ok {
+		return false, nil
+	}
+	data, err := s.Snapshot()
+	if err != nil {
+		return true, err
+	}
+	return true, WriteSnapshotFile(path, data)
+}
+
+// LoadSnapshot recharge l'état depuis path ; false si le générateur n'est pas Snapshotter
+// ou si le fichier n'existe pas (premier démarrage)
+func LoadSnapshot(g Generator, path string) (bool, error) {
+	s, ok := g.(Snapshotter)
+	if !ok {
+		return false, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("snapshot: %w", err)
+	}
+	if err := s.Restore(data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WriteSnapshotFile écrit data via un fichier temporaire renommé (pas de fichier tronqué en cas de crash)
+func WriteSnapshotFile(path string, data []byte) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("snapshot: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	return nil
+}
@@ -40,10 +40,26 @@ type TrendGenerator struct {
 	adx              []float64
 	atr              []float64
 	lastProcessedIdx int
+	lastProcessed    signals.ResumePoint  // Dernière bougie traitée (snapshot)
+	resume           *signals.ResumePoint // Reprise en attente après Restore
 	openPositions    map[int]*OpenPosition // index → position
 	nextPositionID   int
 	metrics          signals.GeneratorMetrics
 
+	// Filtres squeeze (BB dans KC) et cassure Donchian
+	enableSqueezeFilter  bool
+	enableBreakoutFilter bool
+	bbPeriode            int
+	bbMult               float64
+	kcPeriode            int
+	kcMult               float64
+	kcAtrPeriode         int
+	donchianPeriode      int
+	breakoutWindow       int
+	squeeze              []bool
+	donchianHaut         []float64
+	donchianBas          []float64
+
 	// Sorties indépendantes
 	enableExitVWMA     bool
 	enableExitTrailing bool
@@ -63,28 +79,41 @@ type OpenPosition struct {
 
 // Config configuration spécifique Trend
 type Config struct {
-	VwmaRapide          int
-	VwmaLent            int
-	DmiPeriode          int
-	DmiSmooth           int
-	AtrPeriode          int
-	GammaGapVWMA        float64
-	GammaGapDI          float64
-	GammaGapDX          float64
-	VolatiliteMin       float64
-	WindowGammaValidate int
-	WindowW             int
+	VwmaRapide          int     `yaml:"vwma_rapide"`
+	VwmaLent            int     `yaml:"vwma_lent"`
+	DmiPeriode          int     `yaml:"dmi_periode"`
+	DmiSmooth           int     `yaml:"dmi_smooth"`
+	AtrPeriode          int     `yaml:"atr_periode"`
+	GammaGapVWMA        float64 `yaml:"gamma_gap_vwma"`
+	GammaGapDI          float64 `yaml:"gamma_gap_di"`
+	GammaGapDX          float64 `yaml:"gamma_gap_dx"`
+	VolatiliteMin       float64 `yaml:"volatilite_min"`
+	WindowGammaValidate int     `yaml:"window_gamma_validate"`
+	WindowW             int     `yaml:"window_w"`
 
 	// Filtres de bougie initiale
-	BodyPctMin             float64
-	BodyATRMin             float64
-	EnforceCandleDirection bool
+	BodyPctMin             float64 `yaml:"body_pct_min"`
+	BodyATRMin             float64 `yaml:"body_atr_min"`
+	EnforceCandleDirection bool    `yaml:"enforce_candle_direction"`
+
+	// Filtre squeeze : bloque les entrées tant que les bandes de Bollinger sont dans le canal de Keltner
+	EnableSqueezeFilter bool    `yaml:"enable_squeeze_filter"`
+	BBPeriode           int     `yaml:"bb_periode"`     // Défaut 20
+	BBMult              float64 `yaml:"bb_mult"`        // Défaut 2.0
+	KCPeriode           int     `yaml:"kc_periode"`     // Défaut 20
+	KCMult              float64 `yaml:"kc_mult"`        // Défaut 1.5 (squeeze TTM)
+	KCAtrPeriode        int     `yaml:"kc_atr_periode"` // Défaut 10
+
+	// Filtre cassure : exige un close au-delà du canal de Donchian précédent dans le sens de l'entrée
+	EnableBreakoutFilter bool `yaml:"enable_breakout_filter"`
+	DonchianPeriode      int  `yaml:"donchian_periode"` // Défaut 20
+	BreakoutWindow       int  `yaml:"breakout_window"`  // Bougies avant l'entrée où la cassure est acceptée (défaut 0 = bougie d'entrée)
 
 	// Sorties indépendantes
-	EnableExitVWMA      bool
-	EnableExitTrailing  bool
-	TrailingATRCoeff    float64
-	TrailingCapPct      float64
+	EnableExitVWMA     bool    `yaml:"enable_exit_vwma"`
+	EnableExitTrailing bool    `yaml:"enable_exit_trailing"`
+	TrailingATRCoeff   float64 `yaml:"trailing_atr_coeff"`
+	TrailingCapPct     float64 `yaml:"trailing_cap_pct"`
 }
 
 // SignalVWMA signal VWMA détecté
@@ -159,7 +188,30 @@ func NewTrendGenerator(config Config) *TrendGenerator {
 		bodyPctMin:             config.BodyPctMin,
 		bodyATRMin:             config.BodyATRMin,
 		enforceCandleDirection: config.EnforceCandleDirection,
+		enableSqueezeFilter:    config.EnableSqueezeFilter,
+		enableBreakoutFilter:   config.EnableBreakoutFilter,
+		bbPeriode:              intOrDefault(config.BBPeriode, 20),
+		bbMult:                 floatOrDefault(config.BBMult, 2.0),
+		kcPeriode:              intOrDefault(config.KCPeriode, 20),
+		kcMult:                 floatOrDefault(config.KCMult, 1.5),
+		kcAtrPeriode:           intOrDefault(config.KCAtrPeriode, 10),
+		donchianPeriode:        intOrDefault(config.DonchianPeriode, 20),
+		breakoutWindow:         config.BreakoutWindow,
+	}
+}
+
+func intOrDefault(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+func floatOrDefault(v, def float64) float64 {
+	if v > 0 {
+		return v
 	}
+	return def
 }
 
 // Name retourne le nom du générateur
@@ -167,6 +219,29 @@ func (g *TrendGenerator) Name() string {
 	return "TrendVWMADMI"
 }
 
+// MinHistorySize historique minimal déduit du registre d'indicateurs (VWMA, ATR, ADX stabilisés)
+func (g *TrendGenerator) MinHistorySize() int {
+	refs := []string{
+		fmt.Sprintf("vwma(%d)", g.vwmaRapide),
+		fmt.Sprintf("vwma(%d)", g.vwmaLent),
+		fmt.Sprintf("atr(%d)", g.atrPeriode),
+		fmt.Sprintf("dmi(%d,%d).adx", g.dmiPeriode, g.dmiPeriode),
+	}
+	if g.enableSqueezeFilter {
+		refs = append(refs,
+			fmt.Sprintf("bb(%d,%g).upper", g.bbPeriode, g.bbMult),
+			fmt.Sprintf("kc(%d,%g,%d).upper", g.kcPeriode, g.kcMult, g.kcAtrPeriode))
+	}
+	if g.enableBreakoutFilter {
+		refs = append(refs, fmt.Sprintf("donchian(%d).upper", g.donchianPeriode))
+	}
+	size, err := signals.IndicatorHistory(refs...)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
 // Initialize initialise le générateur
 func (g *TrendGenerator) Initialize(config signals.GeneratorConfig) error {
 	g.config = config
@@ -224,6 +299,18 @@ func (g *TrendGenerator) CalculateIndicators(klines []signals.Kline) error {
 		}
 	}
 
+	// Squeeze BB/KC et canal de Donchian (si filtres activés)
+	g.squeeze = nil
+	if g.enableSqueezeFilter {
+		_, bbUpper, bbLower := indicators.NewBollingerTVStandard(g.bbPeriode, g.bbMult).Calculate(closes)
+		_, kcUpper, kcLower := indicators.NewKeltnerTVStandard(g.kcPeriode, g.kcMult, g.kcAtrPeriode).Calculate(highs, lows, closes)
+		g.squeeze = indicators.DetecterSqueeze(bbUpper, bbLower, kcUpper, kcLower)
+	}
+	g.donchianHaut, g.donchianBas = nil, nil
+	if g.enableBreakoutFilter {
+		g.donchianHaut, g.donchianBas, _ = indicators.NewDonchianTVStandard(g.donchianPeriode).Calculate(highs, lows)
+	}
+
 	return nil
 }
 
@@ -238,6 +325,8 @@ func (g *TrendGenerator) DetectSignals(klines []signals.Kline) ([]signals.Signal
 		return newSignals, nil
 	}
 
+	g.applyResume(klines)
+
 	// Mode demo: traiter TOUTES les klines si c'est le premier appel
 	startIdx := g.lastProcessedIdx + 1
 	if g.lastProcessedIdx == -1 {
@@ -264,6 +353,7 @@ func (g *TrendGenerator) DetectSignals(klines []signals.Kline) ([]signals.Signal
 
 	// Mettre à jour avec la dernière bougie fermée traitée
 	g.lastProcessedIdx = lastClosedIdx
+	g.lastProcessed = signals.NewResumePoint(klines, lastClosedIdx)
 	g.metrics.TotalSignals += len(newSignals)
 
 	return newSignals, nil
@@ -307,6 +397,13 @@ func (g *TrendGenerator) detectEntrySignals(klines []signals.Kline, startIdx int
 			if sigType == signals.SignalTypeLong && !(k.Close > k.Open) { continue }
 			if sigType == signals.SignalTypeShort && !(k.Close < k.Open) { continue }
 		}
+		// Filtres squeeze / cassure (bougies <= i uniquement)
+		if g.enableSqueezeFilter && i < len(g.squeeze) && g.squeeze[i] { continue }
+		breakoutBars := -1
+		if g.enableBreakoutFilter {
+			breakoutBars = g.barsSinceBreakout(klines, i, ms.Direction)
+			if breakoutBars < 0 { continue }
+		}
 
 		sig := signals.Signal{
 			Timestamp:  ms.Timestamp,
@@ -325,6 +422,9 @@ func (g *TrendGenerator) detectEntrySignals(klines []signals.Kline, startIdx int
 				"body_to_atr":   func() float64 { if atrVal>0 { return bodyAbs/atrVal }; return 0 }(),
 			},
 		}
+		if g.enableBreakoutFilter {
+			sig.Metadata["breakout_bars"] = breakoutBars
+		}
 
 		entrySignals = append(entrySignals, sig)
 
@@ -360,6 +460,22 @@ func (g *TrendGenerator) detectEntrySignals(klines []signals.Kline, startIdx int
 	return entrySignals
 }
 
+// barsSinceBreakout cherche une cassure Donchian dans le sens de l'entrée sur [i-breakoutWindow, i]
+// Retourne le nombre de bougies depuis la cassure la plus récente, -1 si aucune
+func (g *TrendGenerator) barsSinceBreakout(klines []signals.Kline, i int, direction string) int {
+	dc := indicators.NewDonchianTVStandard(g.donchianPeriode)
+	closes := make([]float64, i+1)
+	for j := range closes {
+		closes[j] = klines[j].Close
+	}
+	for j := i; j >= 0 && j >= i-g.breakoutWindow; j-- {
+		if dc.DetecterCassure(closes, g.donchianHaut, g.donchianBas, j) == direction {
+			return i - j
+		}
+	}
+	return -1
+}
+
 // detectExitSignals détecte les sorties par croisement inverse VWMA
 func (g *TrendGenerator) detectExitSignals(klines []signals.Kline, startIdx int, lastClosedIdx int) []signals.Signal {
 	var exitSignals []signals.Signal
@@ -0,0 +1,78 @@
+package trend
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "trend",
+		Description:   "Croisement VWMA rapide/lente + DMI, filtres bougie/squeeze/cassure, sorties VWMA et trailing ATR",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return NewTrendGenerator(*config.(*Config)), nil
+		},
+	})
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/trend_generator_demo)
+func DefaultConfig() Config {
+	return Config{
+		VwmaRapide:             5,
+		VwmaLent:               15,
+		DmiPeriode:             5,
+		DmiSmooth:              3,
+		AtrPeriode:             3,
+		GammaGapVWMA:           0.5,
+		GammaGapDI:             2.0,
+		GammaGapDX:             2.0,
+		VolatiliteMin:          0.3,
+		WindowGammaValidate:    5,
+		WindowW:                10,
+		BodyPctMin:             0.60,
+		BodyATRMin:             0.60,
+		EnforceCandleDirection: true,
+		BBPeriode:              20,
+		BBMult:                 2.0,
+		KCPeriode:              20,
+		KCMult:                 1.5,
+		KCAtrPeriode:           10,
+		DonchianPeriode:        20,
+		BreakoutWindow:         3,
+		EnableExitVWMA:         true,
+		TrailingATRCoeff:       1.0,
+		TrailingCapPct:         0.003,
+	}
+}
+
+// validateConfig vérifie périodes et seuils (erreurs nommant le champ YAML)
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("vwma_rapide", c.VwmaRapide),
+		signals.CheckPeriod("vwma_lent", c.VwmaLent),
+		signals.CheckPeriod("dmi_periode", c.DmiPeriode),
+		signals.CheckPeriod("dmi_smooth", c.DmiSmooth),
+		signals.CheckPeriod("atr_periode", c.AtrPeriode),
+		signals.CheckPeriod("window_w", c.WindowW),
+		signals.CheckRange("body_pct_min", c.BodyPctMin, 0, 1),
+		signals.CheckRange("body_atr_min", c.BodyATRMin, 0, 100),
+		signals.CheckRange("trailing_cap_pct", c.TrailingCapPct, 0, 1),
+	)
+	if err != nil {
+		return err
+	}
+	if c.VwmaRapide >= c.VwmaLent {
+		return signals.FieldErrorf("vwma_lent", "must be > vwma_rapide (%d), got %d", c.VwmaRapide, c.VwmaLent)
+	}
+	if c.WindowGammaValidate < 0 {
+		return signals.FieldErrorf("window_gamma_validate", "must be >= 0, got %d", c.WindowGammaValidate)
+	}
+	if c.BreakoutWindow < 0 {
+		return signals.FieldErrorf("breakout_window", "must be >= 0, got %d", c.BreakoutWindow)
+	}
+	if !c.EnableExitVWMA && !c.EnableExitTrailing {
+		return signals.FieldErrorf("enable_exit_vwma", "at least one of enable_exit_vwma / enable_exit_trailing must be true")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package trend
+
+import (
+	"sort"
+
+	"agent-economique/internal/execution"
+	"agent-economique/internal/signals"
+)
+
+// snapshotVersion version du format d'état
+const snapshotVersion = 1
+
+// positionState position ouverte et son trailing éventuel
+type positionState struct {
+	OpenPosition
+	Trailing *execution.Trailing `json:"trailing,omitempty"`
+}
+
+// snapshotState état persisté : positions ouvertes, trailings et dernière bougie traitée
+type snapshotState struct {
+	Resume         signals.ResumePoint      `json:"resume"`
+	Positions      []positionState          `json:"positions"`
+	NextPositionID int                      `json:"next_position_id"`
+	Metrics        signals.GeneratorMetrics `json:"metrics"`
+}
+
+// Snapshot encode l'état du générateur (JSON versionné)
+func (g *TrendGenerator) Snapshot() ([]byte, error) {
+	state := snapshotState{Resume: g.lastProcessed, NextPositionID: g.nextPositionID, Metrics: g.metrics}
+	if g.resume != nil {
+		state.Resume = *g.resume
+	}
+	for id, pos := range g.openPositions {
+		state.Positions = append(state.Positions, positionState{OpenPosition: *pos, Trailing: g.trailings[id]})
+	}
+	sort.Slice(state.Positions, func(a, b int) bool { return state.Positions[a].ID < state.Positions[b].ID })
+	return signals.EncodeSnapshot("trend", snapshotVersion, state)
+}
+
+// Restore recharge l'état ; les index sont recalés sur la prochaine fenêtre de klines
+func (g *TrendGenerator) Restore(data []byte) error {
+	var state snapshotState
+	if _, err := signals.DecodeSnapshot(data, "trend", snapshotVersion, &state); err != nil {
+		return err
+	}
+	g.openPositions = make(map[int]*OpenPosition, len(state.Positions))
+	g.trailings = make(map[int]*execution.Trailing)
+	for _, ps := range state.Positions {
+		pos := ps.OpenPosition
+		g.openPositions[pos.ID] = &pos
+		if ps.Trailing != nil {
+			g.trailings[pos.ID] = ps.Trailing
+		}
+	}
+	g.nextPositionID = state.NextPositionID
+	g.metrics = state.Metrics
+	g.lastProcessed = state.Resume
+	g.resume = &state.Resume
+	return nil
+}
+
+// applyResume recale lastProcessedIdx et les index d'entrée sur la fenêtre courante après un Restore
+func (g *TrendGenerator) applyResume(klines []signals.Kline) {
+	if g.resume == nil {
+		return
+	}
+	shift := g.resume.Shift(klines)
+	g.lastProcessedIdx = g.resume.Index + shift
+	for _, pos := range g.openPositions {
+		pos.EntryIndex += shift
+	}
+	g.resume = nil
+}
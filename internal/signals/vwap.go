@@ -0,0 +1,30 @@
+package signals
+
+import (
+	"time"
+
+	"agent-economique/internal/indicators"
+)
+
+// SignalAnchorTime instant d'ancrage d'un signal : entrée de la position (EntryTime) pour un EXIT, sinon sa bougie
+func SignalAnchorTime(sig Signal) time.Time {
+	if sig.EntryTime != nil {
+		return *sig.EntryTime
+	}
+	return sig.Timestamp
+}
+
+// AnchoredVWAP VWAP ancré (hlc3) et écart-type depuis un instant ; NaN avant la bougie d'ancrage
+func AnchoredVWAP(klines []Kline, anchor time.Time) (vwap, stdev []float64) {
+	return indicators.NewAnchoredVWAPTVStandard(anchor.UnixMilli()).CalculateFromKlines(ToIndicatorKlines(klines))
+}
+
+// AnchoredVWAPFromSignal VWAP ancré sur la bougie d'entrée d'un signal (idée smart_eco_anchored)
+func AnchoredVWAPFromSignal(klines []Kline, sig Signal) (vwap, stdev []float64) {
+	return AnchoredVWAP(klines, SignalAnchorTime(sig))
+}
+
+// SessionVWAP VWAP de session (hlc3) et écart-type, reset quotidien UTC par défaut
+func SessionVWAP(klines []Kline, session indicators.VWAPSession) (vwap, stdev []float64) {
+	return indicators.NewVWAPTVStandard(session).CalculateFromKlines(ToIndicatorKlines(klines))
+}
@@ -27,16 +27,18 @@ type VWMACrossDMISimpleGenerator struct {
 	adx            []float64
 	currentPosition signals.SignalType // LONG ou SHORT (pas de NONE)
 	lastProcessedIdx int
+	lastProcessed    signals.ResumePoint  // Dernière bougie traitée (snapshot)
+	resume           *signals.ResumePoint // Reprise en attente après Restore
 	metrics          signals.GeneratorMetrics
 }
 
 // Config configuration spécifique VWMA Cross + DMI Simple
 type Config struct {
-	VWMAShortPeriod int
-	VWMALongPeriod  int
-	DMIPeriod        int
-	DMISmooth        int
-	WindowMatching   int
+	VWMAShortPeriod int `yaml:"vwma_short_period"`
+	VWMALongPeriod  int `yaml:"vwma_long_period"`
+	DMIPeriod       int `yaml:"dmi_period"`
+	DMISmooth       int `yaml:"dmi_smooth"`
+	WindowMatching  int `yaml:"window_matching"`
 }
 
 // NewVWMACrossDMISimpleGenerator crée un nouveau générateur VWMA Cross + DMI Simple
@@ -63,6 +65,19 @@ func (g *VWMACrossDMISimpleGenerator) Initialize(config signals.GeneratorConfig)
 	return nil
 }
 
+// MinHistorySize historique minimal déduit du registre d'indicateurs (VWMA long, ADX stabilisé)
+func (g *VWMACrossDMISimpleGenerator) MinHistorySize() int {
+	size, err := signals.IndicatorHistory(
+		fmt.Sprintf("vwma(%d)", g.vwmaShortPeriod),
+		fmt.Sprintf("vwma(%d)", g.vwmaLongPeriod),
+		fmt.Sprintf("dmi(%d,%d).adx", g.dmiPeriod, g.dmiPeriod),
+	)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
 // CalculateIndicators calcule VWMA court/long et DMI
 func (g *VWMACrossDMISimpleGenerator) CalculateIndicators(klines []signals.Kline) error {
 	if len(klines) < g.vwmaLongPeriod {
@@ -115,6 +130,7 @@ func (g *VWMACrossDMISimpleGenerator) DetectSignals(klines []signals.Kline) ([]s
 	if lastClosedIdx < 0 {
 		return newSignals, nil
 	}
+	g.applyResume(klines)
 
 	// Démarrer après la période de warmup
 	startIdx := g.lastProcessedIdx + 1
@@ -152,6 +168,7 @@ func (g *VWMACrossDMISimpleGenerator) DetectSignals(klines []signals.Kline) ([]s
 
 	// Mettre à jour avec la dernière bougie fermée traitée
 	g.lastProcessedIdx = lastClosedIdx
+	g.lastProcessed = signals.NewResumePoint(klines, lastClosedIdx)
 
 	// Calculer confiance moyenne
 	if len(newSignals) > 0 {
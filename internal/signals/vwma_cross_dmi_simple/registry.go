@@ -0,0 +1,46 @@
+package vwma_cross_dmi_simple
+
+import (
+	"agent-economique/internal/signals"
+)
+
+func init() {
+	signals.RegisterGenerator(signals.GeneratorFactory{
+		Name:          "vwma_cross_dmi_simple",
+		Description:   "Croisement VWMA court/long confirmé par DMI dans une fenêtre de matching",
+		DefaultConfig: func() interface{} { cfg := DefaultConfig(); return &cfg },
+		Validate:      func(config interface{}) error { return validateConfig(config.(*Config)) },
+		New: func(config interface{}) (signals.Generator, error) {
+			return NewVWMACrossDMISimpleGenerator(*config.(*Config)), nil
+		},
+	})
+}
+
+// DefaultConfig valeurs par défaut (alignées sur cmd/vwma_cross_dmi_simple_demo)
+func DefaultConfig() Config {
+	return Config{
+		VWMAShortPeriod: 4,
+		VWMALongPeriod:  12,
+		DMIPeriod:       14,
+		DMISmooth:       6,
+		WindowMatching:  5,
+	}
+}
+
+// validateConfig vérifie les périodes (erreurs nommant le champ YAML)
+func validateConfig(c *Config) error {
+	err := signals.FirstError(
+		signals.CheckPeriod("vwma_short_period", c.VWMAShortPeriod),
+		signals.CheckPeriod("vwma_long_period", c.VWMALongPeriod),
+		signals.CheckPeriod("dmi_period", c.DMIPeriod),
+		signals.CheckPeriod("dmi_smooth", c.DMISmooth),
+		signals.CheckPeriod("window_matching", c.WindowMatching),
+	)
+	if err != nil {
+		return err
+	}
+	if c.VWMAShortPeriod >= c.VWMALongPeriod {
+		return signals.FieldErrorf("vwma_long_period", "must be > vwma_short_period (%d), got %d", c.VWMAShortPeriod, c.VWMALongPeriod)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package vwma_cross_dmi_simple
+
+import (
+	"agent-economique/internal/signals"
+)
+
+// snapshotVersion version du format d'état
+const snapshotVersion = 1
+
+// snapshotState état persisté : position courante et dernière bougie traitée
+type snapshotState struct {
+	Resume   signals.ResumePoint      `json:"resume"`
+	Position signals.SignalType       `json:"position"`
+	Metrics  signals.GeneratorMetrics `json:"metrics"`
+}
+
+// Snapshot encode l'état du générateur (JSON versionné)
+func (g *VWMACrossDMISimpleGenerator) Snapshot() ([]byte, error) {
+	state := snapshotState{Resume: g.lastProcessed, Position: g.currentPosition, Metrics: g.metrics}
+	if g.resume != nil {
+		state.Resume = *g.resume
+	}
+	return signals.EncodeSnapshot("vwma_cross_dmi_simple", snapshotVersion, state)
+}
+
+// Restore recharge l'état ; la reprise est recalée sur la prochaine fenêtre de klines
+func (g *VWMACrossDMISimpleGenerator) Restore(data []byte) error {
+	var state snapshotState
+	if _, err := signals.DecodeSnapshot(data, "vwma_cross_dmi_simple", snapshotVersion, &state); err != nil {
+		return err
+	}
+	g.currentPosition = state.Position
+	g.metrics = state.Metrics
+	g.lastProcessed = state.Resume
+	g.resume = &state.Resume
+	return nil
+}
+
+// applyResume recale lastProcessedIdx sur la fenêtre courante après un Restore
+func (g *VWMACrossDMISimpleGenerator) applyResume(klines []signals.Kline) {
+	if g.resume == nil {
+		return
+	}
+	g.lastProcessedIdx = g.resume.IndexIn(klines)
+	g.resume = nil
+}
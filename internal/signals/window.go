@@ -0,0 +1,525 @@
+package signals
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WindowSource source de conditions évaluées bougie par bougie
+type WindowSource interface {
+	// Name identifiant de la source (utilisé par OpenMode et les diagnostics)
+	Name() string
+	// Calculate calcule les indicateurs de la source sur la fenêtre de klines
+	Calculate(klines []Kline) error
+	// At indique si la condition est vraie sur la bougie i pour le sens side
+	At(i int, side SignalType) bool
+	// MinHistorySize bougies nécessaires avant une valeur valide
+	MinHistorySize() int
+}
+
+// CrossEventSource source d'événements de croisement (ouvertures auto et cross)
+type CrossEventSource interface {
+	WindowSource
+	CrossEvents() bool
+}
+
+// WindowConditionMode mode d'accumulation d'une condition dans la fenêtre
+type WindowConditionMode string
+
+const (
+	WindowModeSticky WindowConditionMode = "sticky" // Vraie au moins une fois dans la fenêtre (défaut)
+	WindowModeLatest WindowConditionMode = "latest" // Dernier événement de la fenêtre dans le sens (croisements)
+	WindowModeBar    WindowConditionMode = "bar"    // Vraie sur la bougie d'émission
+)
+
+// WindowOpenMode choix des sources qui ouvrent une fenêtre
+type WindowOpenMode string
+
+const (
+	WindowOpenAuto       WindowOpenMode = "auto"        // Croisements s'il y en a, sinon "bar"
+	WindowOpenCross      WindowOpenMode = "cross"       // N'importe quelle source de croisement
+	WindowOpenBar        WindowOpenMode = "bar"         // Source "bar"
+	WindowOpenStochCross WindowOpenMode = "stoch_cross" // Source "stoch_cross"
+	WindowOpenVWMACross  WindowOpenMode = "vwma_cross"  // Source "vwma_cross"
+	WindowOpenMACDCross  WindowOpenMode = "macd_cross"  // Source "macd_cross"
+)
+
+// WindowCondition condition de la fenêtre
+type WindowCondition struct {
+	Source WindowSource
+	Mode   WindowConditionMode
+}
+
+// WindowConfig configuration du combinateur de fenêtre de confirmation
+type WindowConfig struct {
+	Name       string
+	Conditions []WindowCondition
+	OpenMode   WindowOpenMode // auto (défaut), cross, bar ou nom d'une source
+	Sides      []SignalType   // Sens autorisés (défaut LONG et SHORT)
+
+	WindowBars      int // Durée de la fenêtre, bougie d'ouverture incluse (défaut 10)
+	WindowBarsLong  int // Durée spécifique LONG (0 = WindowBars)
+	WindowBarsShort int // Durée spécifique SHORT (0 = WindowBars)
+	PreWindow       int // Bougies avant l'ouverture prises en compte (sticky / latest)
+
+	Confidence        float64 // Confiance des entrées (défaut 0.5)
+	ReverseOnOpposite bool    // Une entrée opposée clôture et retourne la position
+	MaxHoldBars       int     // Clôture après N bougies (0 = jamais)
+	MaxDiagnostics    int     // Fenêtres conservées (0 = 1000, < 0 = aucune)
+	NoPosition        bool    // Émet chaque fenêtre réunie, position gérée par l'appelant
+}
+
+// WindowOutcome issue d'une fenêtre
+type WindowOutcome string
+
+const (
+	WindowFired   WindowOutcome = "FIRED"   // Toutes les conditions réunies, signal émis
+	WindowExpired WindowOutcome = "EXPIRED" // Échéance dépassée
+	WindowReset   WindowOutcome = "RESET"   // Annulée par le signal de l'autre sens
+)
+
+// WindowDiagnostic trace d'une fenêtre clôturée
+type WindowDiagnostic struct {
+	Side      SignalType
+	Opener    string    // Source ayant ouvert la fenêtre
+	OpenTime  time.Time // Bougie d'ouverture
+	CloseTime time.Time // Bougie d'émission, d'expiration ou d'annulation
+	Bars      int       // Bougies écoulées depuis l'ouverture
+	Outcome   WindowOutcome
+	Satisfied []string // Conditions satisfaites à la clôture
+	Missing   []string // Conditions manquantes à la clôture
+}
+
+// windowState fenêtre d'un sens
+type windowState struct {
+	active   bool
+	opener   string
+	openBar  int // Index absolu de la bougie d'ouverture
+	deadline int // Dernier index absolu autorisé
+	openTime time.Time
+	sticky   []bool
+	latest   []int // +1 événement dans le sens, -1 contraire, 0 aucun
+}
+
+// WindowGenerator combinateur « une source ouvre une fenêtre, les conditions s'accumulent,
+// le signal est émis quand tout est réuni avant l'échéance » (WindowFinder BAN_FIN_MOMENTIUM,
+// fenêtre ancrée SmartEco, PendingAnalysis scalping_paper)
+//
+// Une fenêtre par sens ; priorité LONG puis SHORT sur une même bougie ; une émission
+// réinitialise les deux fenêtres. La bougie qui suit l'échéance clôture la fenêtre sans
+// en rouvrir une du même sens. Les bougies sont traitées une seule fois (suivi par
+// OpenTime), la dernière kline (bougie en cours) est ignorée. Position unique.
+type WindowGenerator struct {
+	config  WindowConfig
+	openers []bool // Conditions pouvant ouvrir une fenêtre
+	sides   map[SignalType]bool
+
+	// État
+	windows       map[SignalType]*windowState
+	bar           int // Index absolu de la prochaine bougie
+	lastProcessed time.Time
+	diagnostics   []WindowDiagnostic
+	position      SignalType
+	entryPrice    float64
+	entryTime     time.Time
+	entryBar      int
+	metrics       GeneratorMetrics
+}
+
+// NewWindowGenerator valide la configuration et crée le combinateur
+func NewWindowGenerator(config WindowConfig) (*WindowGenerator, error) {
+	if len(config.Conditions) == 0 {
+		return nil, fmt.Errorf("conditions: at least one condition required")
+	}
+	config.Conditions = append([]WindowCondition(nil), config.Conditions...)
+	for i := range config.Conditions {
+		c := &config.Conditions[i]
+		if c.Source == nil {
+			return nil, fmt.Errorf("conditions[%d].source: missing source", i)
+		}
+		switch c.Mode {
+		case "":
+			c.Mode = WindowModeSticky
+		case WindowModeSticky, WindowModeLatest, WindowModeBar:
+		default:
+			return nil, fmt.Errorf("conditions[%d].mode: unknown mode %q (sticky, latest, bar)", i, c.Mode)
+		}
+	}
+	if config.Name == "" {
+		config.Name = "window"
+	}
+	if config.OpenMode == "" {
+		config.OpenMode = WindowOpenAuto
+	}
+	if config.WindowBars <= 0 {
+		config.WindowBars = 10
+	}
+	if config.WindowBarsLong < 0 || config.WindowBarsShort < 0 || config.PreWindow < 0 || config.MaxHoldBars < 0 {
+		return nil, fmt.Errorf("window_bars_long, window_bars_short, pre_window, max_hold_bars: must be >= 0")
+	}
+	if config.Confidence < 0 || config.Confidence > 1 {
+		return nil, fmt.Errorf("confidence: must be within [0, 1], got %v", config.Confidence)
+	}
+	if config.Confidence == 0 {
+		config.Confidence = 0.5
+	}
+	if config.MaxDiagnostics == 0 {
+		config.MaxDiagnostics = 1000
+	}
+
+	sides := make(map[SignalType]bool)
+	for _, side := range config.Sides {
+		if side != SignalTypeLong && side != SignalTypeShort {
+			return nil, fmt.Errorf("sides: unknown side %q (LONG, SHORT)", side)
+		}
+		sides[side] = true
+	}
+	if len(sides) == 0 {
+		sides[SignalTypeLong], sides[SignalTypeShort] = true, true
+	}
+
+	openers, err := resolveOpeners(config.OpenMode, config.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	g := &WindowGenerator{config: config, openers: openers, sides: sides}
+	g.reset()
+	return g, nil
+}
+
+// resolveOpeners sélectionne les conditions qui ouvrent une fenêtre selon le mode
+func resolveOpeners(mode WindowOpenMode, conditions []WindowCondition) ([]bool, error) {
+	openers := make([]bool, len(conditions))
+	found := false
+	match := func(accept func(WindowSource) bool) {
+		for i, c := range conditions {
+			if accept(c.Source) {
+				openers[i], found = true, true
+			}
+		}
+	}
+	switch mode {
+	case WindowOpenAuto:
+		match(isCrossSource)
+		if !found {
+			match(func(s WindowSource) bool { return s.Name() == string(WindowOpenBar) })
+		}
+	case WindowOpenCross:
+		match(isCrossSource)
+	default:
+		match(func(s WindowSource) bool { return s.Name() == string(mode) })
+	}
+	if !found {
+		names := make([]string, len(conditions))
+		for i, c := range conditions {
+			names[i] = c.Source.Name()
+		}
+		return nil, fmt.Errorf("open_mode: no source for mode %q (%s)", mode, strings.Join(names, ", "))
+	}
+	return openers, nil
+}
+
+// isCrossSource indique si la source détecte des croisements
+func isCrossSource(s WindowSource) bool {
+	c, ok := s.(CrossEventSource)
+	return ok && c.CrossEvents()
+}
+
+// Name retourne le nom du combinateur
+func (g *WindowGenerator) Name() string {
+	return g.config.Name
+}
+
+// Config retourne la configuration normalisée
+func (g *WindowGenerator) Config() WindowConfig {
+	return g.config
+}
+
+// Position retourne la position courante ("" si à plat)
+func (g *WindowGenerator) Position() SignalType {
+	return g.position
+}
+
+// Diagnostics retourne les fenêtres clôturées (plus anciennes en premier)
+func (g *WindowGenerator) Diagnostics() []WindowDiagnostic {
+	return g.diagnostics
+}
+
+// MinHistorySize historique des sources plus la pré-fenêtre
+func (g *WindowGenerator) MinHistorySize() int {
+	history := 0
+	for _, c := range g.config.Conditions {
+		if h := c.Source.MinHistorySize(); h > history {
+			history = h
+		}
+	}
+	return history + g.config.PreWindow + 1
+}
+
+// Initialize réinitialise l'état (et les sources qui le supportent)
+func (g *WindowGenerator) Initialize(config GeneratorConfig) error {
+	for _, c := range g.config.Conditions {
+		if init, ok := c.Source.(interface{ Initialize(GeneratorConfig) error }); ok {
+			if err := init.Initialize(config); err != nil {
+				return err
+			}
+		}
+	}
+	g.reset()
+	return nil
+}
+
+// reset remet l'état à zéro
+func (g *WindowGenerator) reset() {
+	g.windows = map[SignalType]*windowState{SignalTypeLong: {}, SignalTypeShort: {}}
+	g.bar = 0
+	g.lastProcessed = time.Time{}
+	g.diagnostics = nil
+	g.position = ""
+	g.metrics = GeneratorMetrics{}
+}
+
+// CalculateIndicators calcule les sources sur la fenêtre de klines
+func (g *WindowGenerator) CalculateIndicators(klines []Kline) error {
+	if len(klines) == 0 {
+		return fmt.Errorf("aucune kline")
+	}
+	for _, c := range g.config.Conditions {
+		if err := c.Source.Calculate(klines); err != nil {
+			return fmt.Errorf("%s: %w", c.Source.Name(), err)
+		}
+	}
+	return nil
+}
+
+// DetectSignals fait avancer les fenêtres sur les bougies clôturées non encore traitées
+func (g *WindowGenerator) DetectSignals(klines []Kline) ([]Signal, error) {
+	var out []Signal
+	lastClosedIdx := len(klines) - 2
+	for j := 0; j <= lastClosedIdx; j++ {
+		out = append(out, g.Step(klines, j)...)
+	}
+	return out, nil
+}
+
+// Step fait avancer les fenêtres sur la bougie clôturée j (appelant bougie par bougie,
+// sources déjà calculées) ; une bougie déjà traitée est ignorée
+func (g *WindowGenerator) Step(klines []Kline, j int) []Signal {
+	if !klines[j].OpenTime.After(g.lastProcessed) {
+		return nil
+	}
+	out := g.processBar(klines, j)
+	g.lastProcessed = klines[j].OpenTime
+	g.bar++
+	for _, sig := range out {
+		g.record(sig)
+	}
+	return out
+}
+
+// processBar met à jour les fenêtres sur la bougie j et émet au plus une entrée
+func (g *WindowGenerator) processBar(klines []Kline, j int) []Signal {
+	var out []Signal
+	if g.position != "" && g.config.MaxHoldBars > 0 && g.bar-g.entryBar >= g.config.MaxHoldBars {
+		out = append(out, g.exitSignal(klines[j], "max_hold"))
+	}
+
+	for _, side := range []SignalType{SignalTypeLong, SignalTypeShort} {
+		if g.sides[side] {
+			g.advance(klines, j, side)
+		}
+	}
+
+	for _, side := range []SignalType{SignalTypeLong, SignalTypeShort} {
+		w := g.windows[side]
+		if !w.active || !g.complete(w, j, side) {
+			continue
+		}
+		openTime, bars := w.openTime, g.bar-w.openBar
+		g.close(side, klines[j], WindowFired, j)
+		if other := opposite(side); g.windows[other].active {
+			g.close(other, klines[j], WindowReset, j)
+		}
+		return append(out, g.entry(klines[j], side, openTime, bars)...)
+	}
+	return out
+}
+
+// advance ouvre, expire et met à jour la fenêtre d'un sens sur la bougie j
+func (g *WindowGenerator) advance(klines []Kline, j int, side SignalType) {
+	w := g.windows[side]
+	if !w.active {
+		for i, c := range g.config.Conditions {
+			if !g.openers[i] || !c.Source.At(j, side) {
+				continue
+			}
+			bars := g.config.WindowBars
+			if side == SignalTypeLong && g.config.WindowBarsLong > 0 {
+				bars = g.config.WindowBarsLong
+			} else if side == SignalTypeShort && g.config.WindowBarsShort > 0 {
+				bars = g.config.WindowBarsShort
+			}
+			*w = windowState{
+				active: true, opener: c.Source.Name(), openBar: g.bar, deadline: g.bar + bars - 1,
+				openTime: klines[j].OpenTime,
+				sticky:   make([]bool, len(g.config.Conditions)),
+				latest:   make([]int, len(g.config.Conditions)),
+			}
+			// Pré-fenêtre : événements antérieurs à l'ouverture
+			from := j - g.config.PreWindow
+			if from < 0 {
+				from = 0
+			}
+			for k := from; k < j; k++ {
+				g.accumulate(w, k, side)
+			}
+			break
+		}
+	}
+	if w.active && g.bar > w.deadline {
+		g.close(side, klines[j], WindowExpired, j-1)
+	}
+	if w.active {
+		g.accumulate(w, j, side)
+	}
+}
+
+// accumulate enregistre les conditions sticky / latest de la bougie k
+func (g *WindowGenerator) accumulate(w *windowState, k int, side SignalType) {
+	for i, c := range g.config.Conditions {
+		switch c.Mode {
+		case WindowModeSticky:
+			w.sticky[i] = w.sticky[i] || c.Source.At(k, side)
+		case WindowModeLatest:
+			if c.Source.At(k, side) {
+				w.latest[i] = 1
+			} else if c.Source.At(k, opposite(side)) {
+				w.latest[i] = -1
+			}
+		}
+	}
+}
+
+// satisfied indique si la condition i est réunie sur la bougie j
+func (g *WindowGenerator) satisfied(w *windowState, i, j int, side SignalType) bool {
+	switch g.config.Conditions[i].Mode {
+	case WindowModeSticky:
+		return w.sticky[i]
+	case WindowModeLatest:
+		return w.latest[i] == 1
+	default:
+		return g.config.Conditions[i].Source.At(j, side)
+	}
+}
+
+// complete indique si toutes les conditions sont réunies sur la bougie j
+func (g *WindowGenerator) complete(w *windowState, j int, side SignalType) bool {
+	for i := range g.config.Conditions {
+		if !g.satisfied(w, i, j, side) {
+			return false
+		}
+	}
+	return true
+}
+
+// close clôture la fenêtre d'un sens et enregistre son diagnostic (conditions évaluées en j)
+func (g *WindowGenerator) close(side SignalType, k Kline, outcome WindowOutcome, j int) {
+	w := g.windows[side]
+	if g.config.MaxDiagnostics > 0 {
+		diag := WindowDiagnostic{
+			Side: side, Opener: w.opener, OpenTime: w.openTime, CloseTime: k.OpenTime,
+			Bars: g.bar - w.openBar, Outcome: outcome,
+		}
+		for i, c := range g.config.Conditions {
+			if j >= 0 && g.satisfied(w, i, j, side) {
+				diag.Satisfied = append(diag.Satisfied, c.Source.Name())
+			} else {
+				diag.Missing = append(diag.Missing, c.Source.Name())
+			}
+		}
+		g.diagnostics = append(g.diagnostics, diag)
+		if len(g.diagnostics) > g.config.MaxDiagnostics {
+			g.diagnostics = g.diagnostics[len(g.diagnostics)-g.config.MaxDiagnostics:]
+		}
+	}
+	*w = windowState{}
+}
+
+// entry ouvre (ou retourne) la position sur la bougie k
+func (g *WindowGenerator) entry(k Kline, side SignalType, openTime time.Time, bars int) []Signal {
+	var out []Signal
+	switch {
+	case g.config.NoPosition:
+	case g.position == side:
+		return out
+	case g.position != "":
+		if !g.config.ReverseOnOpposite {
+			return out
+		}
+		out = append(out, g.exitSignal(k, "reverse"))
+	}
+	if !g.config.NoPosition {
+		g.position, g.entryPrice, g.entryTime, g.entryBar = side, k.Close, k.OpenTime, g.bar
+	}
+	return append(out, Signal{
+		Timestamp:  k.OpenTime,
+		Action:     SignalActionEntry,
+		Type:       side,
+		Price:      k.Close,
+		Confidence: g.config.Confidence,
+		Metadata: map[string]interface{}{
+			"generator":        g.config.Name,
+			"window_open_time": openTime,
+			"bars_from_open":   bars,
+		},
+	})
+}
+
+// exitSignal clôture la position courante
+func (g *WindowGenerator) exitSignal(k Kline, reason string) Signal {
+	entryPrice, entryTime := g.entryPrice, g.entryTime
+	sig := Signal{
+		Timestamp:  k.OpenTime,
+		Action:     SignalActionExit,
+		Type:       g.position,
+		Price:      k.Close,
+		Confidence: g.config.Confidence,
+		Metadata:   map[string]interface{}{"generator": g.config.Name, "exit_reason": reason},
+		EntryPrice: &entryPrice,
+		EntryTime:  &entryTime,
+	}
+	g.position = ""
+	return sig
+}
+
+// opposite retourne le sens contraire
+func opposite(side SignalType) SignalType {
+	if side == SignalTypeLong {
+		return SignalTypeShort
+	}
+	return SignalTypeLong
+}
+
+// record met à jour les métriques
+func (g *WindowGenerator) record(sig Signal) {
+	m := &g.metrics
+	m.TotalSignals++
+	if sig.Action == SignalActionEntry {
+		m.EntrySignals++
+		if sig.Type == SignalTypeLong {
+			m.LongSignals++
+		} else {
+			m.ShortSignals++
+		}
+	} else {
+		m.ExitSignals++
+	}
+	m.AvgConfidence += (sig.Confidence - m.AvgConfidence) / float64(m.TotalSignals)
+	m.LastSignalTime = sig.Timestamp
+}
+
+// GetMetrics retourne les métriques
+func (g *WindowGenerator) GetMetrics() GeneratorMetrics {
+	return g.metrics
+}
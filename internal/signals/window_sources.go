@@ -0,0 +1,278 @@
+package signals
+
+import (
+	"fmt"
+	"math"
+
+	"agent-economique/internal/indicators"
+	"agent-economique/internal/indicators/expr"
+)
+
+// CrossSource croisement de deux séries : LONG = rapide passe au-dessus de lente, SHORT = en dessous
+type CrossSource struct {
+	name    string
+	history int
+	series  func(klines []Kline) (fast, slow []float64)
+
+	fast, slow []float64
+}
+
+// NewCrossSource crée une source de croisement générique
+func NewCrossSource(name string, history int, series func(klines []Kline) (fast, slow []float64)) *CrossSource {
+	return &CrossSource{name: name, history: history, series: series}
+}
+
+// NewStochCrossSource croisement %K / %D du stochastique ("stoch_cross")
+func NewStochCrossSource(periodK, smoothK, periodD int) *CrossSource {
+	return NewCrossSource(string(WindowOpenStochCross), periodK+smoothK+periodD, func(klines []Kline) ([]float64, []float64) {
+		high, low, closes, _ := klineSeries(klines)
+		return indicators.NewStochTVStandard(periodK, smoothK, periodD).Calculate(high, low, closes)
+	})
+}
+
+// NewVWMACrossSource croisement VWMA rapide / lente ("vwma_cross")
+func NewVWMACrossSource(fast, slow int) *CrossSource {
+	return NewCrossSource(string(WindowOpenVWMACross), slow+1, func(klines []Kline) ([]float64, []float64) {
+		_, _, closes, volume := klineSeries(klines)
+		return indicators.NewVWMATVStandard(fast).Calculate(closes, volume), indicators.NewVWMATVStandard(slow).Calculate(closes, volume)
+	})
+}
+
+// NewMACDCrossSource croisement ligne MACD / signal ("macd_cross")
+func NewMACDCrossSource(fast, slow, signal int) *CrossSource {
+	return NewCrossSource(string(WindowOpenMACDCross), slow+signal, func(klines []Kline) ([]float64, []float64) {
+		_, _, closes, _ := klineSeries(klines)
+		line, sig, _ := indicators.NewMACDTVStandard(fast, slow, signal).Calculate(closes)
+		return line, sig
+	})
+}
+
+// Name retourne le nom de la source
+func (s *CrossSource) Name() string { return s.name }
+
+// MinHistorySize bougies nécessaires avant un croisement valide
+func (s *CrossSource) MinHistorySize() int { return s.history }
+
+// CrossEvents marque la source comme source de croisements
+func (s *CrossSource) CrossEvents() bool { return true }
+
+// Calculate calcule les deux séries
+func (s *CrossSource) Calculate(klines []Kline) error {
+	s.fast, s.slow = s.series(klines)
+	if len(s.fast) != len(klines) || len(s.slow) != len(klines) {
+		return fmt.Errorf("series length mismatch")
+	}
+	return nil
+}
+
+// At indique un croisement dans le sens side sur la bougie i
+func (s *CrossSource) At(i int, side SignalType) bool {
+	if i < 1 || i >= len(s.fast) {
+		return false
+	}
+	pf, ps, cf, cs := s.fast[i-1], s.slow[i-1], s.fast[i], s.slow[i]
+	if math.IsNaN(pf) || math.IsNaN(ps) || math.IsNaN(cf) || math.IsNaN(cs) {
+		return false
+	}
+	if side == SignalTypeLong {
+		return pf <= ps && cf > cs
+	}
+	return pf >= ps && cf < cs
+}
+
+// BarSourceConfig seuils de la bougie d'impulsion
+type BarSourceConfig struct {
+	ATRPeriod    int     // Défaut 14
+	BodyATR      float64 // Corps minimal en ATR
+	VolumePeriod int     // SMA du volume (défaut 20)
+	VolumeCoeff  float64 // Volume minimal en multiple de la SMA (0 = ignoré)
+}
+
+// BarSource bougie d'impulsion ("bar") : verte (LONG) ou rouge (SHORT), corps >= BodyATR × ATR
+// et volume >= VolumeCoeff × SMA(volume)
+type BarSource struct {
+	config BarSourceConfig
+	klines []Kline
+	atr    []float64
+	volSMA []float64
+}
+
+// NewBarSource crée la source de bougie d'impulsion
+func NewBarSource(config BarSourceConfig) *BarSource {
+	if config.ATRPeriod <= 0 {
+		config.ATRPeriod = 14
+	}
+	if config.VolumePeriod <= 0 {
+		config.VolumePeriod = 20
+	}
+	return &BarSource{config: config}
+}
+
+// Name retourne "bar"
+func (s *BarSource) Name() string { return string(WindowOpenBar) }
+
+// MinHistorySize ATR et SMA du volume
+func (s *BarSource) MinHistorySize() int {
+	if s.config.VolumeCoeff > 0 && s.config.VolumePeriod > s.config.ATRPeriod {
+		return s.config.VolumePeriod
+	}
+	return s.config.ATRPeriod
+}
+
+// Calculate calcule l'ATR et la SMA du volume
+func (s *BarSource) Calculate(klines []Kline) error {
+	high, low, closes, volume := klineSeries(klines)
+	s.klines = klines
+	s.atr = indicators.NewATRTVStandard(s.config.ATRPeriod).Calculate(high, low, closes)
+	s.volSMA = indicators.NewSMATVStandard(s.config.VolumePeriod).Calculate(volume)
+	return nil
+}
+
+// At valide la bougie i dans le sens side
+func (s *BarSource) At(i int, side SignalType) bool {
+	if i < 0 || i >= len(s.klines) || math.IsNaN(s.atr[i]) {
+		return false
+	}
+	k := s.klines[i]
+	if (side == SignalTypeLong && k.Close <= k.Open) || (side == SignalTypeShort && k.Close >= k.Open) {
+		return false
+	}
+	if math.Abs(k.Close-k.Open) < s.config.BodyATR*s.atr[i] {
+		return false
+	}
+	if s.config.VolumeCoeff > 0 {
+		return !math.IsNaN(s.volSMA[i]) && k.Volume >= s.config.VolumeCoeff*s.volSMA[i]
+	}
+	return true
+}
+
+// ExprSource condition en expressions (internal/indicators/expr), une par sens
+type ExprSource struct {
+	name        string
+	long, short *expr.Expr
+	ctx         *expr.Context
+}
+
+// NewExprSource compile les conditions LONG et SHORT (une expression vide n'est jamais vraie)
+func NewExprSource(name, long, short string) (*ExprSource, error) {
+	s := &ExprSource{name: name}
+	for _, item := range []struct {
+		field  string
+		source string
+		target **expr.Expr
+	}{{"long", long, &s.long}, {"short", short, &s.short}} {
+		if item.source == "" {
+			continue
+		}
+		compiled, err := expr.CompileCondition(item.source)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", name, item.field, err)
+		}
+		*item.target = compiled
+	}
+	return s, nil
+}
+
+// Name retourne le nom de la source
+func (s *ExprSource) Name() string { return s.name }
+
+// MinHistorySize historique des expressions
+func (s *ExprSource) MinHistorySize() int {
+	history := 0
+	for _, e := range []*expr.Expr{s.long, s.short} {
+		if e != nil && e.MinHistory() > history {
+			history = e.MinHistory()
+		}
+	}
+	return history
+}
+
+// Calculate prépare le contexte d'évaluation
+func (s *ExprSource) Calculate(klines []Kline) error {
+	s.ctx = expr.NewContext(ToIndicatorKlines(klines))
+	return nil
+}
+
+// At évalue l'expression du sens side sur la bougie i
+func (s *ExprSource) At(i int, side SignalType) bool {
+	e := s.long
+	if side == SignalTypeShort {
+		e = s.short
+	}
+	return e != nil && s.ctx != nil && i >= 0 && i < s.ctx.Len() && e.True(s.ctx, i)
+}
+
+// GeneratorSource entrées d'un générateur utilisées comme condition (entrée du sens sur la bougie)
+type GeneratorSource struct {
+	inner   Generator
+	entries map[int64]SignalType // Entrées émises (OpenTime ms)
+	times   []int64
+}
+
+// NewGeneratorSource adapte un générateur en source de fenêtre
+func NewGeneratorSource(inner Generator) *GeneratorSource {
+	return &GeneratorSource{inner: inner, entries: make(map[int64]SignalType)}
+}
+
+// Name retourne le nom du générateur
+func (s *GeneratorSource) Name() string { return s.inner.Name() }
+
+// MinHistorySize historique du générateur s'il le déclare
+func (s *GeneratorSource) MinHistorySize() int {
+	return RequiredHistorySize(s.inner, 0)
+}
+
+// Initialize réinitialise le générateur et les entrées mémorisées
+func (s *GeneratorSource) Initialize(config GeneratorConfig) error {
+	s.entries = make(map[int64]SignalType)
+	return s.inner.Initialize(config)
+}
+
+// Calculate fait tourner le générateur et mémorise ses entrées
+func (s *GeneratorSource) Calculate(klines []Kline) error {
+	if err := s.inner.CalculateIndicators(klines); err != nil {
+		return err
+	}
+	out, err := s.inner.DetectSignals(klines)
+	if err != nil {
+		return err
+	}
+	for _, sig := range out {
+		if sig.Action == SignalActionEntry {
+			s.entries[sig.Timestamp.UnixMilli()] = sig.Type
+		}
+	}
+	// Oubli des entrées antérieures à la fenêtre
+	if len(klines) > 0 {
+		first := klines[0].OpenTime.UnixMilli()
+		for ms := range s.entries {
+			if ms < first {
+				delete(s.entries, ms)
+			}
+		}
+	}
+	s.times = make([]int64, len(klines))
+	for i, k := range klines {
+		s.times[i] = k.OpenTime.UnixMilli()
+	}
+	return nil
+}
+
+// At indique une entrée du générateur dans le sens side sur la bougie i
+func (s *GeneratorSource) At(i int, side SignalType) bool {
+	if i < 0 || i >= len(s.times) {
+		return false
+	}
+	t, ok := s.entries[s.times[i]]
+	return ok && t == side
+}
+
+// klineSeries colonnes high, low, close, volume
+func klineSeries(klines []Kline) (high, low, closes, volume []float64) {
+	n := len(klines)
+	high, low, closes, volume = make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for i, k := range klines {
+		high[i], low[i], closes[i], volume[i] = k.High, k.Low, k.Close, k.Volume
+	}
+	return high, low, closes, volume
+}
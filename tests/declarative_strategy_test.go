@@ -0,0 +1,155 @@
+// Package tests provides tests for declarative YAML strategies
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+	"agent-economique/internal/signals/declarative"
+)
+
+const windowStrategy = `
+name: close_cross
+entry_long:
+  trigger: crossover(close, 100)
+  confirm:
+    - close > 102
+  window: 3
+exit_long:
+  trigger: crossunder(close, 100)
+confidence: 0.4 + (close - 100) / 10
+metadata:
+  above: close > 100
+  close: close
+`
+
+// closeKlines builds klines from closes; the last kline is the bar in progress
+func closeKlines(closes ...float64) []signals.Kline {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]signals.Kline, len(closes))
+	for i, c := range closes {
+		klines[i] = signals.Kline{OpenTime: start.Add(time.Duration(i) * time.Minute), Open: c, High: c + 0.5, Low: c - 0.5, Close: c, Volume: 1}
+	}
+	return klines
+}
+
+func runDeclarative(t *testing.T, g *declarative.Generator, klines []signals.Kline) []signals.Signal {
+	t.Helper()
+	if err := g.CalculateIndicators(klines); err != nil {
+		t.Fatalf("CalculateIndicators failed: %v", err)
+	}
+	sigs, err := g.DetectSignals(klines)
+	if err != nil {
+		t.Fatalf("DetectSignals failed: %v", err)
+	}
+	return sigs
+}
+
+// TestDeclarative_ConfirmationWindow checks anchored confirmations, exits and incremental calls
+func TestDeclarative_ConfirmationWindow(t *testing.T) {
+	klines := closeKlines(99, 99, 101, 101, 103, 104, 99, 99, 101, 101, 101, 101, 101, 103, 200)
+
+	g, err := declarative.Parse([]byte(windowStrategy))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := g.Initialize(signals.GeneratorConfig{Symbol: "TEST", Timeframe: "1m"}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	sigs := runDeclarative(t, g, klines)
+	if len(sigs) != 2 {
+		t.Fatalf("Expected entry + exit, got %d signals: %+v", len(sigs), sigs)
+	}
+
+	entry, exit := sigs[0], sigs[1]
+	if entry.Action != signals.SignalActionEntry || entry.Type != signals.SignalTypeLong || !entry.Timestamp.Equal(klines[4].OpenTime) {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if entry.Metadata["bars_from_anchor"] != 2 || entry.Metadata["above"] != true || entry.Metadata["close"] != 103.0 {
+		t.Errorf("Unexpected entry metadata %v", entry.Metadata)
+	}
+	if entry.Confidence < 0.69 || entry.Confidence > 0.71 {
+		t.Errorf("Expected confidence 0.7, got %v", entry.Confidence)
+	}
+	if exit.Action != signals.SignalActionExit || !exit.Timestamp.Equal(klines[6].OpenTime) || *exit.EntryPrice != 103 {
+		t.Errorf("Unexpected exit %+v", exit)
+	}
+
+	// Same result bar by bar (live usage), without duplicates
+	g2, _ := declarative.Parse([]byte(windowStrategy))
+	g2.Initialize(signals.GeneratorConfig{})
+	var incremental []signals.Signal
+	for n := 2; n <= len(klines); n++ {
+		incremental = append(incremental, runDeclarative(t, g2, klines[:n])...)
+	}
+	if len(incremental) != len(sigs) {
+		t.Fatalf("Incremental run: expected %d signals, got %d", len(sigs), len(incremental))
+	}
+	for i := range sigs {
+		if !incremental[i].Timestamp.Equal(sigs[i].Timestamp) || incremental[i].Action != sigs[i].Action {
+			t.Errorf("Incremental signal %d differs: %+v vs %+v", i, incremental[i], sigs[i])
+		}
+	}
+}
+
+// TestDeclarative_Reverse checks an opposite entry closes and reverses the position
+func TestDeclarative_Reverse(t *testing.T) {
+	g, err := declarative.Parse([]byte(`
+name: reverse
+entry_long:
+  trigger: crossover(close, 100)
+entry_short:
+  trigger: crossunder(close, 100)
+confidence: (close - 100) * 10
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	g.Initialize(signals.GeneratorConfig{})
+
+	sigs := runDeclarative(t, g, closeKlines(99, 101, 99, 0))
+	if len(sigs) != 3 || sigs[1].Action != signals.SignalActionExit || sigs[1].Metadata["exit_reason"] != "reverse" ||
+		sigs[2].Type != signals.SignalTypeShort || g.Position() != signals.SignalTypeShort {
+		t.Fatalf("Expected ENTRY LONG, EXIT LONG, ENTRY SHORT, got %+v", sigs)
+	}
+	if sigs[0].Confidence != 1 || sigs[2].Confidence != 0 {
+		t.Errorf("Confidence must be clipped to [0,1], got %v and %v", sigs[0].Confidence, sigs[2].Confidence)
+	}
+	// Sides count entries only: the reverse exit is not a second LONG
+	if m := g.GetMetrics(); m.EntrySignals != 2 || m.ExitSignals != 1 || m.LongSignals != 1 || m.ShortSignals != 1 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+}
+
+// TestDeclarative_Errors checks compile errors point at the YAML field
+func TestDeclarative_Errors(t *testing.T) {
+	cases := map[string]string{
+		"name: x\nentry_long:\n  trigger: crossover(vwma(6), vwma(0))\n":          "line 3: entry_long.trigger: column 20",
+		"name: x\nentry_long:\n  trigger: close > 1\n  confirm:\n    - close +\n": "line 5: entry_long.confirm[0]",
+		"name: x\nentry_long:\n  trigger: close > 1\nconfidence: close > 2\n":     "line 4: confidence: expression must be a number",
+		"name: x\nexit_long:\n  trigger: close > 1\n":                             "at least one entry rule",
+		"name: x\nentry_long:\n  trigger: close > 1\n  windw: 3\n":                "field windw not found",
+	}
+	for source, want := range cases {
+		if _, err := declarative.Parse([]byte(source)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+// TestDeclarative_StrategyFiles checks the shipped strategy files compile
+func TestDeclarative_StrategyFiles(t *testing.T) {
+	for _, name := range []string{"vwma_cross_dmi", "stoch_anchored"} {
+		g, err := declarative.LoadFile("../config/strategies/" + name + ".yaml")
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if g.Name() != name || g.MinHistorySize() <= 0 {
+			t.Errorf("%s: unexpected name %s or history %d", name, g.Name(), g.MinHistorySize())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package tests provides tests for the divergence entry filter
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+)
+
+// TestDivergenceFilter_RequireMode checks entries need a confirmed same-side divergence
+func TestDivergenceFilter_RequireMode(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Oscillateur sma(1) = close : plus bas supérieur en 7, prix (low) plus bas inférieur
+	closes := []float64{50, 40, 30, 40, 50, 60, 50, 35, 45, 55, 60, 65, 70, 70}
+	klines := testKlines(start, len(closes), time.Hour)
+	for i, c := range closes {
+		klines[i].Close, klines[i].High, klines[i].Low = c, c+1, c-1
+	}
+	klines[7].Low = 20
+
+	entryTime := klines[11].OpenTime
+	inner := &stubGenerator{out: []signals.Signal{
+		{Timestamp: klines[10].OpenTime, Action: signals.SignalActionEntry, Type: signals.SignalTypeLong},
+		{Timestamp: klines[11].OpenTime, Action: signals.SignalActionEntry, Type: signals.SignalTypeShort},
+		{Timestamp: klines[12].OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeShort, EntryTime: &entryTime},
+	}}
+
+	filter, err := signals.NewDivergenceFilter(inner, signals.DivergenceFilterConfig{
+		Oscillator: "sma(1)", PivotLeft: 2, PivotRight: 2, RangeMin: 2, RangeMax: 20, MaxAge: 3,
+	})
+	if err != nil {
+		t.Fatalf("NewDivergenceFilter failed: %v", err)
+	}
+	filter.Initialize(signals.GeneratorConfig{Symbol: "TEST", Timeframe: "1h"})
+	if err := filter.CalculateIndicators(klines); err != nil {
+		t.Fatalf("CalculateIndicators failed: %v", err)
+	}
+
+	out, err := filter.DetectSignals(klines)
+	if err != nil {
+		t.Fatalf("DetectSignals failed: %v", err)
+	}
+	if len(out) != 1 || out[0].Type != signals.SignalTypeLong {
+		t.Fatalf("Expected only the long entry, got %+v", out)
+	}
+	if out[0].Metadata["divergence"] != "HAUSSIERE_REGULIERE" || out[0].Metadata["divergence_bars"] != 1 {
+		t.Errorf("Unexpected divergence metadata %v", out[0].Metadata)
+	}
+	if filter.BlockedEntries() != 1 {
+		t.Errorf("Expected 1 blocked entry, got %d", filter.BlockedEntries())
+	}
+
+	if _, err := signals.NewDivergenceFilter(inner, signals.DivergenceFilterConfig{Oscillator: "rsi(14)"}); err == nil {
+		t.Errorf("Expected unknown oscillator error")
+	}
+}
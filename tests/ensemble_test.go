@@ -0,0 +1,130 @@
+// Package tests provides tests for the ensemble meta-generator
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+)
+
+// namedStub stub generator with its own name
+type namedStub struct {
+	stubGenerator
+	name string
+}
+
+func (s *namedStub) Name() string { return s.name }
+
+// TestEnsemble_Policies checks entry policies, exit policies and child traceability
+func TestEnsemble_Policies(t *testing.T) {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 30, time.Hour)
+	entry := func(i int, conf float64) signals.Signal {
+		return signals.Signal{Timestamp: klines[i].OpenTime, Action: signals.SignalActionEntry, Type: signals.SignalTypeLong, Confidence: conf}
+	}
+	exit := func(i int) signals.Signal {
+		return signals.Signal{Timestamp: klines[i].OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeLong}
+	}
+	children := func() []signals.Generator {
+		return []signals.Generator{
+			&namedStub{name: "trend", stubGenerator: stubGenerator{out: []signals.Signal{entry(5, 0.9), exit(12)}}},
+			&namedStub{name: "direction_dmi", stubGenerator: stubGenerator{out: []signals.Signal{entry(6, 0.5), exit(14)}}},
+			&namedStub{name: "smart_eco", stubGenerator: stubGenerator{out: []signals.Signal{entry(7, 0.5), exit(20)}}},
+		}
+	}
+	var ensemble *signals.Ensemble
+	run := func(config signals.EnsembleConfig) []signals.Signal {
+		var err error
+		ensemble, err = signals.NewEnsemble(children(), config)
+		if err != nil {
+			t.Fatalf("NewEnsemble failed: %v", err)
+		}
+		if err := ensemble.Initialize(signals.GeneratorConfig{}); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		if err := ensemble.CalculateIndicators(klines); err != nil {
+			t.Fatalf("CalculateIndicators failed: %v", err)
+		}
+		out, err := ensemble.DetectSignals(klines)
+		if err != nil {
+			t.Fatalf("DetectSignals failed: %v", err)
+		}
+		return out
+	}
+	entryAt := func(out []signals.Signal) int {
+		for _, sig := range out {
+			if sig.Action == signals.SignalActionEntry {
+				for i, k := range klines {
+					if k.OpenTime.Equal(sig.Timestamp) {
+						return i
+					}
+				}
+			}
+		}
+		return -1
+	}
+
+	cases := []struct {
+		name   string
+		config signals.EnsembleConfig
+		entry  int
+	}{
+		{"unanimous", signals.EnsembleConfig{Window: 2}, 7},
+		{"unanimous_short_window", signals.EnsembleConfig{Window: 1}, -1},
+		{"k_of_n", signals.EnsembleConfig{Policy: signals.EnsembleKOfN, K: 2, Window: 1}, 6},
+		{"primary", signals.EnsembleConfig{Policy: signals.EnsemblePrimary, Window: 2}, 6},
+		{"weighted", signals.EnsembleConfig{Policy: signals.EnsembleWeighted, Weights: []float64{3, 1, 1}}, 5},
+		{"weighted_low", signals.EnsembleConfig{Policy: signals.EnsembleWeighted, Weights: []float64{1, 1, 1}}, -1},
+	}
+	for _, c := range cases {
+		if got := entryAt(run(c.config)); got != c.entry {
+			t.Errorf("%s: expected entry at %d, got %d", c.name, c.entry, got)
+		}
+	}
+
+	// Exits: first child exit (12) or majority (14)
+	out := run(signals.EnsembleConfig{Window: 2})
+	if len(out) != 2 || !out[1].Timestamp.Equal(klines[12].OpenTime) || out[1].Metadata["exit_reason"] != "first" {
+		t.Fatalf("Unexpected first exit %+v", out)
+	}
+	// Sides count entries only: the exit is not a second LONG
+	if m := ensemble.GetMetrics(); m.EntrySignals != 1 || m.ExitSignals != 1 || m.LongSignals != 1 || m.ShortSignals != 0 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+	votes := out[0].Metadata["children"].([]signals.EnsembleVote)
+	if len(votes) != 3 || votes[0].Generator != "trend" || votes[2].Signal.Timestamp != klines[7].OpenTime {
+		t.Errorf("Unexpected child votes %+v", votes)
+	}
+	if out[0].Confidence < 0.633 || out[0].Confidence > 0.634 {
+		t.Errorf("Expected mean child confidence, got %v", out[0].Confidence)
+	}
+
+	out = run(signals.EnsembleConfig{Window: 2, ExitPolicy: signals.EnsembleExitMajority})
+	if len(out) != 2 || !out[1].Timestamp.Equal(klines[14].OpenTime) || len(out[1].Metadata["children"].([]signals.EnsembleVote)) != 2 {
+		t.Errorf("Unexpected majority exit %+v", out)
+	}
+}
+
+// TestEnsemble_Validation checks configuration errors
+func TestEnsemble_Validation(t *testing.T) {
+	two := []signals.Generator{&stubGenerator{}, &stubGenerator{}}
+	cases := []struct {
+		children []signals.Generator
+		config   signals.EnsembleConfig
+		field    string
+	}{
+		{nil, signals.EnsembleConfig{}, "generators"},
+		{two, signals.EnsembleConfig{Policy: "vote"}, "policy"},
+		{two, signals.EnsembleConfig{Policy: signals.EnsembleKOfN, K: 3}, "k"},
+		{two, signals.EnsembleConfig{Policy: signals.EnsemblePrimary, K: 2}, "k"},
+		{two, signals.EnsembleConfig{ExitPolicy: "last"}, "exit_policy"},
+		{two, signals.EnsembleConfig{Weights: []float64{1}}, "weights"},
+		{two, signals.EnsembleConfig{Weights: []float64{1, -1}}, "weights[1]"},
+	}
+	for _, c := range cases {
+		if _, err := signals.NewEnsemble(c.children, c.config); err == nil || !strings.HasPrefix(err.Error(), c.field+":") {
+			t.Errorf("Expected %s error, got %v", c.field, err)
+		}
+	}
+}
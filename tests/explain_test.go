@@ -0,0 +1,127 @@
+// Package tests provides tests for the generator explain mode
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"agent-economique/internal/signals"
+	"agent-economique/internal/signals/smart_eco"
+)
+
+func explainSmartEco(t *testing.T, cfg smart_eco.Config, sink signals.ExplainSink) []signals.Signal {
+	t.Helper()
+	klines := trendKlines(120)
+	gen := smart_eco.NewGenerator(cfg)
+	if err := gen.Initialize(signals.GeneratorConfig{}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if !signals.EnableExplain(gen, sink) {
+		t.Fatalf("smart_eco must support explain mode")
+	}
+	if err := gen.CalculateIndicators(klines); err != nil {
+		t.Fatalf("CalculateIndicators failed: %v", err)
+	}
+	out, err := gen.DetectSignals(klines)
+	if err != nil {
+		t.Fatalf("DetectSignals failed: %v", err)
+	}
+	return out
+}
+
+// TestExplain_SmartEcoRecords checks one record per bar and side with the blocking rule
+func TestExplain_SmartEcoRecords(t *testing.T) {
+	cfg := smart_eco.Config{
+		ATRPeriod: 14, BodyPctMin: 0.3, BodyATRMin: 0.1,
+		StochKPeriod: 14, StochKSmooth: 3, StochDPeriod: 3,
+		StochKLongMax: 30, StochKShortMin: 70, EnableStochExtremes: true,
+	}
+	buf := &signals.ExplainBuffer{}
+	if out := explainSmartEco(t, cfg, buf); len(out) != 0 {
+		t.Fatalf("Stoch extreme must block entries in a steady uptrend, got %d signals", len(out))
+	}
+	records := buf.Records()
+	if len(records) == 0 || len(records)%2 != 0 {
+		t.Fatalf("Expected LONG and SHORT records per bar, got %d", len(records))
+	}
+	for _, r := range records {
+		switch r.Side {
+		case signals.SignalTypeLong:
+			if r.Fired || len(r.Failed) != 1 || r.Failed[0] != "stoch_extreme" {
+				t.Fatalf("LONG must only fail stoch_extreme: %+v", r)
+			}
+		case signals.SignalTypeShort:
+			if r.Fired || r.Failed[0] != "direction" {
+				t.Fatalf("SHORT must fail direction first: %+v", r)
+			}
+		}
+	}
+
+	// Without the filter every LONG record fires and carries the signal action
+	cfg.EnableStochExtremes = false
+	buf.Reset()
+	out := explainSmartEco(t, cfg, buf)
+	fired := 0
+	for _, r := range buf.Records() {
+		if r.Fired {
+			fired++
+			if r.Action == "" {
+				t.Errorf("Fired record without action: %+v", r)
+			}
+		}
+	}
+	if len(out) == 0 || fired != len(out) {
+		t.Errorf("Expected fired records to match %d signals, got %d", len(out), fired)
+	}
+}
+
+// TestExplain_JSONLExport checks JSONL export (one object per line, missing values omitted)
+func TestExplain_JSONLExport(t *testing.T) {
+	var data bytes.Buffer
+	cfg := smart_eco.Config{ATRPeriod: 14, BodyPctMin: 0.3, StochKPeriod: 14, StochKSmooth: 3, StochDPeriod: 3}
+	explainSmartEco(t, cfg, signals.NewJSONLExplainWriter(&data))
+	if !bytes.Contains(data.Bytes(), []byte(`{"rule":"warmup","pass":true}`)) {
+		t.Errorf("Rule without value must omit value and threshold")
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(&data)
+	for scanner.Scan() {
+		var r signals.BarExplanation
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("Invalid JSONL line %q: %v", scanner.Text(), err)
+		}
+		if len(r.Rules) != 4 || r.Rules[3].Rule != "body_atr" || *r.Rules[3].Threshold != 0 {
+			t.Fatalf("Unexpected rules %+v", r.Rules)
+		}
+		lines++
+	}
+	if lines == 0 {
+		t.Errorf("Expected explain lines")
+	}
+}
+
+// TestExplain_Coverage checks EnableExplain reports unsupported generators, also through decorators
+func TestExplain_Coverage(t *testing.T) {
+	buf := &signals.ExplainBuffer{}
+	if signals.EnableExplain(&stubGenerator{}, buf) {
+		t.Error("A generator without Explainer must report false")
+	}
+	wrapped := signals.NewFundingFilter(&stubGenerator{}, signals.FundingFilterConfig{})
+	if signals.EnableExplain(wrapped, buf) {
+		t.Error("A decorator around a non-explaining generator must report false")
+	}
+	eco := signals.NewFundingFilter(smart_eco.NewGenerator(smart_eco.Config{}), signals.FundingFilterConfig{})
+	if !signals.EnableExplain(eco, buf) {
+		t.Error("A decorator around smart_eco must report true")
+	}
+	ensemble, err := signals.NewEnsemble([]signals.Generator{&stubGenerator{}, smart_eco.NewGenerator(smart_eco.Config{})}, signals.EnsembleConfig{})
+	if err != nil {
+		t.Fatalf("NewEnsemble failed: %v", err)
+	}
+	if !signals.SupportsExplain(ensemble) {
+		t.Error("An ensemble with one explaining child must report true")
+	}
+}
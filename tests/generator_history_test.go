@@ -0,0 +1,47 @@
+// Package tests provides tests for the minimal history declared by generators
+package tests
+
+import (
+	"testing"
+
+	"agent-economique/internal/signals"
+	_ "agent-economique/internal/signals/all"
+)
+
+// TestGeneratorHistory_AllDeclare checks every registered generator declares its history from its periods
+func TestGeneratorHistory_AllDeclare(t *testing.T) {
+	for _, name := range signals.GeneratorNames() {
+		if name == "declarative" {
+			continue // History comes from the strategy file (see declarative tests)
+		}
+		g, err := signals.NewGeneratorFromSpec(signals.GeneratorSpec{Name: name}, signals.GeneratorConfig{Timeframe: "1m"})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, ok := g.(signals.HistoryRequirement); !ok {
+			t.Errorf("%s does not implement MinHistorySize", name)
+			continue
+		}
+		size := signals.RequiredHistorySize(g, -1)
+		if size <= 0 {
+			t.Errorf("%s: invalid history %d", name, size)
+			continue
+		}
+
+		// A window of exactly the declared history (+ forming bar) must be usable
+		klines := trendKlines(size + 1)
+		if err := g.CalculateIndicators(klines); err != nil {
+			t.Errorf("%s: CalculateIndicators on %d klines failed: %v", name, len(klines), err)
+		}
+		if _, err := g.DetectSignals(klines); err != nil {
+			t.Errorf("%s: DetectSignals on %d klines failed: %v", name, len(klines), err)
+		}
+	}
+
+	// A longer period raises the requirement
+	short, _ := signals.NewGeneratorFromSpec(registrySpec(t, "generator:\n  name: smart_eco\n  params:\n    atr_period: 3\n"), signals.GeneratorConfig{})
+	long, _ := signals.NewGeneratorFromSpec(registrySpec(t, "generator:\n  name: smart_eco\n  params:\n    atr_period: 50\n"), signals.GeneratorConfig{})
+	if signals.RequiredHistorySize(long, 0) <= signals.RequiredHistorySize(short, 0) {
+		t.Errorf("History must grow with atr_period: %d vs %d", signals.RequiredHistorySize(long, 0), signals.RequiredHistorySize(short, 0))
+	}
+}
@@ -0,0 +1,174 @@
+// Package tests provides tests for the generator registry (generator: {name, params})
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"agent-economique/internal/signals"
+	_ "agent-economique/internal/signals/all"
+	"agent-economique/internal/signals/smart_eco"
+)
+
+func registrySpec(t *testing.T, yamlText string) signals.GeneratorSpec {
+	t.Helper()
+	spec, err := signals.ParseGeneratorSpec([]byte(yamlText))
+	if err != nil || spec == nil {
+		t.Fatalf("ParseGeneratorSpec failed: %v", err)
+	}
+	return *spec
+}
+
+// TestGeneratorRegistry_Defaults checks every registered generator builds from its defaults
+func TestGeneratorRegistry_Defaults(t *testing.T) {
+	names := strings.Join(signals.GeneratorNames(), ",")
+	expected := "ban_fin_momentium,declarative,direction,direction_dmi,scalping_momentium,smart_eco,smart_eco_anchored,trend,vwma_cross_dmi_simple"
+	if names != expected {
+		t.Fatalf("Unexpected registered generators: %s", names)
+	}
+	for _, name := range signals.GeneratorNames() {
+		g, err := signals.NewGeneratorFromSpec(signals.GeneratorSpec{Name: name}, signals.GeneratorConfig{Timeframe: "1m"})
+		if name == "declarative" {
+			if err == nil || err.Error() != "generator.params.file: required (path to the strategy YAML)" {
+				t.Errorf("declarative must require a file, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: defaults must be valid: %v", name, err)
+			continue
+		}
+		klines := trendKlines(200)
+		if err := g.CalculateIndicators(klines); err != nil {
+			t.Errorf("%s CalculateIndicators failed: %v", name, err)
+		}
+		if _, err := g.DetectSignals(klines); err != nil {
+			t.Errorf("%s DetectSignals failed: %v", name, err)
+		}
+	}
+	if _, ok := signals.LookupGenerator("direction_dmi"); !ok {
+		t.Fatalf("direction_dmi must be registered")
+	}
+	g, _ := signals.NewGeneratorFromSpec(signals.GeneratorSpec{Name: "direction_dmi"}, signals.GeneratorConfig{})
+	if _, ok := g.(signals.Snapshotter); !ok {
+		t.Errorf("direction_dmi adapter must keep optional interfaces")
+	}
+}
+
+// TestGeneratorRegistry_Params checks params override defaults and match a hand-built generator
+func TestGeneratorRegistry_Params(t *testing.T) {
+	spec := registrySpec(t, `
+generator:
+  name: smart_eco
+  params:
+    atr_period: 14
+    body_pct_min: 0.3
+    body_atr_min: 0.1
+`)
+	g, err := signals.NewGeneratorFromSpec(spec, signals.GeneratorConfig{})
+	if err != nil {
+		t.Fatalf("NewGeneratorFromSpec failed: %v", err)
+	}
+	cfg := smart_eco.DefaultConfig()
+	cfg.ATRPeriod, cfg.BodyPctMin, cfg.BodyATRMin = 14, 0.3, 0.1
+	direct := smart_eco.NewGenerator(cfg)
+	_ = direct.Initialize(signals.GeneratorConfig{})
+
+	klines := trendKlines(120)
+	got := detectAll(t, g, klines)
+	want := detectAll(t, direct, klines)
+	if len(want) == 0 || !sameSignals(got, want) {
+		t.Errorf("Registry generator differs from hand-built one (%d vs %d signals)", len(got), len(want))
+	}
+}
+
+func detectAll(t *testing.T, g signals.Generator, klines []signals.Kline) []signals.Signal {
+	t.Helper()
+	if err := g.CalculateIndicators(klines); err != nil {
+		t.Fatalf("CalculateIndicators failed: %v", err)
+	}
+	out, err := g.DetectSignals(klines)
+	if err != nil {
+		t.Fatalf("DetectSignals failed: %v", err)
+	}
+	return out
+}
+
+// TestGeneratorRegistry_FieldErrors checks validation errors point at the bad field and line
+func TestGeneratorRegistry_FieldErrors(t *testing.T) {
+	cases := map[string]string{
+		"generator:\n  name: smart_ecco\n":                                                                                   "generator.name: unknown generator \"smart_ecco\"",
+		"generator:\n  params: {}\n":                                                                                         "generator.name: required",
+		"generator:\n  name: trend\n  params:\n    vwma_rapid: 5\n":                                                          "generator.params.vwma_rapid (line 4): unknown parameter",
+		"generator:\n  name: trend\n  params:\n    vwma_lent: abc\n":                                                         "generator.params.vwma_lent (line 4): cannot unmarshal !!str `abc` into int",
+		"generator:\n  name: trend\n  params:\n    vwma_rapide: 20\n":                                                        "generator.params.vwma_lent: must be > vwma_rapide (20), got 15",
+		"generator:\n  name: smart_eco\n  params:\n    stoch_k_period: 0\n":                                                  "generator.params.stoch_k_period (line 4): must be > 0, got 0",
+		"generator:\n  name: vwma_cross_dmi_simple\n  params: [4, 12]\n":                                                     "generator.params (line 3): must be a mapping of parameters",
+		"generator:\n  name: direction_dmi\n  params:\n    enable_exit_trend: false\n    enable_exit_counter_trend: false\n": "generator.params.enable_exit_trend (line 4): at least one",
+	}
+	for text, want := range cases {
+		_, err := signals.NewGeneratorFromSpec(registrySpec(t, text), signals.GeneratorConfig{})
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("%q: expected error %q, got %v", text, want, err)
+		}
+	}
+}
+
+// TestGeneratorRegistry_Declarative checks a declarative strategy file is loaded through the registry
+func TestGeneratorRegistry_Declarative(t *testing.T) {
+	dir := t.TempDir()
+	strategy := filepath.Join(dir, "strategy.yaml")
+	if err := os.WriteFile(strategy, []byte("name: cross100\nentry_long:\n  trigger: crossover(close, 100)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "config.yaml")
+	text := "backtest:\n  export_json: true\ngenerator:\n  name: declarative\n  params:\n    file: " + strategy + "\n"
+	if err := os.WriteFile(config, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := signals.LoadGeneratorSpec(config)
+	if err != nil || spec == nil {
+		t.Fatalf("LoadGeneratorSpec failed: %v", err)
+	}
+	g, err := signals.NewGeneratorFromSpec(*spec, signals.GeneratorConfig{})
+	if err != nil {
+		t.Fatalf("NewGeneratorFromSpec failed: %v", err)
+	}
+	if g.Name() != "cross100" {
+		t.Errorf("Unexpected generator %s", g.Name())
+	}
+
+	if spec, err := signals.ParseGeneratorSpec([]byte("backtest:\n  export_json: true\n")); spec != nil || err != nil {
+		t.Errorf("Missing section must return nil, got %v %v", spec, err)
+	}
+}
+
+// TestGeneratorRegistry_DecodeOnce checks a decoded spec builds independent generators
+func TestGeneratorRegistry_DecodeOnce(t *testing.T) {
+	factory, config, err := signals.DecodeGeneratorSpec(registrySpec(t, "generator:\n  name: smart_eco\n  params:\n    atr_period: 7\n"))
+	if err != nil {
+		t.Fatalf("DecodeGeneratorSpec failed: %v", err)
+	}
+	if config.(*smart_eco.Config).ATRPeriod != 7 {
+		t.Errorf("Params not decoded: %+v", config)
+	}
+	klines := trendKlines(120)
+	var counts []int
+	for i := 0; i < 2; i++ {
+		g, err := factory.NewGenerator(config, signals.GeneratorConfig{})
+		if err != nil {
+			t.Fatalf("NewGenerator failed: %v", err)
+		}
+		counts = append(counts, len(detectAll(t, g, klines)))
+	}
+	if counts[0] != counts[1] {
+		t.Errorf("Generators built from the same config must not share state: %v", counts)
+	}
+
+	if _, _, err := signals.DecodeGeneratorSpec(registrySpec(t, "generator:\n  name: smart_eco\n  params:\n    atr_period: 0\n")); err == nil || !strings.Contains(err.Error(), "generator.params.atr_period") {
+		t.Errorf("Expected field error on atr_period, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+// Package tests provides tests for the higher-timeframe trend gate
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+)
+
+// gateKlines 15m bars from midnight: hours 0-3 close 110, hours 4-7 close 90, hours 8-11 close 100
+func gateKlines() []signals.Kline {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 48, 15*time.Minute)
+	for i := range klines {
+		c := 100.0
+		switch {
+		case i < 16:
+			c = 110
+		case i < 32:
+			c = 90
+		}
+		klines[i].Open, klines[i].High, klines[i].Low, klines[i].Close = c, c, c, c
+	}
+	return klines
+}
+
+// TestHTFGate_BlockFlipForceExit checks gating on the last closed 1h bar, flips and forced exits
+func TestHTFGate_BlockFlipForceExit(t *testing.T) {
+	klines := gateKlines()
+	at := func(i int) time.Time { return klines[i].OpenTime }
+	entry := func(i int, side signals.SignalType) signals.Signal {
+		return signals.Signal{Timestamp: at(i), Action: signals.SignalActionEntry, Type: side, Price: klines[i].Close}
+	}
+	exit := func(i, entryIdx int, side signals.SignalType) signals.Signal {
+		entryTime := at(entryIdx)
+		return signals.Signal{Timestamp: at(i), Action: signals.SignalActionExit, Type: side, EntryTime: &entryTime}
+	}
+	long, short := signals.SignalTypeLong, signals.SignalTypeShort
+	inner := []signals.Signal{
+		entry(5, long),     // HTF LONG (hour 0 closed at bar 3): kept
+		entry(6, short),    // Against HTF: blocked, its exit too
+		exit(8, 6, short),  //
+		entry(22, long),    // HTF SHORT since bar 19 (hour 4 closed)
+		exit(24, 22, long), //
+		exit(25, 5, long),  // Already force-closed at bar 19
+		entry(40, long),    // HTF neutral since bar 35
+	}
+
+	run := func(config signals.HTFGateConfig) (*signals.HTFGate, []signals.Signal) {
+		source, err := signals.NewHTFIndicatorState(htfAggregator(t), "1h", "sma(1)", 95, 105)
+		if err != nil {
+			t.Fatalf("NewHTFIndicatorState failed: %v", err)
+		}
+		gate, err := signals.NewHTFGate(&stubGenerator{out: inner}, source, config)
+		if err != nil {
+			t.Fatalf("NewHTFGate failed: %v", err)
+		}
+		if err := gate.Initialize(signals.GeneratorConfig{Timeframe: "15m"}); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		if err := gate.CalculateIndicators(klines); err != nil {
+			t.Fatalf("CalculateIndicators failed: %v", err)
+		}
+		out, err := gate.DetectSignals(klines)
+		if err != nil {
+			t.Fatalf("DetectSignals failed: %v", err)
+		}
+		return gate, out
+	}
+
+	// Block mode with forced exit
+	gate, out := run(signals.HTFGateConfig{ForceExit: true})
+	if len(out) != 2 {
+		t.Fatalf("Expected LONG entry and forced exit, got %+v", out)
+	}
+	if out[0].Type != long || out[0].Metadata["htf_state"] != "LONG" {
+		t.Errorf("Unexpected kept entry %+v", out[0])
+	}
+	if !out[1].Timestamp.Equal(at(19)) || out[1].Metadata["exit_reason"] != "htf_reversal" || !out[1].EntryTime.Equal(at(5)) {
+		t.Errorf("Unexpected forced exit %+v", out[1])
+	}
+	if gate.BlockedEntries() != 3 || gate.ForcedExits() != 1 {
+		t.Errorf("Unexpected counters blocked=%d forced=%d", gate.BlockedEntries(), gate.ForcedExits())
+	}
+
+	// Flip mode, neutral allowed, no forced exit
+	gate, out = run(signals.HTFGateConfig{Mode: signals.HTFGateFlip, AllowNeutral: true})
+	if len(out) != 7 {
+		t.Fatalf("Expected 7 signals, got %+v", out)
+	}
+	flipped, flippedExit := out[3], out[4]
+	if !out[1].Timestamp.Equal(at(6)) || out[1].Type != long || out[1].Metadata["htf_original_type"] != "SHORT" {
+		t.Errorf("Unexpected flipped entry %+v", out[1])
+	}
+	if !flipped.Timestamp.Equal(at(22)) || flipped.Type != short || flipped.Metadata["htf_flipped"] != true {
+		t.Errorf("Unexpected flipped entry %+v", flipped)
+	}
+	if !flippedExit.Timestamp.Equal(at(24)) || flippedExit.Type != short {
+		t.Errorf("Flipped entry exit must be flipped too: %+v", flippedExit)
+	}
+	if !out[5].Timestamp.Equal(at(25)) || !out[5].EntryTime.Equal(at(5)) {
+		t.Errorf("Inner exit must pass without ForceExit: %+v", out[5])
+	}
+	if out[6].Type != long || out[6].Metadata["htf_state"] != "" {
+		t.Errorf("Neutral entry must pass with AllowNeutral: %+v", out[6])
+	}
+	if gate.FlippedEntries() != 2 || gate.BlockedEntries() != 0 {
+		t.Errorf("Unexpected counters flipped=%d blocked=%d", gate.FlippedEntries(), gate.BlockedEntries())
+	}
+
+	if _, err := signals.NewHTFGate(&stubGenerator{}, nil, signals.HTFGateConfig{}); err == nil {
+		t.Errorf("Expected missing source error")
+	}
+}
+
+// TestHTFGeneratorState_NoLookAhead checks a HTF generator signal is only visible at its bar close
+func TestHTFGeneratorState_NoLookAhead(t *testing.T) {
+	klines := gateKlines()
+	hour := func(h int) time.Time { return klines[0].OpenTime.Add(time.Duration(h) * time.Hour) }
+	entryTime := hour(1)
+	htfGen := &stubGenerator{out: []signals.Signal{
+		{Timestamp: hour(1), Action: signals.SignalActionEntry, Type: signals.SignalTypeShort},
+		{Timestamp: hour(3), Action: signals.SignalActionExit, Type: signals.SignalTypeShort, EntryTime: &entryTime},
+	}}
+	source, err := signals.NewHTFGeneratorState(htfAggregator(t), htfGen, "1h")
+	if err != nil {
+		t.Fatalf("NewHTFGeneratorState failed: %v", err)
+	}
+	if err := source.Initialize(signals.GeneratorConfig{}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	states, err := source.States(klines, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("States failed: %v", err)
+	}
+	// Hour 1 = bars 4-7 (visible from bar 7), hour 3 = bars 12-15 (neutral from bar 15)
+	expected := map[int]signals.SignalType{6: "", 7: signals.SignalTypeShort, 14: signals.SignalTypeShort, 15: ""}
+	for i, want := range expected {
+		if states[i] != want {
+			t.Errorf("bar %d: got %q, want %q", i, states[i], want)
+		}
+	}
+}
+
+// crossGenerator index-based generator (like the production ones): LONG while Close > 100,
+// each call only processes the closed bars after the last processed index
+type crossGenerator struct {
+	lastProcessedIdx int
+	long             bool
+	entryTime        time.Time
+}
+
+func (g *crossGenerator) Name() string { return "cross" }
+func (g *crossGenerator) Initialize(signals.GeneratorConfig) error {
+	g.lastProcessedIdx, g.long = -1, false
+	return nil
+}
+func (g *crossGenerator) CalculateIndicators([]signals.Kline) error { return nil }
+func (g *crossGenerator) DetectSignals(klines []signals.Kline) ([]signals.Signal, error) {
+	var out []signals.Signal
+	for i := g.lastProcessedIdx + 1; i <= len(klines)-2; i++ {
+		k := klines[i]
+		switch {
+		case !g.long && k.Close > 100:
+			g.long, g.entryTime = true, k.OpenTime
+			out = append(out, signals.Signal{Timestamp: k.OpenTime, Action: signals.SignalActionEntry, Type: signals.SignalTypeLong})
+		case g.long && k.Close < 100:
+			entryTime := g.entryTime
+			g.long = false
+			out = append(out, signals.Signal{Timestamp: k.OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeLong, EntryTime: &entryTime})
+		}
+	}
+	if len(klines) >= 2 {
+		g.lastProcessedIdx = len(klines) - 2
+	}
+	return out, nil
+}
+func (g *crossGenerator) GetMetrics() signals.GeneratorMetrics { return signals.GeneratorMetrics{} }
+
+// TestHTFGeneratorState_SlidingWindow checks a live sliding window yields the same states as the full series
+func TestHTFGeneratorState_SlidingWindow(t *testing.T) {
+	// 15m bars, alternating 4h blocks below and above 100
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 96, 15*time.Minute)
+	for i := range klines {
+		c := 90.0
+		if (i/16)%2 == 1 {
+			c = 110
+		}
+		klines[i].Open, klines[i].High, klines[i].Low, klines[i].Close = c, c, c, c
+	}
+
+	newSource := func() *signals.HTFGeneratorState {
+		source, err := signals.NewHTFGeneratorState(htfAggregator(t), &crossGenerator{}, "1h")
+		if err != nil {
+			t.Fatalf("NewHTFGeneratorState failed: %v", err)
+		}
+		if err := source.Initialize(signals.GeneratorConfig{}); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		return source
+	}
+
+	expected, err := newSource().States(klines, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("States failed: %v", err)
+	}
+
+	const window = 40
+	source := newSource()
+	transitions := 0
+	for end := window; end <= len(klines); end++ {
+		states, err := source.States(klines[end-window:end], 15*time.Minute)
+		if err != nil {
+			t.Fatalf("States failed: %v", err)
+		}
+		if got, want := states[len(states)-1], expected[end-1]; got != want {
+			t.Errorf("bar %d: got %q, want %q", end-1, got, want)
+		}
+		if expected[end-1] != expected[end-2] {
+			transitions++
+		}
+	}
+	if transitions < 3 {
+		t.Fatalf("Expected several HTF transitions in the sliding range, got %d", transitions)
+	}
+}
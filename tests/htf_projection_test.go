@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"go/parser"
+	"go/token"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-economique/internal/datasource/binance"
+	"agent-economique/internal/shared"
+	"agent-economique/internal/signals"
+)
+
+// htfAggregator Binance aggregator injected in the HTF projections
+func htfAggregator(t *testing.T) shared.TimeframeAggregator {
+	t.Helper()
+	aggregator, err := binance.NewTimeframeAggregator(shared.AggregationConfig{})
+	if err != nil {
+		t.Fatalf("NewTimeframeAggregator: %v", err)
+	}
+	return aggregator
+}
+
+// htfKlines 15m bars starting mid-hour with Close = bar index
+func htfKlines(n int) []signals.Kline {
+	start := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+	klines := testKlines(start, n, 15*time.Minute)
+	for i := range klines {
+		c := float64(i)
+		klines[i].Open, klines[i].High, klines[i].Low, klines[i].Close = c, c, c, c
+	}
+	return klines
+}
+
+// TestHTFProjection_NoLookAhead checks the 1h value only appears once the hour is closed
+func TestHTFProjection_NoLookAhead(t *testing.T) {
+	klines := htfKlines(20)
+	values, err := signals.ProjectHigherTimeframe(htfAggregator(t), klines, "15m", "1h", "sma(2)")
+	if err != nil {
+		t.Fatalf("ProjectHigherTimeframe: %v", err)
+	}
+
+	// Partial 00:xx hour dropped; 1h closes 5, 9, 13, 17 => sma(2) NaN, 7, 11, 15
+	expected := map[int]float64{8: math.NaN(), 9: 7, 12: 7, 13: 11, 16: 11, 17: 15, 19: 15}
+	for i, want := range expected {
+		got := values[i]
+		if math.IsNaN(want) != math.IsNaN(got) || (!math.IsNaN(want) && got != want) {
+			t.Errorf("bar %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	// The forming hour never leaks: changing its bars leaves the projection intact
+	changed := htfKlines(20)
+	changed[18].Close, changed[19].Close = 1000, 1000
+	again, _ := signals.ProjectHigherTimeframe(htfAggregator(t), changed, "15m", "1h", "sma(2)")
+	if again[19] != values[19] {
+		t.Errorf("forming HTF candle leaked: %v vs %v", again[19], values[19])
+	}
+}
+
+// TestHTFProjection_StreamingMatchesBatch checks Update reproduces Project bar by bar
+func TestHTFProjection_StreamingMatchesBatch(t *testing.T) {
+	klines := htfKlines(40)
+	projector, err := signals.NewHTFProjector(htfAggregator(t), "1h", "ema(3)")
+	if err != nil {
+		t.Fatalf("NewHTFProjector: %v", err)
+	}
+	batch, err := projector.Project(klines, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("Project: %v", err)
+	}
+
+	for i, k := range klines {
+		got, err := projector.Update(k, 15*time.Minute)
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if math.IsNaN(got) != math.IsNaN(batch[i]) || (!math.IsNaN(got) && math.Abs(got-batch[i]) > 1e-9) {
+			t.Errorf("bar %d: streaming %v, batch %v", i, got, batch[i])
+		}
+	}
+
+	if _, err := projector.Project(klines, time.Hour); err == nil {
+		t.Error("expected error when base timeframe is not lower than the HTF")
+	}
+}
+
+// TestHTFProjection_NoDataSourceDependency checks the signals package takes its aggregator
+// as a parameter instead of importing a data source
+func TestHTFProjection_NoDataSourceDependency(t *testing.T) {
+	files, err := filepath.Glob("../internal/signals/*.go")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no signals sources: %v", err)
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		parsed, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		for _, imp := range parsed.Imports {
+			if strings.Contains(imp.Path.Value, "internal/datasource") {
+				t.Errorf("%s imports %s", filepath.Base(file), imp.Path.Value)
+			}
+		}
+	}
+}
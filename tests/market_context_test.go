@@ -0,0 +1,103 @@
+// Package tests provides tests for derivatives alignment and the funding filter
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"agent-economique/internal/shared"
+	"agent-economique/internal/signals"
+)
+
+// stubGenerator emits fixed signals
+type stubGenerator struct {
+	out []signals.Signal
+}
+
+func (s *stubGenerator) Name() string                              { return "stub" }
+func (s *stubGenerator) Initialize(signals.GeneratorConfig) error  { return nil }
+func (s *stubGenerator) CalculateIndicators([]signals.Kline) error { return nil }
+func (s *stubGenerator) DetectSignals([]signals.Kline) ([]signals.Signal, error) {
+	return append([]signals.Signal(nil), s.out...), nil
+}
+func (s *stubGenerator) GetMetrics() signals.GeneratorMetrics { return signals.GeneratorMetrics{} }
+
+func testKlines(start time.Time, n int, step time.Duration) []signals.Kline {
+	klines := make([]signals.Kline, n)
+	for i := range klines {
+		klines[i] = signals.Kline{OpenTime: start.Add(time.Duration(i) * step), Close: 100}
+	}
+	return klines
+}
+
+// TestNewMarketContext_NoLookAhead checks values only appear once known at bar close
+func TestNewMarketContext_NoLookAhead(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := testKlines(start, 4, time.Hour)
+	ms := func(d time.Duration) int64 { return start.Add(d).UnixMilli() }
+
+	series := &shared.DerivativesSeries{
+		FundingRates: []shared.DerivativesPoint{
+			{Time: ms(2 * time.Hour), Value: 0.0002}, // Known at close of bar 2 only
+			{Time: ms(30 * time.Minute), Value: 0.0001},
+		},
+		PredictedFunding: []shared.DerivativesPoint{{Time: ms(90 * time.Minute), Value: 0.0009}},
+		OpenInterest:     []shared.DerivativesPoint{{Time: ms(0), Value: 1000}, {Time: ms(3 * time.Hour), Value: 1100}},
+	}
+
+	ctx := signals.NewMarketContext(klines, time.Hour, series)
+
+	if v, ok := ctx.Funding(0); !ok || v != 0.0001 {
+		t.Errorf("bar 0: expected settled funding 0.0001, got %v (%v)", v, ok)
+	}
+	if v, ok := ctx.Funding(1); !ok || v != 0.0009 {
+		t.Errorf("bar 1: expected predicted funding 0.0009, got %v (%v)", v, ok)
+	}
+	if v, ok := ctx.Funding(2); !ok || v != 0.0002 {
+		t.Errorf("bar 2: expected settlement to supersede prediction, got %v (%v)", v, ok)
+	}
+	if change, ok := ctx.OpenInterestChange(3, 3); !ok || change < 0.0999 || change > 0.1001 {
+		t.Errorf("bar 3: expected OI change 10%%, got %v (%v)", change, ok)
+	}
+	if _, ok := ctx.LongShort(0); ok {
+		t.Error("Expected long/short ratio to be unknown")
+	}
+}
+
+// TestFundingFilter_BlocksExtremeEntries checks entries are dropped with their exits, other exits kept
+func TestFundingFilter_BlocksExtremeEntries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := testKlines(start, 3, time.Hour)
+	longEntry := klines[0].OpenTime
+
+	inner := &stubGenerator{out: []signals.Signal{
+		{Timestamp: klines[0].OpenTime, Action: signals.SignalActionEntry, Type: signals.SignalTypeLong},
+		{Timestamp: klines[1].OpenTime, Action: signals.SignalActionEntry, Type: signals.SignalTypeShort},
+		{Timestamp: klines[2].OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeLong, EntryTime: &longEntry},
+		{Timestamp: klines[2].OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeLong},
+	}}
+
+	filter := signals.NewFundingFilter(inner, signals.FundingFilterConfig{MaxLongFunding: 0.0005, MinShortFunding: -0.0005})
+	if err := filter.Initialize(signals.GeneratorConfig{Symbol: "SOLUSDT", Timeframe: "1h"}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	series := &shared.DerivativesSeries{FundingRates: []shared.DerivativesPoint{{Time: start.UnixMilli(), Value: 0.001}}}
+	if err := signals.CalculateIndicatorsWithContext(filter, klines, signals.NewMarketContext(klines, time.Hour, series)); err != nil {
+		t.Fatalf("CalculateIndicators failed: %v", err)
+	}
+
+	out, err := filter.DetectSignals(klines)
+	if err != nil {
+		t.Fatalf("DetectSignals failed: %v", err)
+	}
+	if len(out) != 2 || out[0].Type != signals.SignalTypeShort || out[1].Action != signals.SignalActionExit {
+		t.Fatalf("Expected long entry blocked, got %+v", out)
+	}
+	if out[0].Metadata["funding_rate"] != 0.001 {
+		t.Errorf("Expected funding_rate metadata, got %v", out[0].Metadata)
+	}
+	if filter.BlockedEntries() != 1 {
+		t.Errorf("Expected 1 blocked entry, got %d", filter.BlockedEntries())
+	}
+}
@@ -0,0 +1,76 @@
+// Package tests provides tests for the candlestick pattern filter
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+)
+
+// patternKlines flat 1h bars (ATR 2) with a bullish engulfing completed on bar 30
+func patternKlines() []signals.Kline {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 40, time.Hour)
+	for i := range klines {
+		klines[i].Open, klines[i].High, klines[i].Low, klines[i].Close = 100, 101, 99, 100
+		klines[i].Volume = 1000
+	}
+	klines[29].Open, klines[29].High, klines[29].Low, klines[29].Close = 101, 101.5, 99.5, 100
+	klines[30].Open, klines[30].High, klines[30].Low, klines[30].Close = 99.8, 102.5, 99.6, 102.2
+	return klines
+}
+
+// TestPatternFilter_RequireAndBlock checks confirmation, opposition and pattern metadata
+func TestPatternFilter_RequireAndBlock(t *testing.T) {
+	klines := patternKlines()
+	longEntry, shortEntry, lateEntry := klines[31].OpenTime, klines[32].OpenTime, klines[36].OpenTime
+	signalsIn := []signals.Signal{
+		{Timestamp: longEntry, Action: signals.SignalActionEntry, Type: signals.SignalTypeLong},
+		{Timestamp: shortEntry, Action: signals.SignalActionEntry, Type: signals.SignalTypeShort},
+		{Timestamp: klines[33].OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeLong, EntryTime: &longEntry},
+		{Timestamp: lateEntry, Action: signals.SignalActionEntry, Type: signals.SignalTypeLong},
+	}
+
+	run := func(config signals.PatternFilterConfig) (*signals.PatternFilter, []signals.Signal) {
+		filter, err := signals.NewPatternFilter(&stubGenerator{out: append([]signals.Signal(nil), signalsIn...)}, config)
+		if err != nil {
+			t.Fatalf("NewPatternFilter failed: %v", err)
+		}
+		filter.Initialize(signals.GeneratorConfig{Symbol: "TEST", Timeframe: "1h"})
+		if err := filter.CalculateIndicators(klines); err != nil {
+			t.Fatalf("CalculateIndicators failed: %v", err)
+		}
+		out, err := filter.DetectSignals(klines)
+		if err != nil {
+			t.Fatalf("DetectSignals failed: %v", err)
+		}
+		return filter, out
+	}
+
+	filter, out := run(signals.PatternFilterConfig{Require: []string{"engulfing_haussier"}, MaxAge: 2})
+	if len(out) != 2 || out[0].Type != signals.SignalTypeLong || out[1].Action != signals.SignalActionExit {
+		t.Fatalf("Expected the confirmed long entry and its exit, got %+v", out)
+	}
+	if out[0].Metadata["pattern"] != "ENGULFING_HAUSSIER" || filter.BlockedEntries() != 2 {
+		t.Errorf("Unexpected metadata %v or blocked count %d", out[0].Metadata, filter.BlockedEntries())
+	}
+
+	// Neutral dojis confirm both sides; the bullish engulfing blocks the short
+	filter, out = run(signals.PatternFilterConfig{Require: []string{"DOJI", "DOJI_PATTES_LONGUES"}, Block: []string{"ENGULFING_HAUSSIER"}, MaxAge: 5})
+	if len(out) != 3 || filter.BlockedEntries() != 1 {
+		t.Errorf("Expected only the short entry to be blocked, got %+v", out)
+	}
+	for _, sig := range out {
+		if sig.Type == signals.SignalTypeShort {
+			t.Errorf("Short entry must be blocked by the bullish engulfing, got %+v", out)
+		}
+	}
+
+	if _, out := run(signals.PatternFilterConfig{TagOnly: true, MaxAge: 1}); len(out) != 4 {
+		t.Errorf("Tag-only mode must keep every signal, got %+v", out)
+	}
+
+	if _, err := signals.NewPatternFilter(&stubGenerator{}, signals.PatternFilterConfig{Block: []string{"hammerr"}}); err == nil {
+		t.Errorf("Expected unknown pattern error")
+	}
+}
@@ -0,0 +1,70 @@
+// Package tests provides tests for the market regime filter
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+)
+
+// trendKlines steady uptrend so that the classifier settles on TENDANCE_HAUSSE
+func trendKlines(n int) []signals.Kline {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), n, time.Hour)
+	price := 100.0
+	for i := range klines {
+		open := price
+		price *= 1.004
+		klines[i].Open, klines[i].Close = open, price
+		klines[i].High, klines[i].Low = price+0.1, open-0.1
+		klines[i].Volume = 1000
+	}
+	return klines
+}
+
+// TestRegimeFilter_GateAndTags checks gating per side and regime labels on trades
+func TestRegimeFilter_GateAndTags(t *testing.T) {
+	klines := trendKlines(200)
+	longEntry, shortEntry := klines[180].OpenTime, klines[181].OpenTime
+	signalsIn := []signals.Signal{
+		{Timestamp: longEntry, Action: signals.SignalActionEntry, Type: signals.SignalTypeLong},
+		{Timestamp: shortEntry, Action: signals.SignalActionEntry, Type: signals.SignalTypeShort},
+		{Timestamp: klines[185].OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeShort, EntryTime: &shortEntry},
+		{Timestamp: klines[190].OpenTime, Action: signals.SignalActionExit, Type: signals.SignalTypeLong, EntryTime: &longEntry},
+	}
+
+	run := func(config signals.RegimeFilterConfig) (*signals.RegimeFilter, []signals.Signal) {
+		filter, err := signals.NewRegimeFilter(&stubGenerator{out: signalsIn}, config)
+		if err != nil {
+			t.Fatalf("NewRegimeFilter failed: %v", err)
+		}
+		filter.Initialize(signals.GeneratorConfig{Symbol: "TEST", Timeframe: "1h"})
+		if err := filter.CalculateIndicators(klines); err != nil {
+			t.Fatalf("CalculateIndicators failed: %v", err)
+		}
+		out, err := filter.DetectSignals(klines)
+		if err != nil {
+			t.Fatalf("DetectSignals failed: %v", err)
+		}
+		return filter, out
+	}
+
+	filter, out := run(signals.RegimeFilterConfig{})
+	if len(out) != 2 || out[0].Type != signals.SignalTypeLong || out[1].Action != signals.SignalActionExit {
+		t.Fatalf("Expected the long entry and its exit, got %+v", out)
+	}
+	if filter.BlockedEntries() != 1 {
+		t.Errorf("Expected 1 blocked entry, got %d", filter.BlockedEntries())
+	}
+	if out[0].Metadata["regime"] != "TENDANCE_HAUSSE" || signals.SignalRegime(out[1]) != "TENDANCE_HAUSSE" {
+		t.Errorf("Unexpected regime metadata %v / %v", out[0].Metadata, out[1].Metadata)
+	}
+
+	if _, out := run(signals.RegimeFilterConfig{TagOnly: true}); len(out) != 4 || out[1].Metadata["regime"] != "TENDANCE_HAUSSE" {
+		t.Errorf("Tag-only mode must keep every signal, got %+v", out)
+	}
+
+	if _, err := signals.NewRegimeFilter(&stubGenerator{}, signals.RegimeFilterConfig{AllowLong: []string{"bull"}}); err == nil {
+		t.Errorf("Expected unknown regime error")
+	}
+}
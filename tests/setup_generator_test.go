@@ -0,0 +1,168 @@
+// Package tests provides tests for the setup state machine generator adapter
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"agent-economique/internal/setups"
+	"agent-economique/internal/setups/dmiopen"
+	"agent-economique/internal/signals"
+)
+
+// scriptSetup triggers and validates on bars identified by their open time
+type scriptSetup struct {
+	triggers map[time.Time]setups.Side
+	gates    map[time.Time]bool
+}
+
+type scriptSetupRules struct {
+	setup  *scriptSetup
+	klines []signals.Kline
+}
+
+func (s *scriptSetup) Name() string                 { return "script" }
+func (s *scriptSetup) MinHistorySize() int          { return 1 }
+func (s *scriptSetup) MachineConfig() setups.Config { return setups.Config{RecheckLags: []int{2}} }
+func (s *scriptSetup) Prepare(klines []signals.Kline) (setups.Rules, error) {
+	return scriptSetupRules{setup: s, klines: klines}, nil
+}
+
+func (r scriptSetupRules) Trigger(i int) (setups.Side, bool) {
+	side, ok := r.setup.triggers[r.klines[i].OpenTime]
+	return side, ok
+}
+
+func (r scriptSetupRules) Gate(i int, side setups.Side, lag int) setups.Check {
+	return setups.Check{Pass: r.setup.gates[r.klines[i].OpenTime], Values: map[string]float64{"bar": float64(i)}}
+}
+
+func (r scriptSetupRules) Invariant(i int, side setups.Side) setups.Check {
+	return setups.Check{Pass: true}
+}
+
+// TestSetupGenerator_SlidingWindow checks rechecks across a sliding window and position handling
+func TestSetupGenerator_SlidingWindow(t *testing.T) {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 40, time.Hour)
+	setup := &scriptSetup{
+		triggers: map[time.Time]setups.Side{klines[10].OpenTime: setups.SideLong, klines[20].OpenTime: setups.SideShort},
+		gates:    map[time.Time]bool{klines[12].OpenTime: true, klines[20].OpenTime: true},
+	}
+	gen := setups.NewGenerator(setup, setups.GeneratorConfig{ReverseOnOpposite: true, MaxHoldBars: 15})
+	if err := gen.Initialize(signals.GeneratorConfig{}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if gen.MinHistorySize() != 4 {
+		t.Errorf("Expected min history 4, got %d", gen.MinHistorySize())
+	}
+
+	// Window of 8 klines sliding one bar per call (the last one is forming)
+	var out []signals.Signal
+	for end := 8; end <= len(klines); end++ {
+		window := klines[end-8 : end]
+		if err := gen.CalculateIndicators(window); err != nil {
+			t.Fatalf("CalculateIndicators failed: %v", err)
+		}
+		sigs, err := gen.DetectSignals(window)
+		if err != nil {
+			t.Fatalf("DetectSignals failed: %v", err)
+		}
+		out = append(out, sigs...)
+	}
+
+	if len(out) != 4 {
+		t.Fatalf("Expected 4 signals, got %+v", out)
+	}
+	long, exitLong, short, exitShort := out[0], out[1], out[2], out[3]
+	if long.Type != signals.SignalTypeLong || !long.Timestamp.Equal(klines[12].OpenTime) ||
+		long.Metadata["lag"] != 2 || !long.Metadata["trigger_time"].(time.Time).Equal(klines[10].OpenTime) {
+		t.Errorf("Unexpected recheck entry %+v", long)
+	}
+	if exitLong.Action != signals.SignalActionExit || exitLong.Metadata["exit_reason"] != "opposite" || !exitLong.Timestamp.Equal(klines[20].OpenTime) {
+		t.Errorf("Unexpected reverse exit %+v", exitLong)
+	}
+	if short.Type != signals.SignalTypeShort || short.Metadata["lag"] != 0 {
+		t.Errorf("Unexpected short entry %+v", short)
+	}
+	if exitShort.Metadata["exit_reason"] != "max_hold" || !exitShort.Timestamp.Equal(klines[35].OpenTime) {
+		t.Errorf("Unexpected max hold exit %+v", exitShort)
+	}
+	if m := gen.GetMetrics(); m.EntrySignals != 2 || m.ExitSignals != 2 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+}
+
+// TestSetupGenerator_TriggerOutsideWindow checks a recheck whose trigger bar left the window
+func TestSetupGenerator_TriggerOutsideWindow(t *testing.T) {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 20, time.Hour)
+	setup := &scriptSetup{
+		triggers: map[time.Time]setups.Side{klines[10].OpenTime: setups.SideLong},
+		gates:    map[time.Time]bool{klines[12].OpenTime: true},
+	}
+	gen := setups.NewGenerator(setup, setups.GeneratorConfig{})
+	_ = gen.Initialize(signals.GeneratorConfig{})
+
+	// 2 closed bars + the forming one: bar 10 is gone when bar 12 is rechecked
+	var out []signals.Signal
+	for end := 3; end <= len(klines); end++ {
+		window := klines[end-3 : end]
+		if err := gen.CalculateIndicators(window); err != nil {
+			t.Fatalf("CalculateIndicators failed: %v", err)
+		}
+		sigs, err := gen.DetectSignals(window)
+		if err != nil {
+			t.Fatalf("DetectSignals failed: %v", err)
+		}
+		out = append(out, sigs...)
+	}
+
+	if len(out) != 1 || !out[0].Timestamp.Equal(klines[12].OpenTime) ||
+		!out[0].Metadata["trigger_time"].(time.Time).Equal(klines[10].OpenTime) {
+		t.Errorf("Unexpected signals %+v", out)
+	}
+}
+
+// TestSetupGenerator_DmiOpen checks that the DmiOpen setup runs as a generator
+func TestSetupGenerator_DmiOpen(t *testing.T) {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 300, time.Hour)
+	for i := range klines {
+		price := 100 + 10*math.Sin(float64(i)/12) + float64(i%7)
+		klines[i].Open, klines[i].Close = price-0.5, price
+		klines[i].High, klines[i].Low = price+1, price-1.5
+	}
+	gen, err := dmiopen.NewGenerator(dmiopen.Config{UseRecheck1: true, UseRecheck2: true}, 14, 14,
+		setups.GeneratorConfig{ReverseOnOpposite: true})
+	if err != nil {
+		t.Fatalf("NewGenerator failed: %v", err)
+	}
+	if gen.Name() != "dmi_open" {
+		t.Errorf("Unexpected name %q", gen.Name())
+	}
+	if err := gen.Initialize(signals.GeneratorConfig{}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if err := gen.CalculateIndicators(klines); err != nil {
+		t.Fatalf("CalculateIndicators failed: %v", err)
+	}
+	out, err := gen.DetectSignals(klines)
+	if err != nil {
+		t.Fatalf("DetectSignals failed: %v", err)
+	}
+	if len(out) == 0 {
+		t.Fatalf("Expected DMI open signals")
+	}
+	for _, sig := range out {
+		if sig.Action == signals.SignalActionEntry {
+			if _, ok := sig.Metadata["adx"]; !ok {
+				t.Errorf("Entry metadata must carry DMI values: %+v", sig.Metadata)
+			}
+		}
+	}
+	if len(gen.Machine().Diagnostics()) == 0 {
+		t.Errorf("Expected recorded diagnostics")
+	}
+	if _, err := dmiopen.NewGenerator(dmiopen.Config{}, 0, 14, setups.GeneratorConfig{}); err == nil {
+		t.Errorf("Expected invalid period error")
+	}
+}
@@ -0,0 +1,218 @@
+// Package tests provides tests for generator snapshot / restore
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+	"agent-economique/internal/signals/direction_dmi"
+	"agent-economique/internal/signals/smart_eco"
+	"agent-economique/internal/signals/trend"
+	"agent-economique/internal/signals/vwma_cross_dmi_simple"
+)
+
+// randomWalkKlines deterministic random walk klines (1m)
+func randomWalkKlines(n int, seed int64) []signals.Kline {
+	r := rand.New(rand.NewSource(seed))
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), n, time.Minute)
+	price := 100.0
+	for i := range klines {
+		open := price
+		price += r.NormFloat64() * 0.5
+		klines[i].Open, klines[i].Close = open, price
+		klines[i].High = maxFloat(open, price) + r.Float64()*0.3
+		klines[i].Low = minFloat(open, price) - r.Float64()*0.3
+		klines[i].Volume = 100 + r.Float64()*900
+	}
+	return klines
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// statefulGenerator detection methods plus snapshot contract (direction_dmi has its own Initialize)
+type statefulGenerator interface {
+	Name() string
+	CalculateIndicators(klines []signals.Kline) error
+	DetectSignals(klines []signals.Kline) ([]signals.Signal, error)
+	signals.Snapshotter
+}
+
+// snapshotCases initialized stateful generators under test
+func snapshotCases() map[string]func() statefulGenerator {
+	initialized := func(g signals.Generator) statefulGenerator {
+		_ = g.Initialize(signals.GeneratorConfig{Timeframe: "1m"})
+		return g.(statefulGenerator)
+	}
+	return map[string]func() statefulGenerator{
+		"trend": func() statefulGenerator {
+			return initialized(trend.NewTrendGenerator(trend.Config{
+				VwmaRapide: 6, VwmaLent: 24, DmiPeriode: 14, DmiSmooth: 14, AtrPeriode: 14,
+				WindowGammaValidate: 5, WindowW: 10,
+				EnableExitVWMA: true, EnableExitTrailing: true, TrailingATRCoeff: 2, TrailingCapPct: 0.01,
+			}))
+		},
+		"direction_dmi": func() statefulGenerator {
+			g := direction_dmi.NewDirectionDMIGenerator(signals.GeneratorConfig{}, direction_dmi.Config{
+				VWMAPeriod: 20, SlopePeriod: 3, KConfirmation: 2, ATRPeriod: 14, ATRCoefficient: 0.1,
+				DMIPeriod: 14, DMISmooth: 14, WindowGammaValidate: 5, WindowMatching: 5,
+				EnableEntryTrend: true, EnableEntryCounterTrend: true, EnableExitTrend: true, EnableExitCounterTrend: true,
+			})
+			_ = g.Initialize()
+			return g
+		},
+		"vwma_cross_dmi_simple": func() statefulGenerator {
+			return initialized(vwma_cross_dmi_simple.NewVWMACrossDMISimpleGenerator(vwma_cross_dmi_simple.Config{
+				VWMAShortPeriod: 6, VWMALongPeriod: 24, DMIPeriod: 14, DMISmooth: 14, WindowMatching: 5,
+			}))
+		},
+		"smart_eco": func() statefulGenerator {
+			return initialized(smart_eco.NewGenerator(smart_eco.Config{ATRPeriod: 3, BodyPctMin: 0.6, BodyATRMin: 0.6, StochKPeriod: 14, StochKSmooth: 3, StochDPeriod: 3}))
+		},
+	}
+}
+
+func detect(t *testing.T, g statefulGenerator, klines []signals.Kline) []signals.Signal {
+	t.Helper()
+	if err := g.CalculateIndicators(klines); err != nil {
+		t.Fatalf("%s CalculateIndicators failed: %v", g.Name(), err)
+	}
+	out, err := g.DetectSignals(klines)
+	if err != nil {
+		t.Fatalf("%s DetectSignals failed: %v", g.Name(), err)
+	}
+	return out
+}
+
+// sameSignals deep comparison, metadata compared printed (NaN indicators in warmup are never DeepEqual)
+func sameSignals(a, b []signals.Signal) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		if fmt.Sprint(x.Metadata) != fmt.Sprint(y.Metadata) {
+			return false
+		}
+		x.Metadata, y.Metadata = nil, nil
+		if !reflect.DeepEqual(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSnapshot_RestoreEveryMarker checks a fresh generator restored at every marker emits
+// exactly the signals of a generator kept in memory (positions, trailings, exits included)
+func TestSnapshot_RestoreEveryMarker(t *testing.T) {
+	klines := randomWalkKlines(600, 7)
+	for name, build := range snapshotCases() {
+		continuous := build()
+		var expected, got []signals.Signal
+		var snapshot []byte
+		for end := 200; end <= len(klines); end++ {
+			window := klines[:end]
+			expected = append(expected, detect(t, continuous, window)...)
+
+			g := build()
+			if snapshot != nil {
+				if err := g.Restore(snapshot); err != nil {
+					t.Fatalf("%s Restore failed: %v", name, err)
+				}
+			}
+			got = append(got, detect(t, g, window)...)
+			var err error
+			if snapshot, err = g.Snapshot(); err != nil {
+				t.Fatalf("%s Snapshot failed: %v", name, err)
+			}
+		}
+		if len(expected) == 0 {
+			t.Fatalf("%s: expected signals on random walk", name)
+		}
+		if !sameSignals(expected, got) {
+			t.Errorf("%s: restored run differs (%d vs %d signals)", name, len(expected), len(got))
+		}
+	}
+}
+
+// TestSnapshot_SlidingWindow checks the resume point is re-anchored by OpenTime on a shifted window
+func TestSnapshot_SlidingWindow(t *testing.T) {
+	klines := randomWalkKlines(400, 3)
+	g := smart_eco.NewGenerator(smart_eco.Config{ATRPeriod: 3, BodyPctMin: 0.3, StochKPeriod: 14, StochKSmooth: 3, StochDPeriod: 3})
+	_ = g.Initialize(signals.GeneratorConfig{})
+	detect(t, g, klines[:300])
+	snapshot, err := g.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	// Window slid by 50 bars and 20 new bars: only bars after the last processed one are evaluated
+	restored := smart_eco.NewGenerator(smart_eco.Config{ATRPeriod: 3, BodyPctMin: 0.3, StochKPeriod: 14, StochKSmooth: 3, StochDPeriod: 3})
+	_ = restored.Initialize(signals.GeneratorConfig{})
+	if err := restored.Restore(snapshot); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	lastProcessed := klines[298].OpenTime
+	for _, sig := range detect(t, restored, klines[50:320]) {
+		if !sig.Timestamp.After(lastProcessed) {
+			t.Errorf("Signal at %s was already processed before the snapshot", sig.Timestamp)
+		}
+	}
+	next, _ := restored.Snapshot()
+	var envelope signals.GeneratorSnapshot
+	var state struct{ Resume signals.ResumePoint }
+	_ = json.Unmarshal(next, &envelope)
+	_ = json.Unmarshal(envelope.State, &state)
+	if state.Resume.Index != 268 || !state.Resume.Time.Equal(klines[318].OpenTime) {
+		t.Errorf("Unexpected resume point %+v", state.Resume)
+	}
+}
+
+// TestSnapshot_FileAndValidation checks file persistence and envelope validation errors
+func TestSnapshot_FileAndValidation(t *testing.T) {
+	build := snapshotCases()["trend"]
+	g := build()
+	path := filepath.Join(t.TempDir(), "state", "trend.json")
+
+	if loaded, err := signals.LoadSnapshot(g.(signals.Generator), path); loaded || err != nil {
+		t.Fatalf("Missing file must be ignored, got %v %v", loaded, err)
+	}
+	detect(t, g, randomWalkKlines(300, 5))
+	if saved, err := signals.SaveSnapshot(g.(signals.Generator), path); !saved || err != nil {
+		t.Fatalf("SaveSnapshot failed: %v %v", saved, err)
+	}
+	if loaded, err := signals.LoadSnapshot(build().(signals.Generator), path); !loaded || err != nil {
+		t.Fatalf("LoadSnapshot failed: %v %v", loaded, err)
+	}
+	if saved, _ := signals.SaveSnapshot(&stubGenerator{}, path); saved {
+		t.Errorf("Generator without state must not be saved")
+	}
+
+	data, _ := os.ReadFile(path)
+	if err := snapshotCases()["smart_eco"]().Restore(data); err == nil || !strings.HasPrefix(err.Error(), "generator:") {
+		t.Errorf("Expected generator mismatch error, got %v", err)
+	}
+	future, _ := signals.EncodeSnapshot("trend", 99, struct{}{})
+	if err := build().Restore(future); err == nil || !strings.HasPrefix(err.Error(), "version:") {
+		t.Errorf("Expected version error, got %v", err)
+	}
+}
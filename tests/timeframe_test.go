@@ -0,0 +1,104 @@
+// Package tests provides tests for timeframe parsing and alignment
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"agent-economique/internal/shared"
+)
+
+// TestParseTimeframe tests standard and custom timeframe parsing
+func TestParseTimeframe(t *testing.T) {
+	valid := map[string]int64{
+		"15s": 15 * 1000,
+		"30s": 30 * 1000,
+		"1m":  60 * 1000,
+		"2m":  2 * 60 * 1000,
+		"7m":  7 * 60 * 1000,
+		"3h":  3 * 60 * 60 * 1000,
+		"1d":  24 * 60 * 60 * 1000,
+		"1w":  7 * 24 * 60 * 60 * 1000,
+	}
+	for tf, expected := range valid {
+		got, err := shared.ParseTimeframe(tf)
+		if err != nil {
+			t.Errorf("ParseTimeframe(%s) unexpected error: %v", tf, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ParseTimeframe(%s) = %d, want %d", tf, got, expected)
+		}
+	}
+
+	for _, tf := range []string{"", "m", "0m", "-5m", "5x", "abc"} {
+		if _, err := shared.ParseTimeframe(tf); err == nil {
+			t.Errorf("ParseTimeframe(%q) expected error", tf)
+		}
+	}
+}
+
+// TestSourceTimeframe tests selection of the native source timeframe
+func TestSourceTimeframe(t *testing.T) {
+	cases := map[string]string{
+		"5m":  "5m",
+		"2m":  "1m",
+		"7m":  "1m",
+		"10m": "5m",
+		"45m": "15m",
+		"3h":  "1h",
+		"15s": "",
+	}
+	for tf, expected := range cases {
+		got, err := shared.SourceTimeframe(tf)
+		if err != nil {
+			t.Errorf("SourceTimeframe(%s) unexpected error: %v", tf, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("SourceTimeframe(%s) = %q, want %q", tf, got, expected)
+		}
+	}
+}
+
+// TestTimeframeBucketAlignment tests bucket boundaries for divisor and non-divisor intervals
+func TestTimeframeBucketAlignment(t *testing.T) {
+	ms := func(tm time.Time) int64 { return tm.UnixMilli() }
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	// 15s: epoch aligned
+	interval, _ := shared.ParseTimeframe("15s")
+	ts := ms(day.Add(10*time.Hour + 37*time.Second))
+	if got := shared.TimeframeBucketStart(ts, interval); got != ms(day.Add(10*time.Hour+30*time.Second)) {
+		t.Errorf("15s bucket start mismatch: %d", got)
+	}
+
+	// 7m: resets at UTC midnight, last bucket of the day is truncated
+	interval, _ = shared.ParseTimeframe("7m")
+	lastStart := day.Add(1435 * time.Minute) // 205 * 7m
+	ts = ms(day.Add(1438 * time.Minute))
+	if got := shared.TimeframeBucketStart(ts, interval); got != ms(lastStart) {
+		t.Errorf("7m bucket start mismatch: got %v, want %v", time.UnixMilli(got).UTC(), lastStart)
+	}
+	if got := shared.TimeframeBucketEnd(ms(lastStart), interval); got != ms(day.Add(24*time.Hour))-1 {
+		t.Errorf("7m last bucket should end at midnight, got %v", time.UnixMilli(got).UTC())
+	}
+	next, _ := shared.NextTimeframeBoundary(ts, "7m")
+	if next != ms(day.Add(24*time.Hour)) {
+		t.Errorf("7m next boundary should be midnight, got %v", time.UnixMilli(next).UTC())
+	}
+
+	// 1w: starts Monday 00:00 UTC (2024-03-04 is a Monday)
+	interval, _ = shared.ParseTimeframe("1w")
+	if got := shared.TimeframeBucketStart(ms(day.Add(36*time.Hour)), interval); got != ms(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("1w bucket should start on Monday, got %v", time.UnixMilli(got).UTC())
+	}
+
+	// Boundary detection ignores milliseconds within the boundary second
+	if !shared.IsTimeframeBoundary(ms(day.Add(2*time.Minute))+250, "2m") {
+		t.Error("Expected 2m boundary")
+	}
+	if shared.IsTimeframeBoundary(ms(day.Add(3*time.Minute)), "2m") {
+		t.Error("Did not expect 2m boundary at odd minute")
+	}
+}
@@ -0,0 +1,291 @@
+// Package tests provides the equivalence test of the BAN_FIN_MOMENTIUM window finder port
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"agent-economique/internal/indicators"
+	"agent-economique/internal/signals"
+	bfm "agent-economique/internal/signals/ban_fin_momentium"
+)
+
+// legacyWindow window state of the finder before its port onto signals.WindowGenerator
+type legacyWindow struct {
+	active   bool
+	deadline int
+	last     [3]int // Latest stoch / VWMA / MACD cross: +1 up, -1 down, 0 none
+}
+
+// legacyFinder reference: the pre-port WindowFinder.Step state machine on its own indicators
+type legacyFinder struct {
+	cfg    bfm.Config
+	win    bfm.WindowConfig
+	klines []signals.Kline
+
+	atr, volSMA, stochK, stochD, mfi, cci, vwmaFast, vwmaSlow, macd, macdSig, macdHist []float64
+
+	long, short legacyWindow
+}
+
+func newLegacyFinder(cfg bfm.Config, win bfm.WindowConfig, klines []signals.Kline) *legacyFinder {
+	n := len(klines)
+	high, low, closes, volume := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for i, k := range klines {
+		high[i], low[i], closes[i], volume[i] = k.High, k.Low, k.Close, k.Volume
+	}
+	f := &legacyFinder{cfg: cfg, win: win, klines: klines}
+	f.atr = indicators.NewATRTVStandard(cfg.ATRPeriod).Calculate(high, low, closes)
+	f.volSMA = indicators.NewSMATVStandard(cfg.VolumeSMAPeriod).Calculate(volume)
+	f.stochK, f.stochD = indicators.NewStochTVStandard(cfg.StochKPeriod, cfg.StochKSmooth, cfg.StochDPeriod).Calculate(high, low, closes)
+	f.mfi = indicators.NewMFITVStandard(cfg.MFIPeriod).Calculate(high, low, closes, volume)
+	f.cci = indicators.NewCCITVStandard(cfg.CCIPeriod).Calculate(high, low, closes)
+	f.vwmaFast = indicators.NewVWMATVStandard(cfg.VWMAFastPeriod).Calculate(closes, volume)
+	f.vwmaSlow = indicators.NewVWMATVStandard(cfg.VWMASlowPeriod).Calculate(closes, volume)
+	f.macd, f.macdSig, f.macdHist = indicators.NewMACDTVStandard(cfg.MACDFastPeriod, cfg.MACDSlowPeriod, cfg.MACDSignalPeriod).Calculate(closes)
+	return f
+}
+
+// cross up/down between two series on bar i (strict before the stochastic cross, as the generator)
+func legacyCross(fast, slow []float64, i int, strict bool) (up, down bool) {
+	pf, ps, cf, cs := fast[i-1], slow[i-1], fast[i], slow[i]
+	if math.IsNaN(pf) || math.IsNaN(ps) || math.IsNaN(cf) || math.IsNaN(cs) {
+		return false, false
+	}
+	if strict {
+		return pf < ps && cf > cs, pf > ps && cf < cs
+	}
+	return pf <= ps && cf > cs, pf >= ps && cf < cs
+}
+
+func (f *legacyFinder) step(idx int) (signals.SignalType, bool) {
+	c := f.cfg
+	for _, arr := range [][]float64{f.atr, f.volSMA, f.stochK, f.stochD, f.mfi, f.cci, f.vwmaFast, f.vwmaSlow, f.macd, f.macdSig, f.macdHist} {
+		if math.IsNaN(arr[idx]) {
+			return "", false
+		}
+	}
+
+	o, cl, vol := f.klines[idx].Open, f.klines[idx].Close, f.klines[idx].Volume
+	if c.Aggregate3 {
+		o, vol = f.klines[idx-2].Open, (f.klines[idx-2].Volume+f.klines[idx-1].Volume+vol)/3
+	}
+	b2atr, v2sma := math.Abs(cl-o)/f.atr[idx], vol/f.volSMA[idx]
+
+	var up, down [3]bool
+	enabled := [3]bool{c.EnableStochCross, c.EnableVWMACross, c.EnableMACDCrossGate}
+	up[0], down[0] = legacyCross(f.stochK, f.stochD, idx, true)
+	up[1], down[1] = legacyCross(f.vwmaFast, f.vwmaSlow, idx, false)
+	up[2], down[2] = legacyCross(f.macd, f.macdSig, idx, false)
+
+	// Gates on the current bar
+	ok := map[signals.SignalType]bool{signals.SignalTypeLong: true, signals.SignalTypeShort: true}
+	if c.EnableVWMATrendGate {
+		ok[signals.SignalTypeLong] = f.vwmaFast[idx] > f.vwmaSlow[idx]
+		ok[signals.SignalTypeShort] = f.vwmaFast[idx] < f.vwmaSlow[idx]
+	}
+	if c.EnableBarGate {
+		ok[signals.SignalTypeLong] = ok[signals.SignalTypeLong] && cl > o && b2atr >= c.BodyATRMultiplier && v2sma >= c.VolumeCoeff
+		ok[signals.SignalTypeShort] = ok[signals.SignalTypeShort] && cl < o && b2atr >= c.BodyATRMultiplier && v2sma >= c.VolumeCoeff
+	}
+	if c.EnableCCIGate {
+		ok[signals.SignalTypeLong] = ok[signals.SignalTypeLong] && (!c.EnableCCIOversoldLongGate || f.cci[idx] >= c.CCIOversoldLong) &&
+			(!c.EnableCCIOverboughtLongGate || f.cci[idx] <= c.CCIOverboughtLong)
+		ok[signals.SignalTypeShort] = ok[signals.SignalTypeShort] && (!c.EnableCCIOversoldShortGate || f.cci[idx] >= c.CCIOversoldShort) &&
+			(!c.EnableCCIOverboughtShortGate || f.cci[idx] <= c.CCIOverboughtShort)
+	}
+	if c.EnableMFIGate {
+		ok[signals.SignalTypeLong] = ok[signals.SignalTypeLong] && (!c.EnableMFIOversoldLongGate || f.mfi[idx] >= c.MFIOversoldLong) &&
+			(!c.EnableMFIOverboughtLongGate || f.mfi[idx] <= c.MFIOverboughtLong)
+		ok[signals.SignalTypeShort] = ok[signals.SignalTypeShort] && (!c.EnableMFIOversoldShortGate || f.mfi[idx] >= c.MFIOversoldShort) &&
+			(!c.EnableMFIOverboughtShortGate || f.mfi[idx] <= c.MFIOverboughtShort)
+	}
+	ok[signals.SignalTypeLong] = ok[signals.SignalTypeLong] && (!c.EnableStochKOversoldLongGate || f.stochK[idx] <= c.StochKOversoldLong)
+	ok[signals.SignalTypeShort] = ok[signals.SignalTypeShort] && (!c.EnableStochKOversoldShortGate || f.stochK[idx] >= c.StochKOversoldShort)
+	if c.EnableMACDSignGate {
+		ok[signals.SignalTypeLong] = ok[signals.SignalTypeLong] && f.macd[idx] < 0 && f.macdSig[idx] < 0
+		ok[signals.SignalTypeShort] = ok[signals.SignalTypeShort] && f.macd[idx] > 0 && f.macdSig[idx] > 0
+	}
+	if c.EnableMACDHistGate {
+		ok[signals.SignalTypeLong] = ok[signals.SignalTypeLong] && f.macdHist[idx] > 0
+		ok[signals.SignalTypeShort] = ok[signals.SignalTypeShort] && f.macdHist[idx] < 0
+	}
+	barOK := map[signals.SignalType]bool{signals.SignalTypeLong: !c.EnableBarGate || cl > o && b2atr >= c.BodyATRMultiplier && v2sma >= c.VolumeCoeff,
+		signals.SignalTypeShort: !c.EnableBarGate || cl < o && b2atr >= c.BodyATRMultiplier && v2sma >= c.VolumeCoeff}
+
+	crossEnabled := enabled[0] || enabled[1] || enabled[2]
+	windows := map[signals.SignalType]*legacyWindow{signals.SignalTypeLong: &f.long, signals.SignalTypeShort: &f.short}
+	events := map[signals.SignalType][3]bool{signals.SignalTypeLong: up, signals.SignalTypeShort: down}
+	sideEnabled := map[signals.SignalType]bool{signals.SignalTypeLong: c.EnableOpenLong, signals.SignalTypeShort: c.EnableOpenShort}
+	sides := []signals.SignalType{signals.SignalTypeLong, signals.SignalTypeShort}
+	update := func(w *legacyWindow) {
+		for s := range enabled {
+			if enabled[s] && up[s] {
+				w.last[s] = 1
+			} else if enabled[s] && down[s] {
+				w.last[s] = -1
+			}
+		}
+	}
+
+	// Opening, then expiry, then latest crosses
+	for _, side := range sides {
+		w, ev := windows[side], events[side]
+		if !sideEnabled[side] || w.active {
+			continue
+		}
+		anyCross := enabled[0] && ev[0] || enabled[1] && ev[1] || enabled[2] && ev[2]
+		open := false
+		switch f.win.OpenMode {
+		case bfm.WindowOpenModeAuto:
+			open = crossEnabled && anyCross || !crossEnabled && barOK[side]
+		case bfm.WindowOpenModeCross:
+			open = anyCross
+		case bfm.WindowOpenModeBar:
+			open = barOK[side]
+		case bfm.WindowOpenModeStochCross:
+			open = enabled[0] && ev[0]
+		case bfm.WindowOpenModeVWMACross:
+			open = enabled[1] && ev[1]
+		case bfm.WindowOpenModeMACDCross:
+			open = enabled[2] && ev[2]
+		}
+		if open {
+			*w = legacyWindow{active: true, deadline: idx + f.win.WindowBars - 1}
+		}
+	}
+	for _, side := range sides {
+		if w := windows[side]; w.active && idx > w.deadline {
+			*w = legacyWindow{}
+		}
+		if w := windows[side]; w.active {
+			update(w)
+		}
+	}
+
+	// Validation, LONG first
+	for _, side := range sides {
+		w := windows[side]
+		if !w.active || !sideEnabled[side] || !ok[side] {
+			continue
+		}
+		want := 1
+		if side == signals.SignalTypeShort {
+			want = -1
+		}
+		crossOK := true
+		for s := range enabled {
+			crossOK = crossOK && (!enabled[s] || w.last[s] == want)
+		}
+		if crossOK {
+			f.long, f.short = legacyWindow{}, legacyWindow{}
+			return side, true
+		}
+	}
+	return "", false
+}
+
+// momentiumKlines oscillating series with volume bursts
+func momentiumKlines(n int) []signals.Kline {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), n, 5*time.Minute)
+	prev := 100.0
+	for i := range klines {
+		price := 100 + 6*math.Sin(float64(i)/9) + 3*math.Sin(float64(i)/2.3) + float64(i%5)*0.4
+		klines[i].Open, klines[i].Close = prev, price
+		klines[i].High = math.Max(prev, price) + 0.3 + float64(i%3)*0.2
+		klines[i].Low = math.Min(prev, price) - 0.3 - float64(i%4)*0.15
+		klines[i].Volume = 1000 + 600*math.Abs(math.Sin(float64(i)/4)) + float64(i%7)*120
+		prev = price
+	}
+	return klines
+}
+
+// TestWindowFinder_MatchesLegacy checks the WindowGenerator port against the pre-port state machine
+func TestWindowFinder_MatchesLegacy(t *testing.T) {
+	base := bfm.Config{
+		ATRPeriod: 10, BodyATRMultiplier: 0.3, VolumeSMAPeriod: 20, VolumeCoeff: 0.9,
+		VWMAFastPeriod: 5, VWMASlowPeriod: 20,
+		StochKPeriod: 14, StochKSmooth: 3, StochDPeriod: 3,
+		MFIPeriod: 14, CCIPeriod: 20,
+		MACDFastPeriod: 12, MACDSlowPeriod: 26, MACDSignalPeriod: 9,
+		EnableOpenLong: true, EnableOpenShort: true,
+	}
+	cases := []struct {
+		name   string
+		win    bfm.WindowConfig
+		modify func(c *bfm.Config)
+	}{
+		{"auto_stoch_bar", bfm.WindowConfig{WindowBars: 6}, func(c *bfm.Config) {
+			c.EnableStochCross, c.EnableBarGate = true, true
+		}},
+		{"cross_all_filters", bfm.WindowConfig{WindowBars: 8, OpenMode: bfm.WindowOpenModeCross}, func(c *bfm.Config) {
+			c.EnableStochCross, c.EnableMACDCrossGate, c.EnableBarGate = true, true, true
+			c.EnableCCIGate, c.EnableCCIOversoldLongGate, c.EnableCCIOverboughtShortGate = true, true, true
+			c.CCIOversoldLong, c.CCIOverboughtShort = -150, 150
+			c.EnableMFIGate, c.EnableMFIOverboughtLongGate, c.EnableMFIOversoldShortGate = true, true, true
+			c.MFIOverboughtLong, c.MFIOversoldShort = 85, 15
+		}},
+		{"bar_trend_agg3", bfm.WindowConfig{WindowBars: 4, OpenMode: bfm.WindowOpenModeBar}, func(c *bfm.Config) {
+			c.EnableBarGate, c.EnableVWMATrendGate, c.Aggregate3, c.EnableMACDHistGate = true, true, true, true
+			c.EnableOpenShort = false
+		}},
+		{"vwma_open_stochk", bfm.WindowConfig{WindowBars: 12, OpenMode: bfm.WindowOpenModeVWMACross}, func(c *bfm.Config) {
+			c.EnableVWMACross, c.EnableStochCross = true, true
+			c.EnableStochKOversoldLongGate, c.EnableStochKOversoldShortGate = true, true
+			c.StochKOversoldLong, c.StochKOversoldShort = 60, 40
+		}},
+		{"macd_open_sign", bfm.WindowConfig{WindowBars: 10, OpenMode: bfm.WindowOpenModeMACDCross}, func(c *bfm.Config) {
+			c.EnableMACDCrossGate, c.EnableMACDSignGate, c.EnableBarGate = true, true, true
+		}},
+		{"stoch_open_without_stoch", bfm.WindowConfig{WindowBars: 5, OpenMode: bfm.WindowOpenModeStochCross}, func(c *bfm.Config) {
+			c.EnableBarGate = true
+		}},
+	}
+
+	klines := momentiumKlines(800)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := base
+			tc.modify(&cfg)
+			gen := bfm.NewGenerator(cfg)
+			if err := gen.Initialize(signals.GeneratorConfig{}); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+			if err := gen.CalculateIndicators(klines); err != nil {
+				t.Fatalf("CalculateIndicators failed: %v", err)
+			}
+			finder := bfm.NewWindowFinder(gen, tc.win)
+			win := tc.win
+			if win.OpenMode == "" {
+				win.OpenMode = bfm.WindowOpenModeAuto
+			}
+			legacy := newLegacyFinder(cfg, win, klines)
+
+			fired := 0
+			for i := 40; i < len(klines); i++ {
+				sig, err := finder.Step(klines, i)
+				if err != nil {
+					t.Fatalf("Step failed: %v", err)
+				}
+				side, want := legacy.step(i)
+				if want != (sig != nil) || sig != nil && (sig.Type != side || !sig.Timestamp.Equal(klines[i].OpenTime)) {
+					t.Fatalf("bar %d: port %+v, legacy %v %q", i, sig, want, side)
+				}
+				if sig != nil {
+					fired++
+					if sig.Action != signals.SignalActionEntry || sig.Metadata["generator"] != "ban_fin_momentium" {
+						t.Errorf("bar %d: unexpected signal %+v", i, sig)
+					}
+				}
+			}
+			if tc.name == "stoch_open_without_stoch" {
+				if fired != 0 {
+					t.Errorf("No stochastic cross enabled, no window expected: %d signals", fired)
+				}
+			} else if fired == 0 {
+				t.Errorf("Expected signals to compare, got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package tests provides tests for the confirmation window combinator
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"agent-economique/internal/signals"
+)
+
+// scriptSource is true on the bars listed per side (identified by open time)
+type scriptSource struct {
+	name   string
+	at     map[time.Time]signals.SignalType
+	klines []signals.Kline
+}
+
+func (s *scriptSource) Name() string        { return s.name }
+func (s *scriptSource) MinHistorySize() int { return 0 }
+func (s *scriptSource) Calculate(klines []signals.Kline) error {
+	s.klines = klines
+	return nil
+}
+func (s *scriptSource) At(i int, side signals.SignalType) bool {
+	return i >= 0 && i < len(s.klines) && s.at[s.klines[i].OpenTime] == side
+}
+
+// runWindow feeds a sliding window of 10 klines, one new bar per call
+func runWindow(t *testing.T, gen *signals.WindowGenerator, klines []signals.Kline) []signals.Signal {
+	t.Helper()
+	if err := gen.Initialize(signals.GeneratorConfig{}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	var out []signals.Signal
+	for end := 10; end <= len(klines); end++ {
+		window := klines[end-10 : end]
+		if err := gen.CalculateIndicators(window); err != nil {
+			t.Fatalf("CalculateIndicators failed: %v", err)
+		}
+		sigs, err := gen.DetectSignals(window)
+		if err != nil {
+			t.Fatalf("DetectSignals failed: %v", err)
+		}
+		out = append(out, sigs...)
+	}
+	return out
+}
+
+// TestWindowGenerator_FireAndExpire checks opening, pre-window lookback, firing and expiry
+func TestWindowGenerator_FireAndExpire(t *testing.T) {
+	klines := testKlines(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 60, time.Hour)
+	long, short := signals.SignalTypeLong, signals.SignalTypeShort
+	opener := &scriptSource{name: "trigger", at: map[time.Time]signals.SignalType{
+		klines[15].OpenTime: long, klines[30].OpenTime: short, klines[45].OpenTime: long,
+	}}
+	filter := &scriptSource{name: "filter", at: map[time.Time]signals.SignalType{
+		klines[13].OpenTime: long, // Pre-window of the first window
+		klines[31].OpenTime: short,
+	}}
+	candle := &scriptSource{name: "candle", at: map[time.Time]signals.SignalType{
+		klines[18].OpenTime: long, klines[36].OpenTime: short, klines[47].OpenTime: long,
+	}}
+	momentum := &scriptSource{name: "momentum", at: map[time.Time]signals.SignalType{
+		klines[46].OpenTime: short, // Opposite event after the opening: latest is SHORT
+	}}
+
+	gen, err := signals.NewWindowGenerator(signals.WindowConfig{
+		Name: "test_window",
+		Conditions: []signals.WindowCondition{
+			{Source: opener, Mode: signals.WindowModeLatest},
+			{Source: filter},
+			{Source: candle, Mode: signals.WindowModeBar},
+			{Source: momentum, Mode: signals.WindowModeLatest},
+		},
+		OpenMode:        "trigger",
+		WindowBars:      5,
+		WindowBarsShort: 4,
+		PreWindow:       2,
+	})
+	if err != nil {
+		t.Fatalf("NewWindowGenerator failed: %v", err)
+	}
+	// Momentum never turns LONG inside a LONG window (opposite event at 46)
+	if out := runWindow(t, gen, klines); len(out) != 0 {
+		t.Fatalf("Momentum condition missing, no signal expected: %+v", out)
+	}
+
+	// Without momentum: LONG fires at 18, SHORT expires (deadline 33 with 4 bars), LONG at 45/47
+	gen, err = signals.NewWindowGenerator(signals.WindowConfig{
+		Name: "test_window",
+		Conditions: []signals.WindowCondition{
+			{Source: opener, Mode: signals.WindowModeLatest},
+			{Source: filter},
+			{Source: candle, Mode: signals.WindowModeBar},
+		},
+		OpenMode:          "trigger",
+		WindowBars:        5,
+		WindowBarsShort:   4,
+		PreWindow:         2,
+		ReverseOnOpposite: true,
+		MaxHoldBars:       20,
+	})
+	if err != nil {
+		t.Fatalf("NewWindowGenerator failed: %v", err)
+	}
+	out := runWindow(t, gen, klines)
+	if len(out) != 2 {
+		t.Fatalf("Expected entry and max hold exit, got %+v", out)
+	}
+	if out[0].Type != long || !out[0].Timestamp.Equal(klines[18].OpenTime) || out[0].Metadata["bars_from_open"] != 3 ||
+		!out[0].Metadata["window_open_time"].(time.Time).Equal(klines[15].OpenTime) {
+		t.Errorf("Unexpected entry %+v", out[0])
+	}
+	if out[1].Action != signals.SignalActionExit || out[1].Metadata["exit_reason"] != "max_hold" || !out[1].Timestamp.Equal(klines[38].OpenTime) {
+		t.Errorf("Unexpected exit %+v", out[1])
+	}
+	// Sides count entries only: the exit is not a second LONG
+	if m := gen.GetMetrics(); m.EntrySignals != 1 || m.ExitSignals != 1 || m.LongSignals != 1 || m.ShortSignals != 0 {
+		t.Errorf("Unexpected metrics %+v", m)
+	}
+
+	diags := gen.Diagnostics()
+	if len(diags) != 3 {
+		t.Fatalf("Expected 3 closed windows, got %+v", diags)
+	}
+	if diags[0].Outcome != signals.WindowFired || diags[1].Outcome != signals.WindowExpired || diags[1].Side != short ||
+		strings.Join(diags[1].Missing, ",") != "candle" || diags[1].Bars != 4 {
+		t.Errorf("Unexpected diagnostics %+v", diags[:2])
+	}
+	// Third window (LONG at 45): filter missing until expiry
+	if diags[2].Outcome != signals.WindowExpired || strings.Join(diags[2].Missing, ",") != "filter,candle" {
+		t.Errorf("Unexpected last diagnostic %+v", diags[2])
+	}
+}
+
+// TestWindowGenerator_Validation checks configuration errors
+func TestWindowGenerator_Validation(t *testing.T) {
+	source := &scriptSource{name: "a"}
+	cases := []struct {
+		config signals.WindowConfig
+		field  string
+	}{
+		{signals.WindowConfig{}, "conditions"},
+		{signals.WindowConfig{Conditions: []signals.WindowCondition{{Source: source, Mode: "always"}}}, "conditions[0].mode"},
+		{signals.WindowConfig{Conditions: []signals.WindowCondition{{Source: source}}}, "open_mode"},
+		{signals.WindowConfig{Conditions: []signals.WindowCondition{{Source: source}}, OpenMode: "a", Sides: []signals.SignalType{"UP"}}, "sides"},
+	}
+	for _, c := range cases {
+		if _, err := signals.NewWindowGenerator(c.config); err == nil || !strings.HasPrefix(err.Error(), c.field) {
+			t.Errorf("Expected %s error, got %v", c.field, err)
+		}
+	}
+
+	// Auto mode picks the cross sources
+	gen, err := signals.NewWindowGenerator(signals.WindowConfig{Conditions: []signals.WindowCondition{
+		{Source: signals.NewStochCrossSource(14, 3, 3), Mode: signals.WindowModeLatest},
+		{Source: signals.NewBarSource(signals.BarSourceConfig{BodyATR: 0.5})},
+	}})
+	if err != nil || gen.MinHistorySize() != 21 {
+		t.Errorf("Unexpected auto mode generator: %v", err)
+	}
+}